@@ -411,6 +411,22 @@ func (c *Config) ApplyOptimizations(v *viper.Viper, input *OptimizationInput) ma
 	return optimizedFlags
 }
 
+type ChaosConfig struct {
+	Enable bool `yaml:"enable"`
+
+	ErrorRates []string `yaml:"error-rates"`
+
+	ReadStallDuration time.Duration `yaml:"read-stall-duration"`
+
+	ReadStallRate float64 `yaml:"read-stall-rate"`
+
+	Seed int64 `yaml:"seed"`
+
+	TruncatedReadRate float64 `yaml:"truncated-read-rate"`
+
+	WritePerMbLatency time.Duration `yaml:"write-per-mb-latency"`
+}
+
 type CloudProfilerConfig struct {
 	AllocatedHeap bool `yaml:"allocated-heap"`
 
@@ -434,6 +450,8 @@ type Config struct {
 
 	CacheDir ResolvedPath `yaml:"cache-dir"`
 
+	Chaos ChaosConfig `yaml:"chaos"`
+
 	CloudProfiler CloudProfilerConfig `yaml:"cloud-profiler"`
 
 	Debug DebugConfig `yaml:"debug"`
@@ -456,6 +474,8 @@ type Config struct {
 
 	EnableUnsupportedPathSupport bool `yaml:"enable-unsupported-path-support"`
 
+	Encryption EncryptionConfig `yaml:"encryption"`
+
 	FileCache FileCacheConfig `yaml:"file-cache"`
 
 	FileSystem FileSystemConfig `yaml:"file-system"`
@@ -484,6 +504,8 @@ type Config struct {
 
 	OnlyDir string `yaml:"only-dir"`
 
+	PointInTime string `yaml:"point-in-time"`
+
 	Profile string `yaml:"profile"`
 
 	Read ReadConfig `yaml:"read"`
@@ -513,6 +535,12 @@ type DummyIoConfig struct {
 	ReaderLatency time.Duration `yaml:"reader-latency"`
 }
 
+type EncryptionConfig struct {
+	ClientSideKeyFile ResolvedPath `yaml:"client-side-key-file"`
+
+	CustomerSuppliedKeyFile ResolvedPath `yaml:"customer-supplied-key-file"`
+}
+
 type FileCacheConfig struct {
 	CacheFileForRangeRead bool `yaml:"cache-file-for-range-read"`
 
@@ -526,6 +554,8 @@ type FileCacheConfig struct {
 
 	EnableParallelDownloads bool `yaml:"enable-parallel-downloads"`
 
+	EvictionPolicy string `yaml:"eviction-policy"`
+
 	ExcludeRegex string `yaml:"exclude-regex"`
 
 	ExperimentalDisableSizeCalculationFix bool `yaml:"experimental-disable-size-calculation-fix"`
@@ -542,20 +572,38 @@ type FileCacheConfig struct {
 
 	ParallelDownloadsPerFile int64 `yaml:"parallel-downloads-per-file"`
 
+	PersistIndex bool `yaml:"persist-index"`
+
+	RamTierMaxBlocks int64 `yaml:"ram-tier-max-blocks"`
+
 	SharedCacheChunkSizeMb int64 `yaml:"shared-cache-chunk-size-mb"`
 
+	WarmUpManifest ResolvedPath `yaml:"warm-up-manifest"`
+
+	WarmUpParallelism int64 `yaml:"warm-up-parallelism"`
+
 	WriteBufferSize int64 `yaml:"write-buffer-size"`
 }
 
 type FileSystemConfig struct {
+	AdminSocketPath ResolvedPath `yaml:"admin-socket-path"`
+
 	CongestionThreshold int64 `yaml:"congestion-threshold"`
 
 	DirMode Octal `yaml:"dir-mode"`
 
 	DisableParallelDirops bool `yaml:"disable-parallel-dirops"`
 
+	EnableHardLinks bool `yaml:"enable-hard-links"`
+
 	EnableKernelReader bool `yaml:"enable-kernel-reader"`
 
+	EnableStableInodeIds bool `yaml:"enable-stable-inode-ids"`
+
+	EnableVersionsDir bool `yaml:"enable-versions-dir"`
+
+	EnableXattrs bool `yaml:"enable-xattrs"`
+
 	ExperimentalEnableDentryCache bool `yaml:"experimental-enable-dentry-cache"`
 
 	ExperimentalEnablePirlo bool `yaml:"experimental-enable-pirlo"`
@@ -584,11 +632,17 @@ type FileSystemConfig struct {
 
 	MaxReadAheadKb int64 `yaml:"max-read-ahead-kb"`
 
+	PersistPosixAttributes bool `yaml:"persist-posix-attributes"`
+
+	QuotaMb int64 `yaml:"quota-mb"`
+
 	RenameDirLimit int64 `yaml:"rename-dir-limit"`
 
 	TempDir ResolvedPath `yaml:"temp-dir"`
 
 	Uid int64 `yaml:"uid"`
+
+	UsageRefreshInterval time.Duration `yaml:"usage-refresh-interval"`
 }
 
 type GcsAuthConfig struct {
@@ -784,11 +838,23 @@ type WriteConfig struct {
 
 	GlobalMaxBlocks int64 `yaml:"global-max-blocks"`
 
+	GzipCompressionGlobs []string `yaml:"gzip-compression-globs"`
+
 	MaxBlocksPerFile int64 `yaml:"max-blocks-per-file"`
+
+	ObjectMetadataRulesFile ResolvedPath `yaml:"object-metadata-rules-file"`
+
+	OutOfOrderWindowBlocks int64 `yaml:"out-of-order-window-blocks"`
+
+	ParallelCompositeUploadParts int64 `yaml:"parallel-composite-upload-parts"`
+
+	ParallelCompositeUploadThresholdMb int64 `yaml:"parallel-composite-upload-threshold-mb"`
 }
 
 func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
+	flagSet.StringP("admin-socket-path", "", "", "Path of a unix domain socket on which the mount process serves an admin API to inspect the mount and invalidate its caches. Disabled if empty.")
+
 	flagSet.BoolP("anonymous-access", "", false, "This flag disables authentication.")
 
 	flagSet.StringP("app-name", "", "", "The application name of this mount.")
@@ -797,6 +863,42 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.StringP("cache-dir", "", "", "Enables file-caching. Specifies the directory to use for file-cache.")
 
+	flagSet.StringSliceP("chaos-error-rates", "", []string{}, "Rates of errors to inject in chaos mode, each as \"<method>:<HTTP status>:<rate>\", such as \"StatObject:503:0.01\" or \"*:429:0.001\". The method is one of those of the bucket interface, such as NewReaderWithReadHandle, FinalizeUpload or ComposeObjects, or * for all of them, and the status one of 404, 412, 429 and 503.")
+
+	if err := flagSet.MarkHidden("chaos-error-rates"); err != nil {
+		return err
+	}
+
+	flagSet.DurationP("chaos-read-stall-duration", "", 30000000000*time.Nanosecond, "How long stalled reads stall in chaos mode.")
+
+	if err := flagSet.MarkHidden("chaos-read-stall-duration"); err != nil {
+		return err
+	}
+
+	flagSet.Float64P("chaos-read-stall-rate", "", 0, "Fraction of the readers that stall at a random offset for chaos-read-stall-duration in chaos mode.")
+
+	if err := flagSet.MarkHidden("chaos-read-stall-rate"); err != nil {
+		return err
+	}
+
+	flagSet.IntP("chaos-seed", "", 0, "Seed of the random faults injected in chaos mode, so that a sequence of operations meets the same faults from one run to the next.")
+
+	if err := flagSet.MarkHidden("chaos-seed"); err != nil {
+		return err
+	}
+
+	flagSet.Float64P("chaos-truncated-read-rate", "", 0, "Fraction of the readers that fail with an unexpected EOF at a random offset in chaos mode.")
+
+	if err := flagSet.MarkHidden("chaos-truncated-read-rate"); err != nil {
+		return err
+	}
+
+	flagSet.DurationP("chaos-write-per-mb-latency", "", 0*time.Nanosecond, "Latency added to the uploads of each MiB in chaos mode.")
+
+	if err := flagSet.MarkHidden("chaos-write-per-mb-latency"); err != nil {
+		return err
+	}
+
 	flagSet.IntP("chunk-retry-deadline-secs", "", 120, "We send larger file uploads in 16 MiB (Legacy Writes) or 32MiB (Streaming Writes) chunks. This flag controls the overall duration that GCSFuse would keep retrying for a single chunk upload completion. 0 means infinity duration for chunk retries.")
 
 	if err := flagSet.MarkHidden("chunk-retry-deadline-secs"); err != nil {
@@ -811,6 +913,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.StringP("client-protocol", "", "http1", "The protocol used for communicating with the GCS backend. Value can be 'http1' (HTTP/1.1), 'http2' (HTTP/2) or 'grpc'.")
 
+	flagSet.StringP("client-side-encryption-key-file", "", "", "Path to a JSON keyfile of the form {\"primary\": \"<key id>\", \"keys\": {\"<key id>\": \"<base64-encoded 256-bit key>\"}}. If set, the contents of the objects written through the mount are encrypted with the primary key before upload, with its ID recorded in their metadata, and the contents of the objects read are decrypted with the key of the recorded ID. Objects without a key ID are read as is. Not supported for zonal buckets.")
+
 	flagSet.IntP("cloud-metrics-export-interval-secs", "", 0, "Specifies the interval at which the metrics are uploaded to cloud monitoring")
 
 	flagSet.BoolP("cloud-profiler-allocated-heap", "", true, "Enables allocated heap (HeapProfileAllocs) profiling. This only works when --enable-cloud-profiler is set to true.")
@@ -867,7 +971,9 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
-	flagSet.StringP("custom-endpoint", "", "", "To specify a custom storage endpoint, ensure it supports the same resources as the default storage.googleapis.com:443 and includes the port number.")
+	flagSet.StringP("custom-endpoint", "", "", "To specify a custom storage endpoint, ensure it supports the same resources as the default storage.googleapis.com:443 and includes the port number. A file:// URL instead stores each bucket in the directory of the same name under the given path, which must have been created beforehand.")
+
+	flagSet.StringP("customer-supplied-encryption-key-file", "", "", "Path to a file holding a base64-encoded 256-bit AES key. If set, the key is sent to GCS as a customer-supplied encryption key (CSEK) with every object read, write, compose and copy, which makes objects encrypted with it readable and encrypts the objects written through the mount with it.")
 
 	flagSet.BoolP("debug_fs", "", false, "This flag is unused.")
 
@@ -937,6 +1043,12 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.BoolP("enable-buffered-read", "", false, "When enabled, read starts using buffer to prefetch (asynchronous and in parallel) data from GCS. This improves performance for large file sequential reads. Note: Enabling this flag can increase the memory usage significantly.")
 
+	flagSet.BoolP("enable-chaos", "", false, "Inject faults into the Cloud Storage operations for testing purposes, as configured by the other chaos options.")
+
+	if err := flagSet.MarkHidden("enable-chaos"); err != nil {
+		return err
+	}
+
 	flagSet.BoolP("enable-cloud-profiler", "", false, "Enables cloud-profiler, by default disabled.")
 
 	if err := flagSet.MarkHidden("enable-cloud-profiler"); err != nil {
@@ -963,6 +1075,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("enable-hard-links", "", false, "Emulates hard links with small pointer objects. The contents of a file are moved under the hidden .gcsfuse_links directory at the root of the bucket when it is first linked.")
+
 	flagSet.BoolP("enable-hns", "", true, "Enables support for HNS buckets")
 
 	if err := flagSet.MarkHidden("enable-hns"); err != nil {
@@ -1007,6 +1121,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("enable-stable-inode-ids", "", false, "Derives inode IDs from a hash of the bucket and object names instead of allocating them sequentially, so that they stay the same across remounts.")
+
 	flagSet.BoolP("enable-standard-symlinks", "", true, "Enables the creation and reading of symbolic links using the standard GCS representation. When enabled, new symlinks created via GCSFuse mount ensure compatibility with other GCS clients like Storage Transfer Service (STS).")
 
 	if err := flagSet.MarkHidden("enable-standard-symlinks"); err != nil {
@@ -1027,6 +1143,10 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("enable-versions-dir", "", false, "Enables the hidden .gcsfuse_versions directory at the root of each bucket, which lists the noncurrent and soft-deleted generations of the objects and lets you read the noncurrent ones.")
+
+	flagSet.BoolP("enable-xattrs", "", false, "Enables extended attributes on files. user.* attributes are stored in the custom metadata of the backing object and read-only gcsfuse.* attributes expose object properties such as generation and CRC32C.")
+
 	flagSet.BoolP("experimental-enable-dentry-cache", "", false, "When enabled, it sets the Dentry cache entry timeout same as metadata-cache-ttl. This enables kernel to use cached entry to map the file paths to inodes, instead of making LookUpInode calls to GCSFuse.")
 
 	if err := flagSet.MarkHidden("experimental-enable-dentry-cache"); err != nil {
@@ -1107,6 +1227,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.BoolP("file-cache-enable-parallel-downloads", "", false, "Enable parallel downloads.")
 
+	flagSet.StringP("file-cache-eviction-policy", "", "lru", "The policy choosing the files evicted from the file-cache when it's full. Supported values: \"lru\" evicts the least recently used file, \"2q\" resists large sequential scans by only protecting files that are cached again soon after their eviction, and \"gdsf\" prefers to keep small and frequently read files.")
+
 	flagSet.StringP("file-cache-exclude-regex", "", "", "Exclude file paths (in the format bucket_name/object_key) specified by this regex from file caching.")
 
 	flagSet.BoolP("file-cache-experimental-disable-size-calculation-fix", "", false, "Disable the fix in calculation of disk-utilization of file-cache.")
@@ -1135,12 +1257,20 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.IntP("file-cache-parallel-downloads-per-file", "", 16, "Number of concurrent download requests per file.")
 
+	flagSet.BoolP("file-cache-persist-index", "", false, "Saves the index of the file-cache of each bucket to the cache-dir at unmount so that cached files whose objects haven't changed are reused by the next mount at the same mount point instead of being downloaded again.")
+
+	flagSet.IntP("file-cache-ram-tier-max-blocks", "", 0, "Maximum number of blocks of read-block-size-mb each that are kept in memory in front of the file-cache, so that hot data is served across file handles without reading cache-dir. The blocks count towards read-global-max-blocks. 0 disables the in-memory tier.")
+
 	flagSet.IntP("file-cache-shared-cache-chunk-size-mb", "", 8, "Chunk size in MiBs for shared chunk cache. Each chunk is downloaded on-demand.")
 
 	if err := flagSet.MarkHidden("file-cache-shared-cache-chunk-size-mb"); err != nil {
 		return err
 	}
 
+	flagSet.StringP("file-cache-warm-up-manifest", "", "", "Path to a manifest of object name prefixes and globs, one per line, whose objects are downloaded to the file-cache before the mount completes, so that the mount is ready only once they are local. Lines starting with # are ignored. Also used by the warm-cache subcommand.")
+
+	flagSet.IntP("file-cache-warm-up-parallelism", "", 16, "Maximum number of objects downloaded concurrently while warming up the file-cache.")
+
 	flagSet.IntP("file-cache-write-buffer-size", "", 4194304, "Size of in-memory buffer that is used per goroutine in parallel downloads while writing to file-cache.")
 
 	if err := flagSet.MarkHidden("file-cache-write-buffer-size"); err != nil {
@@ -1171,6 +1301,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.StringSliceP("gzip-compression-globs", "", []string{}, "Comma separated globs of object names, such as \"logs/**/*.log\", whose contents are gzip-compressed when written. The uncompressed size is recorded in the object metadata and reads decompress transparently.")
+
 	flagSet.DurationP("http-client-timeout", "", 0*time.Nanosecond, "The time duration that http client will wait to get response from the server. A value of 0 indicates no timeout.")
 
 	flagSet.BoolP("ignore-interrupts", "", true, "Instructs gcsfuse to ignore system interrupt signals (like SIGINT, triggered by Ctrl+C). This prevents those signals from immediately terminating gcsfuse inflight operations.")
@@ -1275,12 +1407,20 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.StringSliceP("o", "", []string{}, "Additional system-specific mount options. Multiple options can be passed as comma separated. For readonly, use --o ro")
 
+	flagSet.StringP("object-metadata-rules-file", "", "", "Path to a YAML file of ordered rules, each with a \"match\" glob of object names and any of content-type, cache-control, content-encoding, content-disposition, storage-class, custom-time, event-based-hold and metadata. The attributes of the first rule matching the name of a new object are set on it, unless already set.")
+
 	flagSet.StringP("only-dir", "", "", "Mount only a specific directory within the bucket. See docs/mounting for more information")
 
+	flagSet.BoolP("persist-posix-attributes", "", false, "Persists the mode, owner, group and access time of files in the goog-reserved-posix-mode, goog-reserved-posix-uid, goog-reserved-posix-gid and goog-reserved-file-atime metadata keys of the backing object, as written by gsutil -P, and reports them instead of the mount-wide file-mode, uid and gid.")
+
+	flagSet.StringP("point-in-time", "", "", "Mount the bucket read-only as it was at the given RFC3339 time, e.g. 2026-01-02T15:04:05Z, serving the generations that were live then from the noncurrent generations of the objects. Requires object versioning to retain them. Generations that have since been soft-deleted are skipped.")
+
 	flagSet.StringP("profile", "", "", "The name of the profile to apply. e.g. aiml-training, aiml-serving, aiml-checkpointing")
 
 	flagSet.IntP("prometheus-port", "", 0, "Expose Prometheus metrics endpoint on this port and a path of /metrics.")
 
+	flagSet.IntP("quota-mb", "", 0, "Size of the file system reported to statfs(2), e.g. by df, in MiB. The space used is estimated as configured by usage-refresh-interval. 0 reports a practically unlimited size.")
+
 	flagSet.IntP("read-block-size-mb", "", 16, "Specifies the block size for buffered reads. The value should be more than 0. This is used to read data in chunks from GCS.")
 
 	if err := flagSet.MarkHidden("read-block-size-mb"); err != nil {
@@ -1405,6 +1545,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.IntP("uid", "", -1, "UID owner of all inodes.")
 
+	flagSet.DurationP("usage-refresh-interval", "", 0*time.Nanosecond, "How often the space used in the bucket is estimated by listing all of its objects, to be reported to statfs(2) when quota-mb is set. Listing large buckets is slow and costly. 0 disables the estimate, and reports the whole quota as free.")
+
 	flagSet.BoolP("visualize-workload-insight", "", false, "A flag to enable workload visualization. When enabled, workload insights will include visualizations to help understand access patterns. Insights will be written to the file specified by --workload-insight-output-file.")
 
 	if err := flagSet.MarkHidden("visualize-workload-insight"); err != nil {
@@ -1443,11 +1585,33 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.IntP("write-out-of-order-window-blocks", "", 0, "Specifies the number of full blocks of a file kept in memory before being uploaded by streaming writes, so that writes which aren't at the end of the file but within these blocks don't fall back to staged writes. The value should be >= 0 and less than write-max-blocks-per-file, unless that is -1. A value of 0 disables out-of-order writes.")
+
+	if err := flagSet.MarkHidden("write-out-of-order-window-blocks"); err != nil {
+		return err
+	}
+
+	flagSet.IntP("write-parallel-composite-upload-parts", "", 8, "Specifies the number of parts uploaded concurrently by parallel composite uploads. The value should be between 2 and 32.")
+
+	if err := flagSet.MarkHidden("write-parallel-composite-upload-parts"); err != nil {
+		return err
+	}
+
+	flagSet.IntP("write-parallel-composite-upload-threshold-mb", "", 0, "Files of at least this size that are synced from a local copy are uploaded as parts in parallel and composed into the object. The value should be >= 0. A value of 0 disables parallel composite uploads, which are also disabled with client-side encryption and gzip compression.")
+
+	if err := flagSet.MarkHidden("write-parallel-composite-upload-threshold-mb"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 
+	if err := v.BindPFlag("file-system.admin-socket-path", flagSet.Lookup("admin-socket-path")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-auth.anonymous-access", flagSet.Lookup("anonymous-access")); err != nil {
 		return err
 	}
@@ -1464,6 +1628,30 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("chaos.error-rates", flagSet.Lookup("chaos-error-rates")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("chaos.read-stall-duration", flagSet.Lookup("chaos-read-stall-duration")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("chaos.read-stall-rate", flagSet.Lookup("chaos-read-stall-rate")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("chaos.seed", flagSet.Lookup("chaos-seed")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("chaos.truncated-read-rate", flagSet.Lookup("chaos-truncated-read-rate")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("chaos.write-per-mb-latency", flagSet.Lookup("chaos-write-per-mb-latency")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-retries.chunk-retry-deadline-secs", flagSet.Lookup("chunk-retry-deadline-secs")); err != nil {
 		return err
 	}
@@ -1476,6 +1664,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("encryption.client-side-key-file", flagSet.Lookup("client-side-encryption-key-file")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("metrics.cloud-metrics-export-interval-secs", flagSet.Lookup("cloud-metrics-export-interval-secs")); err != nil {
 		return err
 	}
@@ -1520,6 +1712,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("encryption.customer-supplied-key-file", flagSet.Lookup("customer-supplied-encryption-key-file")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("debug.fuse", flagSet.Lookup("debug_fuse")); err != nil {
 		return err
 	}
@@ -1564,6 +1760,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("chaos.enable", flagSet.Lookup("enable-chaos")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("cloud-profiler.enabled", flagSet.Lookup("enable-cloud-profiler")); err != nil {
 		return err
 	}
@@ -1584,6 +1784,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.enable-hard-links", flagSet.Lookup("enable-hard-links")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("enable-hns", flagSet.Lookup("enable-hns")); err != nil {
 		return err
 	}
@@ -1624,6 +1828,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.enable-stable-inode-ids", flagSet.Lookup("enable-stable-inode-ids")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("enable-standard-symlinks", flagSet.Lookup("enable-standard-symlinks")); err != nil {
 		return err
 	}
@@ -1640,6 +1848,14 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.enable-versions-dir", flagSet.Lookup("enable-versions-dir")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.enable-xattrs", flagSet.Lookup("enable-xattrs")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.experimental-enable-dentry-cache", flagSet.Lookup("experimental-enable-dentry-cache")); err != nil {
 		return err
 	}
@@ -1704,6 +1920,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-cache.eviction-policy", flagSet.Lookup("file-cache-eviction-policy")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-cache.exclude-regex", flagSet.Lookup("file-cache-exclude-regex")); err != nil {
 		return err
 	}
@@ -1736,10 +1956,26 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-cache.persist-index", flagSet.Lookup("file-cache-persist-index")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-cache.ram-tier-max-blocks", flagSet.Lookup("file-cache-ram-tier-max-blocks")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-cache.shared-cache-chunk-size-mb", flagSet.Lookup("file-cache-shared-cache-chunk-size-mb")); err != nil {
 		return err
 	}
 
+	if err := v.BindPFlag("file-cache.warm-up-manifest", flagSet.Lookup("file-cache-warm-up-manifest")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-cache.warm-up-parallelism", flagSet.Lookup("file-cache-warm-up-parallelism")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-cache.write-buffer-size", flagSet.Lookup("file-cache-write-buffer-size")); err != nil {
 		return err
 	}
@@ -1768,6 +2004,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("write.gzip-compression-globs", flagSet.Lookup("gzip-compression-globs")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-connection.http-client-timeout", flagSet.Lookup("http-client-timeout")); err != nil {
 		return err
 	}
@@ -1892,10 +2132,22 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("write.object-metadata-rules-file", flagSet.Lookup("object-metadata-rules-file")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("only-dir", flagSet.Lookup("only-dir")); err != nil {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.persist-posix-attributes", flagSet.Lookup("persist-posix-attributes")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("point-in-time", flagSet.Lookup("point-in-time")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("profile", flagSet.Lookup("profile")); err != nil {
 		return err
 	}
@@ -1904,6 +2156,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.quota-mb", flagSet.Lookup("quota-mb")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("read.block-size-mb", flagSet.Lookup("read-block-size-mb")); err != nil {
 		return err
 	}
@@ -2016,6 +2272,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.usage-refresh-interval", flagSet.Lookup("usage-refresh-interval")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("workload-insight.visualize", flagSet.Lookup("visualize-workload-insight")); err != nil {
 		return err
 	}
@@ -2044,5 +2304,17 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("write.out-of-order-window-blocks", flagSet.Lookup("write-out-of-order-window-blocks")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("write.parallel-composite-upload-parts", flagSet.Lookup("write-parallel-composite-upload-parts")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("write.parallel-composite-upload-threshold-mb", flagSet.Lookup("write-parallel-composite-upload-threshold-mb")); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -16,8 +16,10 @@ package cfg
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -103,3 +105,67 @@ func GetBucketType(hierarchical, zonal, pirlo bool) BucketType {
 	}
 	return BucketTypeFlat
 }
+
+// chaosMethods are the methods of gcs.Bucket that errors can be injected into.
+var chaosMethods = map[string]bool{
+	"NewReaderWithReadHandle":      true,
+	"NewMultiRangeDownloader":      true,
+	"CreateObject":                 true,
+	"CreateObjectChunkWriter":      true,
+	"CreateAppendableObjectWriter": true,
+	"FinalizeUpload":               true,
+	"FlushPendingWrites":           true,
+	"CopyObject":                   true,
+	"ComposeObjects":               true,
+	"StatObject":                   true,
+	"ListObjects":                  true,
+	"UpdateObject":                 true,
+	"DeleteObject":                 true,
+	"MoveObject":                   true,
+	"DeleteFolder":                 true,
+	"GetFolder":                    true,
+	"RenameFolder":                 true,
+	"CreateFolder":                 true,
+}
+
+// ChaosErrorRate is a rate of errors to inject into a method of gcs.Bucket, as
+// parsed from chaos:error-rates.
+type ChaosErrorRate struct {
+	// The name of the method, or "*" for all of them.
+	Method string
+	Code   int
+	Rate   float64
+}
+
+// ParseChaosErrorRate parses an error rate of the form
+// "<method>:<HTTP status>:<rate>".
+func ParseChaosErrorRate(spec string) (ChaosErrorRate, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 {
+		return ChaosErrorRate{}, fmt.Errorf("error rate %q isn't of the form <method>:<HTTP status>:<rate>", spec)
+	}
+
+	r := ChaosErrorRate{Method: parts[0]}
+	if r.Method != "*" && !chaosMethods[r.Method] {
+		return ChaosErrorRate{}, fmt.Errorf("error rate %q: unknown method %q", spec, r.Method)
+	}
+
+	var err error
+	r.Code, err = strconv.Atoi(parts[1])
+	switch {
+	case err != nil:
+		return ChaosErrorRate{}, fmt.Errorf("error rate %q: %w", spec, err)
+	case r.Code != http.StatusNotFound && r.Code != http.StatusPreconditionFailed &&
+		r.Code != http.StatusTooManyRequests && r.Code != http.StatusServiceUnavailable:
+		return ChaosErrorRate{}, fmt.Errorf("error rate %q: unsupported status %d, want 404, 412, 429 or 503", spec, r.Code)
+	}
+
+	r.Rate, err = strconv.ParseFloat(parts[2], 64)
+	switch {
+	case err != nil:
+		return ChaosErrorRate{}, fmt.Errorf("error rate %q: %w", spec, err)
+	case r.Rate < 0 || r.Rate > 1:
+		return ChaosErrorRate{}, fmt.Errorf("error rate %q: rate %v isn't between 0 and 1", spec, r.Rate)
+	}
+	return r, nil
+}
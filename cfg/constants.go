@@ -48,6 +48,17 @@ const (
 	ExperimentalMetadataPrefetchOnMountAsynchronous = "async"
 )
 
+const (
+	// FileCacheEvictionPolicyLRU evicts the least recently used file.
+	FileCacheEvictionPolicyLRU = "lru"
+	// FileCacheEvictionPolicy2Q evicts files in the order they were cached unless
+	// they are cached again soon after their eviction.
+	FileCacheEvictionPolicy2Q = "2q"
+	// FileCacheEvictionPolicyGDSF evicts the files with the lowest
+	// Greedy-Dual-Size-Frequency priority, i.e. large and rarely read ones.
+	FileCacheEvictionPolicyGDSF = "gdsf"
+)
+
 const (
 	// maxSequentialReadSizeMb is the max value supported by sequential-read-size-mb flag.
 	maxSequentialReadSizeMB = 1024
@@ -33,6 +33,8 @@ const (
 	ParallelDownloadsPerFileInvalidValueError = "the value of parallel-downloads-per-file for file-cache can't be less than 1"
 	DownloadChunkSizeMBInvalidValueError      = "the value of download-chunk-size-mb for file-cache can't be less than 1"
 	MaxParallelDownloadsCantBeZeroError       = "the value of max-parallel-downloads for file-cache must not be 0 when enable-parallel-downloads is true"
+	RAMTierMaxBlocksInvalidValueError         = "the value of ram-tier-max-blocks for file-cache can't be less than 0"
+	WarmUpParallelismInvalidValueError        = "the value of warm-up-parallelism for file-cache can't be less than 1"
 	ProfileAIMLTraining                       = "aiml-training"
 	ProfileAIMLServing                        = "aiml-serving"
 	ProfileAIMLCheckpointing                  = "aiml-checkpointing"
@@ -85,6 +87,14 @@ func isValidFileCacheConfig(config *FileCacheConfig) error {
 	if config.DownloadChunkSizeMb < 1 {
 		return errors.New(DownloadChunkSizeMBInvalidValueError)
 	}
+	if config.RamTierMaxBlocks < 0 {
+		return errors.New(RAMTierMaxBlocksInvalidValueError)
+	}
+	switch config.EvictionPolicy {
+	case "", FileCacheEvictionPolicyLRU, FileCacheEvictionPolicy2Q, FileCacheEvictionPolicyGDSF:
+	default:
+		return fmt.Errorf("unsupported eviction-policy for file-cache: %q", config.EvictionPolicy)
+	}
 	if _, err := regexp.Compile(config.ExcludeRegex); err != nil {
 		return fmt.Errorf("invalid regex value %q provided for exclude-regex", config.ExcludeRegex)
 	}
@@ -96,6 +106,22 @@ func isValidFileCacheConfig(config *FileCacheConfig) error {
 	return nil
 }
 
+func isValidFileCacheWarmUpConfig(config *Config) error {
+	if config.FileCache.WarmUpManifest == "" {
+		return nil
+	}
+	if !IsFileCacheEnabled(config) {
+		return errors.New("file cache should be enabled for warm-up-manifest")
+	}
+	if config.FileCache.EnableExperimentalSharedChunkCache {
+		return errors.New("warm-up-manifest isn't supported with the shared chunk cache")
+	}
+	if config.FileCache.WarmUpParallelism < 1 {
+		return errors.New(WarmUpParallelismInvalidValueError)
+	}
+	return nil
+}
+
 func IsValidExperimentalMetadataPrefetchOnMount(mode string) error {
 	switch mode {
 	case ExperimentalMetadataPrefetchOnMountDisabled,
@@ -199,6 +225,22 @@ func isValidWriteStreamingConfig(wc *WriteConfig) error {
 	if wc.GlobalMaxBlocks < -1 {
 		return fmt.Errorf("invalid value of write-global-max-blocks: %d; should be >=0 or -1 (for infinite)", wc.GlobalMaxBlocks)
 	}
+	if wc.OutOfOrderWindowBlocks < 0 || (wc.MaxBlocksPerFile != -1 && wc.OutOfOrderWindowBlocks >= wc.MaxBlocksPerFile) {
+		return fmt.Errorf("invalid value of write-out-of-order-window-blocks: %d; should be >=0 and less than write-max-blocks-per-file", wc.OutOfOrderWindowBlocks)
+	}
+	return nil
+}
+
+func isValidParallelCompositeUploadConfig(wc *WriteConfig) error {
+	if wc.ParallelCompositeUploadThresholdMb < 0 || wc.ParallelCompositeUploadThresholdMb > util.MaxMiBsInInt64 {
+		return fmt.Errorf("invalid value of write-parallel-composite-upload-threshold-mb: %d; should be >=0 and not more than %d", wc.ParallelCompositeUploadThresholdMb, util.MaxMiBsInInt64)
+	}
+	if wc.ParallelCompositeUploadThresholdMb == 0 {
+		return nil
+	}
+	if wc.ParallelCompositeUploadParts < 2 || wc.ParallelCompositeUploadParts > 32 {
+		return fmt.Errorf("invalid value of write-parallel-composite-upload-parts: %d; should be between 2 and 32", wc.ParallelCompositeUploadParts)
+	}
 	return nil
 }
 
@@ -358,6 +400,10 @@ func ValidateConfig(v *viper.Viper, config *Config) error {
 		return fmt.Errorf("error parsing write config: %w", err)
 	}
 
+	if err = isValidParallelCompositeUploadConfig(&config.Write); err != nil {
+		return fmt.Errorf("error parsing write config: %w", err)
+	}
+
 	if err = isValidReadStallGcsRetriesConfig(&config.GcsRetries.ReadStall); err != nil {
 		return fmt.Errorf("error parsing read-stall-gcs-retries config: %w", err)
 	}
@@ -400,6 +446,10 @@ func ValidateConfig(v *viper.Viper, config *Config) error {
 		return fmt.Errorf("error parsing parallel download config: %w", err)
 	}
 
+	if err = isValidFileCacheWarmUpConfig(config); err != nil {
+		return fmt.Errorf("error parsing file cache warm-up config: %w", err)
+	}
+
 	if err = isValidBufferedReadConfig(&config.Read); err != nil {
 		return fmt.Errorf("error parsing buffered read config: %w", err)
 	}
@@ -412,6 +462,63 @@ func ValidateConfig(v *viper.Viper, config *Config) error {
 		return fmt.Errorf("error parsing optimize profile config: %w", err)
 	}
 
+	if err = isValidQuotaConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing quota config: %w", err)
+	}
+
+	if err = isValidPointInTime(config.PointInTime); err != nil {
+		return fmt.Errorf("error parsing point-in-time config: %w", err)
+	}
+
+	if err = isValidChaosConfig(&config.Chaos); err != nil {
+		return fmt.Errorf("error parsing chaos config: %w", err)
+	}
+
+	return nil
+}
+
+func isValidQuotaConfig(fsc *FileSystemConfig) error {
+	if fsc.QuotaMb < 0 || fsc.QuotaMb > util.MaxMiBsInInt64 {
+		return fmt.Errorf("invalid value of quota-mb: %d; should be >=0 and not more than %d", fsc.QuotaMb, util.MaxMiBsInInt64)
+	}
+	if fsc.UsageRefreshInterval < 0 {
+		return fmt.Errorf("invalid value of usage-refresh-interval: %v; can't be negative", fsc.UsageRefreshInterval)
+	}
+	return nil
+}
+
+func isValidPointInTime(pointInTime string) error {
+	if pointInTime == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, pointInTime)
+	if err != nil {
+		return fmt.Errorf("point-in-time must be an RFC3339 time: %w", err)
+	}
+	if t.After(time.Now()) {
+		return fmt.Errorf("point-in-time %q is in the future", pointInTime)
+	}
+	return nil
+}
+
+func isValidChaosConfig(c *ChaosConfig) error {
+	if c.ReadStallRate < 0 || c.ReadStallRate > 1 {
+		return fmt.Errorf("invalid value of read-stall-rate: %v; should be between 0 and 1", c.ReadStallRate)
+	}
+	if c.TruncatedReadRate < 0 || c.TruncatedReadRate > 1 {
+		return fmt.Errorf("invalid value of truncated-read-rate: %v; should be between 0 and 1", c.TruncatedReadRate)
+	}
+	if c.ReadStallDuration < 0 {
+		return fmt.Errorf("invalid value of read-stall-duration: %v; can't be negative", c.ReadStallDuration)
+	}
+	if c.WritePerMbLatency < 0 {
+		return fmt.Errorf("invalid value of write-per-mb-latency: %v; can't be negative", c.WritePerMbLatency)
+	}
+	for _, spec := range c.ErrorRates {
+		if _, err := ParseChaosErrorRate(spec); err != nil {
+			return fmt.Errorf("invalid value of error-rates: %w", err)
+		}
+	}
 	return nil
 }
 
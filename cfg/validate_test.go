@@ -397,6 +397,32 @@ func TestValidateConfigSuccessful(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Valid file cache warm-up config.",
+			config: &Config{
+				Logging:  LoggingConfig{LogRotate: validLogRotateConfig()},
+				CacheDir: "/tmp/cache",
+				FileCache: func() FileCacheConfig {
+					cfg := validFileCacheConfig(t)
+					cfg.WarmUpManifest = "/tmp/manifest"
+					cfg.WarmUpParallelism = 16
+					return cfg
+				}(),
+				GcsConnection: GcsConnectionConfig{
+					SequentialReadSizeMb: 200,
+				},
+				MetadataCache: MetadataCacheConfig{
+					ExperimentalMetadataPrefetchOnMount: "disabled",
+				},
+				Metrics: MetricsConfig{
+					Workers:    3,
+					BufferSize: 256,
+				},
+				Mrd: MrdConfig{
+					PoolSize: 4,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -602,6 +628,52 @@ func TestValidateConfig_ErrorScenarios(t *testing.T) {
 				FileCache: validFileCacheConfigWithIncludeRegex(t, "["),
 			},
 		},
+		{
+			name: "file_cache_ram_tier_max_blocks_negative",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					cfg := validFileCacheConfig(t)
+					cfg.RamTierMaxBlocks = -1
+					return cfg
+				}(),
+			},
+		},
+		{
+			name: "file_cache_unsupported_eviction_policy",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					cfg := validFileCacheConfig(t)
+					cfg.EvictionPolicy = "mru"
+					return cfg
+				}(),
+			},
+		},
+		{
+			name: "file_cache_warm_up_manifest_without_file_cache",
+			config: &Config{
+				Logging: LoggingConfig{LogRotate: validLogRotateConfig()},
+				FileCache: func() FileCacheConfig {
+					cfg := validFileCacheConfig(t)
+					cfg.WarmUpManifest = "/tmp/manifest"
+					cfg.WarmUpParallelism = 16
+					return cfg
+				}(),
+			},
+		},
+		{
+			name: "file_cache_warm_up_parallelism_zero",
+			config: &Config{
+				Logging:  LoggingConfig{LogRotate: validLogRotateConfig()},
+				CacheDir: "/tmp/cache",
+				FileCache: func() FileCacheConfig {
+					cfg := validFileCacheConfig(t)
+					cfg.WarmUpManifest = "/tmp/manifest"
+					return cfg
+				}(),
+			},
+		},
 		{
 			name: "chunk_retry_deadline_secs_in_negative",
 			config: &Config{
@@ -778,6 +850,20 @@ func Test_isValidWriteStreamingConfig_ErrorScenarios(t *testing.T) {
 			GlobalMaxBlocks:       20,
 			MaxBlocksPerFile:      0,
 		}},
+		{"negative_out_of_order_window_blocks", WriteConfig{
+			BlockSizeMb:            10,
+			EnableStreamingWrites:  true,
+			GlobalMaxBlocks:        20,
+			MaxBlocksPerFile:       -1,
+			OutOfOrderWindowBlocks: -1,
+		}},
+		{"out_of_order_window_blocks_equal_to_max_blocks_per_file", WriteConfig{
+			BlockSizeMb:            10,
+			EnableStreamingWrites:  true,
+			GlobalMaxBlocks:        20,
+			MaxBlocksPerFile:       4,
+			OutOfOrderWindowBlocks: 4,
+		}},
 	}
 
 	for _, tc := range testCases {
@@ -787,6 +873,33 @@ func Test_isValidWriteStreamingConfig_ErrorScenarios(t *testing.T) {
 	}
 }
 
+func Test_isValidParallelCompositeUploadConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		writeConfig WriteConfig
+		wantErr     bool
+	}{
+		{"disabled", WriteConfig{ParallelCompositeUploadThresholdMb: 0, ParallelCompositeUploadParts: 0}, false},
+		{"enabled", WriteConfig{ParallelCompositeUploadThresholdMb: 1024, ParallelCompositeUploadParts: 8}, false},
+		{"max_parts", WriteConfig{ParallelCompositeUploadThresholdMb: 1024, ParallelCompositeUploadParts: 32}, false},
+		{"negative_threshold", WriteConfig{ParallelCompositeUploadThresholdMb: -1, ParallelCompositeUploadParts: 8}, true},
+		{"too_few_parts", WriteConfig{ParallelCompositeUploadThresholdMb: 1024, ParallelCompositeUploadParts: 1}, true},
+		{"too_many_parts", WriteConfig{ParallelCompositeUploadThresholdMb: 1024, ParallelCompositeUploadParts: 33}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidParallelCompositeUploadConfig(&tc.writeConfig)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func Test_isValidBufferedReadConfig_ErrorScenarios(t *testing.T) {
 	var testCases = []struct {
 		testName string
@@ -997,6 +1110,20 @@ func Test_isValidWriteStreamingConfig_SuccessScenarios(t *testing.T) {
 			GlobalMaxBlocks:       20,
 			MaxBlocksPerFile:      1,
 		}},
+		{"out_of_order_window_blocks", WriteConfig{
+			BlockSizeMb:            10,
+			EnableStreamingWrites:  true,
+			GlobalMaxBlocks:        20,
+			MaxBlocksPerFile:       4,
+			OutOfOrderWindowBlocks: 3,
+		}},
+		{"out_of_order_window_blocks_with_infinite_blocks_per_file", WriteConfig{
+			BlockSizeMb:            10,
+			EnableStreamingWrites:  true,
+			GlobalMaxBlocks:        20,
+			MaxBlocksPerFile:       -1,
+			OutOfOrderWindowBlocks: 8,
+		}},
 	}
 
 	for _, tc := range testCases {
@@ -1465,3 +1592,87 @@ func Test_isValidMaxRetrySleep_ErrorScenarios(t *testing.T) {
 		})
 	}
 }
+
+func Test_isValidQuotaConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		quotaMb  int64
+		interval time.Duration
+		wantErr  bool
+	}{
+		{"unset", 0, 0, false},
+		{"quota_with_refresh", 1024, time.Hour, false},
+		{"negative_quota", -1, 0, true},
+		{"quota_too_high", util.MaxMiBsInInt64 + 1, 0, true},
+		{"negative_interval", 1024, -time.Second, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidQuotaConfig(&FileSystemConfig{QuotaMb: tc.quotaMb, UsageRefreshInterval: tc.interval})
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidPointInTime(t *testing.T) {
+	testCases := []struct {
+		name        string
+		pointInTime string
+		wantErr     bool
+	}{
+		{"unset", "", false},
+		{"utc", "2024-01-02T15:04:05Z", false},
+		{"offset", "2024-01-02T15:04:05+05:30", false},
+		{"date_only", "2024-01-02", true},
+		{"future", time.Now().Add(time.Hour).Format(time.RFC3339), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidPointInTime(tc.pointInTime)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidChaosConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  ChaosConfig
+		wantErr bool
+	}{
+		{"unset", ChaosConfig{}, false},
+		{"valid", ChaosConfig{ReadStallRate: 0.1, TruncatedReadRate: 1, ReadStallDuration: time.Second, WritePerMbLatency: time.Millisecond}, false},
+		{"negative_read_stall_rate", ChaosConfig{ReadStallRate: -0.1}, true},
+		{"truncated_read_rate_above_one", ChaosConfig{TruncatedReadRate: 1.5}, true},
+		{"negative_read_stall_duration", ChaosConfig{ReadStallDuration: -time.Second}, true},
+		{"negative_write_latency", ChaosConfig{WritePerMbLatency: -time.Second}, true},
+		{"valid_error_rates", ChaosConfig{ErrorRates: []string{"*:503:0.01", "StatObject:404:1"}}, false},
+		{"error_rate_of_unknown_method", ChaosConfig{ErrorRates: []string{"StatObjects:503:0.1"}}, true},
+		{"error_rate_of_unsupported_status", ChaosConfig{ErrorRates: []string{"StatObject:500:0.1"}}, true},
+		{"malformed_error_rate", ChaosConfig{ErrorRates: []string{"StatObject:503"}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidChaosConfig(&tc.config)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -58,12 +58,14 @@ func defaultFileCacheConfig(t *testing.T) cfg.FileCacheConfig {
 		DownloadChunkSizeMb:                    200,
 		EnableCrc:                              false,
 		EnableParallelDownloads:                false,
+		EvictionPolicy:                         cfg.FileCacheEvictionPolicyLRU,
 		ExperimentalParallelDownloadsDefaultOn: true,
 		MaxParallelDownloads:                   int64(max(16, 2*runtime.NumCPU())),
 		MaxSizeMb:                              -1,
 		ParallelDownloadsPerFile:               16,
 		SharedCacheChunkSizeMb:                 8,
 		WriteBufferSize:                        4 * 1024 * 1024,
+		WarmUpParallelism:                      16,
 		EnableODirect:                          false,
 		ExperimentalDisableSizeCalculationFix:  false,
 	}
@@ -212,12 +214,14 @@ func TestValidateConfigFile_WriteConfig(t *testing.T) {
 			configFile: "testdata/empty_file.yaml",
 			expectedConfig: &cfg.Config{
 				Write: cfg.WriteConfig{
-					CreateEmptyFile:       false,
-					BlockSizeMb:           32,
-					EnableStreamingWrites: true,
-					GlobalMaxBlocks:       4,
-					MaxBlocksPerFile:      1,
-					EnableRapidAppends:    true,
+					CreateEmptyFile:              false,
+					BlockSizeMb:                  32,
+					EnableStreamingWrites:        true,
+					GlobalMaxBlocks:              4,
+					GzipCompressionGlobs:         []string{},
+					MaxBlocksPerFile:             1,
+					EnableRapidAppends:           true,
+					ParallelCompositeUploadParts: 8,
 				},
 			},
 		},
@@ -226,11 +230,13 @@ func TestValidateConfigFile_WriteConfig(t *testing.T) {
 			configFile: "testdata/valid_config.yaml",
 			expectedConfig: &cfg.Config{
 				Write: cfg.WriteConfig{
-					CreateEmptyFile:       false, // changed due to enabled streaming writes.
-					BlockSizeMb:           10,
-					EnableStreamingWrites: true,
-					GlobalMaxBlocks:       20,
-					MaxBlocksPerFile:      2,
+					CreateEmptyFile:              false, // changed due to enabled streaming writes.
+					BlockSizeMb:                  10,
+					EnableStreamingWrites:        true,
+					GlobalMaxBlocks:              20,
+					GzipCompressionGlobs:         []string{},
+					MaxBlocksPerFile:             2,
+					ParallelCompositeUploadParts: 8,
 				},
 			},
 		},
@@ -432,11 +438,13 @@ func TestValidateConfigFile_FileCacheConfigSuccessful(t *testing.T) {
 					DownloadChunkSizeMb:                    300,
 					EnableCrc:                              true,
 					EnableParallelDownloads:                false,
+					EvictionPolicy:                         cfg.FileCacheEvictionPolicyLRU,
 					MaxParallelDownloads:                   200,
 					MaxSizeMb:                              40,
 					ParallelDownloadsPerFile:               10,
 					SharedCacheChunkSizeMb:                 8,
 					WriteBufferSize:                        8192,
+					WarmUpParallelism:                      16,
 					EnableODirect:                          true,
 					ExperimentalParallelDownloadsDefaultOn: true,
 					ExperimentalDisableSizeCalculationFix:  true,
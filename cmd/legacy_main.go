@@ -36,7 +36,9 @@ import (
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/common"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/admin"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/canned"
+	gcsfusefs "github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/kernelparams"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
@@ -154,6 +156,7 @@ func createStorageHandle(newConfig *cfg.Config, userAgent string, metricHandle m
 		UserAgent:                               userAgent,
 		CustomEndpoint:                          newConfig.GcsConnection.CustomEndpoint,
 		KeyFile:                                 string(newConfig.GcsAuth.KeyFile),
+		CustomerSuppliedEncryptionKeyFile:       string(newConfig.Encryption.CustomerSuppliedKeyFile),
 		AnonymousAccess:                         newConfig.GcsAuth.AnonymousAccess,
 		TokenUrl:                                newConfig.GcsAuth.TokenUrl,
 		ReuseTokenFromUrl:                       newConfig.GcsAuth.ReuseTokenFromUrl,
@@ -183,7 +186,7 @@ func createStorageHandle(newConfig *cfg.Config, userAgent string, metricHandle m
 ////////////////////////////////////////////////////////////////////////
 
 // Mount the file system according to arguments in the supplied context.
-func mountWithArgs(bucketName string, mountPoint string, newConfig *cfg.Config, metricHandle metrics.MetricHandle, traceHandle tracing.TraceHandle, viperConfig *viper.Viper) (mfs *fuse.MountedFileSystem, err error) {
+func mountWithArgs(bucketName string, mountPoint string, newConfig *cfg.Config, metricHandle metrics.MetricHandle, traceHandle tracing.TraceHandle, viperConfig *viper.Viper, reloader *configReloader, inspector *gcsfusefs.Inspector) (mfs *fuse.MountedFileSystem, err error) {
 	// Enable invariant checking if requested.
 	if newConfig.Debug.ExitOnInvariantViolation {
 		locker.EnableInvariantsCheck()
@@ -196,10 +199,11 @@ func mountWithArgs(bucketName string, mountPoint string, newConfig *cfg.Config,
 
 	// Grab the connection.
 	//
-	// Special case: if we're mounting the fake bucket, we don't need an actual
-	// connection.
+	// Special case: if we're mounting the fake bucket or local buckets, we don't
+	// need an actual connection.
 	var storageHandle storage.StorageHandle
-	if bucketName != canned.FakeBucketName {
+	_, isLocal := storageutil.LocalBucketRoot(newConfig.GcsConnection.CustomEndpoint)
+	if bucketName != canned.FakeBucketName && !isLocal {
 		userAgent := getUserAgent(newConfig.AppName, getConfigForUserAgent(newConfig), logger.MountInstanceID(fsName(bucketName)))
 		logger.Info("Creating Storage handle...")
 		storageHandle, err = createStorageHandle(newConfig, userAgent, metricHandle, isGKE)
@@ -219,7 +223,9 @@ func mountWithArgs(bucketName string, mountPoint string, newConfig *cfg.Config,
 		storageHandle,
 		metricHandle,
 		traceHandle,
-		viperConfig)
+		viperConfig,
+		reloader,
+		inspector)
 
 	if err != nil {
 		err = fmt.Errorf("mountWithStorageHandle: %w", err)
@@ -485,9 +491,11 @@ func Mount(mountInfo *mountInfo, bucketName, mountPoint string) (err error) {
 	// Mount, writing information about our progress to the writer that package
 	// daemonize gives us and telling it about the outcome.
 	var mfs *fuse.MountedFileSystem
+	reloader := newConfigReloader(mountInfo.viperConfig, newConfig)
+	inspector := gcsfusefs.NewInspector()
 	{
 		startTime := time.Now()
-		mfs, err = mountWithArgs(bucketName, mountPoint, newConfig, metricHandle, traceHandle, mountInfo.viperConfig)
+		mfs, err = mountWithArgs(bucketName, mountPoint, newConfig, metricHandle, traceHandle, mountInfo.viperConfig, reloader, inspector)
 
 		// This utility is to absorb the error
 		// returned by daemonize.SignalOutcome calls by simply
@@ -551,6 +559,18 @@ func Mount(mountInfo *mountInfo, bucketName, mountPoint string) (err error) {
 	// Let the user unmount with Ctrl-C (SIGINT).
 	registerTerminatingSignalHandler(mfs.Dir())
 
+	// Let the user reload the config with SIGHUP.
+	registerReloadSignalHandler(reloader)
+
+	if newConfig.FileSystem.AdminSocketPath != "" {
+		adminShutdownFn, err := admin.Serve(string(newConfig.FileSystem.AdminSocketPath), reloader.currentConfig, inspector)
+		if err != nil {
+			logger.Errorf("Failed to start the admin API: %v", err)
+		} else {
+			shutdownFn = common.JoinShutdownFunc(adminShutdownFn, shutdownFn)
+		}
+	}
+
 	// Wait for the file system to be unmounted.
 	if err = mfs.Join(ctx); err != nil {
 		err = fmt.Errorf("MountedFileSystem.Join: %w", err)
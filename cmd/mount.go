@@ -22,6 +22,8 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/mount"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/util"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
 	"github.com/spf13/viper"
@@ -46,7 +48,9 @@ func mountWithStorageHandle(
 	storageHandle storage.StorageHandle,
 	metricHandle metrics.MetricHandle,
 	traceHandle tracing.TraceHandle,
-	viperConfig *viper.Viper) (mfs *fuse.MountedFileSystem, err error) {
+	viperConfig *viper.Viper,
+	reloader *configReloader,
+	inspector *fs.Inspector) (mfs *fuse.MountedFileSystem, err error) {
 
 	// Sanity check: make sure the temporary directory exists and is writable
 	// currently. This gives a better user experience than harder to debug EIO
@@ -91,36 +95,20 @@ be interacting with the file system.`)
 		gid = uint32(newConfig.FileSystem.Gid)
 	}
 
-	bucketCfg := gcsx.BucketConfig{
-		BillingProject:                     newConfig.GcsConnection.BillingProject,
-		OnlyDir:                            newConfig.OnlyDir,
-		EgressBandwidthLimitBytesPerSecond: newConfig.GcsConnection.LimitBytesPerSec,
-		OpRateLimitHz:                      newConfig.GcsConnection.LimitOpsPerSec,
-		StatCacheMaxSizeMB:                 uint64(newConfig.MetadataCache.StatCacheMaxSizeMb),
-		StatCacheTTL:                       time.Duration(newConfig.MetadataCache.TtlSecs) * time.Second,
-		NegativeStatCacheTTL:               time.Duration(newConfig.MetadataCache.NegativeTtlSecs) * time.Second,
-		EnableMonitoring:                   cfg.IsMetricsEnabled(&newConfig.Metrics),
-		LogSeverity:                        newConfig.Logging.Severity,
-		AppendThreshold:                    1 << 21, // 2 MiB, a total guess.
-		ChunkRetryDeadlineSecs:             newConfig.GcsRetries.ChunkRetryDeadlineSecs,
-		ChunkTransferTimeoutSecs:           newConfig.GcsRetries.ChunkTransferTimeoutSecs,
-		TmpObjectPrefix:                    ".gcsfuse_tmp/",
-		DummyIOCfg:                         newConfig.DummyIo,
-		IsTypeCacheDeprecated:              newConfig.EnableTypeCacheDeprecation,
-		ImplicitDir:                        newConfig.ImplicitDirs,
-	}
-	bm := gcsx.NewBucketManager(bucketCfg, storageHandle)
+	bm := gcsx.NewBucketManager(newBucketConfig(newConfig), storageHandle)
+	reloader.bucketManager = bm
 
 	// Create a file system server.
 	serverCfg := &fs.ServerConfig{
 		CacheClock:                 timeutil.RealClock(),
 		BucketManager:              bm,
 		BucketName:                 bucketName,
+		MountPoint:                 mountPoint,
 		LocalFileCache:             false,
 		TempDir:                    string(newConfig.FileSystem.TempDir),
 		ImplicitDirectories:        newConfig.ImplicitDirs,
-		InodeAttributeCacheTTL:     time.Duration(newConfig.MetadataCache.TtlSecs) * time.Second,
-		DirTypeCacheTTL:            time.Duration(newConfig.MetadataCache.TtlSecs) * time.Second,
+		InodeAttributeCacheTTL:     metadataCacheTTL(newConfig),
+		DirTypeCacheTTL:            metadataCacheTTL(newConfig),
 		Uid:                        uid,
 		Gid:                        gid,
 		FilePerms:                  os.FileMode(newConfig.FileSystem.FileMode),
@@ -132,8 +120,11 @@ be interacting with the file system.`)
 		ViperConfig:                viperConfig,
 		MetricHandle:               metricHandle,
 		TraceHandle:                traceHandle,
+		ConfigReloader:             reloader.fsReloader,
+		Inspector:                  inspector,
 	}
-	if serverCfg.NewConfig.FileSystem.ExperimentalEnableDentryCache {
+	// The admin socket can ask the kernel to invalidate its caches.
+	if serverCfg.NewConfig.FileSystem.ExperimentalEnableDentryCache || serverCfg.NewConfig.FileSystem.AdminSocketPath != "" {
 		serverCfg.Notifier = fuse.NewNotifier()
 	}
 
@@ -159,12 +150,53 @@ be interacting with the file system.`)
 	return
 }
 
+func newBucketConfig(newConfig *cfg.Config) gcsx.BucketConfig {
+	localBucketRoot, _ := storageutil.LocalBucketRoot(newConfig.GcsConnection.CustomEndpoint)
+	// Validated along with the config, empty otherwise.
+	pointInTime, _ := time.Parse(time.RFC3339, newConfig.PointInTime)
+	return gcsx.BucketConfig{
+		BillingProject:                     newConfig.GcsConnection.BillingProject,
+		OnlyDir:                            newConfig.OnlyDir,
+		EgressBandwidthLimitBytesPerSecond: newConfig.GcsConnection.LimitBytesPerSec,
+		OpRateLimitHz:                      newConfig.GcsConnection.LimitOpsPerSec,
+		StatCacheMaxSizeMB:                 uint64(newConfig.MetadataCache.StatCacheMaxSizeMb),
+		StatCacheTTL:                       metadataCacheTTL(newConfig),
+		NegativeStatCacheTTL:               time.Duration(newConfig.MetadataCache.NegativeTtlSecs) * time.Second,
+		EnableMonitoring:                   cfg.IsMetricsEnabled(&newConfig.Metrics),
+		LogSeverity:                        newConfig.Logging.Severity,
+		AppendThreshold:                    1 << 21, // 2 MiB, a total guess.
+		ChunkRetryDeadlineSecs:             newConfig.GcsRetries.ChunkRetryDeadlineSecs,
+		ChunkTransferTimeoutSecs:           newConfig.GcsRetries.ChunkTransferTimeoutSecs,
+		TmpObjectPrefix:                    ".gcsfuse_tmp/",
+		ParallelCompositeUploadThreshold:   int64(util.MiBsToBytes(uint64(newConfig.Write.ParallelCompositeUploadThresholdMb))),
+		ParallelCompositeUploadParts:       int(newConfig.Write.ParallelCompositeUploadParts),
+		DummyIOCfg:                         newConfig.DummyIo,
+		ChaosCfg:                           newConfig.Chaos,
+		LocalBucketRoot:                    localBucketRoot,
+		PointInTime:                        pointInTime,
+		ClientSideEncryptionKeyFile:        string(newConfig.Encryption.ClientSideKeyFile),
+		GzipCompressionGlobs:               newConfig.Write.GzipCompressionGlobs,
+		ObjectMetadataRulesFile:            string(newConfig.Write.ObjectMetadataRulesFile),
+		IsTypeCacheDeprecated:              newConfig.EnableTypeCacheDeprecation,
+		ImplicitDir:                        newConfig.ImplicitDirs,
+	}
+}
+
+// metadataCacheTTL is the TTL of the stat, type and kernel attribute caches.
+func metadataCacheTTL(newConfig *cfg.Config) time.Duration {
+	return time.Duration(newConfig.MetadataCache.TtlSecs) * time.Second
+}
+
 func getFuseMountConfig(fsName string, newConfig *cfg.Config) *fuse.MountConfig {
 	// Handle the repeated "-o" flag.
 	parsedOptions := make(map[string]string)
 	for _, o := range newConfig.FileSystem.FuseOptions {
 		mount.ParseOptions(parsedOptions, o)
 	}
+	// Point-in-time mounts are read-only.
+	if newConfig.PointInTime != "" {
+		parsedOptions["ro"] = ""
+	}
 
 	mountCfg := &fuse.MountConfig{
 		FSName:     fsName,
@@ -16,6 +16,7 @@ package cmd
 
 import (
 	"testing"
+	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/stretchr/testify/assert"
@@ -148,3 +149,53 @@ func TestGetFuseMountConfig_EnableReaddirplus(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFuseMountConfig_PointInTimeIsReadOnly(t *testing.T) {
+	newConfig := &cfg.Config{PointInTime: "2024-01-02T15:04:05Z"}
+
+	fuseMountCfg := getFuseMountConfig("mybucket", newConfig)
+
+	assert.Contains(t, fuseMountCfg.Options, "ro")
+}
+
+func TestNewBucketConfig_PointInTime(t *testing.T) {
+	newConfig := &cfg.Config{PointInTime: "2024-01-02T15:04:05Z"}
+
+	bucketCfg := newBucketConfig(newConfig)
+
+	assert.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), bucketCfg.PointInTime.UTC())
+	assert.True(t, newBucketConfig(&cfg.Config{}).PointInTime.IsZero())
+}
+
+func TestNewBucketConfig_ClientSideEncryptionKeyFile(t *testing.T) {
+	newConfig := &cfg.Config{Encryption: cfg.EncryptionConfig{ClientSideKeyFile: "/path/to/keys.json"}}
+
+	bucketCfg := newBucketConfig(newConfig)
+
+	assert.Equal(t, "/path/to/keys.json", bucketCfg.ClientSideEncryptionKeyFile)
+}
+
+func TestNewBucketConfig_Chaos(t *testing.T) {
+	chaos := cfg.ChaosConfig{Enable: true, Seed: 42, ErrorRates: []string{"*:503:0.01"}, TruncatedReadRate: 0.1}
+	newConfig := &cfg.Config{Chaos: chaos}
+
+	bucketCfg := newBucketConfig(newConfig)
+
+	assert.Equal(t, chaos, bucketCfg.ChaosCfg)
+}
+
+func TestNewBucketConfig_GzipCompressionGlobs(t *testing.T) {
+	newConfig := &cfg.Config{Write: cfg.WriteConfig{GzipCompressionGlobs: []string{"logs/**", "*.csv"}}}
+
+	bucketCfg := newBucketConfig(newConfig)
+
+	assert.Equal(t, []string{"logs/**", "*.csv"}, bucketCfg.GzipCompressionGlobs)
+}
+
+func TestNewBucketConfig_ObjectMetadataRulesFile(t *testing.T) {
+	newConfig := &cfg.Config{Write: cfg.WriteConfig{ObjectMetadataRulesFile: "/path/to/rules.yaml"}}
+
+	bucketCfg := newBucketConfig(newConfig)
+
+	assert.Equal(t, "/path/to/rules.yaml", bucketCfg.ObjectMetadataRulesFile)
+}
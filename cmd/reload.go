@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/monitor"
+	"github.com/spf13/viper"
+	"golang.org/x/sys/unix"
+)
+
+// configReloader re-reads the config file of a mounted file system and applies
+// the settings that can safely be changed while mounted:
+//
+//   - the log severity,
+//   - the TTLs of the stat, type, kernel attribute and kernel list caches,
+//   - the operation and egress bandwidth rate limits,
+//   - the file cache include and exclude regexes, and
+//   - the cloud metrics export interval.
+//
+// Other changed settings only take effect after remounting. A config enabling
+// or disabling the stat cache or rate limiting is rejected.
+type configReloader struct {
+	viperConfig *viper.Viper
+	fsReloader  *fs.ConfigReloader
+
+	// Set when the file system is mounted.
+	bucketManager gcsx.BucketManager
+
+	mu sync.Mutex
+	// The config last applied successfully.
+	//
+	// GUARDED_BY(mu)
+	config *cfg.Config
+}
+
+func newConfigReloader(viperConfig *viper.Viper, config *cfg.Config) *configReloader {
+	return &configReloader{
+		viperConfig: viperConfig,
+		fsReloader:  fs.NewConfigReloader(),
+		config:      config,
+	}
+}
+
+// currentConfig returns the config the mount was started with, or the one last
+// reloaded successfully.
+func (r *configReloader) currentConfig() *cfg.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.config
+}
+
+// reload re-reads the config file, with the flags passed on the command line
+// still taking precedence, and applies it. An invalid config is rejected
+// without changing any setting.
+//
+// Unlike when mounting, the optimizations for the type of the bucket aren't
+// applied.
+func (r *configReloader) reload() error {
+	if r.viperConfig.ConfigFileUsed() == "" {
+		return errors.New("no config file was specified when mounting")
+	}
+	if err := r.viperConfig.ReadInConfig(); err != nil {
+		return fmt.Errorf("error while reading the config: %w", err)
+	}
+	newConfig := &cfg.Config{}
+	if _, err := loadConfig(r.viperConfig, newConfig); err != nil {
+		return err
+	}
+
+	// The bucket manager validates its settings before applying them, so update
+	// it first to reject the config as a whole.
+	if r.bucketManager != nil {
+		if err := r.bucketManager.UpdateConfig(newBucketConfig(newConfig)); err != nil {
+			return err
+		}
+	}
+	logger.SetLogSeverity(newConfig.Logging.Severity)
+	if err := monitor.SetCloudMetricsExportInterval(newConfig.Metrics.CloudMetricsExportIntervalSecs); err != nil {
+		logger.Warnf("Not updating the cloud metrics export interval: %v", err)
+	}
+	r.fsReloader.Reload(newConfig, metadataCacheTTL(newConfig), metadataCacheTTL(newConfig))
+
+	r.mu.Lock()
+	r.config = newConfig
+	r.mu.Unlock()
+	return nil
+}
+
+func registerReloadSignalHandler(r *configReloader) {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, unix.SIGHUP)
+
+	go func() {
+		for range signalChan {
+			logger.Infof("Received SIGHUP, reloading the config file %q...", r.viperConfig.ConfigFileUsed())
+			if err := r.reload(); err != nil {
+				logger.Errorf("Failed to reload the config, keeping the current one: %v", err)
+				continue
+			}
+			logger.Infof("Successfully reloaded the config.")
+		}
+	}()
+}
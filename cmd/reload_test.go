@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBucketManager struct {
+	gcsx.BucketManager
+	config *gcsx.BucketConfig
+	// Returned by UpdateConfig, which then ignores the config.
+	updateErr error
+}
+
+func (bm *fakeBucketManager) SetUpBucket(context.Context, string, bool, metrics.MetricHandle) (gcsx.SyncerBucket, error) {
+	return gcsx.SyncerBucket{}, nil
+}
+
+func (bm *fakeBucketManager) UpdateConfig(config gcsx.BucketConfig) error {
+	if bm.updateErr != nil {
+		return bm.updateErr
+	}
+	bm.config = &config
+	return nil
+}
+
+// mountedReloader parses the supplied args as when mounting, and returns a
+// reloader for the result.
+func mountedReloader(t *testing.T, args ...string) (*configReloader, *fakeBucketManager) {
+	t.Helper()
+	var v *viper.Viper
+	var c *cfg.Config
+	cmd, err := newRootCmd(func(mountInfo *mountInfo, _, _ string) error {
+		v = mountInfo.viperConfig
+		c = mountInfo.config
+		return nil
+	})
+	require.NoError(t, err)
+	cmd.SetArgs(convertToPosixArgs(append(append([]string{"gcsfuse"}, args...), "abc", "pqr"), cmd))
+	require.NoError(t, cmd.Execute())
+
+	bm := &fakeBucketManager{}
+	r := newConfigReloader(v, c)
+	r.bucketManager = bm
+	return r, bm
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	configFile := createTempConfigFile(t, "metadata-cache:\n  ttl-secs: 10\n")
+	r, bm := mountedReloader(t, "--config-file", configFile, "--limit-ops-per-sec=5")
+	require.NoError(t, os.WriteFile(configFile, []byte("metadata-cache:\n  ttl-secs: 20\ngcs-connection:\n  limit-ops-per-sec: 7\n"), 0644))
+
+	err := r.reload()
+
+	require.NoError(t, err)
+	require.NotNil(t, bm.config)
+	assert.Equal(t, 20*time.Second, bm.config.StatCacheTTL)
+	// Flags passed on the command line take precedence.
+	assert.Equal(t, float64(5), bm.config.OpRateLimitHz)
+	assert.Equal(t, int64(20), r.currentConfig().MetadataCache.TtlSecs)
+}
+
+func TestConfigReloader_ReloadInvalidConfig(t *testing.T) {
+	configFile := createTempConfigFile(t, "metadata-cache:\n  ttl-secs: 10\n")
+	r, bm := mountedReloader(t, "--config-file", configFile)
+	require.NoError(t, os.WriteFile(configFile, []byte("metadata-cache:\n  ttl-secs: -5\n"), 0644))
+
+	err := r.reload()
+
+	assert.Error(t, err)
+	assert.Nil(t, bm.config)
+	assert.Equal(t, int64(10), r.currentConfig().MetadataCache.TtlSecs)
+}
+
+func TestConfigReloader_ReloadRejectedByBucketManager(t *testing.T) {
+	configFile := createTempConfigFile(t, "metadata-cache:\n  ttl-secs: 10\n")
+	r, bm := mountedReloader(t, "--config-file", configFile)
+	bm.updateErr = errors.New("rejected")
+	require.NoError(t, os.WriteFile(configFile, []byte("metadata-cache:\n  ttl-secs: 0\n"), 0644))
+
+	err := r.reload()
+
+	assert.ErrorIs(t, err, bm.updateErr)
+	assert.Equal(t, int64(10), r.currentConfig().MetadataCache.TtlSecs)
+}
+
+func TestConfigReloader_ReloadWithoutConfigFile(t *testing.T) {
+	r, bm := mountedReloader(t)
+
+	err := r.reload()
+
+	assert.Error(t, err)
+	assert.Nil(t, bm.config)
+}
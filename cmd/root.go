@@ -86,6 +86,31 @@ func getConfigFileFlags(v *viper.Viper) map[string]any {
 	return configOnlyViper.AllSettings()
 }
 
+// loadConfig unmarshals the flags and config file bound to the viper instance
+// into c, validates it and applies the optimizations and rationalizations. It
+// returns the flags changed by the optimizations.
+func loadConfig(v *viper.Viper, c *cfg.Config) (map[string]cfg.OptimizationResult, error) {
+	if err := v.Unmarshal(c, viper.DecodeHook(cfg.DecodeHook()), func(decoderConfig *mapstructure.DecoderConfig) {
+		// By default, viper supports mapstructure tags for unmarshalling. Override that to support yaml tag.
+		decoderConfig.TagName = "yaml"
+		// Reject the config file if any of the fields in the YAML don't map to the struct.
+		decoderConfig.ErrorUnused = true
+	},
+	); err != nil {
+		return nil, fmt.Errorf("error while unmarshalling config: %w", err)
+	}
+	if err := cfg.ValidateConfig(v, c); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	optimizedFlags := c.ApplyOptimizations(v, nil)
+	optimizedFlagNames := slices.Collect(maps.Keys(optimizedFlags))
+	if err := cfg.Rationalize(v, c, optimizedFlagNames); err != nil {
+		return nil, fmt.Errorf("error rationalizing config: %w", err)
+	}
+	return optimizedFlags, nil
+}
+
 // newRootCmd accepts the mountFn that it executes with the parsed configuration
 func newRootCmd(m mountFn) (*cobra.Command, error) {
 	var (
@@ -116,26 +141,13 @@ of Cloud Storage FUSE, see https://cloud.google.com/storage/docs/gcs-fuse.`,
 				}
 			}
 
-			if err := viperConfig.Unmarshal(mountInfo.config, viper.DecodeHook(cfg.DecodeHook()), func(decoderConfig *mapstructure.DecoderConfig) {
-				// By default, viper supports mapstructure tags for unmarshalling. Override that to support yaml tag.
-				decoderConfig.TagName = "yaml"
-				// Reject the config file if any of the fields in the YAML don't map to the struct.
-				decoderConfig.ErrorUnused = true
-			},
-			); err != nil {
-				return fmt.Errorf("error while unmarshalling config: %w", err)
-			}
-			if err := cfg.ValidateConfig(viperConfig, mountInfo.config); err != nil {
-				return fmt.Errorf("invalid config: %w", err)
+			optimizedFlags, err := loadConfig(viperConfig, mountInfo.config)
+			if err != nil {
+				return err
 			}
 
 			mountInfo.viperConfig = viperConfig
-			optimizedFlags := mountInfo.config.ApplyOptimizations(viperConfig, nil)
-			optimizedFlagNames := slices.Collect(maps.Keys(optimizedFlags))
-			if err := cfg.Rationalize(viperConfig, mountInfo.config, optimizedFlagNames); err != nil {
-				return fmt.Errorf("error rationalizing config: %w", err)
-			}
-			mountInfo.cliFlags = getCliFlags(cmd.PersistentFlags())
+			mountInfo.cliFlags = getCliFlags(cmd.Root().PersistentFlags())
 			mountInfo.configFileFlags = getConfigFileFlags(viperConfig)
 			optimizedFlagsAsHierarchicalMap, err := cfg.CreateHierarchicalOptimizedFlags(optimizedFlags)
 			if err != nil {
@@ -167,9 +179,22 @@ of Cloud Storage FUSE, see https://cloud.google.com/storage/docs/gcs-fuse.`,
 	if err := cfg.BindFlags(viperConfig, rootCmd.PersistentFlags()); err != nil {
 		return nil, fmt.Errorf("error while binding flags: %w", err)
 	}
+	rootCmd.AddCommand(newWarmCacheCmd(&mountInfo, m))
 	return rootCmd, nil
 }
 
+// commandArgs returns the args for the root command to execute from the
+// command line. The mount command expects the program name first, whereas
+// cobra only finds subcommands in the args without it.
+func commandArgs(c *cobra.Command, args []string) []string {
+	if len(args) > 1 {
+		if sub, _, err := c.Find(args[1:]); err == nil && sub != c {
+			return args[1:]
+		}
+	}
+	return args
+}
+
 // convertToPosixArgs converts a slice of commandline args and transforms them
 // into POSIX compliant args. All it does is that it converts flags specified
 // using a single-hyphen to double-hyphens. We are excluding "-v" because it's
@@ -217,7 +242,7 @@ var ExecuteMountCmd = func() {
 	if err != nil {
 		log.Fatalf("Error occurred while creating the root command on gcsfuse/%s: %v", common.GetVersion(), err)
 	}
-	rootCmd.SetArgs(convertToPosixArgs(os.Args, rootCmd))
+	rootCmd.SetArgs(commandArgs(rootCmd, convertToPosixArgs(os.Args, rootCmd)))
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Error occurred during command execution on gcsfuse/%s: %v", common.GetVersion(), err)
 	}
@@ -595,6 +595,7 @@ func TestArgsParsing_FileCacheFlags(t *testing.T) {
 					DownloadChunkSizeMb:                    20,
 					EnableCrc:                              true,
 					EnableParallelDownloads:                true,
+					EvictionPolicy:                         cfg.FileCacheEvictionPolicyLRU,
 					ExcludeRegex:                           ".*",
 					IncludeRegex:                           ".*",
 					ExperimentalParallelDownloadsDefaultOn: true,
@@ -603,6 +604,7 @@ func TestArgsParsing_FileCacheFlags(t *testing.T) {
 					ParallelDownloadsPerFile:               2,
 					SharedCacheChunkSizeMb:                 8,
 					WriteBufferSize:                        4 * 1024 * 1024,
+					WarmUpParallelism:                      16,
 					EnableODirect:                          false,
 					ExperimentalDisableSizeCalculationFix:  true,
 				},
@@ -617,6 +619,7 @@ func TestArgsParsing_FileCacheFlags(t *testing.T) {
 					DownloadChunkSizeMb:                    200,
 					EnableCrc:                              false,
 					EnableParallelDownloads:                false,
+					EvictionPolicy:                         cfg.FileCacheEvictionPolicyLRU,
 					ExcludeRegex:                           "",
 					IncludeRegex:                           "",
 					ExperimentalParallelDownloadsDefaultOn: true,
@@ -625,6 +628,7 @@ func TestArgsParsing_FileCacheFlags(t *testing.T) {
 					ParallelDownloadsPerFile:               16,
 					SharedCacheChunkSizeMb:                 8,
 					WriteBufferSize:                        4 * 1024 * 1024,
+					WarmUpParallelism:                      16,
 					EnableODirect:                          false,
 					ExperimentalDisableSizeCalculationFix:  false,
 				},
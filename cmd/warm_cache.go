@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/spf13/cobra"
+)
+
+// warmCacheCmdName is the name of the subcommand which warms up the file cache
+// of a bucket without mounting it.
+const warmCacheCmdName = "warm-cache"
+
+type warmCacheFn func(mountInfo *mountInfo, bucketName, mountPoint string) error
+
+// warmCache is run by the warm-cache subcommand. It's a variable so that tests
+// can replace it.
+var warmCache warmCacheFn = WarmCache
+
+// newWarmCacheCmd returns the warm-cache subcommand, which executes warmCache
+// with the configuration parsed by the root command. Given a single argument,
+// it executes m instead to mount the bucket named warm-cache there, as the
+// root command did before the subcommand existed.
+func newWarmCacheCmd(mountInfo *mountInfo, m mountFn) *cobra.Command {
+	return &cobra.Command{
+		Use:   warmCacheCmdName + " [flags] bucket mount_point",
+		Short: "Download the objects of a bucket listed in the warm-up manifest to the file cache",
+		Long: `Downloads the objects of the bucket matching the prefixes and globs of
+file-cache:warm-up-manifest to the file cache in cache-dir, without mounting
+the bucket, and saves the index of the file cache so that a later mount at
+mount_point with the same configuration and file-cache:persist-index reuses
+them.`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				bucket, mountPoint, err := populateArgs([]string{warmCacheCmdName, args[0]})
+				if err != nil {
+					return fmt.Errorf("error occurred while extracting the bucket and mountPoint: %w", err)
+				}
+				return m(mountInfo, bucket, mountPoint)
+			}
+			bucket, mountPoint, err := populateArgs(args)
+			if err != nil {
+				return fmt.Errorf("error occurred while extracting the bucket and mountPoint: %w", err)
+			}
+			return warmCache(mountInfo, bucket, mountPoint)
+		},
+	}
+}
+
+// WarmCache downloads the objects of the given bucket matching the warm-up
+// manifest of the parsed configuration to the file cache of its mount at
+// mountPoint.
+func WarmCache(mountInfo *mountInfo, bucketName, mountPoint string) error {
+	newConfig := mountInfo.config
+	logger.UpdateDefaultLogger(newConfig.Logging.Format, fsName(bucketName))
+	if err := logger.InitLogFile(newConfig.Logging, fsName(bucketName)); err != nil {
+		return fmt.Errorf("init log file: %w", err)
+	}
+	logger.Infof("Warming up the file cache of bucket %q in %q", bucketName, newConfig.CacheDir)
+
+	metricHandle := metrics.NewNoopMetrics()
+	var storageHandle storage.StorageHandle
+	if _, isLocal := storageutil.LocalBucketRoot(newConfig.GcsConnection.CustomEndpoint); !isLocal {
+		userAgent := getUserAgent(newConfig.AppName, getConfigForUserAgent(newConfig), logger.MountInstanceID(fsName(bucketName)))
+		var err error
+		storageHandle, err = createStorageHandle(newConfig, userAgent, metricHandle, false)
+		if err != nil {
+			return fmt.Errorf("failed to create storage handle using createStorageHandle: %w", err)
+		}
+	}
+	bm := gcsx.NewBucketManager(newBucketConfig(newConfig), storageHandle)
+	defer bm.ShutDown()
+
+	return fs.WarmUpFileCache(context.Background(), &fs.ServerConfig{
+		BucketManager:        bm,
+		BucketName:           bucketName,
+		MountPoint:           mountPoint,
+		SequentialReadSizeMb: int32(newConfig.GcsConnection.SequentialReadSizeMb),
+		NewConfig:            newConfig,
+		MetricHandle:         metricHandle,
+		TraceHandle:          tracing.NewNoopTracer(),
+	})
+}
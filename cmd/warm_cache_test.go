@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replaceWarmCache makes the warm-cache subcommand call fn for the duration of
+// the test.
+func replaceWarmCache(t *testing.T, fn warmCacheFn) {
+	t.Helper()
+	original := warmCache
+	warmCache = fn
+	t.Cleanup(func() {
+		warmCache = original
+	})
+}
+
+// setCommandLine sets the args of the root command as ExecuteMountCmd does.
+func setCommandLine(cmd *cobra.Command, args []string) {
+	cmd.SetArgs(commandArgs(cmd, convertToPosixArgs(args, cmd)))
+}
+
+func TestWarmCacheCmd_ParsesFlags(t *testing.T) {
+	var gotConfig *cfg.Config
+	var gotBucket, gotMountPoint string
+	replaceWarmCache(t, func(mountInfo *mountInfo, bucketName, mountPoint string) error {
+		gotConfig = mountInfo.config
+		gotBucket = bucketName
+		gotMountPoint = mountPoint
+		return nil
+	})
+	cmd, err := newRootCmd(func(*mountInfo, string, string) error {
+		t.Error("The mount command must not run")
+		return nil
+	})
+	require.NoError(t, err)
+	// Flags may be given before and after the subcommand.
+	setCommandLine(cmd, []string{"gcsfuse", "--cache-dir", "/some/valid/dir", "-file-cache-warm-up-manifest", "/some/manifest", "warm-cache", "--file-cache-warm-up-parallelism=4", "abc", "/some/mount/point", "-file-cache-persist-index"})
+
+	err = cmd.Execute()
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc", gotBucket)
+	assert.Equal(t, "/some/mount/point", gotMountPoint)
+	assert.Equal(t, cfg.ResolvedPath("/some/valid/dir"), gotConfig.CacheDir)
+	assert.Equal(t, cfg.ResolvedPath("/some/manifest"), gotConfig.FileCache.WarmUpManifest)
+	assert.Equal(t, int64(4), gotConfig.FileCache.WarmUpParallelism)
+	assert.True(t, gotConfig.FileCache.PersistIndex)
+}
+
+func TestWarmCacheCmd_RejectsManifestWithoutFileCache(t *testing.T) {
+	replaceWarmCache(t, func(*mountInfo, string, string) error {
+		t.Error("The cache must not be warmed up")
+		return nil
+	})
+	cmd, err := newRootCmd(func(*mountInfo, string, string) error { return nil })
+	require.NoError(t, err)
+	setCommandLine(cmd, []string{"gcsfuse", "warm-cache", "--file-cache-warm-up-manifest=/some/manifest", "abc", "/some/mount/point"})
+
+	err = cmd.Execute()
+
+	assert.ErrorContains(t, err, "file cache should be enabled for warm-up-manifest")
+}
+
+func TestWarmCacheCmd_TooManyArgs(t *testing.T) {
+	replaceWarmCache(t, func(*mountInfo, string, string) error {
+		t.Error("The cache must not be warmed up")
+		return nil
+	})
+	cmd, err := newRootCmd(func(*mountInfo, string, string) error { return nil })
+	require.NoError(t, err)
+	setCommandLine(cmd, []string{"gcsfuse", "warm-cache", "abc", "/some/mount/point", "extra"})
+
+	err = cmd.Execute()
+
+	assert.Error(t, err)
+}
+
+func TestWarmCacheCmd_MountsBucketNamedWarmCache(t *testing.T) {
+	replaceWarmCache(t, func(*mountInfo, string, string) error {
+		t.Error("The cache must not be warmed up")
+		return nil
+	})
+	var gotBucket, gotMountPoint string
+	var gotConfig *cfg.Config
+	cmd, err := newRootCmd(func(mountInfo *mountInfo, bucketName, mountPoint string) error {
+		gotConfig = mountInfo.config
+		gotBucket = bucketName
+		gotMountPoint = mountPoint
+		return nil
+	})
+	require.NoError(t, err)
+	setCommandLine(cmd, []string{"gcsfuse", "--implicit-dirs", "warm-cache", "/some/mount/point"})
+
+	err = cmd.Execute()
+
+	require.NoError(t, err)
+	assert.Equal(t, "warm-cache", gotBucket)
+	assert.Equal(t, "/some/mount/point", gotMountPoint)
+	assert.True(t, gotConfig.ImplicitDirs)
+}
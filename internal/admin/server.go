@@ -0,0 +1,251 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin serves an administration API for a mounted file system over a
+// unix domain socket.
+//
+// Clients send one JSON request per line, e.g.
+//
+//	{"command": "invalidate-metadata", "bucket": "my-bucket", "prefix": "dir/"}
+//
+// and receive one JSON response per line, with either a "result" or an
+// "error". The supported commands are:
+//
+//   - config: the effective config of the mount, as YAML.
+//   - inodes: the inodes known to the kernel.
+//   - handles: the open file and directory handles.
+//   - invalidate-metadata: removes the stat and type cache entries of the
+//     objects of "bucket" whose names start with "prefix".
+//   - evict-file-cache: removes "object" of "bucket" from the file cache.
+//   - invalidate-kernel-cache: asks the kernel to drop what it cached for
+//     "path", relative to the mount point.
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/common"
+	gcsfusefs "github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// maxRequestSize bounds the size of a single request line.
+const maxRequestSize = 64 * 1024
+
+// Inspector is the part of the file system the API gives access to. It is
+// implemented by fs.Inspector.
+type Inspector interface {
+	Inodes() ([]gcsfusefs.InodeInfo, error)
+	Handles() ([]gcsfusefs.HandleInfo, error)
+	InvalidateMetadata(bucketName string, prefix string) error
+	EvictFileCache(bucketName string, objectName string) error
+	InvalidateKernelCache(localPath string) error
+}
+
+// Request is a request to the API.
+type Request struct {
+	Command string `json:"command"`
+	Bucket  string `json:"bucket,omitempty"`
+	Prefix  string `json:"prefix,omitempty"`
+	Object  string `json:"object,omitempty"`
+	Path    string `json:"path,omitempty"`
+}
+
+// Response is the response to a Request. Error is set if the request failed.
+type Response struct {
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type server struct {
+	listener   net.Listener
+	socketPath string
+	config     func() *cfg.Config
+	inspector  Inspector
+
+	mu sync.Mutex
+	// GUARDED_BY(mu)
+	conns map[net.Conn]struct{}
+
+	wg sync.WaitGroup
+}
+
+// Serve starts serving the API on a unix domain socket at socketPath, which
+// only the owner of the process can connect to. An existing socket at that
+// path, e.g. left by a previous mount that crashed, is replaced. config returns
+// the effective config of the mount.
+//
+// The returned function stops the server and removes the socket.
+func Serve(socketPath string, config func() *cfg.Config, inspector Inspector) (common.ShutdownFn, error) {
+	if fi, err := os.Lstat(socketPath); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%q exists and is not a socket", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("removing the stale admin socket: %w", err)
+		}
+	}
+	listener, err := listenPrivate(socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		listener:   listener,
+		socketPath: socketPath,
+		config:     config,
+		inspector:  inspector,
+		conns:      make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	logger.Infof("Serving the admin API on %q", socketPath)
+	return s.shutdown, nil
+}
+
+// listenPrivate listens on a unix domain socket at socketPath that only the
+// owner of the process can connect to. The socket is created in a private
+// directory and restricted there before being moved into place, so that it is
+// never reachable by others, even briefly.
+func listenPrivate(socketPath string) (*net.UnixListener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(socketPath), ".admin-")
+	if err != nil {
+		return nil, fmt.Errorf("creating a private directory for the admin socket: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tmpPath := filepath.Join(dir, "admin.sock")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmpPath, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("listening on the admin socket: %w", err)
+	}
+	// The socket is removed from its final path on shutdown instead.
+	listener.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("restricting the admin socket permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, socketPath); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("moving the admin socket into place: %w", err)
+	}
+	return listener, nil
+}
+
+func (s *server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logger.Errorf("Admin socket: Accept: %v", err)
+			}
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, maxRequestSize)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var resp Response
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			logger.Infof("Admin socket: handling %q", req.Command)
+			result, err := s.handle(&req)
+			if err != nil {
+				resp.Error = err.Error()
+			} else {
+				resp.Result = result
+			}
+		}
+		if err := encoder.Encode(&resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *server) handle(req *Request) (any, error) {
+	switch req.Command {
+	case "config":
+		out, err := yaml.Marshal(s.config())
+		if err != nil {
+			return nil, err
+		}
+		return string(out), nil
+	case "inodes":
+		return s.inspector.Inodes()
+	case "handles":
+		return s.inspector.Handles()
+	case "invalidate-metadata":
+		if req.Bucket == "" {
+			return nil, errors.New("missing bucket")
+		}
+		return nil, s.inspector.InvalidateMetadata(req.Bucket, req.Prefix)
+	case "evict-file-cache":
+		if req.Bucket == "" || req.Object == "" {
+			return nil, errors.New("missing bucket or object")
+		}
+		return nil, s.inspector.EvictFileCache(req.Bucket, req.Object)
+	case "invalidate-kernel-cache":
+		return nil, s.inspector.InvalidateKernelCache(req.Path)
+	default:
+		return nil, fmt.Errorf("unknown command %q", req.Command)
+	}
+}
+
+func (s *server) shutdown(context.Context) error {
+	err := s.listener.Close()
+	if rmErr := os.Remove(s.socketPath); rmErr != nil && !os.IsNotExist(rmErr) && err == nil {
+		err = rmErr
+	}
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
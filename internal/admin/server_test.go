@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	gcsfusefs "github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInspector struct {
+	calls []string
+}
+
+func (f *fakeInspector) Inodes() ([]gcsfusefs.InodeInfo, error) {
+	return []gcsfusefs.InodeInfo{{ID: 1, Name: "", Type: "directory"}}, nil
+}
+
+func (f *fakeInspector) Handles() ([]gcsfusefs.HandleInfo, error) {
+	return []gcsfusefs.HandleInfo{{ID: 3, InodeID: 1, Type: "directory"}}, nil
+}
+
+func (f *fakeInspector) InvalidateMetadata(bucketName string, prefix string) error {
+	f.calls = append(f.calls, "invalidate-metadata "+bucketName+" "+prefix)
+	return nil
+}
+
+func (f *fakeInspector) EvictFileCache(bucketName string, objectName string) error {
+	f.calls = append(f.calls, "evict-file-cache "+bucketName+" "+objectName)
+	return nil
+}
+
+func (f *fakeInspector) InvalidateKernelCache(localPath string) error {
+	return errors.New("kernel notifications are disabled")
+}
+
+type client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func startServer(t *testing.T, inspector Inspector) (*client, string) {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	config := &cfg.Config{AppName: "app", FileSystem: cfg.FileSystemConfig{FileMode: 0644}}
+	shutdown, err := Serve(socketPath, func() *cfg.Config { return config }, inspector)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, shutdown(context.Background())) })
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	return &client{conn: conn, scanner: bufio.NewScanner(conn)}, socketPath
+}
+
+func (c *client) send(t *testing.T, req string) map[string]any {
+	t.Helper()
+	_, err := c.conn.Write([]byte(req + "\n"))
+	require.NoError(t, err)
+	require.True(t, c.scanner.Scan())
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(c.scanner.Bytes(), &resp))
+	return resp
+}
+
+func TestServe_SocketPermissions(t *testing.T) {
+	_, socketPath := startServer(t, &fakeInspector{})
+
+	fi, err := os.Stat(socketPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
+
+func TestServe_LeavesOnlyTheSocket(t *testing.T) {
+	_, socketPath := startServer(t, &fakeInspector{})
+
+	entries, err := os.ReadDir(filepath.Dir(socketPath))
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "admin.sock", entries[0].Name())
+}
+
+func TestServe_DoesNotReplaceOtherFiles(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	require.NoError(t, os.WriteFile(socketPath, []byte("data"), 0644))
+
+	_, err := Serve(socketPath, func() *cfg.Config { return &cfg.Config{} }, &fakeInspector{})
+
+	assert.Error(t, err)
+	content, err := os.ReadFile(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+}
+
+func TestServe_ReplacesStaleSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	stale, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	// Keep the socket file around, as after a crash.
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	shutdown, err := Serve(socketPath, func() *cfg.Config { return &cfg.Config{} }, &fakeInspector{})
+
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	_, err = os.Stat(socketPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestServe_Config(t *testing.T) {
+	c, _ := startServer(t, &fakeInspector{})
+
+	resp := c.send(t, `{"command": "config"}`)
+
+	require.Nil(t, resp["error"])
+	assert.Contains(t, resp["result"], "app-name: app")
+	assert.Contains(t, resp["result"], "file-mode: \"644\"")
+}
+
+func TestServe_InodesAndHandles(t *testing.T) {
+	c, _ := startServer(t, &fakeInspector{})
+
+	inodes := c.send(t, `{"command": "inodes"}`)
+	handles := c.send(t, `{"command": "handles"}`)
+
+	assert.Equal(t, []any{map[string]any{"id": float64(1), "name": "", "type": "directory"}}, inodes["result"])
+	assert.Equal(t, []any{map[string]any{"id": float64(3), "inode_id": float64(1), "type": "directory"}}, handles["result"])
+}
+
+func TestServe_Invalidation(t *testing.T) {
+	inspector := &fakeInspector{}
+	c, _ := startServer(t, inspector)
+
+	assert.Empty(t, c.send(t, `{"command": "invalidate-metadata", "bucket": "b", "prefix": "dir/"}`))
+	assert.Empty(t, c.send(t, `{"command": "evict-file-cache", "bucket": "b", "object": "dir/a"}`))
+	assert.Equal(t, "kernel notifications are disabled", c.send(t, `{"command": "invalidate-kernel-cache", "path": "dir/a"}`)["error"])
+
+	assert.Equal(t, []string{"invalidate-metadata b dir/", "evict-file-cache b dir/a"}, inspector.calls)
+}
+
+func TestServe_InvalidRequests(t *testing.T) {
+	c, _ := startServer(t, &fakeInspector{})
+
+	assert.NotEmpty(t, c.send(t, `not json`)["error"])
+	assert.NotEmpty(t, c.send(t, `{"command": "unknown"}`)["error"])
+	assert.NotEmpty(t, c.send(t, `{"command": "invalidate-metadata"}`)["error"])
+	assert.NotEmpty(t, c.send(t, `{"command": "evict-file-cache", "bucket": "b"}`)["error"])
+}
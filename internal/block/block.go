@@ -41,6 +41,10 @@ type Block interface {
 
 	// Write writes the given data to block.
 	Write(bytes []byte) (n int, err error)
+
+	// WriteAt overwrites the data of the block starting at the given offset,
+	// which must not be beyond its current size. The block grows if needed.
+	WriteAt(bytes []byte, offset int64) (n int, err error)
 }
 
 type memoryBlock struct {
@@ -127,6 +131,23 @@ func (m *memoryBlock) Write(bytes []byte) (int, error) {
 	return n, nil
 }
 
+func (m *memoryBlock) WriteAt(bytes []byte, offset int64) (int, error) {
+	if offset < 0 || offset > int64(len(m.buffer)) {
+		return 0, fmt.Errorf("offset %d is out of bounds of block of size %d", offset, len(m.buffer))
+	}
+	if int64(len(bytes)) > int64(cap(m.buffer))-offset {
+		return 0, fmt.Errorf("received data more than capacity of the block")
+	}
+
+	end := offset + int64(len(bytes))
+	if end > int64(len(m.buffer)) {
+		m.buffer = m.buffer[:end]
+	}
+	n := copy(m.buffer[offset:end], bytes)
+
+	return n, nil
+}
+
 func (m *memoryBlock) Deallocate() error {
 	if m.buffer == nil {
 		return fmt.Errorf("invalid buffer")
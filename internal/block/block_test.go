@@ -61,6 +61,47 @@ func (testSuite *MemoryBlockTest) TestMemoryBlockWriteWithDataGreaterThanCapacit
 	assert.EqualError(testSuite.T(), err, outOfCapacityError)
 }
 
+func (testSuite *MemoryBlockTest) TestMemoryBlockWriteAt() {
+	mb, err := createBlock(12)
+	require.Nil(testSuite.T(), err)
+	_, err = mb.Write([]byte("hello"))
+	require.Nil(testSuite.T(), err)
+
+	n, err := mb.WriteAt([]byte("yworld"), 4)
+
+	assert.Nil(testSuite.T(), err)
+	assert.Equal(testSuite.T(), 6, n)
+	output, err := io.ReadAll(mb)
+	assert.Nil(testSuite.T(), err)
+	assert.Equal(testSuite.T(), []byte("hellyworld"), output)
+	assert.Equal(testSuite.T(), int64(10), mb.Size())
+}
+
+func (testSuite *MemoryBlockTest) TestMemoryBlockWriteAtBeyondSize() {
+	mb, err := createBlock(12)
+	require.Nil(testSuite.T(), err)
+	_, err = mb.Write([]byte("hi"))
+	require.Nil(testSuite.T(), err)
+
+	n, err := mb.WriteAt([]byte("hello"), 3)
+
+	assert.NotNil(testSuite.T(), err)
+	assert.Equal(testSuite.T(), 0, n)
+	assert.Equal(testSuite.T(), int64(2), mb.Size())
+}
+
+func (testSuite *MemoryBlockTest) TestMemoryBlockWriteAtWithDataGreaterThanCapacity() {
+	mb, err := createBlock(4)
+	require.Nil(testSuite.T(), err)
+	_, err = mb.Write([]byte("hi"))
+	require.Nil(testSuite.T(), err)
+
+	n, err := mb.WriteAt([]byte("hello"), 1)
+
+	assert.EqualError(testSuite.T(), err, outOfCapacityError)
+	assert.Equal(testSuite.T(), 0, n)
+}
+
 func (testSuite *MemoryBlockTest) TestMemoryBlockWriteWithMultipleWrites() {
 	mb, err := createBlock(12)
 	require.Nil(testSuite.T(), err)
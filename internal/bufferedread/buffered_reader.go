@@ -129,6 +129,11 @@ type BufferedReaderOptions struct {
 	TraceHandle        tracing.TraceHandle
 	ReadTypeClassifier *gcsx.ReadTypeClassifier
 	HandleID           fuseops.HandleID
+
+	// ReclaimBlocks, if set, frees up to the given number of blocks of
+	// GlobalMaxBlocksSem held elsewhere when none are left for the reader, and
+	// returns the number freed.
+	ReclaimBlocks func(count int64) int64
 }
 
 // NewBufferedReader returns a new bufferedReader instance.
@@ -141,6 +146,9 @@ func NewBufferedReader(opts *BufferedReaderOptions) (*BufferedReader, error) {
 	blocksInFile := (int64(opts.Object.Size) + opts.Config.PrefetchBlockSizeBytes - 1) / opts.Config.PrefetchBlockSizeBytes
 	numBlocksToReserve := min(blocksInFile, opts.Config.MinBlocksPerHandle)
 	blockpool, err := block.NewPrefetchBlockPool(opts.Config.PrefetchBlockSizeBytes, opts.Config.MaxPrefetchBlockCnt, numBlocksToReserve, opts.GlobalMaxBlocksSem)
+	if errors.Is(err, block.CantAllocateAnyBlockError) && opts.ReclaimBlocks != nil && opts.ReclaimBlocks(numBlocksToReserve) > 0 {
+		blockpool, err = block.NewPrefetchBlockPool(opts.Config.PrefetchBlockSizeBytes, opts.Config.MaxPrefetchBlockCnt, numBlocksToReserve, opts.GlobalMaxBlocksSem)
+	}
 	if err != nil {
 		if errors.Is(err, block.CantAllocateAnyBlockError) {
 			opts.MetricHandle.BufferedReadFallbackTriggerCount(1, "insufficient_memory")
@@ -242,6 +242,31 @@ func (t *BufferedReaderTest) TestNewBufferedReaderFailsWhenPoolAllocationFails()
 	assert.ErrorIs(t.T(), err, block.CantAllocateAnyBlockError)
 }
 
+func (t *BufferedReaderTest) TestNewBufferedReaderReclaimsBlocks() {
+	// All the blocks are held elsewhere, e.g. by the RAM tier of the file cache.
+	t.globalMaxBlocksSem = semaphore.NewWeighted(t.config.MinBlocksPerHandle)
+	require.True(t.T(), t.globalMaxBlocksSem.TryAcquire(t.config.MinBlocksPerHandle))
+	var requested int64
+
+	reader, err := NewBufferedReader(&BufferedReaderOptions{
+		Object:             t.object,
+		Bucket:             t.bucket,
+		Config:             t.config,
+		GlobalMaxBlocksSem: t.globalMaxBlocksSem,
+		WorkerPool:         t.workerPool,
+		MetricHandle:       t.metricHandle,
+		ReadTypeClassifier: t.readTypeClassifier,
+		ReclaimBlocks: func(count int64) int64 {
+			requested = count
+			t.globalMaxBlocksSem.Release(count)
+			return count
+		}})
+
+	require.NoError(t.T(), err)
+	assert.NotNil(t.T(), reader)
+	assert.Equal(t.T(), t.config.MinBlocksPerHandle, requested)
+}
+
 func (t *BufferedReaderTest) TestNewBufferedReaderWithMinimumBlockNotAvailableInPool() {
 	// Simulate no blocks available globally.
 	t.globalMaxBlocksSem = semaphore.NewWeighted(1)
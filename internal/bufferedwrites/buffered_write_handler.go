@@ -48,6 +48,11 @@ type BufferedWriteHandler interface {
 	// SetMtime stores the mtime with the bufferedWriteHandler.
 	SetMtime(mtime time.Time)
 
+	// SetMetadata sets the given metadata keys on the object created by the
+	// upload, and returns false without setting them if the upload has already
+	// started.
+	SetMetadata(metadata map[string]string) bool
+
 	// Truncate allows truncating the file to a larger size.
 	Truncate(size int64) error
 
@@ -68,6 +73,11 @@ type bufferedWriteHandlerImpl struct {
 	current       block.Block
 	blockPool     *block.GenBlockPool[block.Block]
 	uploadHandler *UploadHandler
+	// Full blocks preceding current which haven't been handed over to the
+	// uploadHandler yet, so that out-of-order writes can still modify them.
+	// At most outOfOrderWindowBlocks blocks are kept.
+	pending                []block.Block
+	outOfOrderWindowBlocks int64
 	// Total size of data buffered so far. Some part of buffered data might have
 	// been uploaded to GCS as well. Depending on the state we are in, it might or
 	// might not include truncatedSize.
@@ -101,6 +111,10 @@ type CreateBWHandlerRequest struct {
 	ChunkRetryDeadlineSecs   int64
 	ChunkTransferTimeoutSecs int64
 	TraceHandle              tracing.TraceHandle
+	// Number of full blocks kept before being uploaded to serve out-of-order
+	// writes. It is capped to MaxBlocksPerFile-1 so that a block is always
+	// left for new writes.
+	OutOfOrderWindowBlocks int64
 }
 
 // NewBWHandler creates the bufferedWriteHandler struct.
@@ -128,9 +142,10 @@ func NewBWHandler(req *CreateBWHandlerRequest) (bwh BufferedWriteHandler, err er
 			ChunkTransferTimeoutSecs: req.ChunkTransferTimeoutSecs,
 			TraceHandle:              req.TraceHandle,
 		}),
-		totalSize:     size,
-		mtime:         time.Now(),
-		truncatedSize: -1,
+		outOfOrderWindowBlocks: max(0, min(req.OutOfOrderWindowBlocks, req.MaxBlocksPerFile-1)),
+		totalSize:              size,
+		mtime:                  time.Now(),
+		truncatedSize:          -1,
 	}
 	return
 }
@@ -146,6 +161,9 @@ func (wh *bufferedWriteHandlerImpl) Write(ctx context.Context, data []byte, offs
 	// bytes, and we write 10 bytes starting from offset 5, the total size becomes 15.
 	// A subsequent write at offset 10 (the truncated size) will be rejected as an out of order write.
 	if offset != wh.totalSize && (offset != wh.truncatedSize || wh.totalSize >= wh.truncatedSize) {
+		if wh.isInOutOfOrderWindow(offset, int64(len(data))) {
+			return wh.writeOutOfOrder(ctx, data, offset)
+		}
 		logger.Errorf("BufferedWriteHandler.OutOfOrderError for object: %s, expectedOffset: %d, actualOffset: %d",
 			wh.uploadHandler.objectName, wh.totalSize, offset)
 		return ErrOutOfOrderWrite
@@ -162,11 +180,114 @@ func (wh *bufferedWriteHandlerImpl) Write(ctx context.Context, data []byte, offs
 	return wh.appendBuffer(ctx, data)
 }
 
+// windowStart returns the offset of the first byte which hasn't been handed
+// over to the uploadHandler yet.
+func (wh *bufferedWriteHandlerImpl) windowStart() int64 {
+	start := wh.totalSize - int64(len(wh.pending))*wh.blockPool.BlockSize()
+	if wh.current != nil {
+		start -= wh.current.Size()
+	}
+	return start
+}
+
+// isInOutOfOrderWindow returns true if a write of the given size at the given
+// offset only touches data which hasn't been uploaded yet and fits in the
+// out-of-order window, i.e. the pending blocks and the current one.
+func (wh *bufferedWriteHandlerImpl) isInOutOfOrderWindow(offset int64, size int64) bool {
+	if wh.outOfOrderWindowBlocks == 0 {
+		return false
+	}
+	start := wh.windowStart()
+	return offset >= start && offset+size <= start+(wh.outOfOrderWindowBlocks+1)*wh.blockPool.BlockSize()
+}
+
+// writeOutOfOrder serves a write within the out-of-order window. Data before
+// totalSize is overwritten in place, while a gap after it is filled with
+// zeros, as in a sparse file.
+func (wh *bufferedWriteHandlerImpl) writeOutOfOrder(ctx context.Context, data []byte, offset int64) error {
+	if offset > wh.totalSize {
+		if err := wh.appendZeros(ctx, offset-wh.totalSize); err != nil {
+			return err
+		}
+	}
+
+	// Overwrite the buffered data, block by block.
+	blockSize := wh.blockPool.BlockSize()
+	start := wh.windowStart()
+	for len(data) > 0 && offset < wh.totalSize {
+		i := (offset - start) / blockSize
+		b := wh.current
+		if i < int64(len(wh.pending)) {
+			b = wh.pending[i]
+		}
+		blockOffset := (offset - start) % blockSize
+		n := min(int64(len(data)), blockSize-blockOffset, wh.totalSize-offset)
+		if _, err := b.WriteAt(data[:n], blockOffset); err != nil {
+			return err
+		}
+		data = data[n:]
+		offset += n
+	}
+
+	return wh.appendBuffer(ctx, data)
+}
+
+// getBlock returns a block for new writes. Pending blocks are uploaded as
+// needed so that blocks held by the out-of-order window can't prevent it.
+func (wh *bufferedWriteHandlerImpl) getBlock(ctx context.Context) (block.Block, error) {
+	for len(wh.pending) > 0 {
+		b, err := wh.blockPool.TryGet()
+		if !errors.Is(err, block.CantAllocateAnyBlockError) {
+			return b, err
+		}
+		if err = wh.uploadPending(ctx, int64(len(wh.pending)-1)); err != nil {
+			return nil, err
+		}
+	}
+	return wh.blockPool.Get()
+}
+
+// uploadPending hands the oldest pending blocks over to the uploadHandler
+// until at most keep blocks are pending.
+func (wh *bufferedWriteHandlerImpl) uploadPending(ctx context.Context, keep int64) error {
+	for int64(len(wh.pending)) > keep {
+		if err := wh.uploadHandler.Upload(ctx, wh.pending[0]); err != nil {
+			return err
+		}
+		wh.pending[0] = nil
+		wh.pending = wh.pending[1:]
+	}
+	return nil
+}
+
+// uploadAll hands all the buffered data over to the uploadHandler.
+func (wh *bufferedWriteHandlerImpl) uploadAll(ctx context.Context) error {
+	if err := wh.uploadPending(ctx, 0); err != nil {
+		return err
+	}
+	if wh.current != nil && wh.current.Size() != 0 {
+		if err := wh.uploadHandler.Upload(ctx, wh.current); err != nil {
+			return err
+		}
+		wh.current = nil
+	}
+	return nil
+}
+
+// releasePending returns the pending blocks to the block pool without
+// uploading them.
+func (wh *bufferedWriteHandlerImpl) releasePending() {
+	for _, b := range wh.pending {
+		wh.blockPool.Release(b)
+	}
+	wh.pending = nil
+}
+
 func (wh *bufferedWriteHandlerImpl) appendBuffer(ctx context.Context, data []byte) (err error) {
 	dataWritten := 0
 	for dataWritten < len(data) {
 		if wh.current == nil {
-			wh.current, err = wh.blockPool.Get()
+			wh.current, err = wh.getBlock(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to get new block: %w", err)
 			}
@@ -183,11 +304,12 @@ func (wh *bufferedWriteHandlerImpl) appendBuffer(ctx context.Context, data []byt
 		dataWritten += bytesToCopy
 
 		if wh.current.Size() == wh.blockPool.BlockSize() {
-			err := wh.uploadHandler.Upload(ctx, wh.current)
+			wh.pending = append(wh.pending, wh.current)
+			wh.current = nil
+			err := wh.uploadPending(ctx, wh.outOfOrderWindowBlocks)
 			if err != nil {
 				return err
 			}
-			wh.current = nil
 		}
 	}
 
@@ -203,13 +325,10 @@ func (wh *bufferedWriteHandlerImpl) appendBuffer(ctx context.Context, data []byt
 }
 
 func (wh *bufferedWriteHandlerImpl) Sync(ctx context.Context) (o *gcs.MinObject, err error) {
-	// Upload current block (for both regional and zonal buckets).
-	if wh.current != nil && wh.current.Size() != 0 {
-		err = wh.uploadHandler.Upload(ctx, wh.current)
-		if err != nil {
-			return nil, err
-		}
-		wh.current = nil
+	// Upload pending and current blocks (for both regional and zonal buckets).
+	err = wh.uploadAll(ctx)
+	if err != nil {
+		return nil, err
 	}
 	// Upload all the pending buffers.
 	wh.uploadHandler.AwaitBlocksUpload()
@@ -253,6 +372,10 @@ func (wh *bufferedWriteHandlerImpl) Flush(ctx context.Context) (*gcs.MinObject,
 		return nil, err
 	}
 
+	err = wh.uploadPending(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
 	if wh.current != nil {
 		err := wh.uploadHandler.Upload(ctx, wh.current)
 		if err != nil {
@@ -283,6 +406,10 @@ func (wh *bufferedWriteHandlerImpl) SetMtime(mtime time.Time) {
 	wh.mtime = mtime
 }
 
+func (wh *bufferedWriteHandlerImpl) SetMetadata(metadata map[string]string) bool {
+	return wh.uploadHandler.SetMetadata(metadata)
+}
+
 func (wh *bufferedWriteHandlerImpl) Truncate(size int64) error {
 	if size < wh.totalSize {
 		return ErrOutOfOrderWrite
@@ -301,6 +428,7 @@ func (wh *bufferedWriteHandlerImpl) WriteFileInfo() WriteFileInfo {
 
 func (wh *bufferedWriteHandlerImpl) Destroy() error {
 	wh.uploadHandler.Destroy()
+	wh.releasePending()
 	return wh.blockPool.ClearFreeBlockChannel(true)
 }
 
@@ -312,7 +440,11 @@ func (wh *bufferedWriteHandlerImpl) writeDataForTruncatedSize(ctx context.Contex
 	}
 
 	// Otherwise append dummy data to match truncatedSize.
-	diff := wh.truncatedSize - wh.totalSize
+	return wh.appendZeros(ctx, wh.truncatedSize-wh.totalSize)
+}
+
+// appendZeros appends the given number of zero bytes to the buffer.
+func (wh *bufferedWriteHandlerImpl) appendZeros(ctx context.Context, diff int64) error {
 	// Create 1MB of data at a time to avoid OOM
 	chunkSize := 1024 * 1024
 	for i := 0; i < int(diff); i += chunkSize {
@@ -328,6 +460,7 @@ func (wh *bufferedWriteHandlerImpl) writeDataForTruncatedSize(ctx context.Contex
 
 func (wh *bufferedWriteHandlerImpl) Unlink() {
 	wh.uploadHandler.CancelUpload()
+	wh.releasePending()
 	// Since bwh is not cleared after unlink, we will not release last block yet.
 	// Last block will be released when file handle for this file is closed.
 	err := wh.blockPool.ClearFreeBlockChannel(false)
@@ -75,6 +75,35 @@ func (testSuite *BufferedWriteTest) setupTestWithBucketType(bucketType gcs.Bucke
 	testSuite.bwh = bwh
 }
 
+func (testSuite *BufferedWriteTest) setupTestWithOutOfOrderWindow(windowBlocks int64) {
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "FakeBucketName", gcs.BucketType{})
+	testSuite.globalSemaphore = semaphore.NewWeighted(10)
+	bwh, err := NewBWHandler(&CreateBWHandlerRequest{
+		Object:                   nil,
+		ObjectName:               "testObject",
+		Bucket:                   bucket,
+		BlockSize:                blockSize,
+		MaxBlocksPerFile:         10,
+		GlobalMaxBlocksSem:       testSuite.globalSemaphore,
+		ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+		ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+		TraceHandle:              tracing.NewNoopTracer(),
+		OutOfOrderWindowBlocks:   windowBlocks,
+	})
+	require.Nil(testSuite.T(), err)
+	testSuite.bwh = bwh
+}
+
+// flushAndReadObject finalizes the upload and returns the object contents.
+func (testSuite *BufferedWriteTest) flushAndReadObject() string {
+	_, err := testSuite.bwh.Flush(context.Background())
+	require.NoError(testSuite.T(), err)
+	bwhImpl := testSuite.bwh.(*bufferedWriteHandlerImpl)
+	content, err := storageutil.ReadObject(context.Background(), bwhImpl.uploadHandler.bucket, bwhImpl.uploadHandler.objectName)
+	require.NoError(testSuite.T(), err)
+	return string(content)
+}
+
 func (testSuite *BufferedWriteTest) TestSetMTime() {
 	testTime := time.Now()
 
@@ -145,6 +174,75 @@ func (testSuite *BufferedWriteTest) TestWriteWhenNextOffsetIsGreaterThanExpected
 	assert.Equal(testSuite.T(), int64(2), fileInfo.TotalSize)
 }
 
+func (testSuite *BufferedWriteTest) TestOutOfOrderWindowIsCappedByMaxBlocksPerFile() {
+	bwh, err := NewBWHandler(&CreateBWHandlerRequest{
+		ObjectName:             "testObject",
+		Bucket:                 fake.NewFakeBucket(timeutil.RealClock(), "FakeBucketName", gcs.BucketType{}),
+		BlockSize:              blockSize,
+		MaxBlocksPerFile:       3,
+		GlobalMaxBlocksSem:     semaphore.NewWeighted(10),
+		TraceHandle:            tracing.NewNoopTracer(),
+		OutOfOrderWindowBlocks: 5,
+	})
+
+	require.NoError(testSuite.T(), err)
+	assert.Equal(testSuite.T(), int64(2), bwh.(*bufferedWriteHandlerImpl).outOfOrderWindowBlocks)
+}
+
+func (testSuite *BufferedWriteTest) TestOutOfOrderWriteOverwritesPendingBlocks() {
+	testSuite.setupTestWithOutOfOrderWindow(2)
+	data := strings.Repeat("A", 2*blockSize+10)
+	require.NoError(testSuite.T(), testSuite.bwh.Write(context.Background(), []byte(data), 0))
+
+	// Patch a header and data spanning the first two blocks.
+	err := testSuite.bwh.Write(context.Background(), []byte("header"), 0)
+	require.NoError(testSuite.T(), err)
+	err = testSuite.bwh.Write(context.Background(), []byte("BBBB"), blockSize-2)
+	require.NoError(testSuite.T(), err)
+
+	assert.Equal(testSuite.T(), int64(len(data)), testSuite.bwh.WriteFileInfo().TotalSize)
+	expected := "header" + data[6:blockSize-2] + "BBBB" + data[blockSize+2:]
+	assert.Equal(testSuite.T(), expected, testSuite.flushAndReadObject())
+}
+
+func (testSuite *BufferedWriteTest) TestOutOfOrderWriteAheadFillsGapWithZeros() {
+	testSuite.setupTestWithOutOfOrderWindow(1)
+	require.NoError(testSuite.T(), testSuite.bwh.Write(context.Background(), []byte("hi"), 0))
+
+	err := testSuite.bwh.Write(context.Background(), []byte("hello"), 5)
+	require.NoError(testSuite.T(), err)
+	// Fill the gap afterwards.
+	err = testSuite.bwh.Write(context.Background(), []byte("a"), 3)
+	require.NoError(testSuite.T(), err)
+
+	assert.Equal(testSuite.T(), int64(10), testSuite.bwh.WriteFileInfo().TotalSize)
+	assert.Equal(testSuite.T(), "hi\x00a\x00hello", testSuite.flushAndReadObject())
+}
+
+func (testSuite *BufferedWriteTest) TestOutOfOrderWriteBeforeWindowFails() {
+	testSuite.setupTestWithOutOfOrderWindow(1)
+	// The first block is uploaded once the second one is full.
+	data := strings.Repeat("A", 2*blockSize)
+	require.NoError(testSuite.T(), testSuite.bwh.Write(context.Background(), []byte(data), 0))
+
+	err := testSuite.bwh.Write(context.Background(), []byte("hi"), blockSize-1)
+
+	assert.Equal(testSuite.T(), ErrOutOfOrderWrite, err)
+	// Data of the second block can still be modified.
+	err = testSuite.bwh.Write(context.Background(), []byte("hi"), blockSize)
+	assert.NoError(testSuite.T(), err)
+}
+
+func (testSuite *BufferedWriteTest) TestOutOfOrderWriteBeyondWindowFails() {
+	testSuite.setupTestWithOutOfOrderWindow(1)
+	require.NoError(testSuite.T(), testSuite.bwh.Write(context.Background(), []byte("hi"), 0))
+
+	err := testSuite.bwh.Write(context.Background(), []byte("hi"), 2*blockSize-1)
+
+	assert.Equal(testSuite.T(), ErrOutOfOrderWrite, err)
+	assert.Equal(testSuite.T(), int64(2), testSuite.bwh.WriteFileInfo().TotalSize)
+}
+
 func (testSuite *BufferedWriteTest) TestWriteWhenNextOffsetIsLessThanExpected() {
 	err := testSuite.bwh.Write(context.Background(), []byte("hello"), 0)
 	require.Nil(testSuite.T(), err)
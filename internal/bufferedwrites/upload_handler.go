@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"sync"
 	"sync/atomic"
 
@@ -56,6 +57,7 @@ type UploadHandler struct {
 	bucket               gcs.Bucket
 	objectName           string
 	obj                  *gcs.Object
+	metadata             map[string]string
 	chunkRetryDeadline   int64
 	chunkTransferTimeout int64
 	blockSize            int64
@@ -111,6 +113,7 @@ func (uh *UploadHandler) Upload(ctx context.Context, block block.Block) error {
 // createObjectWriter creates a GCS object writer.
 func (uh *UploadHandler) createObjectWriter(ctx context.Context) (err error) {
 	req := gcs.NewCreateObjectRequest(uh.obj, uh.objectName, nil, uh.chunkRetryDeadline, uh.chunkTransferTimeout)
+	maps.Copy(req.Metadata, uh.metadata)
 	// We need a new context here, since the first writeFile() call will be complete
 	// (and context will be cancelled) by the time complete upload is done.
 	ctx, uh.cancelFunc = context.WithCancel(uh.traceHandle.PropagateTraceContext(context.Background(), ctx))
@@ -127,6 +130,20 @@ func (uh *UploadHandler) createObjectWriter(ctx context.Context) (err error) {
 	return
 }
 
+// SetMetadata sets the given metadata keys on the object created by the
+// upload, unless its writer has already been created, in which case it returns
+// false.
+func (uh *UploadHandler) SetMetadata(metadata map[string]string) bool {
+	if uh.writer != nil {
+		return false
+	}
+	if uh.metadata == nil {
+		uh.metadata = make(map[string]string)
+	}
+	maps.Copy(uh.metadata, metadata)
+	return true
+}
+
 func (uh *UploadHandler) UploadError() (err error) {
 	if uploadError := uh.uploadError.Load(); uploadError != nil {
 		err = *uploadError
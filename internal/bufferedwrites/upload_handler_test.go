@@ -162,6 +162,29 @@ func (t *UploadHandlerTest) TestCreateObjectWriter_CreateObjectChunkWriterCalled
 	t.mockBucket.AssertCalled(t.T(), "CreateObjectChunkWriter", mock.Anything, mock.Anything)
 }
 
+func (t *UploadHandlerTest) TestCreateObjectWriter_SetsMetadata() {
+	t.createUploadHandlerWithObjectOfGivenSize(0, finalized)
+	t.mockBucket.On("BucketType").Return(gcs.BucketType{})
+	var req *gcs.CreateObjectRequest
+	t.mockBucket.On("CreateObjectChunkWriter", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { req = args.Get(1).(*gcs.CreateObjectRequest) }).
+		Return(&storagemock.Writer{}, nil)
+
+	assert.True(t.T(), t.uh.SetMetadata(map[string]string{"taco": "burrito"}))
+	err := t.uh.createObjectWriter(context.Background())
+
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), req)
+	assert.Equal(t.T(), "burrito", req.Metadata["taco"])
+}
+
+func (t *UploadHandlerTest) TestSetMetadata_WriterAlreadyPresent() {
+	t.uh.writer = &storagemock.Writer{}
+
+	assert.False(t.T(), t.uh.SetMetadata(map[string]string{"taco": "burrito"}))
+	assert.Empty(t.T(), t.uh.metadata)
+}
+
 func (t *UploadHandlerTest) TestEnsureWriter_CreateAppendableWriterIsSuccessful() {
 	t.createUploadHandlerWithObjectOfGivenSize(objectSize, time.Time{})
 	t.mockBucket.On("BucketType").Return(gcs.BucketType{Zonal: true})
@@ -129,6 +129,11 @@ func (brm *ByteRangeMap) GetMissingChunks(start, end uint64) []uint64 {
 	return missing
 }
 
+// ChunkSize returns the size of the chunks tracked by the map.
+func (brm *ByteRangeMap) ChunkSize() uint64 {
+	return brm.chunkSize
+}
+
 // TotalBytes returns the total number of bytes downloaded (sum of chunk sizes)
 func (brm *ByteRangeMap) TotalBytes() uint64 {
 	brm.mu.RLock()
@@ -282,6 +282,53 @@ func (fch *CacheHandle) Read(ctx context.Context, bucket gcs.Bucket, object *gcs
 	return
 }
 
+// ReadDownloaded reads the data at the given offset from the cached location
+// if it's already downloaded, without waiting for or starting a download and
+// without affecting the type of subsequent reads. It returns
+// util.ErrFallbackToGCS if the data isn't available.
+func (fch *CacheHandle) ReadDownloaded(bucket gcs.Bucket, object *gcs.MinObject, offset int64, dst []byte) (n int, err error) {
+	if err = fch.validateCacheHandle(); err != nil {
+		return 0, err
+	}
+
+	if offset < 0 || offset >= int64(object.Size) {
+		return 0, fmt.Errorf("wrong offset requested: %d, object size: %d", offset, object.Size)
+	}
+	requiredOffset := min(offset+int64(len(dst)), int64(object.Size))
+
+	fileInfoData, err := fch.getFileInfoData(bucket, object, false)
+	if err != nil {
+		return 0, err
+	}
+	if fileInfoData.ObjectGeneration != object.Generation {
+		return 0, util.ErrFallbackToGCS
+	}
+
+	var downloaded bool
+	switch {
+	case fileInfoData.SparseMode:
+		downloaded = fileInfoData.DownloadedChunks != nil && fileInfoData.DownloadedChunks.ContainsRange(uint64(offset), uint64(requiredOffset))
+	case fch.fileDownloadJob != nil:
+		jobStatus := fch.fileDownloadJob.GetStatus()
+		downloaded = fch.shouldReadFromCache(&jobStatus, requiredOffset) == nil
+	default:
+		downloaded = fileInfoData.Offset >= uint64(requiredOffset)
+	}
+	if !downloaded {
+		return 0, util.ErrFallbackToGCS
+	}
+
+	requestedNumBytes := int(requiredOffset - offset)
+	n, err = fch.fileHandle.ReadAt(dst[:requestedNumBytes], offset)
+	if err == io.EOF && n == requestedNumBytes {
+		err = nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: while reading from %d offset of the local file: %w", util.ErrInReadingFileHandle, offset, err)
+	}
+	return n, nil
+}
+
 // IsSequential returns true if the sequential read is being performed, false for
 // random read.
 func (fch *CacheHandle) IsSequential(currentOffset int64) bool {
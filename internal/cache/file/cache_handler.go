@@ -15,6 +15,7 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -27,6 +28,7 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/sync/semaphore"
 )
 
 // CacheHandler is responsible for creating CacheHandle and invalidating file cache
@@ -55,9 +57,13 @@ type CacheHandler struct {
 	mu locker.Locker
 
 	// excludeRegex is the compiled regex for excluding files from cache
+	//
+	// GUARDED_BY(mu)
 	excludeRegex *regexp.Regexp
 
 	// includeRegex is the compiled regex for including files from cache
+	//
+	// GUARDED_BY(mu)
 	includeRegex *regexp.Regexp
 
 	// isSparse indicates whether sparse file mode is enabled
@@ -65,6 +71,20 @@ type CacheHandler struct {
 
 	// volumeBlockSize caches the block size of the local volume for speculative size accounting
 	volumeBlockSize uint64
+
+	// indexDir is the directory the index of the cache is saved to when the
+	// handler is destroyed, one file per bucket, or empty if the index isn't
+	// persisted. The files are keyed by mountPoint too.
+	indexDir   string
+	mountPoint string
+
+	// restoredIndex contains, by bucket, the entries of the index saved by the
+	// previous mount that are yet to be restored by RestoreIndex.
+	restoredIndex map[string][]indexEntry
+
+	// ramTier keeps hot chunks of the cached files in memory, or is nil if the
+	// in-memory tier is disabled. Its chunks are dropped along with the files.
+	ramTier *RAMTier
 }
 
 func NewCacheHandler(fileInfoCache *lru.Cache, jobManager *downloader.JobManager, cacheDir string, filePerm os.FileMode, dirPerm os.FileMode, excludeRegex string, includeRegex string, isSparse bool, volumeBlockSize uint64) *CacheHandler {
@@ -122,6 +142,9 @@ func (chr *CacheHandler) cleanUpEvictedFile(fileInfo *data.FileInfo) error {
 	}
 
 	chr.jobManager.InvalidateAndRemoveJob(key.ObjectName, key.BucketName)
+	if chr.ramTier != nil {
+		chr.ramTier.Invalidate(key.BucketName, key.ObjectName)
+	}
 
 	localFilePath := util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(key.BucketName, key.ObjectName))
 	err = util.TruncateAndRemoveFile(localFilePath)
@@ -220,6 +243,11 @@ func (chr *CacheHandler) addFileInfoEntryAndCreateDownloadJob(object *gcs.MinObj
 			}
 		}
 	} else {
+		// Entries in sparse mode restored from the index of the previous mount
+		// have no download job yet.
+		if fileInfo.(data.FileInfo).SparseMode {
+			_ = chr.jobManager.CreateJobIfNotExists(object, bucket)
+		}
 		// Move this entry on top of LRU.
 		_ = chr.fileInfoCache.LookUp(fileInfoKeyName)
 	}
@@ -310,19 +338,64 @@ func (chr *CacheHandler) InvalidateCache(objectName string, bucketName string) e
 
 // Destroy destroys the job manager (i.e. invalidate all the jobs).
 // Note: This method is expected to be called at the time of unmounting and
-// because file info cache is in-memory, it is not required to destroy it.
+// because file info cache is in-memory, it is not required to destroy it. If
+// the index is persisted, it is saved before the jobs are invalidated.
 //
 // Acquires and releases Lock(chr.mu)
 func (chr *CacheHandler) Destroy() (err error) {
 	chr.mu.Lock()
 	defer chr.mu.Unlock()
 
+	if chr.indexDir != "" {
+		err = chr.saveIndex()
+	}
 	chr.jobManager.Destroy()
+	if chr.ramTier != nil {
+		err = errors.Join(err, chr.ramTier.Destroy())
+	}
 	return
 }
 
+// EnableRAMTier keeps up to maxChunks chunks of chunkSize bytes of the cached
+// files in memory, sharing the blocks of globalMaxBlocksSem with the readers.
+// It must be called before the handler is used to read.
+func (chr *CacheHandler) EnableRAMTier(chunkSize int64, maxChunks int64, globalMaxBlocksSem *semaphore.Weighted) error {
+	chr.mu.Lock()
+	defer chr.mu.Unlock()
+
+	ramTier, err := NewRAMTier(chunkSize, maxChunks, globalMaxBlocksSem, chr.fileInfoCache)
+	if err != nil {
+		return fmt.Errorf("EnableRAMTier: %w", err)
+	}
+	chr.ramTier = ramTier
+	return nil
+}
+
+// RAMTier returns the in-memory tier of the cache, or nil if it's disabled.
+func (chr *CacheHandler) RAMTier() *RAMTier {
+	return chr.ramTier
+}
+
+// SetRegexes replaces the regexes for excluding and including files from
+// cache, e.g. when the config is reloaded. Files that are already cached stay
+// cached.
+//
+// Acquires and releases LOCK(CacheHandler.mu)
+func (chr *CacheHandler) SetRegexes(excludeRegex string, includeRegex string) {
+	compiledExcludeRegex := compileRegex(excludeRegex)
+	compiledIncludeRegex := compileRegex(includeRegex)
+
+	chr.mu.Lock()
+	defer chr.mu.Unlock()
+
+	chr.excludeRegex = compiledExcludeRegex
+	chr.includeRegex = compiledIncludeRegex
+}
+
 // shouldExcludeFromCache checks if the object should be excluded from cache
 // based on the configured regex pattern of include and/or exclude regex.
+//
+// LOCKS_REQUIRED(chr.mu)
 func (chr *CacheHandler) shouldExcludeFromCache(bucket gcs.Bucket, object *gcs.MinObject) bool {
 	// If no regex is configured, nothing is excluded.
 	if chr.includeRegex == nil && chr.excludeRegex == nil {
@@ -614,6 +614,24 @@ func Test_GetCacheHandle_SameIncludeAndExcludeRegex(t *testing.T) {
 	assert.Nil(t, cacheHandle)
 }
 
+func Test_SetRegexes(t *testing.T) {
+	cacheDir := path.Join(os.Getenv("HOME"), "CacheHandlerTest/dir")
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{EnableCrc: true, ExcludeRegex: ".*object_1"}, cacheDir)
+
+	chTestArgs.cacheHandler.SetRegexes(".*object_2", "")
+
+	// Check cache handle is created for the previously excluded file.
+	chTestArgs.object.Name = "object_1"
+	cacheHandle, err := chTestArgs.cacheHandler.GetCacheHandle(chTestArgs.object, chTestArgs.bucket, false, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, cacheHandle.validateCacheHandle())
+	// Check cache handle is not created for the newly excluded file.
+	chTestArgs.object.Name = "object_2"
+	cacheHandle, err = chTestArgs.cacheHandler.GetCacheHandle(chTestArgs.object, chTestArgs.bucket, false, 0)
+	assert.True(t, errors.Is(err, util.ErrFileExcludedFromCacheByRegex))
+	assert.Nil(t, cacheHandle)
+}
+
 func Test_GetCacheHandle_CacheForRangeRead(t *testing.T) {
 	tbl := []struct {
 		name            string
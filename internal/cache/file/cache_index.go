@@ -0,0 +1,349 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/sync/errgroup"
+)
+
+// indexVersion is the version of the on-disk format of the file cache index.
+// Indexes with a different version are ignored.
+const indexVersion = 1
+
+// restoreBatchSize is the number of index entries validated concurrently
+// against GCS while restoring the index. Entries are inserted in the file info
+// cache batch by batch so that the LRU order is preserved.
+const restoreBatchSize = 64
+
+// cacheIndex is the on-disk representation of the file info cache.
+type cacheIndex struct {
+	Version int          `json:"version"`
+	Entries []indexEntry `json:"entries"`
+}
+
+// indexEntry describes a file in cache which can be reused by the next mount.
+// Only fully downloaded files and sparse files are saved.
+type indexEntry struct {
+	BucketName string `json:"bucket_name"`
+	ObjectName string `json:"object_name"`
+	Generation int64  `json:"generation"`
+	FileSize   uint64 `json:"file_size"`
+
+	// CRCValidated is true if the CRC32C checksum of the file was validated
+	// against the object once downloaded.
+	CRCValidated bool `json:"crc_validated,omitempty"`
+
+	// SparseChunkSize and SparseChunks describe the downloaded chunks of a file
+	// in sparse mode.
+	Sparse          bool     `json:"sparse,omitempty"`
+	SparseChunkSize uint64   `json:"sparse_chunk_size,omitempty"`
+	SparseChunks    []uint64 `json:"sparse_chunks,omitempty"`
+}
+
+// EnablePersistentIndex makes the handler save its index to indexDir when
+// destroyed, in one file per bucket keyed by mountPoint. The indexes saved by
+// the previous mount at mountPoint, if any, are loaded and removed from disk so
+// that they can't be reused after an unclean shutdown. The loaded entries are
+// added back to the cache by RestoreIndex, as buckets are set up, while those
+// of the buckets which aren't are saved again.
+func (chr *CacheHandler) EnablePersistentIndex(indexDir string, mountPoint string) error {
+	chr.mu.Lock()
+	defer chr.mu.Unlock()
+
+	if err := os.MkdirAll(indexDir, chr.dirPerm); err != nil {
+		return fmt.Errorf("EnablePersistentIndex: %w", err)
+	}
+	// Bucket names can't contain "*", so the pattern only matches the indexes
+	// of the mount.
+	indexPaths, err := filepath.Glob(util.GetIndexPath(indexDir, "*", mountPoint))
+	if err != nil {
+		return fmt.Errorf("EnablePersistentIndex: %w", err)
+	}
+
+	chr.indexDir = indexDir
+	chr.mountPoint = mountPoint
+	chr.restoredIndex = make(map[string][]indexEntry)
+	for _, indexPath := range indexPaths {
+		index, err := readIndex(indexPath)
+		if err != nil {
+			return fmt.Errorf("EnablePersistentIndex: %w", err)
+		}
+		for _, entry := range index.Entries {
+			chr.restoredIndex[entry.BucketName] = append(chr.restoredIndex[entry.BucketName], entry)
+		}
+	}
+	return nil
+}
+
+func readIndex(indexPath string) (index cacheIndex, err error) {
+	content, err := os.ReadFile(indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return index, fmt.Errorf("while reading index %s: %w", indexPath, err)
+	}
+	if err = os.Remove(indexPath); err != nil {
+		return index, fmt.Errorf("while removing index %s: %w", indexPath, err)
+	}
+
+	if err = json.Unmarshal(content, &index); err != nil {
+		logger.Warnf("File Cache: ignoring malformed index %s: %v", indexPath, err)
+		return cacheIndex{}, nil
+	}
+	if index.Version != indexVersion {
+		logger.Warnf("File Cache: ignoring index %s with unsupported version %d", indexPath, index.Version)
+		return cacheIndex{}, nil
+	}
+	return index, nil
+}
+
+// RestoreIndex adds the entries of the given bucket in the index loaded by
+// EnablePersistentIndex back to the file info cache, once the corresponding
+// files in cache are validated against the objects in the bucket. Files of
+// stale entries are removed.
+//
+// Entries are never restored over ones added since the mount, and reads of
+// objects whose entries haven't been restored yet are served as cache misses.
+//
+// Acquires and releases LOCK(CacheHandler.mu) for each batch of entries.
+func (chr *CacheHandler) RestoreIndex(ctx context.Context, bucket gcs.Bucket) {
+	chr.mu.Lock()
+	entries := chr.restoredIndex[bucket.Name()]
+	delete(chr.restoredIndex, bucket.Name())
+	chr.mu.Unlock()
+
+	restored := 0
+	for start := 0; start < len(entries); start += restoreBatchSize {
+		batch := entries[start:min(start+restoreBatchSize, len(entries))]
+		valid := make([]bool, len(batch))
+
+		group, groupCtx := errgroup.WithContext(ctx)
+		for i := range batch {
+			group.Go(func() error {
+				err := chr.validateIndexEntry(groupCtx, bucket, &batch[i])
+				if err != nil {
+					logger.Tracef("File Cache: not restoring %s:/%s: %v", batch[i].BucketName, batch[i].ObjectName, err)
+				}
+				valid[i] = err == nil
+				return nil
+			})
+		}
+		_ = group.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+
+		restored += chr.restoreIndexEntries(bucket.Name(), batch, valid)
+	}
+
+	if len(entries) > 0 {
+		logger.Infof("File Cache: restored %d of %d entries from the index of the previous mount", restored, len(entries))
+	}
+}
+
+// validateIndexEntry returns nil if the file in cache for the given entry can
+// be reused, i.e. it is present locally and the object hasn't changed since it
+// was downloaded.
+func (chr *CacheHandler) validateIndexEntry(ctx context.Context, bucket gcs.Bucket, entry *indexEntry) error {
+	if entry.BucketName != bucket.Name() {
+		return fmt.Errorf("entry belongs to bucket %q", entry.BucketName)
+	}
+
+	filePath := util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(entry.BucketName, entry.ObjectName))
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	if !entry.Sparse && uint64(fileInfo.Size()) != entry.FileSize {
+		return fmt.Errorf("size of file in cache %d doesn't match %d", fileInfo.Size(), entry.FileSize)
+	}
+
+	object, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{
+		Name:              entry.ObjectName,
+		ForceFetchFromGcs: true,
+	})
+	if err != nil {
+		return fmt.Errorf("StatObject: %w", err)
+	}
+	if object.Generation != entry.Generation || object.Size != entry.FileSize {
+		return fmt.Errorf("object changed to generation %d and size %d", object.Generation, object.Size)
+	}
+
+	// Checksums of files in sparse mode are never validated.
+	if entry.Sparse || entry.CRCValidated || !chr.jobManager.EnableCrc() || bucket.BucketType().IsRapid() || object.CRC32C == nil {
+		return nil
+	}
+	crc, err := util.CalculateFileCRC32(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	if crc != *object.CRC32C {
+		return fmt.Errorf("checksum mismatch detected. Actual: %d, expected: %d", crc, *object.CRC32C)
+	}
+	return nil
+}
+
+// restoreIndexEntries inserts the valid entries in the file info cache, in
+// order, and removes the files of the invalid ones of the given bucket. It
+// returns the number of restored entries.
+//
+// Acquires and releases LOCK(CacheHandler.mu)
+func (chr *CacheHandler) restoreIndexEntries(bucketName string, entries []indexEntry, valid []bool) (restored int) {
+	chr.mu.Lock()
+	defer chr.mu.Unlock()
+
+	for i, entry := range entries {
+		if entry.BucketName != bucketName {
+			continue
+		}
+		fileInfoKey := data.FileInfoKey{
+			BucketName: entry.BucketName,
+			ObjectName: entry.ObjectName,
+		}
+		fileInfoKeyName, err := fileInfoKey.Key()
+		if err != nil {
+			continue
+		}
+		// The object has been read since the mount and its file in cache may be
+		// in use, so leave it alone.
+		if chr.fileInfoCache.LookUpWithoutChangingOrder(fileInfoKeyName) != nil {
+			continue
+		}
+
+		if !valid[i] {
+			filePath := util.GetDownloadPath(chr.cacheDir, util.GetObjectPath(entry.BucketName, entry.ObjectName))
+			if err := util.TruncateAndRemoveFile(filePath); err != nil && !os.IsNotExist(err) {
+				logger.Warnf("File Cache: while removing stale file %s: %v", filePath, err)
+			}
+			continue
+		}
+
+		fileInfo := data.NewFileInfo(fileInfoKey, entry.Generation, entry.FileSize, entry.FileSize, false, nil, chr.volumeBlockSize)
+		if entry.Sparse {
+			fileInfo.SparseMode = true
+			fileInfo.Offset = ^uint64(0) // math.MaxUint64
+			fileInfo.DownloadedChunks = data.NewByteRangeMap(entry.SparseChunkSize, entry.FileSize)
+			for _, chunkID := range entry.SparseChunks {
+				fileInfo.DownloadedChunks.AddRange(chunkID*entry.SparseChunkSize, min((chunkID+1)*entry.SparseChunkSize, entry.FileSize))
+			}
+		}
+
+		evictedValues, err := chr.fileInfoCache.Insert(fileInfoKeyName, fileInfo)
+		if err != nil {
+			logger.Warnf("File Cache: while restoring %s:/%s: %v", entry.BucketName, entry.ObjectName, err)
+			continue
+		}
+		restored++
+		for _, val := range evictedValues {
+			evicted := val.(data.FileInfo)
+			if err := chr.cleanUpEvictedFile(&evicted); err != nil {
+				logger.Warnf("File Cache: while restoring index: %v", err)
+			}
+		}
+	}
+	return
+}
+
+// saveIndex writes the entries of the file info cache whose files in cache
+// can be reused by the next mount to chr.indexDir, along with the entries of
+// the buckets which haven't been restored.
+//
+// Requires Lock(chr.mu)
+func (chr *CacheHandler) saveIndex() error {
+	// Job statuses can't be checked while iterating over the file info cache
+	// because jobs update the cache with their lock held.
+	var fileInfos []data.FileInfo
+	chr.fileInfoCache.ForEachOldestFirst(func(_ string, value lru.ValueType) {
+		fileInfos = append(fileInfos, value.(data.FileInfo))
+	})
+
+	indexes := make(map[string]*cacheIndex)
+	for bucketName, entries := range chr.restoredIndex {
+		indexes[bucketName] = &cacheIndex{Version: indexVersion, Entries: entries}
+	}
+	for _, fileInfo := range fileInfos {
+		entry := indexEntry{
+			BucketName: fileInfo.Key.BucketName,
+			ObjectName: fileInfo.Key.ObjectName,
+			Generation: fileInfo.ObjectGeneration,
+			FileSize:   fileInfo.FileSize,
+		}
+
+		if fileInfo.SparseMode {
+			if fileInfo.DownloadedChunks == nil {
+				continue
+			}
+			entry.Sparse = true
+			entry.SparseChunkSize = fileInfo.DownloadedChunks.ChunkSize()
+			entry.SparseChunks = fileInfo.DownloadedChunks.Chunks()
+		} else {
+			// Skip files which are partially downloaded or still being validated.
+			if fileInfo.Offset < fileInfo.FileSize {
+				continue
+			}
+			job := chr.jobManager.GetJob(fileInfo.Key.ObjectName, fileInfo.Key.BucketName)
+			if job != nil && job.GetStatus().Name != downloader.Completed {
+				continue
+			}
+			entry.CRCValidated = chr.jobManager.EnableCrc()
+		}
+		index, ok := indexes[entry.BucketName]
+		if !ok {
+			index = &cacheIndex{Version: indexVersion}
+			indexes[entry.BucketName] = index
+		}
+		index.Entries = append(index.Entries, entry)
+	}
+
+	for bucketName, index := range indexes {
+		if err := chr.writeIndex(util.GetIndexPath(chr.indexDir, bucketName, chr.mountPoint), index); err != nil {
+			return fmt.Errorf("saveIndex: %w", err)
+		}
+	}
+	return nil
+}
+
+func (chr *CacheHandler) writeIndex(indexPath string, index *cacheIndex) error {
+	content, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a crash never leaves a partial
+	// index behind.
+	tmpPath := indexPath + ".tmp"
+	if err = os.WriteFile(tmpPath, content, chr.filePerm); err != nil {
+		return fmt.Errorf("while writing %s: %w", tmpPath, err)
+	}
+	if err = os.Rename(tmpPath, indexPath); err != nil {
+		return fmt.Errorf("while renaming %s: %w", tmpPath, err)
+	}
+	logger.Infof("File Cache: saved %d entries to index %s", len(index.Entries), indexPath)
+	return nil
+}
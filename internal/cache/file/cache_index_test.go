@@ -0,0 +1,227 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMountPoint = "/some/mount/point"
+
+// newCacheHandlerForRestart returns a cache handler with an empty file info
+// cache over the given cache directory, as created by a new mount.
+func newCacheHandlerForRestart(t *testing.T, fileCacheConfig *cfg.FileCacheConfig, cacheDir string) (*CacheHandler, *lru.Cache) {
+	t.Helper()
+	cache := lru.NewCache(HandlerCacheMaxSize)
+	jobManager := downloader.NewJobManager(cache, util.DefaultFilePerm, util.DefaultDirPerm, cacheDir,
+		DefaultSequentialReadSizeMb, fileCacheConfig, metrics.NewNoopMetrics(), tracing.NewNoopTracer(), 1)
+	t.Cleanup(func() {
+		jobManager.Destroy()
+	})
+	return NewCacheHandler(cache, jobManager, cacheDir, util.DefaultFilePerm, util.DefaultDirPerm, "", "", false, 1), cache
+}
+
+// cacheObjectFully reads the given object through the cache handler and
+// waits for its download job to complete.
+func cacheObjectFully(t *testing.T, chTestArgs *cacheHandlerTestArgs, object *gcs.MinObject) {
+	t.Helper()
+	cacheHandle, err := chTestArgs.cacheHandler.GetCacheHandle(object, chTestArgs.bucket, true, 0)
+	require.NoError(t, err)
+	buf := make([]byte, object.Size)
+	_, _, err = cacheHandle.Read(context.Background(), chTestArgs.bucket, object, 0, buf)
+	require.NoError(t, err)
+	require.NoError(t, cacheHandle.Close())
+	require.Eventually(t, func() bool {
+		job := chTestArgs.jobManager.GetJob(object.Name, chTestArgs.bucket.Name())
+		return job == nil || job.GetStatus().Name == downloader.Completed
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_Destroy_SavesIndex(t *testing.T) {
+	cacheDir := t.TempDir()
+	indexDir := path.Join(cacheDir, util.FileCacheIndex)
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{EnableCrc: true}, cacheDir)
+	require.NoError(t, chTestArgs.cacheHandler.EnablePersistentIndex(indexDir, testMountPoint))
+	indexPath := util.GetIndexPath(indexDir, chTestArgs.bucket.Name(), testMountPoint)
+	minObject := createObject(t, chTestArgs.bucket, "object_1", []byte("content of object_1"))
+	cacheObjectFully(t, chTestArgs, minObject)
+
+	err := chTestArgs.cacheHandler.Destroy()
+
+	require.NoError(t, err)
+	index, err := readIndex(indexPath)
+	require.NoError(t, err)
+	// The test object is only partially downloaded, so it isn't saved.
+	require.Len(t, index.Entries, 1)
+	assert.Equal(t, indexEntry{
+		BucketName:   chTestArgs.bucket.Name(),
+		ObjectName:   minObject.Name,
+		Generation:   minObject.Generation,
+		FileSize:     minObject.Size,
+		CRCValidated: true,
+	}, index.Entries[0])
+	// Reading the index removes it.
+	_, err = os.Stat(indexPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_RestoreIndex_RestoresUnchangedObjects(t *testing.T) {
+	cacheDir := t.TempDir()
+	indexDir := path.Join(cacheDir, util.FileCacheIndex)
+	fileCacheConfig := &cfg.FileCacheConfig{EnableCrc: true}
+	chTestArgs := initializeCacheHandlerTestArgs(t, fileCacheConfig, cacheDir)
+	require.NoError(t, chTestArgs.cacheHandler.EnablePersistentIndex(indexDir, testMountPoint))
+	indexPath := util.GetIndexPath(indexDir, chTestArgs.bucket.Name(), testMountPoint)
+	minObject := createObject(t, chTestArgs.bucket, "object_1", []byte("content of object_1"))
+	cacheObjectFully(t, chTestArgs, minObject)
+	require.NoError(t, chTestArgs.cacheHandler.Destroy())
+	cacheHandler, cache := newCacheHandlerForRestart(t, fileCacheConfig, cacheDir)
+	require.NoError(t, cacheHandler.EnablePersistentIndex(indexDir, testMountPoint))
+	_, err := os.Stat(indexPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	cacheHandler.RestoreIndex(context.Background(), chTestArgs.bucket)
+
+	assert.True(t, isEntryInFileInfoCache(t, cache, minObject.Name, chTestArgs.bucket.Name()))
+	cacheHandle, err := cacheHandler.GetCacheHandle(minObject, chTestArgs.bucket, false, 0)
+	require.NoError(t, err)
+	defer cacheHandle.Close()
+	buf := make([]byte, minObject.Size)
+	n, cacheHit, err := cacheHandle.Read(context.Background(), chTestArgs.bucket, minObject, 0, buf)
+	require.NoError(t, err)
+	assert.True(t, cacheHit)
+	assert.Equal(t, "content of object_1", string(buf[:n]))
+}
+
+func Test_RestoreIndex_RemovesChangedObjects(t *testing.T) {
+	cacheDir := t.TempDir()
+	indexDir := path.Join(cacheDir, util.FileCacheIndex)
+	fileCacheConfig := &cfg.FileCacheConfig{EnableCrc: true}
+	chTestArgs := initializeCacheHandlerTestArgs(t, fileCacheConfig, cacheDir)
+	require.NoError(t, chTestArgs.cacheHandler.EnablePersistentIndex(indexDir, testMountPoint))
+	minObject := createObject(t, chTestArgs.bucket, "object_1", []byte("content of object_1"))
+	cacheObjectFully(t, chTestArgs, minObject)
+	require.NoError(t, chTestArgs.cacheHandler.Destroy())
+	// Overwrite the object while unmounted.
+	createObject(t, chTestArgs.bucket, "object_1", []byte("new content of object_1"))
+	cacheHandler, cache := newCacheHandlerForRestart(t, fileCacheConfig, cacheDir)
+	require.NoError(t, cacheHandler.EnablePersistentIndex(indexDir, testMountPoint))
+
+	cacheHandler.RestoreIndex(context.Background(), chTestArgs.bucket)
+
+	assert.False(t, isEntryInFileInfoCache(t, cache, minObject.Name, chTestArgs.bucket.Name()))
+	downloadPath := util.GetDownloadPath(cacheDir, util.GetObjectPath(chTestArgs.bucket.Name(), minObject.Name))
+	assert.False(t, doesFileExist(t, downloadPath))
+}
+
+func Test_RestoreIndex_RestoresSparseEntries(t *testing.T) {
+	cacheDir := t.TempDir()
+	indexDir := path.Join(cacheDir, util.FileCacheIndex)
+	fileCacheConfig := &cfg.FileCacheConfig{ExperimentalEnableChunkCache: true, DownloadChunkSizeMb: 1}
+	chTestArgs := initializeCacheHandlerTestArgs(t, fileCacheConfig, cacheDir)
+	indexPath := util.GetIndexPath(indexDir, chTestArgs.bucket.Name(), testMountPoint)
+	chunkSize := uint64(util.MiB)
+	// Pretend the first and third chunks of the test object were downloaded.
+	index := cacheIndex{Version: indexVersion, Entries: []indexEntry{{
+		BucketName:      chTestArgs.bucket.Name(),
+		ObjectName:      chTestArgs.object.Name,
+		Generation:      chTestArgs.object.Generation,
+		FileSize:        chTestArgs.object.Size,
+		Sparse:          true,
+		SparseChunkSize: chunkSize,
+		SparseChunks:    []uint64{0, 2},
+	}}}
+	content, err := json.Marshal(&index)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(indexDir, util.DefaultDirPerm))
+	require.NoError(t, os.WriteFile(indexPath, content, util.DefaultFilePerm))
+	cacheHandler, cache := newCacheHandlerForRestart(t, fileCacheConfig, cacheDir)
+	require.NoError(t, cacheHandler.EnablePersistentIndex(indexDir, testMountPoint))
+
+	cacheHandler.RestoreIndex(context.Background(), chTestArgs.bucket)
+
+	fileInfo := cache.LookUpWithoutChangingOrder(chTestArgs.fileInfoKeyName)
+	require.NotNil(t, fileInfo)
+	restored := fileInfo.(data.FileInfo)
+	assert.True(t, restored.SparseMode)
+	assert.True(t, restored.DownloadedChunks.ContainsRange(0, chunkSize))
+	assert.False(t, restored.DownloadedChunks.ContainsRange(chunkSize, 2*chunkSize))
+	assert.True(t, restored.DownloadedChunks.ContainsRange(2*chunkSize, 3*chunkSize))
+	assert.Equal(t, []uint64{0, 2}, restored.DownloadedChunks.Chunks())
+}
+
+func Test_EnablePersistentIndex_IgnoresMalformedIndex(t *testing.T) {
+	cacheDir := t.TempDir()
+	indexDir := path.Join(cacheDir, util.FileCacheIndex)
+	indexPath := util.GetIndexPath(indexDir, "some_bucket", testMountPoint)
+	require.NoError(t, os.MkdirAll(indexDir, util.DefaultDirPerm))
+	require.NoError(t, os.WriteFile(indexPath, []byte("{not json"), util.DefaultFilePerm))
+	cacheHandler, _ := newCacheHandlerForRestart(t, &cfg.FileCacheConfig{}, cacheDir)
+
+	err := cacheHandler.EnablePersistentIndex(indexDir, testMountPoint)
+
+	require.NoError(t, err)
+	assert.Empty(t, cacheHandler.restoredIndex)
+	_, err = os.Stat(indexPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_EnablePersistentIndex_KeysIndexesByBucketAndMount(t *testing.T) {
+	cacheDir := t.TempDir()
+	indexDir := path.Join(cacheDir, util.FileCacheIndex)
+	require.NoError(t, os.MkdirAll(indexDir, util.DefaultDirPerm))
+	writeIndex := func(bucketName, mountPoint string) string {
+		index := cacheIndex{Version: indexVersion, Entries: []indexEntry{{BucketName: bucketName, ObjectName: "object_1", Generation: 1, FileSize: 10}}}
+		content, err := json.Marshal(&index)
+		require.NoError(t, err)
+		indexPath := util.GetIndexPath(indexDir, bucketName, mountPoint)
+		require.NoError(t, os.WriteFile(indexPath, content, util.DefaultFilePerm))
+		return indexPath
+	}
+	otherBucketIndexPath := writeIndex("other_bucket", testMountPoint)
+	otherMountIndexPath := writeIndex("some_bucket", "/other/mount/point")
+	cacheHandler, _ := newCacheHandlerForRestart(t, &cfg.FileCacheConfig{}, cacheDir)
+
+	require.NoError(t, cacheHandler.EnablePersistentIndex(indexDir, testMountPoint))
+
+	assert.Equal(t, []string{"other_bucket"}, slices.Collect(maps.Keys(cacheHandler.restoredIndex)))
+	_, err := os.Stat(otherBucketIndexPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(otherMountIndexPath)
+	assert.NoError(t, err)
+	// The entries of buckets which haven't been set up are saved again.
+	require.NoError(t, cacheHandler.Destroy())
+	index, err := readIndex(otherBucketIndexPath)
+	require.NoError(t, err)
+	assert.Len(t, index.Entries, 1)
+}
@@ -162,6 +162,11 @@ func (jm *JobManager) DownloadChunkSizeMb() int64 {
 	return jm.fileCacheConfig.DownloadChunkSizeMb
 }
 
+// EnableCrc returns true if the checksums of downloaded files are validated.
+func (jm *JobManager) EnableCrc() bool {
+	return jm.fileCacheConfig.EnableCrc
+}
+
 // Destroy invalidates and deletes all the jobs that job manager is managing.
 //
 // Acquires and releases Lock(jm.mu)
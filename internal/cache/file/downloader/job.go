@@ -543,7 +543,7 @@ func (job *Job) GetStatus() JobStatus {
 // In case of mismatch deletes the file and corresponding entry from file cache.
 func (job *Job) validateCRC() (err error) {
 	// Todo (b/446440219): Enable crc check for rapid buckets once it is fixed
-	if !job.fileCacheConfig.EnableCrc || job.bucket.BucketType().IsRapid() {
+	if !job.fileCacheConfig.EnableCrc || job.bucket.BucketType().IsRapid() || job.object.CRC32C == nil {
 		return
 	}
 
@@ -0,0 +1,344 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/block"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/sync/semaphore"
+)
+
+// seenChunksPerBlock bounds the number of chunks read from the file cache
+// that a RAMTier remembers, per block it can hold, to find those read again.
+const seenChunksPerBlock = 8
+
+// RAMTier keeps hot chunks of cached files in memory in front of the file
+// cache, so that they're served across file handles without reading the cache
+// directory. Chunks are promoted from the file cache when data already read
+// from them is read again, and demoted by dropping them from memory when the
+// least recently used one makes room for another, since the file cache still
+// holds their data.
+//
+// The memory of the chunks is accounted for by the global semaphore of read
+// blocks, so the tier only grows while blocks aren't needed by buffered reads,
+// and gives its blocks up to buffered readers which can't get any.
+type RAMTier struct {
+	mu sync.Mutex
+
+	chunkSize int64
+
+	// The file cache entries of the files, which are used again whenever their
+	// chunks are read from memory so that they aren't evicted before colder
+	// ones.
+	fileInfoCache *lru.Cache
+
+	// GUARDED_BY(mu)
+	blockPool *block.GenBlockPool[block.PrefetchBlock]
+
+	// The chunks in memory, most recently used at the front.
+	//
+	// GUARDED_BY(mu)
+	lru *list.List
+
+	// GUARDED_BY(mu)
+	chunks map[ramTierKey]*list.Element
+
+	// The end of the data read so far from the file cache in the chunks that
+	// aren't in memory, which is forgotten once maxSeen chunks are tracked.
+	//
+	// GUARDED_BY(mu)
+	seen    map[ramTierKey]int64
+	maxSeen int
+}
+
+type ramTierKey struct {
+	bucketName string
+	objectName string
+	generation int64
+	index      int64
+}
+
+type ramTierChunk struct {
+	key   ramTierKey
+	block block.PrefetchBlock
+}
+
+// NewRAMTier creates a tier of at most maxChunks chunks of chunkSize bytes in
+// front of the files of fileInfoCache, each of which holds a permit of
+// globalMaxBlocksSem.
+func NewRAMTier(chunkSize int64, maxChunks int64, globalMaxBlocksSem *semaphore.Weighted, fileInfoCache *lru.Cache) (*RAMTier, error) {
+	blockPool, err := block.NewPrefetchBlockPool(chunkSize, maxChunks, 0, globalMaxBlocksSem)
+	if err != nil {
+		return nil, fmt.Errorf("NewPrefetchBlockPool: %w", err)
+	}
+	return &RAMTier{
+		chunkSize:     chunkSize,
+		fileInfoCache: fileInfoCache,
+		blockPool:     blockPool,
+		lru:           list.New(),
+		chunks:        make(map[ramTierKey]*list.Element),
+		seen:          make(map[ramTierKey]int64),
+		maxSeen:       int(maxChunks) * seenChunksPerBlock,
+	}, nil
+}
+
+func (rt *RAMTier) key(bucket gcs.Bucket, object *gcs.MinObject, index int64) ramTierKey {
+	return ramTierKey{
+		bucketName: bucket.Name(),
+		objectName: object.Name,
+		generation: object.Generation,
+		index:      index,
+	}
+}
+
+// chunkLen returns the number of bytes of the object in the chunk of the
+// given index.
+func (rt *RAMTier) chunkLen(object *gcs.MinObject, index int64) int64 {
+	return min(rt.chunkSize, int64(object.Size)-index*rt.chunkSize)
+}
+
+// ReadAt reads the data of the object at the given offset into dst if all of
+// it is in memory, and returns whether it was.
+//
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) ReadAt(bucket gcs.Bucket, object *gcs.MinObject, offset int64, dst []byte) (n int, hit bool) {
+	if offset < 0 || offset >= int64(object.Size) {
+		return 0, false
+	}
+	end := min(offset+int64(len(dst)), int64(object.Size))
+
+	n, hit = rt.readAt(bucket, object, offset, end, dst)
+	if hit {
+		rt.touch(bucket, object)
+	}
+	return n, hit
+}
+
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) readAt(bucket gcs.Bucket, object *gcs.MinObject, offset int64, end int64, dst []byte) (n int, hit bool) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	// Check that all the chunks are present before copying any of them.
+	var chunks []*ramTierChunk
+	for index := offset / rt.chunkSize; index*rt.chunkSize < end; index++ {
+		e, ok := rt.chunks[rt.key(bucket, object, index)]
+		if !ok {
+			return 0, false
+		}
+		chunks = append(chunks, e.Value.(*ramTierChunk))
+	}
+
+	for _, c := range chunks {
+		start := c.key.index * rt.chunkSize
+		m, err := c.block.ReadAt(dst[n:end-offset], offset+int64(n)-start)
+		if err != nil && err != io.EOF {
+			logger.Warnf("RAMTier.ReadAt: reading chunk %d of %q: %v", c.key.index, object.Name, err)
+			return 0, false
+		}
+		n += m
+		rt.lru.MoveToFront(rt.chunks[c.key])
+	}
+	return n, true
+}
+
+// touch uses the file cache entry of the object, as a read from the cache
+// directory would.
+func (rt *RAMTier) touch(bucket gcs.Bucket, object *gcs.MinObject) {
+	key, err := data.FileInfoKey{BucketName: bucket.Name(), ObjectName: object.Name}.Key()
+	if err != nil {
+		return
+	}
+	rt.fileInfoCache.LookUp(key)
+}
+
+// Promote is called after size bytes at the given offset of the object were
+// read from the file in cache through the cache handle. It copies the chunks
+// covering them into memory if that data was already read from them before,
+// skipping chunks that are already there or that aren't fully downloaded yet.
+//
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) Promote(bucket gcs.Bucket, object *gcs.MinObject, offset int64, size int, fch *CacheHandle) {
+	end := min(offset+int64(size), int64(object.Size))
+	for index := offset / rt.chunkSize; index*rt.chunkSize < end; index++ {
+		key := rt.key(bucket, object, index)
+		start := index * rt.chunkSize
+		b := rt.getBlock(key, max(offset, start), min(end, start+rt.chunkSize))
+		if b == nil {
+			continue
+		}
+
+		// Read without holding the lock, so that reads of other chunks aren't
+		// delayed by the file cache.
+		length := rt.chunkLen(object, index)
+		r := io.NewSectionReader(downloadedReaderAt{bucket: bucket, object: object, fch: fch}, start, length)
+		if _, err := io.Copy(b, r); err != nil || b.Size() != length {
+			rt.releaseBlock(b)
+			continue
+		}
+		rt.insert(key, b)
+	}
+}
+
+// getBlock records that the data between the given offsets of the chunk of
+// the given key was read from the file cache. If some of it was read before, it
+// returns an empty block for the chunk, demoting the least recently used chunk
+// if no more blocks can be allocated. It returns nil if the chunk is already in
+// memory, isn't hot yet or there is no block to use.
+//
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) getBlock(key ramTierKey, readStart int64, readEnd int64) block.PrefetchBlock {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if _, ok := rt.chunks[key]; ok {
+		return nil
+	}
+	seenEnd, ok := rt.seen[key]
+	if !ok || readStart >= seenEnd {
+		if !ok && len(rt.seen) >= rt.maxSeen {
+			clear(rt.seen)
+		}
+		rt.seen[key] = max(seenEnd, readEnd)
+		return nil
+	}
+	delete(rt.seen, key)
+
+	if b, err := rt.blockPool.TryGet(); err == nil {
+		return b
+	}
+
+	e := rt.lru.Back()
+	if e == nil {
+		return nil
+	}
+	c := rt.lru.Remove(e).(*ramTierChunk)
+	delete(rt.chunks, c.key)
+	c.block.Reuse()
+	return c.block
+}
+
+// insert adds the block holding the chunk of the given key to the tier,
+// unless another goroutine promoted the chunk in the meantime.
+//
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) insert(key ramTierKey, b block.PrefetchBlock) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if _, ok := rt.chunks[key]; ok {
+		rt.freeBlockLocked(b)
+		return
+	}
+	rt.chunks[key] = rt.lru.PushFront(&ramTierChunk{key: key, block: b})
+}
+
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) releaseBlock(b block.PrefetchBlock) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.freeBlockLocked(b)
+}
+
+// freeBlockLocked frees the memory of a block that is no longer used and
+// returns its permit to the global semaphore.
+//
+// LOCKS_REQUIRED(rt.mu)
+func (rt *RAMTier) freeBlockLocked(b block.PrefetchBlock) {
+	rt.blockPool.Release(b)
+	if err := rt.blockPool.ClearFreeBlockChannel(false); err != nil {
+		logger.Errorf("RAMTier: freeing a block: %v", err)
+	}
+}
+
+// ReleaseBlocks drops up to count of the least recently used chunks from
+// memory, returning their blocks to the global semaphore, e.g. for a buffered
+// reader which can't get any. It returns the number of blocks released.
+//
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) ReleaseBlocks(count int64) int64 {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	var released int64
+	for ; released < count; released++ {
+		e := rt.lru.Back()
+		if e == nil {
+			break
+		}
+		c := rt.lru.Remove(e).(*ramTierChunk)
+		delete(rt.chunks, c.key)
+		rt.freeBlockLocked(c.block)
+	}
+	return released
+}
+
+// Invalidate drops all the chunks of the given object from memory.
+//
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) Invalidate(bucketName string, objectName string) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	for key, e := range rt.chunks {
+		if key.bucketName == bucketName && key.objectName == objectName {
+			c := rt.lru.Remove(e).(*ramTierChunk)
+			delete(rt.chunks, key)
+			rt.freeBlockLocked(c.block)
+		}
+	}
+	for key := range rt.seen {
+		if key.bucketName == bucketName && key.objectName == objectName {
+			delete(rt.seen, key)
+		}
+	}
+}
+
+// Destroy drops all the chunks and frees their memory.
+//
+// Acquires and releases LOCK(rt.mu)
+func (rt *RAMTier) Destroy() error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	for e := rt.lru.Front(); e != nil; e = e.Next() {
+		rt.blockPool.Release(e.Value.(*ramTierChunk).block)
+	}
+	rt.lru.Init()
+	clear(rt.chunks)
+	clear(rt.seen)
+	return rt.blockPool.ClearFreeBlockChannel(true)
+}
+
+// downloadedReaderAt reads the downloaded data of an object through a cache
+// handle.
+type downloadedReaderAt struct {
+	bucket gcs.Bucket
+	object *gcs.MinObject
+	fch    *CacheHandle
+}
+
+func (r downloadedReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.fch.ReadDownloaded(r.bucket, r.object, off, p)
+}
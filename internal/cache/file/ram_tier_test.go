@@ -0,0 +1,188 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/data"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/semaphore"
+)
+
+const ramTierTestContent = "content of object_1"
+
+// newRAMTierTestArgs returns cache handler test args whose handler has a RAM
+// tier of chunks of 8 bytes, along with a fully cached object spanning three
+// chunks and a cache handle for it.
+func newRAMTierTestArgs(t *testing.T, maxChunks int64, sem *semaphore.Weighted) (*cacheHandlerTestArgs, *gcs.MinObject, *CacheHandle) {
+	t.Helper()
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{}, t.TempDir())
+	require.NoError(t, chTestArgs.cacheHandler.EnableRAMTier(8, maxChunks, sem))
+	t.Cleanup(func() {
+		assert.NoError(t, chTestArgs.cacheHandler.RAMTier().Destroy())
+	})
+	object := createObject(t, chTestArgs.bucket, "object_1", []byte(ramTierTestContent))
+	cacheObjectFully(t, chTestArgs, object)
+	cacheHandle, err := chTestArgs.cacheHandler.GetCacheHandle(object, chTestArgs.bucket, false, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, cacheHandle.Close())
+	})
+	return chTestArgs, object, cacheHandle
+}
+
+// readAgain reports size bytes at the given offset as read twice from the file
+// in cache, so that the chunks covering them are promoted.
+func readAgain(chTestArgs *cacheHandlerTestArgs, object *gcs.MinObject, offset int64, size int, cacheHandle *CacheHandle) {
+	for range 2 {
+		chTestArgs.cacheHandler.RAMTier().Promote(chTestArgs.bucket, object, offset, size, cacheHandle)
+	}
+}
+
+func Test_RAMTier_ReadAtMissesBeforePromotion(t *testing.T) {
+	chTestArgs, object, _ := newRAMTierTestArgs(t, 3, semaphore.NewWeighted(3))
+	buf := make([]byte, 4)
+
+	_, hit := chTestArgs.cacheHandler.RAMTier().ReadAt(chTestArgs.bucket, object, 0, buf)
+
+	assert.False(t, hit)
+}
+
+func Test_RAMTier_ReadAtAfterPromotion(t *testing.T) {
+	chTestArgs, object, cacheHandle := newRAMTierTestArgs(t, 3, semaphore.NewWeighted(3))
+	ramTier := chTestArgs.cacheHandler.RAMTier()
+	readAgain(chTestArgs, object, 0, len(ramTierTestContent), cacheHandle)
+	// Reads across chunks and past the end of the object are served.
+	buf := make([]byte, 32)
+
+	n, hit := ramTier.ReadAt(chTestArgs.bucket, object, 3, buf)
+
+	assert.True(t, hit)
+	assert.Equal(t, ramTierTestContent[3:], string(buf[:n]))
+}
+
+func Test_RAMTier_PromoteDemotesLeastRecentlyUsedChunk(t *testing.T) {
+	chTestArgs, object, cacheHandle := newRAMTierTestArgs(t, 2, semaphore.NewWeighted(2))
+	ramTier := chTestArgs.cacheHandler.RAMTier()
+	buf := make([]byte, 4)
+
+	readAgain(chTestArgs, object, 0, len(ramTierTestContent), cacheHandle)
+
+	_, hit := ramTier.ReadAt(chTestArgs.bucket, object, 0, buf)
+	assert.False(t, hit)
+	n, hit := ramTier.ReadAt(chTestArgs.bucket, object, 16, buf)
+	assert.True(t, hit)
+	assert.Equal(t, ramTierTestContent[16:], string(buf[:n]))
+}
+
+func Test_RAMTier_SharesGlobalBlocks(t *testing.T) {
+	// A reader holds one of the two blocks.
+	sem := semaphore.NewWeighted(2)
+	require.True(t, sem.TryAcquire(1))
+	chTestArgs, object, cacheHandle := newRAMTierTestArgs(t, 3, sem)
+	ramTier := chTestArgs.cacheHandler.RAMTier()
+	buf := make([]byte, 4)
+
+	readAgain(chTestArgs, object, 0, 16, cacheHandle)
+
+	_, hit := ramTier.ReadAt(chTestArgs.bucket, object, 0, buf)
+	assert.False(t, hit)
+	_, hit = ramTier.ReadAt(chTestArgs.bucket, object, 8, buf)
+	assert.True(t, hit)
+	assert.False(t, sem.TryAcquire(1))
+}
+
+func Test_RAMTier_PromotesOnlyDataReadAgain(t *testing.T) {
+	chTestArgs, object, cacheHandle := newRAMTierTestArgs(t, 3, semaphore.NewWeighted(3))
+	ramTier := chTestArgs.cacheHandler.RAMTier()
+	buf := make([]byte, 4)
+	// Reading a chunk sequentially doesn't make it hot.
+	ramTier.Promote(chTestArgs.bucket, object, 0, 4, cacheHandle)
+	ramTier.Promote(chTestArgs.bucket, object, 4, 4, cacheHandle)
+	_, hit := ramTier.ReadAt(chTestArgs.bucket, object, 0, buf)
+	require.False(t, hit)
+
+	ramTier.Promote(chTestArgs.bucket, object, 2, 4, cacheHandle)
+
+	n, hit := ramTier.ReadAt(chTestArgs.bucket, object, 0, buf)
+	assert.True(t, hit)
+	assert.Equal(t, ramTierTestContent[:4], string(buf[:n]))
+}
+
+func Test_RAMTier_ReleaseBlocks(t *testing.T) {
+	sem := semaphore.NewWeighted(3)
+	chTestArgs, object, cacheHandle := newRAMTierTestArgs(t, 3, sem)
+	ramTier := chTestArgs.cacheHandler.RAMTier()
+	readAgain(chTestArgs, object, 0, len(ramTierTestContent), cacheHandle)
+	require.False(t, sem.TryAcquire(1))
+
+	released := ramTier.ReleaseBlocks(2)
+
+	assert.EqualValues(t, 2, released)
+	assert.True(t, sem.TryAcquire(2))
+	// The most recently used chunk is kept.
+	_, hit := ramTier.ReadAt(chTestArgs.bucket, object, 16, make([]byte, 4))
+	assert.True(t, hit)
+	assert.EqualValues(t, 1, ramTier.ReleaseBlocks(2))
+}
+
+func Test_RAMTier_ReadAtUsesFileCacheEntry(t *testing.T) {
+	chTestArgs, object, cacheHandle := newRAMTierTestArgs(t, 3, semaphore.NewWeighted(3))
+	ramTier := chTestArgs.cacheHandler.RAMTier()
+	readAgain(chTestArgs, object, 0, len(ramTierTestContent), cacheHandle)
+	// Another cached object becomes the most recently used.
+	other := createObject(t, chTestArgs.bucket, "object_2", []byte(ramTierTestContent))
+	cacheObjectFully(t, chTestArgs, other)
+	otherKey, err := data.FileInfoKey{BucketName: chTestArgs.bucket.Name(), ObjectName: other.Name}.Key()
+	require.NoError(t, err)
+
+	_, hit := ramTier.ReadAt(chTestArgs.bucket, object, 0, make([]byte, 4))
+
+	require.True(t, hit)
+	var oldest []string
+	chTestArgs.cache.ForEachOldestFirst(func(key string, _ lru.ValueType) {
+		oldest = append(oldest, key)
+	})
+	assert.Equal(t, otherKey, oldest[0])
+}
+
+func Test_RAMTier_InvalidateCacheDropsChunks(t *testing.T) {
+	chTestArgs, object, cacheHandle := newRAMTierTestArgs(t, 3, semaphore.NewWeighted(3))
+	ramTier := chTestArgs.cacheHandler.RAMTier()
+	readAgain(chTestArgs, object, 0, len(ramTierTestContent), cacheHandle)
+
+	require.NoError(t, chTestArgs.cacheHandler.InvalidateCache(object.Name, chTestArgs.bucket.Name()))
+
+	_, hit := ramTier.ReadAt(chTestArgs.bucket, object, 0, make([]byte, 4))
+	assert.False(t, hit)
+}
+
+func Test_ReadDownloaded_FallsBackIfNotDownloaded(t *testing.T) {
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{}, t.TempDir())
+	object := createObject(t, chTestArgs.bucket, "object_1", []byte(ramTierTestContent))
+	cacheHandle, err := chTestArgs.cacheHandler.GetCacheHandle(object, chTestArgs.bucket, true, 0)
+	require.NoError(t, err)
+	defer cacheHandle.Close()
+
+	_, err = cacheHandle.ReadDownloaded(chTestArgs.bucket, object, 0, make([]byte, 4))
+
+	assert.ErrorIs(t, err, util.ErrFallbackToGCS)
+}
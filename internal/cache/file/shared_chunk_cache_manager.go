@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sync"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
@@ -47,10 +48,17 @@ type SharedChunkCacheManager struct {
 	// dirPerm parameter specifies the permission of cache directory
 	dirPerm os.FileMode
 
+	// mu guards the regexes, which may be replaced while the manager is in use.
+	mu sync.RWMutex
+
 	// excludeRegex is the compiled regex for excluding files from cache
+	//
+	// GUARDED_BY(mu)
 	excludeRegex *regexp.Regexp
 
 	// includeRegex is the compiled regex for including files from cache
+	//
+	// GUARDED_BY(mu)
 	includeRegex *regexp.Regexp
 
 	// config contains file cache configuration
@@ -67,39 +75,51 @@ func NewSharedChunkCacheManager(
 	// Determine chunk size
 	chunkSize := config.SharedCacheChunkSizeMb * 1024 * 1024
 
+	handler := &SharedChunkCacheManager{
+		cacheDir:  cacheDir,
+		chunkSize: chunkSize,
+		filePerm:  filePerm,
+		dirPerm:   dirPerm,
+		config:    config,
+	}
+	handler.SetRegexes(config.ExcludeRegex, config.IncludeRegex)
+
+	return handler, nil
+}
+
+// SetRegexes replaces the regexes for excluding and including files from
+// cache, e.g. when the config is reloaded.
+func (sccm *SharedChunkCacheManager) SetRegexes(excludeRegex string, includeRegex string) {
 	// Compile regex patterns
 	var err error
-	var excludeRegex, includeRegex *regexp.Regexp
-	if config.ExcludeRegex != "" {
-		excludeRegex, err = regexp.Compile(config.ExcludeRegex)
+	var compiledExcludeRegex, compiledIncludeRegex *regexp.Regexp
+	if excludeRegex != "" {
+		compiledExcludeRegex, err = regexp.Compile(excludeRegex)
 		if err != nil {
-			logger.Warnf("Failed to compile exclude regex %q: %v", config.ExcludeRegex, err)
+			logger.Warnf("Failed to compile exclude regex %q: %v", excludeRegex, err)
 		}
 	}
-	if config.IncludeRegex != "" {
-		includeRegex, err = regexp.Compile(config.IncludeRegex)
+	if includeRegex != "" {
+		compiledIncludeRegex, err = regexp.Compile(includeRegex)
 		if err != nil {
-			logger.Warnf("Failed to compile include regex %q: %v", config.IncludeRegex, err)
+			logger.Warnf("Failed to compile include regex %q: %v", includeRegex, err)
 		}
 	}
 
-	handler := &SharedChunkCacheManager{
-		cacheDir:     cacheDir,
-		chunkSize:    chunkSize,
-		filePerm:     filePerm,
-		dirPerm:      dirPerm,
-		excludeRegex: excludeRegex,
-		includeRegex: includeRegex,
-		config:       config,
-	}
+	sccm.mu.Lock()
+	defer sccm.mu.Unlock()
 
-	return handler, nil
+	sccm.excludeRegex = compiledExcludeRegex
+	sccm.includeRegex = compiledIncludeRegex
 }
 
 // ShouldExcludeFromCache checks if the file should be excluded from caching.
 func (sccm *SharedChunkCacheManager) ShouldExcludeFromCache(bucket gcs.Bucket, object *gcs.MinObject) bool {
 	objectPath := filepath.Join(bucket.Name(), object.Name)
 
+	sccm.mu.RLock()
+	defer sccm.mu.RUnlock()
+
 	// If include regex is set, only include matching files
 	if sccm.includeRegex != nil {
 		if !sccm.includeRegex.MatchString(objectPath) {
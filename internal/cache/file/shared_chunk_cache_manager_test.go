@@ -135,6 +135,17 @@ func TestSharedChunkCacheManager_ShouldExcludeFromCache(t *testing.T) {
 	}
 }
 
+func TestSharedChunkCacheManager_SetRegexes(t *testing.T) {
+	manager, err := NewSharedChunkCacheManager(t.TempDir(), 0644, 0755, &cfg.FileCacheConfig{ExcludeRegex: ".*\\.log$"})
+	require.NoError(t, err)
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+
+	manager.SetRegexes("", ".*\\.log$")
+
+	assert.False(t, manager.ShouldExcludeFromCache(bucket, &gcs.MinObject{Name: "file.log"}))
+	assert.True(t, manager.ShouldExcludeFromCache(bucket, &gcs.MinObject{Name: "file.txt"}))
+}
+
 func TestSharedChunkCacheManager_GetChunkIndex(t *testing.T) {
 	// Arrange
 	tmpDir := t.TempDir()
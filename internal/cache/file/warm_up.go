@@ -0,0 +1,256 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/sync/errgroup"
+)
+
+// warmUpProgressInterval is the interval at which the progress of a warm-up
+// is logged.
+const warmUpProgressInterval = 10 * time.Second
+
+// globMetaChars are the characters which make a line of a warm-up manifest a
+// glob rather than a prefix.
+const globMetaChars = `*?[\`
+
+// WarmUpStats describes the progress of a warm-up of the file cache.
+type WarmUpStats struct {
+	// Objects and Bytes are the number and total size of the objects matching
+	// the manifest.
+	Objects int64
+	Bytes   uint64
+
+	// DownloadedObjects and DownloadedBytes account for the objects which are
+	// in cache, including the ones which already were.
+	DownloadedObjects int64
+	DownloadedBytes   uint64
+
+	// SkippedObjects are excluded from the cache by regex.
+	SkippedObjects int64
+
+	FailedObjects int64
+}
+
+// warmUpProgress is updated concurrently by the workers of a warm-up.
+type warmUpProgress struct {
+	objects           int64
+	bytes             uint64
+	downloadedObjects atomic.Int64
+	downloadedBytes   atomic.Uint64
+	skippedObjects    atomic.Int64
+	failedObjects     atomic.Int64
+}
+
+func (p *warmUpProgress) stats() WarmUpStats {
+	return WarmUpStats{
+		Objects:           p.objects,
+		Bytes:             p.bytes,
+		DownloadedObjects: p.downloadedObjects.Load(),
+		DownloadedBytes:   p.downloadedBytes.Load(),
+		SkippedObjects:    p.skippedObjects.Load(),
+		FailedObjects:     p.failedObjects.Load(),
+	}
+}
+
+func (s WarmUpStats) String() string {
+	return fmt.Sprintf("%d of %d objects (%d of %d MiB) in cache, %d skipped, %d failed",
+		s.DownloadedObjects, s.Objects, s.DownloadedBytes/util.MiB, s.Bytes/util.MiB, s.SkippedObjects, s.FailedObjects)
+}
+
+// ReadWarmUpManifest returns the patterns of the warm-up manifest at the
+// given path, one per line. Empty lines and lines starting with # are ignored.
+//
+// A pattern containing any of *?[\ is a glob as supported by path.Match, so *
+// doesn't match /, and any other pattern is a prefix of object names.
+func ReadWarmUpManifest(manifestPath string) ([]string, error) {
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("ReadWarmUpManifest: %w", err)
+	}
+
+	var patterns []string
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return nil, fmt.Errorf("ReadWarmUpManifest: line %d of %s: %q: %w", i+1, manifestPath, line, err)
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// listWarmUpObjects returns the objects of the bucket matching any of the
+// given patterns, each one once. Placeholder objects of directories are
+// ignored.
+func listWarmUpObjects(ctx context.Context, bucket gcs.Bucket, patterns []string) ([]*gcs.MinObject, error) {
+	seen := make(map[string]bool)
+	var objects []*gcs.MinObject
+	for _, pattern := range patterns {
+		prefix, isGlob := pattern, false
+		if i := strings.IndexAny(pattern, globMetaChars); i >= 0 {
+			prefix, isGlob = pattern[:i], true
+		}
+
+		req := &gcs.ListObjectsRequest{Prefix: prefix}
+		for {
+			listing, err := bucket.ListObjects(ctx, req)
+			if err != nil {
+				return nil, fmt.Errorf("while listing objects with prefix %q: %w", prefix, err)
+			}
+			for _, object := range listing.MinObjects {
+				if strings.HasSuffix(object.Name, "/") || seen[object.Name] {
+					continue
+				}
+				if isGlob {
+					if matched, _ := path.Match(pattern, object.Name); !matched {
+						continue
+					}
+				}
+				seen[object.Name] = true
+				objects = append(objects, object)
+			}
+			if listing.ContinuationToken == "" {
+				break
+			}
+			req.ContinuationToken = listing.ContinuationToken
+		}
+	}
+	return objects, nil
+}
+
+// WarmUp downloads the objects of the given bucket matching any of the given
+// manifest patterns to the file cache, with at most parallelism objects
+// downloaded at a time, and logs the progress periodically. It returns once
+// all the objects are in cache or failed, with an error if any of them
+// couldn't be listed or downloaded.
+//
+// Objects which are larger than the cache, or which together exceed it, evict
+// one another like objects read through the file system.
+func (chr *CacheHandler) WarmUp(ctx context.Context, bucket gcs.Bucket, patterns []string, parallelism int) (WarmUpStats, error) {
+	objects, err := listWarmUpObjects(ctx, bucket, patterns)
+	if err != nil {
+		return WarmUpStats{}, fmt.Errorf("WarmUp: %w", err)
+	}
+
+	progress := &warmUpProgress{objects: int64(len(objects))}
+	for _, object := range objects {
+		progress.bytes += object.Size
+	}
+	logger.Infof("File Cache: warming up %d objects (%d MiB) of bucket %s", progress.objects, progress.bytes/util.MiB, bucket.Name())
+
+	done := make(chan struct{})
+	var reporter sync.WaitGroup
+	reporter.Add(1)
+	go func() {
+		defer reporter.Done()
+		ticker := time.NewTicker(warmUpProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				logger.Infof("File Cache: warm-up progress: %s", progress.stats())
+			}
+		}
+	}()
+
+	var firstErr error
+	var firstErrOnce sync.Once
+	group := new(errgroup.Group)
+	group.SetLimit(parallelism)
+	for _, object := range objects {
+		group.Go(func() error {
+			err := chr.warmUpObject(ctx, bucket, object)
+			switch {
+			case err == nil:
+				progress.downloadedObjects.Add(1)
+				progress.downloadedBytes.Add(object.Size)
+			case errors.Is(err, util.ErrFileExcludedFromCacheByRegex):
+				progress.skippedObjects.Add(1)
+			default:
+				progress.failedObjects.Add(1)
+				logger.Warnf("File Cache: failed to warm up %s:/%s: %v", bucket.Name(), object.Name, err)
+				firstErrOnce.Do(func() {
+					firstErr = fmt.Errorf("%s: %w", object.Name, err)
+				})
+			}
+			// Errors are reported once all the objects are processed, so that
+			// one failure doesn't prevent the others from being cached.
+			return nil
+		})
+	}
+	_ = group.Wait()
+	close(done)
+	reporter.Wait()
+
+	stats := progress.stats()
+	logger.Infof("File Cache: warm-up of bucket %s finished: %s", bucket.Name(), stats)
+	if firstErr != nil {
+		return stats, fmt.Errorf("WarmUp: %d of %d objects failed, first error: %w", stats.FailedObjects, stats.Objects, firstErr)
+	}
+	return stats, nil
+}
+
+// warmUpObject downloads the given object to the file cache, unless it's
+// already there, and waits for the download to complete.
+func (chr *CacheHandler) warmUpObject(ctx context.Context, bucket gcs.Bucket, object *gcs.MinObject) error {
+	cacheHandle, err := chr.GetCacheHandle(object, bucket, true, 0)
+	if err != nil {
+		return err
+	}
+	defer cacheHandle.Close()
+
+	// Files which are fully downloaded have no download job.
+	job := cacheHandle.fileDownloadJob
+	if job == nil {
+		return nil
+	}
+
+	if chr.isSparse {
+		_, err = job.HandleSparseRead(ctx, 0, int64(object.Size))
+		return err
+	}
+
+	jobStatus, err := job.Download(ctx, int64(object.Size), true)
+	if err != nil {
+		return err
+	}
+	switch jobStatus.Name {
+	case downloader.Failed:
+		return fmt.Errorf("download failed: %w", jobStatus.Err)
+	case downloader.Invalid:
+		return errors.New("download invalidated")
+	}
+	return nil
+}
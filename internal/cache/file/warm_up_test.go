@@ -0,0 +1,138 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createWarmUpObjects creates objects named after their content in the bucket
+// of the given test args.
+func createWarmUpObjects(t *testing.T, chTestArgs *cacheHandlerTestArgs, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		createObject(t, chTestArgs.bucket, name, []byte(name))
+	}
+}
+
+// cachedContent returns the content of the file in cache of the given object,
+// or an error if there's none.
+func cachedContent(chTestArgs *cacheHandlerTestArgs, objectName string) (string, error) {
+	content, err := os.ReadFile(util.GetDownloadPath(chTestArgs.cacheDir, util.GetObjectPath(chTestArgs.bucket.Name(), objectName)))
+	return string(content), err
+}
+
+func Test_ReadWarmUpManifest(t *testing.T) {
+	manifestPath := path.Join(t.TempDir(), "manifest")
+	require.NoError(t, os.WriteFile(manifestPath, []byte("# Hot dataset\n\ndata/train/\n  models/*.bin  \n"), 0644))
+
+	patterns, err := ReadWarmUpManifest(manifestPath)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data/train/", "models/*.bin"}, patterns)
+}
+
+func Test_ReadWarmUpManifest_InvalidGlob(t *testing.T) {
+	manifestPath := path.Join(t.TempDir(), "manifest")
+	require.NoError(t, os.WriteFile(manifestPath, []byte("data/\nmodels/[.bin\n"), 0644))
+
+	_, err := ReadWarmUpManifest(manifestPath)
+
+	assert.ErrorContains(t, err, "line 2")
+	assert.ErrorIs(t, err, path.ErrBadPattern)
+}
+
+func Test_WarmUp_DownloadsMatchingObjects(t *testing.T) {
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{}, t.TempDir())
+	createWarmUpObjects(t, chTestArgs, "data/train/1", "data/train/2", "models/a.bin", "models/a.json", "models/old/b.bin")
+
+	stats, err := chTestArgs.cacheHandler.WarmUp(context.Background(), chTestArgs.bucket, []string{"data/train/", "models/*.bin", "data/train/1"}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Objects)
+	assert.Equal(t, int64(3), stats.DownloadedObjects)
+	assert.Equal(t, stats.Bytes, stats.DownloadedBytes)
+	for _, name := range []string{"data/train/1", "data/train/2", "models/a.bin"} {
+		content, err := cachedContent(chTestArgs, name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, content)
+	}
+	for _, name := range []string{"models/a.json", "models/old/b.bin"} {
+		_, err := cachedContent(chTestArgs, name)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	}
+}
+
+func Test_WarmUp_ReusesCachedObjects(t *testing.T) {
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{}, t.TempDir())
+	createWarmUpObjects(t, chTestArgs, "data/1", "data/2")
+	_, err := chTestArgs.cacheHandler.WarmUp(context.Background(), chTestArgs.bucket, []string{"data/"}, 2)
+	require.NoError(t, err)
+
+	stats, err := chTestArgs.cacheHandler.WarmUp(context.Background(), chTestArgs.bucket, []string{"data/"}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.DownloadedObjects)
+	assert.Nil(t, chTestArgs.jobManager.GetJob("data/1", chTestArgs.bucket.Name()))
+}
+
+func Test_WarmUp_SkipsObjectsExcludedByRegex(t *testing.T) {
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{ExcludeRegex: `\.tmp$`}, t.TempDir())
+	createWarmUpObjects(t, chTestArgs, "data/1", "data/2.tmp")
+
+	stats, err := chTestArgs.cacheHandler.WarmUp(context.Background(), chTestArgs.bucket, []string{"data/"}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.DownloadedObjects)
+	assert.Equal(t, int64(1), stats.SkippedObjects)
+}
+
+func Test_WarmUp_SparseMode(t *testing.T) {
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{ExperimentalEnableChunkCache: true, DownloadChunkSizeMb: 1}, t.TempDir())
+	createWarmUpObjects(t, chTestArgs, "data/1", "data/2")
+
+	stats, err := chTestArgs.cacheHandler.WarmUp(context.Background(), chTestArgs.bucket, []string{"data/"}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.DownloadedObjects)
+	content, err := cachedContent(chTestArgs, "data/2")
+	assert.NoError(t, err)
+	assert.Equal(t, "data/2", content)
+}
+
+func Test_WarmUp_ReportsFailuresAfterCachingOtherObjects(t *testing.T) {
+	chTestArgs := initializeCacheHandlerTestArgs(t, &cfg.FileCacheConfig{}, t.TempDir())
+	createWarmUpObjects(t, chTestArgs, "data/1")
+	// The object doesn't fit in the cache.
+	createObject(t, chTestArgs.bucket, "data/2", make([]byte, HandlerCacheMaxSize+1))
+
+	stats, err := chTestArgs.cacheHandler.WarmUp(context.Background(), chTestArgs.bucket, []string{"data/"}, 2)
+
+	assert.ErrorContains(t, err, "1 of 2 objects failed")
+	assert.ErrorContains(t, err, "data/2")
+	assert.Equal(t, int64(1), stats.DownloadedObjects)
+	assert.Equal(t, int64(1), stats.FailedObjects)
+	content, err := cachedContent(chTestArgs, "data/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "data/1", content)
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"container/list"
+)
+
+// EvictionPolicy orders the entries of a Cache for eviction. Its methods are
+// called with the lock of the cache held, so implementations need not be
+// thread-safe.
+type EvictionPolicy interface {
+	// Add records the insertion of an entry of the given key and size, which
+	// isn't in the cache.
+	Add(key string, size uint64)
+
+	// Access records a look up or an overwrite of the entry of the given key,
+	// whose size is now the given one.
+	Access(key string, size uint64)
+
+	// Resize records that the entry of the given key, which wasn't looked up,
+	// is now of the given size, e.g. as more of it is downloaded.
+	Resize(key string, size uint64)
+
+	// Remove forgets the entry of the given key, which is erased from the cache.
+	Remove(key string)
+
+	// Evict forgets and returns the key of the next entry to evict other than
+	// the one of key keep, which has just been inserted. It's only called when
+	// there is such an entry.
+	Evict(keep string) string
+
+	// ForEach calls fn for the key of each entry, from the next one to be
+	// evicted to the last one.
+	ForEach(fn func(key string))
+
+	// Len returns the number of entries.
+	Len() int
+}
+
+// lruPolicy evicts the least recently used entry.
+type lruPolicy struct {
+	// Keys of the entries, with least recently used at the tail.
+	//
+	// INVARIANT: Each element is of type string
+	keys list.List
+
+	// Index of elements by key.
+	//
+	// INVARIANT: For each k, v: v.Value.(string) == k
+	// INVARIANT: Contains all and only the elements of keys
+	index map[string]*list.Element
+}
+
+// NewLRUPolicy returns a policy evicting the least recently used entry.
+func NewLRUPolicy() EvictionPolicy {
+	return &lruPolicy{
+		index: make(map[string]*list.Element),
+	}
+}
+
+func (p *lruPolicy) Add(key string, size uint64) {
+	p.index[key] = p.keys.PushFront(key)
+}
+
+func (p *lruPolicy) Access(key string, size uint64) {
+	p.keys.MoveToFront(p.index[key])
+}
+
+func (p *lruPolicy) Resize(key string, size uint64) {}
+
+func (p *lruPolicy) Remove(key string) {
+	p.keys.Remove(p.index[key])
+	delete(p.index, key)
+}
+
+func (p *lruPolicy) Evict(keep string) string {
+	e := backExcept(&p.keys, keep, func(v any) string { return v.(string) })
+	key := p.keys.Remove(e).(string)
+	delete(p.index, key)
+	return key
+}
+
+func (p *lruPolicy) ForEach(fn func(key string)) {
+	for e := p.keys.Back(); e != nil; e = e.Prev() {
+		fn(e.Value.(string))
+	}
+}
+
+func (p *lruPolicy) Len() int {
+	return len(p.index)
+}
+
+// backExcept returns the last element of l whose key, as returned by keyOf,
+// isn't keep, or nil if there's none.
+func backExcept(l *list.List, keep string, keyOf func(v any) string) *list.Element {
+	e := l.Back()
+	if e != nil && keyOf(e.Value) == keep {
+		e = e.Prev()
+	}
+	return e
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const policyTestMaxSize = 100
+
+var policies = map[string]func() lru.EvictionPolicy{
+	"lru":  lru.NewLRUPolicy,
+	"2q":   func() lru.EvictionPolicy { return lru.NewTwoQueuePolicy(policyTestMaxSize) },
+	"gdsf": lru.NewGDSFPolicy,
+}
+
+func newCacheWithPolicy(t *testing.T, name string) *lru.Cache {
+	t.Helper()
+	locker.EnableInvariantsCheck()
+	return lru.NewCacheWithPolicy(policyTestMaxSize, policies[name]())
+}
+
+func insert(t *testing.T, cache *lru.Cache, key string, size uint64) []lru.ValueType {
+	t.Helper()
+	evicted, err := cache.Insert(key, testData{DataSize: size})
+	require.NoError(t, err)
+	return evicted
+}
+
+// scan inserts count entries of the given size and name prefix that are never
+// looked up.
+func scan(t *testing.T, cache *lru.Cache, prefix string, count int, size uint64) {
+	t.Helper()
+	for i := range count {
+		insert(t, cache, fmt.Sprintf("%s-%d", prefix, i), size)
+	}
+}
+
+func TestPolicies_InsertKeepsInsertedEntry(t *testing.T) {
+	for name := range policies {
+		t.Run(name, func(t *testing.T) {
+			cache := newCacheWithPolicy(t, name)
+			insert(t, cache, "small", 1)
+			cache.LookUp("small")
+
+			evicted := insert(t, cache, "large", policyTestMaxSize)
+
+			assert.Len(t, evicted, 1)
+			assert.Nil(t, cache.LookUp("small"))
+			assert.NotNil(t, cache.LookUp("large"))
+		})
+	}
+}
+
+func TestPolicies_EraseEntriesWithGivenPrefix(t *testing.T) {
+	for name := range policies {
+		t.Run(name, func(t *testing.T) {
+			cache := newCacheWithPolicy(t, name)
+			insert(t, cache, "a/1", 10)
+			insert(t, cache, "a/2", 10)
+			insert(t, cache, "b/1", 10)
+
+			cache.EraseEntriesWithGivenPrefix("a/")
+
+			assert.Nil(t, cache.LookUp("a/1"))
+			assert.Nil(t, cache.LookUp("a/2"))
+			assert.NotNil(t, cache.LookUp("b/1"))
+			// The erased entries don't take space anymore.
+			assert.Empty(t, insert(t, cache, "c/1", 90))
+		})
+	}
+}
+
+func TestPolicies_UpdateSizeDefersEviction(t *testing.T) {
+	for name := range policies {
+		t.Run(name, func(t *testing.T) {
+			cache := newCacheWithPolicy(t, name)
+			insert(t, cache, "a", 50)
+			insert(t, cache, "b", 40)
+
+			require.NoError(t, cache.UpdateSize("b", 10))
+
+			assert.NotNil(t, cache.LookUpWithoutChangingOrder("a"))
+			assert.NotEmpty(t, insert(t, cache, "c", 1))
+			assert.ErrorIs(t, cache.UpdateSize("d", 1), lru.ErrEntryNotExist)
+		})
+	}
+}
+
+func TestPolicies_ForEachOldestFirstVisitsAllEntries(t *testing.T) {
+	for name := range policies {
+		t.Run(name, func(t *testing.T) {
+			cache := newCacheWithPolicy(t, name)
+			insert(t, cache, "a", 10)
+			insert(t, cache, "b", 10)
+			insert(t, cache, "c", 10)
+			var keys []string
+
+			cache.ForEachOldestFirst(func(key string, value lru.ValueType) {
+				keys = append(keys, key)
+			})
+
+			assert.ElementsMatch(t, []string{"a", "b", "c"}, keys)
+		})
+	}
+}
+
+func TestLRUPolicy_ScanEvictsHotEntry(t *testing.T) {
+	cache := newCacheWithPolicy(t, "lru")
+	insert(t, cache, "hot", 10)
+	cache.LookUp("hot")
+
+	scan(t, cache, "epoch", 10, 10)
+
+	assert.Nil(t, cache.LookUp("hot"))
+}
+
+func TestTwoQueuePolicy_ScanDoesNotEvictFrequentEntry(t *testing.T) {
+	cache := newCacheWithPolicy(t, "2q")
+	insert(t, cache, "hot", 10)
+	scan(t, cache, "epoch-1", 10, 10)
+	require.Nil(t, cache.LookUp("hot"))
+	// Inserting it again soon after its eviction makes it frequent.
+	insert(t, cache, "hot", 10)
+
+	scan(t, cache, "epoch-2", 20, 10)
+
+	assert.NotNil(t, cache.LookUp("hot"))
+}
+
+func TestTwoQueuePolicy_LookUpsOfRecentEntryDoNotProtectIt(t *testing.T) {
+	cache := newCacheWithPolicy(t, "2q")
+	insert(t, cache, "once", 10)
+	for range 10 {
+		cache.LookUp("once")
+	}
+
+	scan(t, cache, "epoch", 10, 10)
+
+	assert.Nil(t, cache.LookUp("once"))
+}
+
+func TestGDSFPolicy_PrefersSmallFrequentEntries(t *testing.T) {
+	cache := newCacheWithPolicy(t, "gdsf")
+	insert(t, cache, "tokenizer", 1)
+	cache.LookUp("tokenizer")
+	insert(t, cache, "shard-1", 60)
+	cache.LookUp("shard-1")
+
+	evicted := insert(t, cache, "shard-2", 60)
+
+	assert.Len(t, evicted, 1)
+	assert.NotNil(t, cache.LookUp("tokenizer"))
+	assert.Nil(t, cache.LookUp("shard-1"))
+}
+
+func TestGDSFPolicy_ForEachOldestFirstIsEvictionOrder(t *testing.T) {
+	cache := newCacheWithPolicy(t, "gdsf")
+	insert(t, cache, "large", 50)
+	insert(t, cache, "small", 5)
+	insert(t, cache, "medium", 20)
+	var keys []string
+
+	cache.ForEachOldestFirst(func(key string, value lru.ValueType) {
+		keys = append(keys, key)
+	})
+
+	assert.Equal(t, []string{"large", "medium", "small"}, keys)
+}
+
+// growingData is an entry whose size grows in place, like a sparse file.
+type growingData struct {
+	size *uint64
+}
+
+func (d growingData) Size() uint64 {
+	return *d.size
+}
+
+func TestGDSFPolicy_UpdateSizeLowersPriority(t *testing.T) {
+	cache := newCacheWithPolicy(t, "gdsf")
+	size := uint64(5)
+	_, err := cache.Insert("sparse", growingData{size: &size})
+	require.NoError(t, err)
+	insert(t, cache, "other", 20)
+
+	size += 45
+	require.NoError(t, cache.UpdateSize("sparse", 45))
+
+	var keys []string
+	cache.ForEachOldestFirst(func(key string, value lru.ValueType) {
+		keys = append(keys, key)
+	})
+	assert.Equal(t, []string{"sparse", "other"}, keys)
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// gdsfPolicy implements Greedy-Dual-Size-Frequency eviction, which evicts the
+// entry of the lowest priority:
+//
+//	clock + frequency / size
+//
+// where frequency counts the insertion and look ups of the entry, and clock
+// is the priority of the last evicted entry when the entry was last used. So,
+// small and frequently used entries are kept over large ones that are used
+// once, while entries that were frequently used long ago age out.
+type gdsfPolicy struct {
+	// The priority of the last evicted entry.
+	clock float64
+
+	// The number of look ups so far, which breaks ties in favor of the most
+	// recently used entries.
+	uses uint64
+
+	// The entries, as a min-heap of priorities.
+	//
+	// INVARIANT: For each i, entries[i].index == i
+	entries gdsfHeap
+
+	// Entries by key.
+	index map[string]*gdsfEntry
+}
+
+type gdsfEntry struct {
+	key       string
+	size      uint64
+	frequency uint64
+	priority  float64
+	lastUse   uint64
+
+	// The position of the entry in the heap.
+	index int
+}
+
+// NewGDSFPolicy returns a size aware policy, which evicts the entries of the
+// lowest Greedy-Dual-Size-Frequency priority.
+func NewGDSFPolicy() EvictionPolicy {
+	return &gdsfPolicy{
+		index: make(map[string]*gdsfEntry),
+	}
+}
+
+// use counts a use of the supplied entry of the given size and updates its
+// priority.
+func (p *gdsfPolicy) use(entry *gdsfEntry, size uint64) {
+	p.uses++
+	entry.size = size
+	entry.frequency++
+	entry.priority = p.clock + float64(entry.frequency)/float64(max(size, 1))
+	entry.lastUse = p.uses
+}
+
+func (p *gdsfPolicy) Add(key string, size uint64) {
+	entry := &gdsfEntry{key: key}
+	p.use(entry, size)
+	p.index[key] = entry
+	heap.Push(&p.entries, entry)
+}
+
+func (p *gdsfPolicy) Access(key string, size uint64) {
+	entry := p.index[key]
+	p.use(entry, size)
+	heap.Fix(&p.entries, entry.index)
+}
+
+func (p *gdsfPolicy) Resize(key string, size uint64) {
+	entry := p.index[key]
+	frequency := float64(entry.frequency)
+	entry.priority += frequency/float64(max(size, 1)) - frequency/float64(max(entry.size, 1))
+	entry.size = size
+	heap.Fix(&p.entries, entry.index)
+}
+
+func (p *gdsfPolicy) Remove(key string) {
+	entry := p.index[key]
+	delete(p.index, key)
+	heap.Remove(&p.entries, entry.index)
+}
+
+func (p *gdsfPolicy) Evict(keep string) string {
+	victim := p.entries[0]
+	if victim.key == keep {
+		// The next lowest priority is one of the children of the root.
+		victim = p.entries[1]
+		if len(p.entries) > 2 && p.entries.Less(2, 1) {
+			victim = p.entries[2]
+		}
+	}
+	p.clock = victim.priority
+	p.Remove(victim.key)
+	return victim.key
+}
+
+func (p *gdsfPolicy) ForEach(fn func(key string)) {
+	entries := slices.Clone(p.entries)
+	slices.SortFunc(entries, func(a, b *gdsfEntry) int {
+		if a.lowerThan(b) {
+			return -1
+		}
+		return 1
+	})
+	for _, entry := range entries {
+		fn(entry.key)
+	}
+}
+
+func (p *gdsfPolicy) Len() int {
+	return len(p.index)
+}
+
+// lowerThan returns whether the entry is evicted before the other one.
+func (e *gdsfEntry) lowerThan(other *gdsfEntry) bool {
+	if e.priority != other.priority {
+		return e.priority < other.priority
+	}
+	return e.lastUse < other.lastUse
+}
+
+// gdsfHeap implements heap.Interface over entries.
+type gdsfHeap []*gdsfEntry
+
+func (h gdsfHeap) Len() int { return len(h) }
+
+func (h gdsfHeap) Less(i, j int) bool { return h[i].lowerThan(h[j]) }
+
+func (h gdsfHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *gdsfHeap) Push(x any) {
+	entry := x.(*gdsfEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *gdsfHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
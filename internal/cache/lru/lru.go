@@ -15,10 +15,8 @@
 package lru
 
 import (
-	"container/list"
 	"errors"
 	"fmt"
-	"reflect"
 	"strings"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
@@ -33,7 +31,8 @@ var (
 )
 
 // Cache is a LRU cache for any lru.ValueType indexed by string keys.
-// That means entry's value should be a lru.ValueType.
+// That means entry's value should be a lru.ValueType. The entries evicted when
+// the cache is full are chosen by an EvictionPolicy, which is LRU by default.
 type Cache struct {
 	/////////////////////////
 	// Constant data
@@ -46,20 +45,18 @@ type Cache struct {
 	// Mutable state
 	/////////////////////////
 
-	// Sum of entry.Value.Size() of all the entries in the cache.
-	currentSize uint64
-
-	// List of cache entries, with least recently used at the tail.
+	// Sum of Size() of all the values in the cache.
 	//
 	// INVARIANT: currentSize <= maxSize
-	// INVARIANT: Each element is of type entry
-	entries list.List
+	currentSize uint64
 
-	// Index of elements by name.
+	// Values of the entries by key.
+	index map[string]ValueType
+
+	// The order in which entries are evicted.
 	//
-	// INVARIANT: For each k, v: v.Value.(entry).Key == k
-	// INVARIANT: Contains all and only the elements of entries
-	index map[string]*list.Element
+	// INVARIANT: Tracks all and only the keys of index
+	policy EvictionPolicy
 
 	// All public methods of this Cache uses this RW mutex based locker while
 	// accessing/updating Cache's data.
@@ -70,17 +67,20 @@ type ValueType interface {
 	Size() uint64
 }
 
-type entry struct {
-	Key   string
-	Value ValueType
-}
-
 // NewCache returns the reference of cache object by initialising the cache with
 // the supplied maxSize, which must be greater than zero.
 func NewCache(maxSize uint64) *Cache {
+	return NewCacheWithPolicy(maxSize, NewLRUPolicy())
+}
+
+// NewCacheWithPolicy returns a cache of the supplied maxSize, which must be
+// greater than zero, whose entries are evicted in the order chosen by the
+// supplied policy. The policy must not be shared with other caches.
+func NewCacheWithPolicy(maxSize uint64, policy EvictionPolicy) *Cache {
 	c := &Cache{
 		maxSize: maxSize,
-		index:   make(map[string]*list.Element),
+		index:   make(map[string]ValueType),
+		policy:  policy,
 	}
 
 	// Set up invariant checking.
@@ -100,39 +100,29 @@ func (c *Cache) checkInvariants() {
 		panic(fmt.Sprintf("CurrentSize %v over maxSize %v", c.currentSize, c.maxSize))
 	}
 
-	// INVARIANT: Each element is of type entry
-	for e := c.entries.Front(); e != nil; e = e.Next() {
-		switch e.Value.(type) {
-		case entry:
-		default:
-			panic(fmt.Sprintf("Unexpected element type: %v", reflect.TypeOf(e.Value)))
-		}
-	}
-
-	// INVARIANT: For each k, v: v.Value.(entry).Key == k
-	// INVARIANT: Contains all and only the elements of entries
-	if c.entries.Len() != len(c.index) {
+	// INVARIANT: Tracks all and only the keys of index
+	if c.policy.Len() != len(c.index) {
 		panic(fmt.Sprintf(
 			"Length mismatch: %v vs. %v",
-			c.entries.Len(),
+			c.policy.Len(),
 			len(c.index)))
 	}
 
-	for e := c.entries.Front(); e != nil; e = e.Next() {
-		if c.index[e.Value.(entry).Key] != e {
-			panic(fmt.Sprintf("Mismatch for key %v", e.Value.(entry).Key))
+	c.policy.ForEach(func(key string) {
+		if _, ok := c.index[key]; !ok {
+			panic(fmt.Sprintf("Unknown key %v", key))
 		}
-	}
+	})
 }
 
-func (c *Cache) evictOne() ValueType {
-	e := c.entries.Back()
-	key := e.Value.(entry).Key
+// evictOne evicts the entry chosen by the policy, other than the one of the
+// supplied key.
+func (c *Cache) evictOne(keep string) ValueType {
+	key := c.policy.Evict(keep)
 
-	evictedEntry := e.Value.(entry).Value
+	evictedEntry := c.index[key]
 	c.currentSize -= evictedEntry.Size()
 
-	c.entries.Remove(e)
 	delete(c.index, key)
 
 	return evictedEntry
@@ -160,24 +150,22 @@ func (c *Cache) Insert(
 		return nil, ErrInvalidEntrySize
 	}
 
-	e, ok := c.index[key]
+	existing, ok := c.index[key]
 	if ok {
 		// Update an entry if already exist.
-		c.currentSize -= e.Value.(entry).Value.Size()
-		c.currentSize += valueSize
-		e.Value = entry{key, value}
-		c.entries.MoveToFront(e)
+		c.currentSize -= existing.Size()
+		c.policy.Access(key, valueSize)
 	} else {
 		// Add the entry if already doesn't exist.
-		e := c.entries.PushFront(entry{key, value})
-		c.index[key] = e
-		c.currentSize += valueSize
+		c.policy.Add(key, valueSize)
 	}
+	c.index[key] = value
+	c.currentSize += valueSize
 
 	var evictedValues []ValueType
-	// Evict until we're at or below maxSize.
+	// Evict until we're at or below maxSize, keeping the inserted entry.
 	for c.currentSize > c.maxSize {
-		evictedValues = append(evictedValues, c.evictOne())
+		evictedValues = append(evictedValues, c.evictOne(key))
 	}
 
 	return evictedValues, nil
@@ -187,16 +175,15 @@ func (c *Cache) Insert(
 // It returns the value of the erased key, or nil if not present.
 // LOCKS_REQUIRED(c.mu)
 func (c *Cache) eraseInternal(key string) (value ValueType) {
-	e, ok := c.index[key]
+	deletedEntry, ok := c.index[key]
 	if !ok {
 		return
 	}
 
-	deletedEntry := e.Value.(entry).Value
 	c.currentSize -= deletedEntry.Size()
 
 	delete(c.index, key)
-	c.entries.Remove(e)
+	c.policy.Remove(key)
 
 	return deletedEntry
 }
@@ -226,15 +213,15 @@ func (c *Cache) LookUp(key string) (value ValueType) {
 	defer c.mu.Unlock()
 
 	// Consult the index.
-	e, ok := c.index[key]
+	value, ok := c.index[key]
 	if !ok {
 		return
 	}
 	// This is now the most recently used entry.
-	c.entries.MoveToFront(e)
+	c.policy.Access(key, value.Size())
 
 	// Return the value.
-	return e.Value.(entry).Value
+	return value
 }
 
 // LookUpWithoutChangingOrder looks up previously-inserted value for a given key
@@ -248,13 +235,13 @@ func (c *Cache) LookUpWithoutChangingOrder(key string) (value ValueType) {
 	defer c.mu.RUnlock()
 
 	// Consult the index.
-	e, ok := c.index[key]
+	value, ok := c.index[key]
 	if !ok {
 		return
 	}
 
 	// Return the value.
-	return e.Value.(entry).Value
+	return value
 }
 
 // UpdateWithoutChangingOrder updates entry with the given key in cache with
@@ -271,17 +258,16 @@ func (c *Cache) UpdateWithoutChangingOrder(
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	e, ok := c.index[key]
+	existing, ok := c.index[key]
 	if !ok {
 		return ErrEntryNotExist
 	}
 
-	if value.Size() != e.Value.(entry).Value.Size() {
+	if value.Size() != existing.Size() {
 		return ErrInvalidUpdateEntrySize
 	}
 
-	e.Value = entry{key, value}
-	c.index[key] = e
+	c.index[key] = value
 
 	return nil
 }
@@ -289,12 +275,13 @@ func (c *Cache) UpdateWithoutChangingOrder(
 // UpdateSize updates the currentSize accounting when an entry's size has changed.
 // This is needed for entries whose size grows incrementally (e.g., sparse files).
 // Eviction is deferred until the next Insert() call.
-// The entry's order in the LRU is not changed.
+// The entry's order in the LRU is not changed, but size aware policies are
+// told its new size.
 func (c *Cache) UpdateSize(key string, sizeDelta uint64) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	_, ok := c.index[key]
+	value, ok := c.index[key]
 	if !ok {
 		return ErrEntryNotExist
 	}
@@ -303,10 +290,25 @@ func (c *Cache) UpdateSize(key string, sizeDelta uint64) error {
 	// Note: This may temporarily violate currentSize <= maxSize invariant
 	// Eviction will happen on the next Insert() call
 	c.currentSize += sizeDelta
+	c.policy.Resize(key, value.Size())
 
 	return nil
 }
 
+// ForEachOldestFirst calls fn for each entry in the cache, from the next one
+// to be evicted (the least recently used one for LRU) to the last one, without
+// changing their order.
+//
+// Note: fn is called with the read lock held and must not call into the cache.
+func (c *Cache) ForEachOldestFirst(fn func(key string, value ValueType)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	c.policy.ForEach(func(key string) {
+		fn(key, c.index[key])
+	})
+}
+
 func (c *Cache) EraseEntriesWithGivenPrefix(prefix string) {
 	c.mu.RLock()
 	var keysToDelete []string
@@ -137,3 +137,91 @@ func BenchmarkEraseEntriesWithGivenPrefix_1Million(b *testing.B) {
 		cache.EraseEntriesWithGivenPrefix("prefix/")
 	}
 }
+
+func BenchmarkPolicyInsert(b *testing.B) {
+	data := testData{Value: 1, DataSize: 10}
+	for name, newPolicy := range benchmarkPolicies(100000) {
+		b.Run(name, func(b *testing.B) {
+			// Small enough for inserts to evict.
+			cache := lru.NewCacheWithPolicy(100000, newPolicy())
+
+			b.ResetTimer()
+			for i := range b.N {
+				key := fmt.Sprintf("key-%d", i)
+				_, _ = cache.Insert(key, data)
+			}
+		})
+	}
+}
+
+func BenchmarkPolicyLookUp(b *testing.B) {
+	data := testData{Value: 1, DataSize: 10}
+	for name, newPolicy := range benchmarkPolicies(10000000) {
+		b.Run(name, func(b *testing.B) {
+			cache := lru.NewCacheWithPolicy(10000000, newPolicy())
+			for i := range 10000 {
+				key := fmt.Sprintf("key-%d", i)
+				_, _ = cache.Insert(key, data)
+			}
+
+			b.ResetTimer()
+			for i := range b.N {
+				key := fmt.Sprintf("key-%d", i%10000)
+				_ = cache.LookUp(key)
+			}
+		})
+	}
+}
+
+// BenchmarkPolicyHitRatio reports the ratio of reads of small hot files that
+// hit the cache while epochs of large files, each read once, are scanned
+// through it. The hot files are read again after more data than the cache
+// holds, so LRU always evicts them first.
+func BenchmarkPolicyHitRatio(b *testing.B) {
+	const (
+		cacheMaxSize  = 1000
+		hotFiles      = 10
+		hotFileSize   = 1
+		epochFiles    = 600
+		epochFileSize = 10
+		hotReadGap    = 150
+	)
+	for name, newPolicy := range benchmarkPolicies(cacheMaxSize) {
+		b.Run(name, func(b *testing.B) {
+			cache := lru.NewCacheWithPolicy(cacheMaxSize, newPolicy())
+			read := func(key string, size uint64) bool {
+				if cache.LookUp(key) != nil {
+					return true
+				}
+				_, _ = cache.Insert(key, testData{DataSize: size})
+				return false
+			}
+			var hits, reads int
+
+			b.ResetTimer()
+			for i := range b.N {
+				for j := range epochFiles {
+					read(fmt.Sprintf("epoch-%d/file-%d", i, j), epochFileSize)
+					if j%hotReadGap != 0 {
+						continue
+					}
+					for k := range hotFiles {
+						if read(fmt.Sprintf("hot-%d", k), hotFileSize) {
+							hits++
+						}
+						reads++
+					}
+				}
+			}
+			b.ReportMetric(float64(hits)/float64(reads), "hits/read")
+		})
+	}
+}
+
+func benchmarkPolicies(maxSize uint64) map[string]func() lru.EvictionPolicy {
+	return map[string]func() lru.EvictionPolicy{
+		"lru":  lru.NewLRUPolicy,
+		"2q":   func() lru.EvictionPolicy { return lru.NewTwoQueuePolicy(maxSize) },
+		"gdsf": lru.NewGDSFPolicy,
+	}
+}
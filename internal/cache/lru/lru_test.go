@@ -389,3 +389,22 @@ func (t *CacheTest) Test_EraseEntriesWithGivenPrefix_Concurrent() {
 
 	wg.Wait()
 }
+
+func (t *CacheTest) TestForEachOldestFirst() {
+	t.insertAndAssert("burrito", testData{Value: 23, DataSize: 4}, []int64{}, nil)
+	t.insertAndAssert("taco", testData{Value: 26, DataSize: 4}, []int64{}, nil)
+	t.insertAndAssert("enchilada", testData{Value: 28, DataSize: 4}, []int64{}, nil)
+	AssertEq(23, t.cache.LookUp("burrito").(testData).Value)
+	var keys []string
+	var values []int64
+
+	t.cache.ForEachOldestFirst(func(key string, value lru.ValueType) {
+		keys = append(keys, key)
+		values = append(values, value.(testData).Value)
+	})
+
+	ExpectEq(fmt.Sprint([]string{"taco", "enchilada", "burrito"}), fmt.Sprint(keys))
+	ExpectEq(fmt.Sprint([]int64{26, 28, 23}), fmt.Sprint(values))
+	// Iterating doesn't change the order.
+	t.insertAndAssert("queso", testData{Value: 34, DataSize: 40}, []int64{26}, nil)
+}
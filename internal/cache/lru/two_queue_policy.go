@@ -0,0 +1,189 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"container/list"
+)
+
+// twoQueuePolicy implements the full version of the 2Q algorithm (Johnson and
+// Shasha, 1994), adapted to entries of different sizes.
+//
+// New entries go to a FIFO queue, where look ups don't count, since they are
+// usually correlated with the insertion (e.g. the reads of a file that has
+// just been cached). Entries evicted from it are remembered as ghosts, and an
+// entry that's inserted again while it's a ghost goes to an LRU queue of
+// frequently used entries. Entries of the FIFO queue are evicted first while
+// they take more than a quarter of the cache, so that a scan of entries that
+// are used only once can't evict the frequently used ones.
+//
+// As in ARC, there are at most as many ghosts as entries, so that an entry is
+// remembered about as long after its eviction as it stayed in the cache.
+type twoQueuePolicy struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	// The size the recent entries may take before they are evicted ahead of
+	// the frequent ones.
+	recentMaxSize uint64
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	// Recently inserted entries, with the oldest at the tail.
+	//
+	// INVARIANT: Each element is of type *twoQueueEntry with frequent false
+	recent list.List
+
+	// Sum of the sizes of the recent entries.
+	recentSize uint64
+
+	// Frequently used entries, with the least recently used at the tail.
+	//
+	// INVARIANT: Each element is of type *twoQueueEntry with frequent true
+	frequent list.List
+
+	// Keys of the entries evicted from recent, with the oldest at the tail.
+	//
+	// INVARIANT: Each element is of type string
+	//
+	// There are at most max(len(index), 1) ghosts after one is added.
+	ghosts list.List
+
+	// Elements of recent and frequent by key.
+	index map[string]*list.Element
+
+	// Elements of ghosts by key.
+	//
+	// INVARIANT: Contains no key of index
+	ghostIndex map[string]*list.Element
+}
+
+type twoQueueEntry struct {
+	key      string
+	size     uint64
+	frequent bool
+}
+
+// NewTwoQueuePolicy returns a scan resistant policy for a cache of the given
+// maximum size, as implemented by the 2Q algorithm.
+func NewTwoQueuePolicy(maxSize uint64) EvictionPolicy {
+	return &twoQueuePolicy{
+		recentMaxSize: maxSize / 4,
+		index:         make(map[string]*list.Element),
+		ghostIndex:    make(map[string]*list.Element),
+	}
+}
+
+func twoQueueKey(v any) string {
+	return v.(*twoQueueEntry).key
+}
+
+func (p *twoQueuePolicy) Add(key string, size uint64) {
+	entry := &twoQueueEntry{key: key, size: size}
+	if e, ok := p.ghostIndex[key]; ok {
+		p.removeGhost(e)
+		entry.frequent = true
+		p.index[key] = p.frequent.PushFront(entry)
+		return
+	}
+
+	p.index[key] = p.recent.PushFront(entry)
+	p.recentSize += size
+}
+
+func (p *twoQueuePolicy) Access(key string, size uint64) {
+	e := p.index[key]
+	entry := e.Value.(*twoQueueEntry)
+	if entry.frequent {
+		entry.size = size
+		p.frequent.MoveToFront(e)
+		return
+	}
+
+	// Recent entries stay where they are.
+	p.recentSize = p.recentSize - entry.size + size
+	entry.size = size
+}
+
+func (p *twoQueuePolicy) Resize(key string, size uint64) {
+	entry := p.index[key].Value.(*twoQueueEntry)
+	if !entry.frequent {
+		p.recentSize = p.recentSize - entry.size + size
+	}
+	entry.size = size
+}
+
+func (p *twoQueuePolicy) Remove(key string) {
+	e := p.index[key]
+	delete(p.index, key)
+	entry := e.Value.(*twoQueueEntry)
+	if entry.frequent {
+		p.frequent.Remove(e)
+		return
+	}
+	p.recent.Remove(e)
+	p.recentSize -= entry.size
+}
+
+func (p *twoQueuePolicy) Evict(keep string) string {
+	var e *list.Element
+	if p.recentSize > p.recentMaxSize {
+		e = backExcept(&p.recent, keep, twoQueueKey)
+	}
+	if e == nil {
+		e = backExcept(&p.frequent, keep, twoQueueKey)
+	}
+	if e == nil {
+		e = backExcept(&p.recent, keep, twoQueueKey)
+	}
+
+	entry := e.Value.(*twoQueueEntry)
+	p.Remove(entry.key)
+	if !entry.frequent {
+		p.addGhost(entry.key)
+	}
+	return entry.key
+}
+
+// addGhost remembers the key of an entry evicted from recent, forgetting the
+// oldest ghosts if needed.
+func (p *twoQueuePolicy) addGhost(key string) {
+	for p.ghosts.Len() >= max(len(p.index), 1) {
+		p.removeGhost(p.ghosts.Back())
+	}
+	p.ghostIndex[key] = p.ghosts.PushFront(key)
+}
+
+func (p *twoQueuePolicy) removeGhost(e *list.Element) {
+	delete(p.ghostIndex, p.ghosts.Remove(e).(string))
+}
+
+// ForEach calls fn for the recent entries and then the frequent ones, oldest
+// first, which is the order of eviction as long as the recent entries take
+// more than their share of the cache.
+func (p *twoQueuePolicy) ForEach(fn func(key string)) {
+	for _, l := range []*list.List{&p.recent, &p.frequent} {
+		for e := l.Back(); e != nil; e = e.Prev() {
+			fn(twoQueueKey(e.Value))
+		}
+	}
+}
+
+func (p *twoQueuePolicy) Len() int {
+	return len(p.index)
+}
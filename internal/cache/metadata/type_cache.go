@@ -53,6 +53,9 @@ type TypeCache interface {
 	Insert(now time.Time, name string, it Type)
 	// Erase removes the entry with the given name.
 	Erase(name string)
+	// EraseEntriesWithGivenPrefix removes the entries whose names start with
+	// the given prefix.
+	EraseEntriesWithGivenPrefix(prefix string)
 	// Get returns the entry with given name, and also
 	// records this entry as latest accessed in the cache.
 	// If now > expiration, then entry is removed from cache, and
@@ -60,6 +63,9 @@ type TypeCache interface {
 	// If entry doesn't exist in the cache, then
 	// UnknownType is returned.
 	Get(now time.Time, name string) Type
+	// SetTTL changes the TTL of the entries inserted from now on. It has no
+	// effect if the cache was created with caching disabled.
+	SetTTL(ttl time.Duration)
 }
 
 type cacheEntry struct {
@@ -114,15 +120,11 @@ func SizeOfTypeCacheEntry(key string) uint64 {
 // External synchronization is required.
 type typeCache struct {
 	/////////////////////////
-	// Constant data
+	// Mutable state
 	/////////////////////////
 
 	ttl time.Duration
 
-	/////////////////////////
-	// Mutable state
-	/////////////////////////
-
 	// A cache mapping names to the cache entry.
 	// INVARIANT: entries.CheckInvariants() does not panic
 	// INVARIANT: Each value is of type cacheEntry
@@ -167,6 +169,12 @@ func (tc *typeCache) Erase(name string) {
 	}
 }
 
+func (tc *typeCache) EraseEntriesWithGivenPrefix(prefix string) {
+	if tc.entries != nil { // only if caching is enabled
+		tc.entries.EraseEntriesWithGivenPrefix(prefix)
+	}
+}
+
 func (tc *typeCache) Get(now time.Time, name string) Type {
 	if tc.entries == nil { // if caching is not enabled
 		return UnknownType
@@ -185,3 +193,9 @@ func (tc *typeCache) Get(now time.Time, name string) Type {
 	}
 	return entry.inodeType
 }
+
+func (tc *typeCache) SetTTL(ttl time.Duration) {
+	if tc.entries != nil { // only if caching is enabled
+		tc.ttl = ttl
+	}
+}
@@ -185,6 +185,18 @@ func (t *TypeCacheTest) TestGetErasedEntry() {
 	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abcd"))
 }
 
+func (t *TypeCacheTest) TestGetEntriesErasedByPrefix() {
+	t.cache.Insert(now, "abcd", RegularFileType)
+	t.cache.Insert(now, "abef", ExplicitDirType)
+	t.cache.Insert(now, "bcd", RegularFileType)
+
+	t.cache.EraseEntriesWithGivenPrefix("ab")
+
+	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abcd"))
+	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abef"))
+	ExpectEq(RegularFileType, t.cache.Get(beforeExpiration, "bcd"))
+}
+
 func (t *TypeCacheTest) TestGetReinsertedEntry() {
 	t.cache.Insert(now, "abcd", RegularFileType)
 	t.cache.Erase("abcd")
@@ -193,6 +205,16 @@ func (t *TypeCacheTest) TestGetReinsertedEntry() {
 	ExpectEq(ExplicitDirType, t.cache.Get(beforeExpiration2, "abcd"))
 }
 
+func (t *TypeCacheTest) TestSetTTL() {
+	t.cache.Insert(now, "abcd", RegularFileType)
+	t.cache.SetTTL(2 * t.ttl)
+	t.cache.Insert(now, "efgh", RegularFileType)
+
+	// The TTL of the existing entry is unchanged.
+	ExpectEq(UnknownType, t.cache.Get(afterExpiration, "abcd"))
+	ExpectEq(RegularFileType, t.cache.Get(afterExpiration, "efgh"))
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Tests for TypeCache created with size=0 - ZeroSizeTypeCacheTest
 ////////////////////////////////////////////////////////////////////////
@@ -220,3 +242,10 @@ func (t *ZeroTtlTypeCacheTest) TestGetInsertedEntry() {
 
 	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abcd"))
 }
+
+func (t *ZeroTtlTypeCacheTest) TestSetTTLDoesNotEnableCaching() {
+	t.cache.SetTTL(TTL)
+	t.cache.Insert(now, "abcd", RegularFileType)
+
+	ExpectEq(UnknownType, t.cache.Get(beforeExpiration, "abcd"))
+}
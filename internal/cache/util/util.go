@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"hash/fnv"
 	"io"
 	"os"
 	"path"
@@ -48,6 +49,7 @@ const (
 	DefaultFilePerm  = os.FileMode(0600)
 	DefaultDirPerm   = os.FileMode(0700)
 	FileCache        = "gcsfuse-file-cache"
+	FileCacheIndex   = "gcsfuse-file-cache-index"
 	SharedChunkCache = "gcsfuse-shared-chunk-cache"
 	BufferSizeForCRC = 65536
 )
@@ -95,6 +97,15 @@ func GetDownloadPath(cacheDir string, objectPath string) string {
 	return path.Join(cacheDir, objectPath)
 }
 
+// GetIndexPath gives the path of the index of the file cache of the given
+// bucket mounted at mountPoint, in indexDir. Mounts sharing a cache directory
+// thus don't reuse each other's index.
+func GetIndexPath(indexDir string, bucketName string, mountPoint string) string {
+	h := fnv.New64a()
+	h.Write([]byte(mountPoint))
+	return path.Join(indexDir, fmt.Sprintf("%s-%016x.json", bucketName, h.Sum64()))
+}
+
 // IsCacheHandleInvalid says either the current cacheHandle is invalid or not, based
 // on the error we got while reading with the cacheHandle.
 // If it's invalid then we should close that cacheHandle and create new cacheHandle
@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// bucketUsage periodically estimates the space used in a bucket, as the sum of
+// the sizes of its objects, to be reported by StatFS.
+type bucketUsage struct {
+	bucket gcs.Bucket
+
+	// The last estimate, in bytes.
+	bytes atomic.Uint64
+
+	cancel context.CancelFunc
+}
+
+// startBucketUsage estimates the space used in the bucket every interval, in
+// the background, until stopped.
+func startBucketUsage(bucket gcs.Bucket, interval time.Duration) *bucketUsage {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := &bucketUsage{
+		bucket: bucket,
+		cancel: cancel,
+	}
+	go u.refreshEvery(ctx, interval)
+	return u
+}
+
+func (u *bucketUsage) refreshEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := u.refresh(ctx); err != nil && ctx.Err() == nil {
+			logger.Warnf("Estimating the space used in bucket %q: %v", u.bucket.Name(), err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh lists all the objects of the bucket to update the estimate.
+func (u *bucketUsage) refresh(ctx context.Context) error {
+	var total uint64
+	req := &gcs.ListObjectsRequest{MaxResults: inode.MaxResultsForListObjectsCall}
+	for {
+		listing, err := u.bucket.ListObjects(ctx, req)
+		if err != nil {
+			return fmt.Errorf("ListObjects: %w", err)
+		}
+		for _, o := range listing.MinObjects {
+			total += o.Size
+		}
+		if listing.ContinuationToken == "" {
+			break
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+	u.bytes.Store(total)
+	return nil
+}
+
+// Bytes returns the last estimate of the space used in the bucket, or zero if
+// it hasn't been estimated yet or u is nil.
+func (u *bucketUsage) Bytes() uint64 {
+	if u == nil {
+		return 0
+	}
+	return u.bytes.Load()
+}
+
+// Stop stops estimating the space used in the bucket. It's a no-op if u is
+// nil.
+func (u *bucketUsage) Stop() {
+	if u != nil {
+		u.cancel()
+	}
+}
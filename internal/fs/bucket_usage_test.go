@@ -0,0 +1,137 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/util/diskutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBucketUsageForTest(t *testing.T, sizes ...int) *bucketUsage {
+	t.Helper()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	for i, size := range sizes {
+		_, err := storageutil.CreateObject(context.Background(), bucket, strings.Repeat("a", i+1), make([]byte, size))
+		require.NoError(t, err)
+	}
+	return &bucketUsage{bucket: bucket}
+}
+
+func TestBucketUsageRefresh(t *testing.T) {
+	u := newBucketUsageForTest(t, 10, 20, 30)
+
+	require.NoError(t, u.refresh(context.Background()))
+
+	assert.EqualValues(t, 60, u.Bytes())
+}
+
+func TestBucketUsageNil(t *testing.T) {
+	var u *bucketUsage
+
+	u.Stop()
+
+	assert.Zero(t, u.Bytes())
+}
+
+func TestStatFS(t *testing.T) {
+	const blockSize = 1 << 17
+	u := newBucketUsageForTest(t, 3*blockSize+1)
+	require.NoError(t, u.refresh(context.Background()))
+	tempDir := t.TempDir()
+	available, err := diskutil.GetAvailableSpace(tempDir)
+	require.NoError(t, err)
+	quotaBlocks := uint64(util.MiB / blockSize)
+
+	for _, tc := range []struct {
+		name                  string
+		quotaMb               int64
+		enableStreamingWrites bool
+		bucketUsage           *bucketUsage
+		expectedBlocks        uint64
+		expectedFree          uint64
+		expectedAvailable     uint64
+	}{
+		{
+			name:              "NoQuota",
+			expectedBlocks:    1 << 33,
+			expectedFree:      1 << 33,
+			expectedAvailable: min(1<<33, available/blockSize),
+		},
+		{
+			name:                  "NoQuotaWithStreamingWrites",
+			enableStreamingWrites: true,
+			expectedBlocks:        1 << 33,
+			expectedFree:          1 << 33,
+			expectedAvailable:     1 << 33,
+		},
+		{
+			name:              "QuotaWithoutUsage",
+			quotaMb:           1,
+			expectedBlocks:    quotaBlocks,
+			expectedFree:      quotaBlocks,
+			expectedAvailable: min(quotaBlocks, available/blockSize),
+		},
+		{
+			name:              "QuotaWithUsage",
+			quotaMb:           1,
+			bucketUsage:       u,
+			expectedBlocks:    quotaBlocks,
+			expectedFree:      quotaBlocks - 4,
+			expectedAvailable: min(quotaBlocks-4, available/blockSize),
+		},
+		{
+			name:                  "QuotaWithStreamingWrites",
+			quotaMb:               1,
+			enableStreamingWrites: true,
+			bucketUsage:           u,
+			expectedBlocks:        quotaBlocks,
+			expectedFree:          quotaBlocks - 4,
+			expectedAvailable:     quotaBlocks - 4,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := &fileSystem{
+				newConfig: &cfg.Config{
+					FileSystem: cfg.FileSystemConfig{QuotaMb: tc.quotaMb},
+					Write:      cfg.WriteConfig{EnableStreamingWrites: tc.enableStreamingWrites},
+				},
+				tempDir:     tempDir,
+				bucketUsage: tc.bucketUsage,
+			}
+			op := &fuseops.StatFSOp{}
+
+			require.NoError(t, fs.StatFS(context.Background(), op))
+
+			assert.EqualValues(t, blockSize, op.BlockSize)
+			assert.Equal(t, tc.expectedBlocks, op.Blocks)
+			assert.Equal(t, tc.expectedFree, op.BlocksFree)
+			// The free space of the temp dir may change a little between
+			// statfs calls.
+			assert.InDelta(t, tc.expectedAvailable, op.BlocksAvailable, 16)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+)
+
+// A ConfigReloader applies the settings of a reloaded config that can be
+// changed while the file system is mounted. It is attached to the file system
+// through ServerConfig.ConfigReloader.
+type ConfigReloader struct {
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	fs *fileSystem
+}
+
+func NewConfigReloader() *ConfigReloader {
+	return &ConfigReloader{}
+}
+
+func (r *ConfigReloader) attach(fs *fileSystem) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fs = fs
+}
+
+// Reload applies the TTLs of the inode attribute, type and kernel list caches
+// and the file cache regexes of the supplied config, which must have been
+// validated and rationalized. The TTLs are as in ServerConfig. It does nothing
+// if no file system has been attached.
+func (r *ConfigReloader) Reload(
+	newConfig *cfg.Config,
+	inodeAttributeCacheTTL time.Duration,
+	dirTypeCacheTTL time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fs != nil {
+		r.fs.reloadConfig(newConfig, inodeAttributeCacheTTL, dirTypeCacheTTL)
+	}
+}
+
+// typeCacheTTLSetter is implemented by the directory inodes that have a type
+// cache.
+type typeCacheTTLSetter interface {
+	// LOCKS_REQUIRED(inode)
+	SetTypeCacheTTL(ttl time.Duration)
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) reloadConfig(
+	newConfig *cfg.Config,
+	inodeAttributeCacheTTL time.Duration,
+	dirTypeCacheTTL time.Duration) {
+	fs.inodeAttributeCacheTTL.Store(int64(inodeAttributeCacheTTL))
+	fs.dirTypeCacheTTL.Store(int64(dirTypeCacheTTL))
+	fs.kernelListCacheTTL.Store(int64(cfg.ListCacheTTLSecsToDuration(newConfig.FileSystem.KernelListCacheTtlSecs)))
+
+	if fs.fileCacheHandler != nil {
+		fs.fileCacheHandler.SetRegexes(newConfig.FileCache.ExcludeRegex, newConfig.FileCache.IncludeRegex)
+	}
+	if fs.sharedChunkCacheManager != nil {
+		fs.sharedChunkCacheManager.SetRegexes(newConfig.FileCache.ExcludeRegex, newConfig.FileCache.IncludeRegex)
+	}
+
+	// Directory inodes created from now on pick up the new type cache TTL, so
+	// apply it to the existing ones. Inode locks can't be acquired while
+	// holding the file system lock, so collect them first.
+	fs.mu.Lock()
+	var dirs []inode.DirInode
+	for _, in := range fs.inodes {
+		if dir, ok := in.(inode.DirInode); ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	fs.mu.Unlock()
+
+	for _, dir := range dirs {
+		if setter, ok := dir.(typeCacheTTLSetter); ok {
+			dir.Lock()
+			setter.SetTypeCacheTTL(dirTypeCacheTTL)
+			dir.Unlock()
+		}
+	}
+}
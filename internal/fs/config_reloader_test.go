@@ -0,0 +1,50 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigReloader_NotAttached(t *testing.T) {
+	r := NewConfigReloader()
+
+	// Must not panic.
+	r.Reload(&cfg.Config{}, time.Second, time.Second)
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	fs := &fileSystem{
+		mu:     locker.New("FS", func() {}),
+		inodes: make(map[fuseops.InodeID]inode.Inode),
+	}
+	fs.inodeAttributeCacheTTL.Store(int64(time.Minute))
+	fs.dirTypeCacheTTL.Store(int64(time.Minute))
+	r := NewConfigReloader()
+	r.attach(fs)
+
+	r.Reload(&cfg.Config{FileSystem: cfg.FileSystemConfig{KernelListCacheTtlSecs: 30}}, time.Second, 2*time.Second)
+
+	assert.Equal(t, time.Second, time.Duration(fs.inodeAttributeCacheTTL.Load()))
+	assert.Equal(t, 2*time.Second, time.Duration(fs.dirTypeCacheTTL.Load()))
+	assert.Equal(t, 30*time.Second, time.Duration(fs.kernelListCacheTTL.Load()))
+}
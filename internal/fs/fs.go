@@ -27,6 +27,7 @@ import (
 	"reflect"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -59,6 +60,7 @@ import (
 	"github.com/jacobsa/fuse/fuseutil"
 	"github.com/jacobsa/timeutil"
 	"github.com/spf13/viper"
+	"golang.org/x/sys/unix"
 )
 
 type ServerConfig struct {
@@ -73,6 +75,10 @@ type ServerConfig struct {
 	// all accessible GCS buckets are mounted as subdirectories of the FS root.
 	BucketName string
 
+	// The absolute path the file system is mounted at, which distinguishes the
+	// indexes of the file caches of mounts sharing a cache directory.
+	MountPoint string
+
 	// LocalFileCache
 	LocalFileCache bool
 
@@ -152,6 +158,15 @@ type ServerConfig struct {
 	// when underlying content changes, improving consistency while still leveraging
 	// kernel caching.
 	Notifier *fuse.Notifier
+
+	// ConfigReloader, if set, is attached to the file system so that the
+	// settings of a reloaded config that can be changed while mounted are
+	// applied to it.
+	ConfigReloader *ConfigReloader
+
+	// Inspector, if set, is attached to the file system so that an
+	// administrator can inspect it and invalidate its caches.
+	Inspector *Inspector
 }
 
 // Create a fuse file system server according to the supplied configuration.
@@ -198,11 +213,9 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		contentCache:               contentCache,
 		implicitDirs:               serverCfg.ImplicitDirectories,
 		enableNonexistentTypeCache: serverCfg.EnableNonexistentTypeCache,
-		inodeAttributeCacheTTL:     serverCfg.InodeAttributeCacheTTL,
-		dirTypeCacheTTL:            serverCfg.DirTypeCacheTTL,
-		kernelListCacheTTL:         cfg.ListCacheTTLSecsToDuration(serverCfg.NewConfig.FileSystem.KernelListCacheTtlSecs),
 		renameDirLimit:             serverCfg.RenameDirLimit,
 		sequentialReadSizeMb:       serverCfg.SequentialReadSizeMb,
+		tempDir:                    serverCfg.TempDir,
 		uid:                        serverCfg.Uid,
 		gid:                        serverCfg.Gid,
 		fileMode:                   serverCfg.FilePerms,
@@ -227,6 +240,22 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		globalMetadataPrefetchSem:  semaphore.NewWeighted(serverCfg.NewConfig.MetadataCache.MetadataPrefetchMaxWorkers),
 	}
 
+	if fs.tempDir == "" {
+		fs.tempDir = os.TempDir()
+	}
+
+	// The in-memory tier of the file cache shares the blocks of the readers.
+	if fs.fileCacheHandler != nil && serverCfg.NewConfig.FileCache.RamTierMaxBlocks > 0 {
+		err := fs.fileCacheHandler.EnableRAMTier(serverCfg.NewConfig.Read.BlockSizeMb*cacheutil.MiB, serverCfg.NewConfig.FileCache.RamTierMaxBlocks, fs.globalMaxReadBlocksSem)
+		if err != nil {
+			return nil, fmt.Errorf("EnableRAMTier: %w", err)
+		}
+	}
+
+	fs.inodeAttributeCacheTTL.Store(int64(serverCfg.InodeAttributeCacheTTL))
+	fs.dirTypeCacheTTL.Store(int64(serverCfg.DirTypeCacheTTL))
+	fs.kernelListCacheTTL.Store(int64(cfg.ListCacheTTLSecsToDuration(serverCfg.NewConfig.FileSystem.KernelListCacheTtlSecs)))
+
 	// Initialize MRD cache if enabled
 	if serverCfg.NewConfig.FileSystem.InactiveMrdCacheSize > 0 {
 		fs.mrdCache = lru.NewCache(uint64(serverCfg.NewConfig.FileSystem.InactiveMrdCacheSize))
@@ -237,6 +266,13 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		fs.notifier = serverCfg.Notifier
 	}
 
+	if serverCfg.ConfigReloader != nil {
+		serverCfg.ConfigReloader.attach(fs)
+	}
+	if serverCfg.Inspector != nil {
+		serverCfg.Inspector.attach(fs)
+	}
+
 	if serverCfg.NewConfig.Read.EnableBufferedRead {
 		var err error
 		fs.bufferedReadWorkerPool, err = workerpool.NewStaticWorkerPoolForCurrentCPU(serverCfg.NewConfig.Read.GlobalMaxBlocks)
@@ -249,6 +285,9 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 	var root inode.DirInode
 	if serverCfg.BucketName == "" || serverCfg.BucketName == "_" {
 		logger.Info("Set up root directory for all accessible buckets")
+		if serverCfg.NewConfig.FileCache.WarmUpManifest != "" {
+			logger.Warnf("file-cache:warm-up-manifest is ignored for dynamic mounts")
+		}
 		root = makeRootForAllBuckets(fs)
 	} else {
 		logger.Info("Set up root directory for bucket " + serverCfg.BucketName)
@@ -292,6 +331,31 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 			kernelParams.ApplyGKE(string(serverCfg.NewConfig.FileSystem.KernelParamsFile))
 		}
 		root = makeRootForBucket(fs, syncerBucket)
+
+		// Files cached by the previous mount are validated against GCS in the
+		// background so that the mount isn't delayed, unless the file cache is
+		// warmed up, in which case they're reused by the warm-up.
+		warmUp := fs.fileCacheHandler != nil && serverCfg.NewConfig.FileCache.WarmUpManifest != ""
+		if fs.fileCacheHandler != nil && serverCfg.NewConfig.FileCache.PersistIndex {
+			if warmUp {
+				fs.fileCacheHandler.RestoreIndex(ctx, syncerBucket)
+			} else {
+				go fs.fileCacheHandler.RestoreIndex(context.Background(), syncerBucket)
+			}
+		}
+		// The mount completes only once the hot dataset is in cache. Objects
+		// which can't be downloaded are read from GCS instead.
+		if warmUp {
+			if err := warmUpFileCache(ctx, fs.fileCacheHandler, syncerBucket, &serverCfg.NewConfig.FileCache); err != nil {
+				logger.Errorf("File Cache: mounting with a partially warmed up cache: %v", err)
+			}
+		}
+
+		// The usage is listed below the stat cache so as not to fill it.
+		fsConfig := serverCfg.NewConfig.FileSystem
+		if fsConfig.QuotaMb > 0 && fsConfig.UsageRefreshInterval > 0 {
+			fs.bucketUsage = startBucketUsage(syncerBucket.Uncached(), fsConfig.UsageRefreshInterval)
+		}
 	}
 	root.Lock()
 	root.IncrementLookupCount()
@@ -305,6 +369,19 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 	return fs, nil
 }
 
+// newFileCacheEvictionPolicy returns the eviction policy of the given name for
+// a file cache of the given size, defaulting to LRU.
+func newFileCacheEvictionPolicy(name string, sizeInBytes uint64) lru.EvictionPolicy {
+	switch name {
+	case cfg.FileCacheEvictionPolicy2Q:
+		return lru.NewTwoQueuePolicy(sizeInBytes)
+	case cfg.FileCacheEvictionPolicyGDSF:
+		return lru.NewGDSFPolicy()
+	default:
+		return lru.NewLRUPolicy()
+	}
+}
+
 // createFileCacheHandler either returns a regular file cache handler with an in-memory LRU cache, or
 // a shared chunk cache manager that allows multiple gcsfuse instances to share the same cache directory
 // on disk, based on the configuration.
@@ -379,7 +456,7 @@ func createSingleMountFileCacheHandler(baseCacheDir string, filePerm, dirPerm os
 		logger.Infof("File Cache: Regular cache size: %d MB (%d bytes)", serverCfg.NewConfig.FileCache.MaxSizeMb, sizeInBytes)
 	}
 
-	fileInfoCache := lru.NewCache(sizeInBytes)
+	fileInfoCache := lru.NewCacheWithPolicy(sizeInBytes, newFileCacheEvictionPolicy(serverCfg.NewConfig.FileCache.EvictionPolicy, sizeInBytes))
 	cacheDirVolumeBlockSize := cacheDirVolumeBlockSize(serverCfg, cacheDir)
 	jobManager := downloader.NewJobManager(
 		fileInfoCache,
@@ -404,6 +481,12 @@ func createSingleMountFileCacheHandler(baseCacheDir string, filePerm, dirPerm os
 		cacheDirVolumeBlockSize,
 	)
 
+	if serverCfg.NewConfig.FileCache.PersistIndex {
+		if err := fileCacheHandler.EnablePersistentIndex(path.Join(baseCacheDir, cacheutil.FileCacheIndex), serverCfg.MountPoint); err != nil {
+			return nil, fmt.Errorf("createSingleMountFileCacheHandler: %w", err)
+		}
+	}
+
 	return fileCacheHandler, nil
 }
 
@@ -426,7 +509,7 @@ func makeRootForBucket(
 		},
 		fs.implicitDirs,
 		fs.enableNonexistentTypeCache,
-		fs.dirTypeCacheTTL,
+		time.Duration(fs.dirTypeCacheTTL.Load()),
 		&syncerBucket,
 		fs.mtimeClock,
 		fs.cacheClock,
@@ -435,7 +518,34 @@ func makeRootForBucket(
 	)
 }
 
+// indexRestoringBucketManager restores the entries of the file cache index
+// of the buckets as they're set up by a multi-bucket mount.
+type indexRestoringBucketManager struct {
+	gcsx.BucketManager
+	fileCacheHandler *file.CacheHandler
+}
+
+func (bm *indexRestoringBucketManager) SetUpBucket(
+	ctx context.Context,
+	name string, isMultibucketMount bool, metricHandle metrics.MetricHandle) (gcsx.SyncerBucket, error) {
+	b, err := bm.BucketManager.SetUpBucket(ctx, name, isMultibucketMount, metricHandle)
+	if err != nil {
+		return b, err
+	}
+	// Files cached by the previous mount are validated against GCS in the
+	// background so that the lookup isn't delayed.
+	go bm.fileCacheHandler.RestoreIndex(context.Background(), b)
+	return b, nil
+}
+
 func makeRootForAllBuckets(fs *fileSystem) inode.DirInode {
+	var bucketManager gcsx.BucketManager = fs.bucketManager
+	if fs.fileCacheHandler != nil && fs.newConfig.FileCache.PersistIndex {
+		bucketManager = &indexRestoringBucketManager{
+			BucketManager:    fs.bucketManager,
+			fileCacheHandler: fs.fileCacheHandler,
+		}
+	}
 	return inode.NewBaseDirInode(
 		fuseops.RootInodeID,
 		inode.NewRootName(""),
@@ -449,7 +559,7 @@ func makeRootForAllBuckets(fs *fileSystem) inode.DirInode {
 			Ctime: fs.mtimeClock.Now(),
 			Mtime: fs.mtimeClock.Now(),
 		},
-		fs.bucketManager,
+		bucketManager,
 		fs.metricHandle,
 		fs.newConfig.EnableTypeCacheDeprecation,
 	)
@@ -500,17 +610,13 @@ type fileSystem struct {
 	contentCache               *contentcache.ContentCache
 	implicitDirs               bool
 	enableNonexistentTypeCache bool
-	inodeAttributeCacheTTL     time.Duration
-	dirTypeCacheTTL            time.Duration
-
-	// kernelListCacheTTL specifies the duration to keep the readdir response cached
-	// in kernel. After ttl, gcsfuse, (filesystem) on next opendir call (just before as part
-	// of next list call) from user, asks the kernel to evict the old cache entries.
-	kernelListCacheTTL time.Duration
 
 	renameDirLimit       int64
 	sequentialReadSizeMb int32
 
+	// The directory where writes are staged prior to upload.
+	tempDir string
+
 	// The user and group owning everything in the file system.
 	uid uint32
 	gid uint32
@@ -523,13 +629,25 @@ type fileSystem struct {
 	// Mutable state
 	/////////////////////////
 
+	// How long to allow the kernel to cache inode attributes, and the TTL of the
+	// type cache of new directory inodes, as time.Durations. See ServerConfig.
+	// They may be changed by a ConfigReloader.
+	inodeAttributeCacheTTL atomic.Int64
+	dirTypeCacheTTL        atomic.Int64
+
+	// kernelListCacheTTL specifies the duration to keep the readdir response cached
+	// in kernel. After ttl, gcsfuse, (filesystem) on next opendir call (just before as part
+	// of next list call) from user, asks the kernel to evict the old cache entries.
+	// It may be changed by a ConfigReloader.
+	kernelListCacheTTL atomic.Int64
+
 	// A lock protecting the state of the file system struct itself (distinct
 	// from per-inode locks). Make sure to see the notes on lock ordering above.
 	mu locker.Locker
 
-	// The next inode ID to hand out. We assume that this will never overflow,
-	// since even if we were handing out inode IDs at 4 GHz, it would still take
-	// over a century to do so.
+	// The next inode ID to hand out, unless stable inode IDs are enabled. We
+	// assume that this will never overflow, since even if we were handing out
+	// inode IDs at 4 GHz, it would still take over a century to do so.
 	//
 	// GUARDED_BY(mu)
 	nextInodeID fuseops.InodeID
@@ -537,7 +655,8 @@ type fileSystem struct {
 	// The collection of live inodes, keyed by inode ID. No ID less than
 	// fuseops.RootInodeID is ever used.
 	//
-	// INVARIANT: For all keys k, fuseops.RootInodeID <= k < nextInodeID
+	// INVARIANT: For all keys k, fuseops.RootInodeID <= k
+	// INVARIANT: For all keys k, k < nextInodeID unless stable inode IDs are enabled
 	// INVARIANT: For all keys k, inodes[k].ID() == k
 	// INVARIANT: inodes[fuseops.RootInodeID] is missing or of type inode.DirInode
 	// INVARIANT: For all v, if v.Name().IsDir() then v is inode.DirInode
@@ -621,6 +740,11 @@ type fileSystem struct {
 	// GUARDED_BY(mu)
 	handles map[fuseops.HandleID]any
 
+	// bucketUsage estimates the space used in the mounted bucket for StatFS. It
+	// is non-nil only when a single bucket is mounted with a quota and a usage
+	// refresh interval.
+	bucketUsage *bucketUsage
+
 	// The next handle ID to hand out. We assume that this will never overflow.
 	//
 	// INVARIANT: For all keys k in handles, k < nextHandleID
@@ -849,9 +973,10 @@ func (fs *fileSystem) checkInvariantsForGenerationBackedInodes() {
 }
 
 func (fs *fileSystem) checkInvariantsForInodes() {
-	// INVARIANT: For all keys k, fuseops.RootInodeID <= k < nextInodeID
+	// INVARIANT: For all keys k, fuseops.RootInodeID <= k
+	// INVARIANT: For all keys k, k < nextInodeID unless stable inode IDs are enabled
 	for id := range fs.inodes {
-		if id < fuseops.RootInodeID || id >= fs.nextInodeID {
+		if id < fuseops.RootInodeID || (id >= fs.nextInodeID && !fs.newConfig.FileSystem.EnableStableInodeIds) {
 			panic(fmt.Sprintf("Illegal inode ID: %v", id))
 		}
 	}
@@ -900,11 +1025,13 @@ func (fs *fileSystem) checkInvariants() {
 	// handles
 	//////////////////////////////////
 
-	// INVARIANT: All values are of type *dirHandle or *handle.FileHandle
+	// INVARIANT: All values are of type *dirHandle, *handle.FileHandle or
+	// *handle.GenerationFileHandle
 	for _, h := range fs.handles {
 		switch h.(type) {
 		case *handle.DirHandle:
 		case *handle.FileHandle:
+		case *handle.GenerationFileHandle:
 		default:
 			panic(fmt.Sprintf("Unexpected handle type: %T", h))
 		}
@@ -940,7 +1067,7 @@ func (fs *fileSystem) createExplicitDirInode(inodeID fuseops.InodeID, ic inode.C
 		},
 		fs.implicitDirs,
 		fs.enableNonexistentTypeCache,
-		fs.dirTypeCacheTTL,
+		time.Duration(fs.dirTypeCacheTTL.Load()),
 		ic.Bucket,
 		fs.mtimeClock,
 		fs.cacheClock,
@@ -956,11 +1083,40 @@ func (fs *fileSystem) createExplicitDirInode(inodeID fuseops.InodeID, ic inode.C
 // LOCKS_REQUIRED(fs.mu)
 func (fs *fileSystem) mintInode(ic inode.Core, parInodeCtx context.Context) (in inode.Inode, err error) {
 	// Choose an ID.
-	id := fs.nextInodeID
-	fs.nextInodeID++
+	id := fs.allocateInodeID(ic)
 
 	// Create the inode.
 	switch {
+	// Directories of the versions directory tree.
+	case ic.Versions && ic.FullName.IsDir():
+		in = inode.NewVersionsDirInode(
+			id,
+			ic.FullName,
+			parInodeCtx,
+			fuseops.InodeAttributes{
+				Uid:   fs.uid,
+				Gid:   fs.gid,
+				Mode:  fs.dirMode &^ 0222,
+				Atime: fs.mtimeClock.Now(),
+				Ctime: fs.mtimeClock.Now(),
+				Mtime: fs.mtimeClock.Now(),
+			},
+			ic.Bucket)
+
+	// Generations of objects in the versions directory tree.
+	case ic.Versions:
+		in = inode.NewGenerationFileInode(
+			id,
+			ic.FullName,
+			ic.Bucket,
+			ic.MinObject,
+			ic.SoftDeleted,
+			fuseops.InodeAttributes{
+				Uid:  fs.uid,
+				Gid:  fs.gid,
+				Mode: fs.fileMode &^ 0222,
+			})
+
 	// Explicit directories or folders in hierarchical bucket.
 	case (ic.MinObject != nil && ic.FullName.IsDir()), ic.Folder != nil:
 		in = fs.createExplicitDirInode(id, ic, parInodeCtx)
@@ -983,7 +1139,7 @@ func (fs *fileSystem) mintInode(ic inode.Core, parInodeCtx context.Context) (in
 			},
 			fs.implicitDirs,
 			fs.enableNonexistentTypeCache,
-			fs.dirTypeCacheTTL,
+			time.Duration(fs.dirTypeCacheTTL.Load()),
 			ic.Bucket,
 			fs.mtimeClock,
 			fs.cacheClock,
@@ -1222,6 +1378,20 @@ func (fs *fileSystem) lookUpOrCreateChildInode(
 	// If the requested child is not a localFileInode, continue with the existing
 	// flow of checking GCS for file/directory.
 
+	// The versions directory hides any object of the same name at the root of
+	// the bucket.
+	if fs.newConfig.FileSystem.EnableVersionsDir && childName == inode.VersionsDirName && parent.Name().IsBucketRoot() {
+		if bucketOwned, ok := parent.(inode.BucketOwnedDirInode); ok {
+			return fs.lookUpOrCreateInodeIfNotStale(parent.Context(), inode.NewVersionsRootCore(bucketOwned.Bucket(), parent.Name()))
+		}
+	}
+
+	// The contents of files with hard links are only reachable through their
+	// links.
+	if fs.newConfig.FileSystem.EnableHardLinks && childName == inode.HardLinksDirName && parent.Name().IsBucketRoot() {
+		return nil, fuse.ENOENT
+	}
+
 	// Set up a function that will find a lookup result for the child with the
 	// given name. Expects no locks to be held.
 	getLookupResult := func() (*inode.Core, error) {
@@ -1610,8 +1780,8 @@ func (fs *fileSystem) getAttributes(
 	}
 
 	// Set up the expiration time.
-	if fs.inodeAttributeCacheTTL > 0 {
-		expiration = time.Now().Add(fs.inodeAttributeCacheTTL)
+	if ttl := time.Duration(fs.inodeAttributeCacheTTL.Load()); ttl > 0 {
+		expiration = time.Now().Add(ttl)
 	}
 
 	return
@@ -1715,7 +1885,7 @@ func (fs *fileSystem) coreToDirentPlus(ctx context.Context, fullName inode.Name,
 		return nil, fmt.Errorf("coreToDirentPlus: unable to fetch attributes for %s: %w", path.Base(fullName.LocalName()), err)
 	}
 
-	expiration := time.Now().Add(fs.inodeAttributeCacheTTL)
+	expiration := time.Now().Add(time.Duration(fs.inodeAttributeCacheTTL.Load()))
 	entryPlus = &fuseutil.DirentPlus{
 		Dirent: fuseutil.Dirent{
 			Name:  path.Base(fullName.LocalName()),
@@ -1796,7 +1966,7 @@ func (fs *fileSystem) lookupAndFetchAttributesForLocalFileEntriesPlus(parentName
 		// Unlock the inode after retrieving its attributes.
 		child.Unlock()
 
-		expiration := time.Now().Add(fs.inodeAttributeCacheTTL)
+		expiration := time.Now().Add(time.Duration(fs.inodeAttributeCacheTTL.Load()))
 		childInodeEntry := fuseops.ChildInodeEntry{
 			Child:                child.ID(),
 			Attributes:           attrs,
@@ -1819,43 +1989,66 @@ func (fs *fileSystem) lookupAndFetchAttributesForLocalFileEntriesPlus(parentName
 //
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) invalidateCachedEntry(childID fuseops.InodeID) error {
+	parentInodeID, childBase, err := fs.cachedEntry(childID)
+	if err != nil {
+		return fmt.Errorf("invalidateCachedEntry: %w", err)
+	}
+	return fs.notifier.InvalidateEntry(parentInodeID, childBase)
+}
+
+// invalidateCachedEntryAfterOp is invalidateCachedEntry for ops during which
+// the kernel holds the lock of the parent directory of the entry, such as
+// link: the notification waits for the lock, so it's sent once the op returns.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) invalidateCachedEntryAfterOp(childID fuseops.InodeID) {
+	parentInodeID, childBase, err := fs.cachedEntry(childID)
+	if err != nil {
+		logger.Warnf("invalidateCachedEntryAfterOp: %v", err)
+		return
+	}
+	go func() {
+		if err := fs.notifier.InvalidateEntry(parentInodeID, childBase); err != nil {
+			logger.Warnf("invalidateCachedEntryAfterOp: invalidating %q: %v", childBase, err)
+		}
+	}()
+}
+
+// cachedEntry returns the parent inode ID and the name of the directory entry
+// of the given child inode.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) cachedEntry(childID fuseops.InodeID) (fuseops.InodeID, string, error) {
 	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
 	childInode, ok := fs.inodes[childID]
-	fs.mu.Unlock()
 	if !ok {
-		return fmt.Errorf("invalidateCachedEntry: inode with ID %d not found", childID)
+		return 0, "", fmt.Errorf("inode with ID %d not found", childID)
 	}
 
 	childName := childInode.Name()
-	parentPath := path.Dir(childName.LocalName())
+	childBase := path.Base(childName.LocalName())
 	// If the parent path resolves to the current directory ".", it means the parent
 	// is the root of the file system.
-	if parentPath == "." {
-		return fs.notifier.InvalidateEntry(fuseops.RootInodeID, path.Base(childInode.Name().LocalName()))
+	if path.Dir(childName.LocalName()) == "." {
+		return fuseops.RootInodeID, childBase, nil
 	}
 
 	parentName, err := childName.ParentName()
 	if err != nil {
-		return fmt.Errorf("invalidateCachedEntry: cannot find Parent name: %w", err)
+		return 0, "", fmt.Errorf("cannot find Parent name: %w", err)
 	}
-	childBase := path.Base(childName.LocalName())
-
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
 
-	var parentInodeID fuseops.InodeID
 	// Check in all maps: implicit dirs → folders → generation-backed
 	if parentInode, ok := fs.implicitDirInodes[parentName]; ok {
-		parentInodeID = parentInode.ID()
+		return parentInode.ID(), childBase, nil
 	} else if parentInode, ok := fs.folderInodes[parentName]; ok {
-		parentInodeID = parentInode.ID()
+		return parentInode.ID(), childBase, nil
 	} else if parentInode, ok := fs.generationBackedInodes[parentName]; ok {
-		parentInodeID = parentInode.ID()
-	} else {
-		return fmt.Errorf("invalidateCachedEntry: failed to invalidate the entry, parent inode not found for child ID %d (parent: %s)", childID, parentName.String())
+		return parentInode.ID(), childBase, nil
 	}
-
-	return fs.notifier.InvalidateEntry(parentInodeID, childBase)
+	return 0, "", fmt.Errorf("failed to invalidate the entry, parent inode not found for child ID %d (parent: %s)", childID, parentName.String())
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -1863,9 +2056,12 @@ func (fs *fileSystem) invalidateCachedEntry(childID fuseops.InodeID) error {
 ////////////////////////////////////////////////////////////////////////
 
 func (fs *fileSystem) Destroy() {
+	fs.bucketUsage.Stop()
 	fs.bucketManager.ShutDown()
 	if fs.fileCacheHandler != nil {
-		_ = fs.fileCacheHandler.Destroy()
+		if err := fs.fileCacheHandler.Destroy(); err != nil {
+			logger.Warnf("Destroy: while destroying the file cache handler: %v", err)
+		}
 	}
 	if fs.bufferedReadWorkerPool != nil {
 		fs.bufferedReadWorkerPool.Stop()
@@ -1875,13 +2071,30 @@ func (fs *fileSystem) Destroy() {
 func (fs *fileSystem) StatFS(
 	ctx context.Context,
 	op *fuseops.StatFSOp) (err error) {
-	// Simulate a large amount of free space so that the Finder doesn't refuse to
-	// copy in files. (See issue #125.) Use 2^17 as the block size because that
-	// is the largest that OS X will pass on.
+	// Use 2^17 as the block size because that is the largest that OS X will
+	// pass on.
 	op.BlockSize = 1 << 17
+
+	// Without a quota, simulate a large amount of free space so that the Finder
+	// doesn't refuse to copy in files. (See issue #125.)
 	op.Blocks = 1 << 33
 	op.BlocksFree = op.Blocks
-	op.BlocksAvailable = op.Blocks
+	if quotaMb := fs.newConfig.FileSystem.QuotaMb; quotaMb > 0 {
+		op.Blocks = uint64(quotaMb) * util.MiB / uint64(op.BlockSize)
+		usedBlocks := (fs.bucketUsage.Bytes() + uint64(op.BlockSize) - 1) / uint64(op.BlockSize)
+		op.BlocksFree = op.Blocks - min(usedBlocks, op.Blocks)
+	}
+
+	// Without streaming writes, writes are staged in the temp dir prior to
+	// upload, so don't report more available space than it has.
+	op.BlocksAvailable = op.BlocksFree
+	if !fs.newConfig.Write.EnableStreamingWrites {
+		if available, err := diskutil.GetAvailableSpace(fs.tempDir); err != nil {
+			logger.Warnf("StatFS: %v", err)
+		} else {
+			op.BlocksAvailable = min(op.BlocksAvailable, available/uint64(op.BlockSize))
+		}
+	}
 
 	// Similarly with inodes.
 	op.Inodes = 1 << 50
@@ -1975,6 +2188,9 @@ func (fs *fileSystem) SetInodeAttributes(
 
 	in.Lock()
 	defer in.Unlock()
+	if inode.IsVersionsInode(in) {
+		return syscall.EROFS
+	}
 	file, isFile := in.(*inode.FileInode)
 
 	// Set file mtimes.
@@ -2005,7 +2221,16 @@ func (fs *fileSystem) SetInodeAttributes(
 		}
 	}
 
-	// We silently ignore updates to mode and atime.
+	// Persist mode, ownership and atime of files in object metadata if enabled.
+	// Otherwise we silently ignore them.
+	if isFile && fs.newConfig.FileSystem.PersistPosixAttributes &&
+		(op.Mode != nil || op.Uid != nil || op.Gid != nil || op.Atime != nil) {
+		err = file.SetPosixAttributes(ctx, op.Mode, op.Uid, op.Gid, op.Atime)
+		if err != nil {
+			err = fmt.Errorf("SetPosixAttributes: %w", err)
+			return err
+		}
+	}
 
 	// Fill in the response.
 	op.Attributes, op.AttributesExpiration, err = fs.getAttributes(ctx, in)
@@ -2341,6 +2566,102 @@ func (fs *fileSystem) CreateSymlink(
 	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) CreateLink(
+	ctx context.Context,
+	op *fuseops.CreateLinkOp) (err error) {
+	if !fs.newConfig.FileSystem.EnableHardLinks {
+		return fuse.ENOSYS
+	}
+	ctx = fs.getInterruptlessContext(ctx)
+	// Find the parent and the target.
+	fs.mu.Lock()
+	parent := fs.dirInodeOrDie(op.Parent)
+	target := fs.inodeOrDie(op.Target)
+	fs.mu.Unlock()
+
+	if inode.IsVersionsInode(parent) {
+		return syscall.EROFS
+	}
+	file, ok := target.(*inode.FileInode)
+	if !ok {
+		return syscall.EPERM
+	}
+	bucketOwned, ok := parent.(inode.BucketOwnedDirInode)
+	if !ok || bucketOwned.Bucket().Name() != file.Bucket().Name() {
+		return syscall.EXDEV
+	}
+
+	// Count the new link, moving the contents of the target out of its name if
+	// it's the first one.
+	contents, err := fs.addHardLink(ctx, file)
+	if errors.Is(err, syscall.EBUSY) {
+		return syscall.EBUSY
+	}
+	if err != nil {
+		return fmt.Errorf("addHardLink: %w", err)
+	}
+
+	// If the contents were moved, the kernel must look the name of the target up
+	// again to find them.
+	if !inode.IsHardLinkContents(file.Name()) && fs.notifier != nil {
+		fs.invalidateCachedEntryAfterOp(file.ID())
+	}
+
+	// Create the link in GCS, failing if the name already exists.
+	parent.Lock()
+	result, err := parent.CreateChildHardLink(ctx, op.Name, contents)
+	parent.Unlock()
+
+	if err != nil {
+		contentsCore := inode.Core{
+			Bucket:    bucketOwned.Bucket(),
+			FullName:  inode.NewDescendantName(parent.Name(), contents.Name),
+			MinObject: contents,
+		}
+		if dropErr := fs.dropHardLinkOf(ctx, parent, contentsCore); dropErr != nil {
+			logger.Warnf("Dropping the link to %q after a failed link: %v", contents.Name, dropErr)
+		}
+	}
+
+	// Special case: *gcs.PreconditionError means the name already exists.
+	var preconditionErr *gcs.PreconditionError
+	if errors.As(err, &preconditionErr) {
+		err = fuse.EEXIST
+		return
+	}
+
+	// Propagate other errors.
+	if err != nil {
+		err = fmt.Errorf("CreateChildHardLink: %w", err)
+		return err
+	}
+
+	// Find the inode of the contents, which all the links share.
+	child, err := fs.lookUpOrCreateInodeIfNotStale(parent.Context(), *result)
+	if err != nil {
+		return err
+	}
+	if child == nil {
+		err = fmt.Errorf("newly-linked record is already stale")
+		return err
+	}
+
+	defer fs.unlockAndMaybeDisposeOfInode(child, &err)
+
+	// Fill out the response.
+	e := &op.Entry
+	e.Child = child.ID()
+	e.Attributes, e.AttributesExpiration, err = fs.getAttributes(ctx, child)
+
+	if err != nil {
+		err = fmt.Errorf("getAttributes: %w", err)
+		return err
+	}
+
+	return
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) RmDir(
 	// When rm -r or os.RemoveAll call is made, the following calls are made in order
@@ -2390,6 +2711,11 @@ func (fs *fileSystem) RmDir(
 		return
 	}
 
+	if inode.IsVersionsInode(childDir) {
+		err = syscall.EROFS
+		return
+	}
+
 	// Ensure that the child directory is empty.
 	//
 	// Yes, this is not atomic with the delete below. See here for discussion:
@@ -2430,7 +2756,7 @@ func (fs *fileSystem) RmDir(
 			}
 		}
 
-		if fs.kernelListCacheTTL > 0 {
+		if fs.kernelListCacheTTL.Load() > 0 {
 			// Clear kernel list cache after removing a directory. This ensures remote
 			// GCS files are included in future directory listings for unlinking.
 			childDir.InvalidateKernelListCache()
@@ -2491,6 +2817,10 @@ func (fs *fileSystem) Rename(
 		}
 	}
 
+	if inode.IsVersionsInode(oldParent) || inode.IsVersionsInode(newParent) {
+		return syscall.EROFS
+	}
+
 	child, err := fs.lookUpOrCreateChildInode(ctx, oldParent, op.OldName)
 	if err != nil {
 		return err
@@ -2501,6 +2831,10 @@ func (fs *fileSystem) Rename(
 	child.DecrementLookupCount(1)
 	child.Unlock()
 
+	if inode.IsVersionsInode(child) {
+		return syscall.EROFS
+	}
+
 	childBktOwned, ok := child.(inode.BucketOwnedInode)
 	if !ok { // Won't happen in ideal case.
 		return fmt.Errorf("child inode (id %v) is not owned by any bucket", child.ID())
@@ -2530,6 +2864,18 @@ func (fs *fileSystem) renameFile(ctx context.Context, op *fuseops.RenameOp, chil
 		if err != nil {
 			return fmt.Errorf("flushPendingWrites: %w", err)
 		}
+		// A hard link is renamed by moving its pointer, not the contents.
+		if inode.IsHardLinkContents(c.Name()) {
+			var core *inode.Core
+			core, err = fs.lookUpHardLink(ctx, oldParent, op.OldName)
+			if err != nil {
+				return err
+			}
+			if core == nil {
+				return fuse.ENOENT
+			}
+			updatedMinObject = core.HardLink
+		}
 	case *inode.SymlinkInode:
 		updatedMinObject = c.Source()
 	default:
@@ -2873,6 +3219,18 @@ func (fs *fileSystem) Unlink(
 
 	fs.mu.Unlock()
 
+	// A hard link is unlinked by dropping it from the count of its contents.
+	if fs.newConfig.FileSystem.EnableHardLinks && !isLocalFile && !holdsOwnContents(in) {
+		var core *inode.Core
+		core, err = fs.lookUpHardLink(ctx, parent, op.Name)
+		if err != nil {
+			return err
+		}
+		if core != nil {
+			return fs.unlinkHardLink(ctx, parent, op.Name, core)
+		}
+	}
+
 	if in != nil {
 		// Perform the unlink operation on the inode.
 		in.Lock()
@@ -2935,10 +3293,10 @@ func (fs *fileSystem) OpenDir(
 	fs.mu.Unlock()
 
 	// Enables kernel list-cache in case of non-zero kernelListCacheTTL.
-	if fs.kernelListCacheTTL > 0 {
+	if ttl := time.Duration(fs.kernelListCacheTTL.Load()); ttl > 0 {
 		// Invalidates the kernel list-cache once the last cached response is out of
 		// kernelListCacheTTL.
-		op.KeepCache = !in.ShouldInvalidateKernelListCache(fs.kernelListCacheTTL)
+		op.KeepCache = !in.ShouldInvalidateKernelListCache(ttl)
 
 		op.CacheDir = true
 	}
@@ -3046,6 +3404,11 @@ func (fs *fileSystem) OpenFile(
 
 	fs.mu.Lock()
 
+	if generationFile, ok := fs.inodes[op.Inode].(*inode.GenerationFileInode); ok {
+		fs.mu.Unlock()
+		return fs.openGenerationFile(generationFile, op)
+	}
+
 	// Find the inode.
 	in := fs.fileInodeOrDie(op.Inode)
 	// Follow lock ordering rules to get inode lock.
@@ -3087,6 +3450,32 @@ func (fs *fileSystem) OpenFile(
 	return
 }
 
+// openGenerationFile opens a file of the versions directory tree, which can
+// only be read.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) openGenerationFile(in *inode.GenerationFileInode, op *fuseops.OpenFileOp) error {
+	if util.FileOpenMode(op.OpenFlags).AccessMode() != util.ReadOnly {
+		return syscall.EROFS
+	}
+	if in.IsSoftDeleted() {
+		logger.Warnf("Generation %d of %q is soft-deleted and must be restored before it can be read", in.Source().Generation, in.Source().Name)
+		return syscall.EACCES
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	op.Handle = fs.nextHandleID
+	fs.nextHandleID++
+	fs.handles[op.Handle] = handle.NewGenerationFileHandle(in)
+
+	// Generations are immutable.
+	op.KeepPageCache = true
+
+	return nil
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) ReadFile(
 	ctx context.Context,
@@ -3095,6 +3484,14 @@ func (fs *fileSystem) ReadFile(
 
 	// Find the handle and lock it.
 	fs.mu.Lock()
+	if gh, ok := fs.handles[op.Handle].(*handle.GenerationFileHandle); ok {
+		fs.mu.Unlock()
+		op.BytesRead, err = gh.Read(ctx, op.Dst, op.Offset)
+		if err == io.EOF {
+			err = nil
+		}
+		return err
+	}
 	fh := fs.handles[op.Handle].(*handle.FileHandle)
 	fs.mu.Unlock()
 
@@ -3223,6 +3620,48 @@ func (fs *fileSystem) WriteFile(
 	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) Fallocate(
+	ctx context.Context,
+	op *fuseops.FallocateOp) (err error) {
+	ctx = fs.getInterruptlessContext(ctx)
+
+	// Punching holes and the other modes can't be supported without rewriting
+	// the object.
+	if op.Mode&^unix.FALLOC_FL_KEEP_SIZE != 0 {
+		return syscall.EOPNOTSUPP
+	}
+
+	// Find the inode and file handle.
+	fs.mu.Lock()
+	fh := fs.handles[op.Handle].(*handle.FileHandle)
+	in := fs.fileInodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	// Cloud Storage has no notion of allocated space, so there is nothing to do
+	// unless the file grows.
+	if op.Mode&unix.FALLOC_FL_KEEP_SIZE != 0 {
+		return
+	}
+
+	in.Lock()
+	defer in.Unlock()
+	if err = fs.initBufferedWriteHandlerAndSyncFileIfEligible(ctx, in, fh.OpenMode()); err != nil {
+		return err
+	}
+	gcsSynced, err := in.Fallocate(ctx, int64(op.Offset+op.Length))
+	// Sync the inode if finalize during fallocate is successful even if the
+	// fallocate operation later resulted in error.
+	if gcsSynced {
+		fs.promoteToGenerationBacked(in)
+	}
+	if err != nil {
+		err = fmt.Errorf("fallocate: %w", err)
+		return err
+	}
+	return
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) SyncFile(
 	ctx context.Context,
@@ -3257,6 +3696,11 @@ func (fs *fileSystem) FlushFile(
 	ctx = fs.getInterruptlessContext(ctx)
 	// Find the inode.
 	fs.mu.Lock()
+	if _, ok := fs.inodes[op.Inode].(*inode.GenerationFileInode); ok {
+		// Nothing to flush.
+		fs.mu.Unlock()
+		return
+	}
 	in := fs.fileInodeOrDie(op.Inode)
 	fs.mu.Unlock()
 
@@ -3277,6 +3721,11 @@ func (fs *fileSystem) ReleaseFileHandle(
 	op *fuseops.ReleaseFileHandleOp) (err error) {
 	fs.mu.Lock()
 
+	if _, ok := fs.handles[op.Handle].(*handle.GenerationFileHandle); ok {
+		delete(fs.handles, op.Handle)
+		fs.mu.Unlock()
+		return
+	}
 	fileHandle := fs.handles[op.Handle].(*handle.FileHandle)
 	// Update the map. We are okay updating the map before destroy is called
 	// since destroy is doing only internal cleanup.
@@ -3291,16 +3740,145 @@ func (fs *fileSystem) ReleaseFileHandle(
 	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) GetXattr(
 	ctx context.Context,
 	op *fuseops.GetXattrOp) (err error) {
-	return syscall.ENOSYS
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+	ctx = fs.getInterruptlessContext(ctx)
+
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	// Only files carry extended attributes.
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return fuse.ENOATTR
+	}
+
+	file.Lock()
+	value, err := file.GetXattr(ctx, op.Name)
+	file.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// An empty destination buffer asks for the size of the value.
+	op.BytesRead = len(value)
+	if len(op.Dst) == 0 {
+		return
+	}
+	if len(op.Dst) < len(value) {
+		return syscall.ERANGE
+	}
+	copy(op.Dst, value)
+
+	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) ListXattr(
 	ctx context.Context,
 	op *fuseops.ListXattrOp) error {
-	return syscall.ENOSYS
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return nil
+	}
+
+	file.Lock()
+	names := file.ListXattrs()
+	file.Unlock()
+
+	// The names are returned as a sequence of NUL-terminated strings.
+	for _, name := range names {
+		op.BytesRead += len(name) + 1
+	}
+	if len(op.Dst) == 0 {
+		return nil
+	}
+	if len(op.Dst) < op.BytesRead {
+		return syscall.ERANGE
+	}
+	dst := op.Dst[:0]
+	for _, name := range names {
+		dst = append(dst, name...)
+		dst = append(dst, 0)
+	}
+
+	return nil
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) SetXattr(
+	ctx context.Context,
+	op *fuseops.SetXattrOp) (err error) {
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+	ctx = fs.getInterruptlessContext(ctx)
+
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return syscall.ENOTSUP
+	}
+
+	file.Lock()
+	defer file.Unlock()
+
+	if err = file.SetXattr(ctx, op.Name, op.Value, op.Flags); err != nil {
+		err = fmt.Errorf("SetXattr: %w", err)
+		return err
+	}
+
+	return
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) RemoveXattr(
+	ctx context.Context,
+	op *fuseops.RemoveXattrOp) (err error) {
+	if !fs.newConfig.FileSystem.EnableXattrs {
+		return syscall.ENOSYS
+	}
+	ctx = fs.getInterruptlessContext(ctx)
+
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return fuse.ENOATTR
+	}
+
+	file.Lock()
+	defer file.Unlock()
+
+	if err = file.RemoveXattr(ctx, op.Name); err != nil {
+		err = fmt.Errorf("RemoveXattr: %w", err)
+		return err
+	}
+
+	return
 }
 
 func (fs *fileSystem) SyncFS(
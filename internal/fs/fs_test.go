@@ -372,6 +372,10 @@ type fakeBucketManager struct {
 	tmpObjectPrefix          string
 }
 
+func (bm *fakeBucketManager) UpdateConfig(gcsx.BucketConfig) error { return nil }
+
+func (bm *fakeBucketManager) InvalidateStatCache(string, string) {}
+
 func (bm *fakeBucketManager) ShutDown() {}
 
 func (bm *fakeBucketManager) SetUpBucket(
@@ -380,12 +384,13 @@ func (bm *fakeBucketManager) SetUpBucket(
 	bucket, ok := bm.buckets[name]
 	if ok {
 		sb = gcsx.NewSyncerBucket(
-			bm.appendThreshold,
-			bm.chunkRetryDeadlineSecs,
-			bm.chunkTransferTimeoutSecs,
-			bm.tmpObjectPrefix,
-			gcsx.NewContentTypeBucket(bucket),
-		)
+			gcsx.SyncerConfig{
+				AppendThreshold:          bm.appendThreshold,
+				ChunkRetryDeadlineSecs:   bm.chunkRetryDeadlineSecs,
+				ChunkTransferTimeoutSecs: bm.chunkTransferTimeoutSecs,
+				TmpObjectPrefix:          bm.tmpObjectPrefix,
+			},
+			gcsx.NewContentTypeBucket(bucket))
 		return
 	}
 	err = fmt.Errorf("Bucket %q does not exist", name)
@@ -57,15 +57,19 @@ func (bm *fakeBucketManagerWithMetrics) SetUpBucket(
 
 	// Wrap bucket with monitor.NewMonitoringBucket to enable GCS metrics.
 	sb = gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 0,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/",
-		gcsx.NewContentTypeBucket(monitor.NewMonitoringBucket(bucket, mh)),
-	)
+		gcsx.SyncerConfig{
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		gcsx.NewContentTypeBucket(monitor.NewMonitoringBucket(bucket, mh)))
 	return sb, err
 }
 
+func (bm *fakeBucketManagerWithMetrics) UpdateConfig(gcsx.BucketConfig) error { return nil }
+
+func (bm *fakeBucketManagerWithMetrics) InvalidateStatCache(string, string) {}
+
 func (bm *fakeBucketManagerWithMetrics) ShutDown() {}
 
 func createTestFileSystemWithMonitoredBucket(ctx context.Context, t *testing.T, params *serverConfigParams) (gcs.Bucket, fuseutil.FileSystem, metrics.MetricHandle, *metric.ManualReader) {
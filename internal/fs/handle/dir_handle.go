@@ -370,6 +370,11 @@ func (dh *DirHandle) ensureEntries(ctx context.Context, localFileEntries map[str
 // Public interface
 ////////////////////////////////////////////////////////////////////////
 
+// Inode returns the inode backing this handle.
+func (dh *DirHandle) Inode() inode.DirInode {
+	return dh.in
+}
+
 // ReadDir handles a request to read from the directory, without responding.
 //
 // Special case: we assume that a zero offset indicates that rewinddir has been
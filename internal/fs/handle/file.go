@@ -235,15 +235,17 @@ func (fh *FileHandle) ReadWithReadManager(ctx context.Context, req *gcsx.ReadReq
 			SequentialReadSizeMB:    sequentialReadSizeMb,
 			FileCacheHandler:        fh.fileCacheHandler,
 			SharedChunkCacheManager: fh.SharedChunkCacheManager,
-			CacheFileForRangeRead:   fh.cacheFileForRangeRead,
-			MetricHandle:            fh.metricHandle,
-			TraceHandle:             fh.traceHandle,
-			MrdWrapper:              mrdWrapper,
-			Config:                  fh.config,
-			GlobalMaxBlocksSem:      fh.globalMaxReadBlocksSem,
-			WorkerPool:              fh.bufferedReadWorkerPool,
-			HandleID:                fh.handleID,
-			InitialOffset:           req.Offset,
+			// Compressed objects are cached in full on random reads, which
+			// would otherwise decompress them from the start each time.
+			CacheFileForRangeRead: fh.cacheFileForRangeRead || gcsx.IsCompressed(minObj),
+			MetricHandle:          fh.metricHandle,
+			TraceHandle:           fh.traceHandle,
+			MrdWrapper:            mrdWrapper,
+			Config:                fh.config,
+			GlobalMaxBlocksSem:    fh.globalMaxReadBlocksSem,
+			WorkerPool:            fh.bufferedReadWorkerPool,
+			HandleID:              fh.handleID,
+			InitialOffset:         req.Offset,
 		})
 
 		// Override the read-manager with visual-read-manager (a wrapper over read_manager with visualizer) if configured.
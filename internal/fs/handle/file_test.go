@@ -74,11 +74,13 @@ func (t *fileTest) SetupTest() {
 	t.ctx = context.TODO()
 	t.clock.SetTime(time.Date(2015, 4, 5, 2, 15, 0, 0, time.Local))
 	t.bucket = gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/", fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{}),
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{}))
 }
 
 func (t *fileTest) TearDownTest() {
@@ -677,11 +679,13 @@ func (t *fileTest) Test_ReadWithKernelReader_Success() {
 			mockBucket.On("BucketType").Return(gcs.BucketType{Zonal: tc.isZonal})
 			mockBucket.On("CreateObject", mock.Anything, mock.Anything).Return(&gcs.Object{}, nil).Once()
 			mockSyncerBucket := gcsx.NewSyncerBucket(
-				/*appendThreshold=*/ 1,
-				/*chunkRetryDeadlineSecs=*/ 120,
-				/*chunkTransferTimeoutSecs=*/ 10,
-				".gcsfuse_tmp/", mockBucket,
-			)
+				gcsx.SyncerConfig{
+					AppendThreshold:          1,
+					ChunkRetryDeadlineSecs:   120,
+					ChunkTransferTimeoutSecs: 10,
+					TmpObjectPrefix:          ".gcsfuse_tmp/",
+				},
+				mockBucket)
 			parent := createDirInode(&mockSyncerBucket, &t.clock)
 			// Create the file inode and file handle. Setting EnableKernelReader to true initializes fh.kernelReader.
 			in := createFileInode(t.T(), &mockSyncerBucket, &t.clock, &cfg.Config{}, parent, objectName, expectedData, false)
@@ -734,11 +738,13 @@ func (t *fileTest) Test_ReadWithKernelReader_NotAuthoritative() {
 		t.Run(tc.name, func() {
 			t.SetupTest()
 			bucket := gcsx.NewSyncerBucket(
-				/*appendThreshold=*/ 1,
-				/*chunkRetryDeadlineSecs=*/ 120,
-				/*chunkTransferTimeoutSecs=*/ 10,
-				".gcsfuse_tmp/", fake.NewFakeBucket(&t.clock, "test_bucket", gcs.BucketType{Zonal: tc.isZonal}),
-			)
+				gcsx.SyncerConfig{
+					AppendThreshold:          1,
+					ChunkRetryDeadlineSecs:   120,
+					ChunkTransferTimeoutSecs: 10,
+					TmpObjectPrefix:          ".gcsfuse_tmp/",
+				},
+				fake.NewFakeBucket(&t.clock, "test_bucket", gcs.BucketType{Zonal: tc.isZonal}))
 			originalData := []byte("some data")
 			// Create file inode.
 			parent := createDirInode(&bucket, &t.clock)
@@ -789,11 +795,13 @@ func (t *fileTest) Test_ReadWithKernelReader_NotAuthoritative_ReadError() {
 		t.Run(tc.name, func() {
 			t.SetupTest()
 			bucket := gcsx.NewSyncerBucket(
-				/*appendThreshold=*/ 1,
-				/*chunkRetryDeadlineSecs=*/ 120,
-				/*chunkTransferTimeoutSecs=*/ 10,
-				".gcsfuse_tmp/", fake.NewFakeBucket(&t.clock, "test_bucket", gcs.BucketType{Zonal: tc.isZonal}),
-			)
+				gcsx.SyncerConfig{
+					AppendThreshold:          1,
+					ChunkRetryDeadlineSecs:   120,
+					ChunkTransferTimeoutSecs: 10,
+					TmpObjectPrefix:          ".gcsfuse_tmp/",
+				},
+				fake.NewFakeBucket(&t.clock, "test_bucket", gcs.BucketType{Zonal: tc.isZonal}))
 			originalData := []byte("some data")
 			// Create file inode.
 			parent := createDirInode(&bucket, &t.clock)
@@ -843,11 +851,13 @@ func (t *fileTest) Test_ReadWithKernelReader_NilReader() {
 		t.Run(tc.name, func() {
 			t.SetupTest()
 			bucket := gcsx.NewSyncerBucket(
-				/*appendThreshold=*/ 1,
-				/*chunkRetryDeadlineSecs=*/ 120,
-				/*chunkTransferTimeoutSecs=*/ 10,
-				".gcsfuse_tmp/", fake.NewFakeBucket(&t.clock, "test_bucket", gcs.BucketType{Zonal: tc.isZonal}),
-			)
+				gcsx.SyncerConfig{
+					AppendThreshold:          1,
+					ChunkRetryDeadlineSecs:   120,
+					ChunkTransferTimeoutSecs: 10,
+					TmpObjectPrefix:          ".gcsfuse_tmp/",
+				},
+				fake.NewFakeBucket(&t.clock, "test_bucket", gcs.BucketType{Zonal: tc.isZonal}))
 			// Create file inode.
 			parent := createDirInode(&bucket, &t.clock)
 			in := createFileInode(t.T(), &bucket, &t.clock, &cfg.Config{}, parent, "test_obj", []byte("data"), false)
@@ -896,11 +906,13 @@ func (t *fileTest) Test_ReadWithKernelReader_ReadAtError() {
 			mockBucket.On("BucketType").Return(gcs.BucketType{Zonal: tc.isZonal})
 			mockBucket.On("CreateObject", mock.Anything, mock.Anything).Return(&gcs.Object{}, nil).Once()
 			mockSyncerBucket := gcsx.NewSyncerBucket(
-				/*appendThreshold=*/ 1,
-				/*chunkRetryDeadlineSecs=*/ 120,
-				/*chunkTransferTimeoutSecs=*/ 10,
-				".gcsfuse_tmp/", mockBucket,
-			)
+				gcsx.SyncerConfig{
+					AppendThreshold:          1,
+					ChunkRetryDeadlineSecs:   120,
+					ChunkTransferTimeoutSecs: 10,
+					TmpObjectPrefix:          ".gcsfuse_tmp/",
+				},
+				mockBucket)
 			// Create file inode & file handle with kernel reader enabled.
 			parent := createDirInode(&mockSyncerBucket, &t.clock)
 			in := createFileInode(t.T(), &mockSyncerBucket, &t.clock, &cfg.Config{FileSystem: cfg.FileSystemConfig{EnableKernelReader: true}}, parent, objectName, expectedData, false)
@@ -1371,11 +1383,13 @@ func (t *fileTest) Test_ShouldSkipSizeChecks() {
 			t.Run(bt.name+"_"+tc.name, func() {
 				// Setup test bucket.
 				t.bucket = gcsx.NewSyncerBucket(
-					/*appendThreshold=*/ 1,
-					/*chunkRetryDeadlineSecs=*/ 120,
-					/*chunkTransferTimeoutSecs=*/ 10,
-					".gcsfuse_tmp/", fake.NewFakeBucket(&t.clock, "some_bucket", bt.bucketType),
-				)
+					gcsx.SyncerConfig{
+						AppendThreshold:          1,
+						ChunkRetryDeadlineSecs:   120,
+						ChunkTransferTimeoutSecs: 10,
+						TmpObjectPrefix:          ".gcsfuse_tmp/",
+					},
+					fake.NewFakeBucket(&t.clock, "some_bucket", bt.bucketType))
 				parent := createDirInode(&t.bucket, &t.clock)
 				config := &cfg.Config{}
 				in := createFileInode(t.T(), &t.bucket, &t.clock, config, parent, tc.object.Name, nil, false)
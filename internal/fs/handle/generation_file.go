@@ -0,0 +1,45 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+)
+
+// GenerationFileHandle is a read-only handle on a file of the versions
+// directory tree. Reads go straight to the generation in GCS, bypassing the
+// file cache and the readers of FileHandle, as old generations are rarely
+// read more than once.
+type GenerationFileHandle struct {
+	inode *inode.GenerationFileInode
+}
+
+// NewGenerationFileHandle returns a handle on the given file.
+func NewGenerationFileHandle(in *inode.GenerationFileInode) *GenerationFileHandle {
+	return &GenerationFileHandle{inode: in}
+}
+
+func (gh *GenerationFileHandle) Inode() *inode.GenerationFileInode {
+	return gh.inode
+}
+
+// Read reads the generation at the given offset into dst.
+//
+// LOCKS_EXCLUDED(gh.inode)
+func (gh *GenerationFileHandle) Read(ctx context.Context, dst []byte, offset int64) (n int, err error) {
+	return gh.inode.Read(ctx, dst, offset)
+}
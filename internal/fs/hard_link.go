@@ -0,0 +1,238 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+)
+
+// addHardLink counts a new link to the supplied file, moving its contents
+// under inode.HardLinksDirName if it's the first one, and returns the object
+// holding the contents.
+//
+// The first link is refused with EBUSY while the file is open for writing,
+// since the open handles would keep writing to the original name, which no
+// longer holds the contents.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(f)
+func (fs *fileSystem) addHardLink(ctx context.Context, f *inode.FileInode) (*gcs.MinObject, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.IsUnlinked() {
+		return nil, fuse.ENOENT
+	}
+
+	// Write out any pending writes, so that the links share them.
+	if err := fs.flushFile(ctx, f); err != nil {
+		return nil, fmt.Errorf("flushFile: %w", err)
+	}
+
+	if inode.IsHardLinkContents(f.Name()) {
+		if err := f.SetLinkCount(ctx, inode.LinkCount(f.Source())+1); err != nil {
+			return nil, fmt.Errorf("SetLinkCount: %w", err)
+		}
+		return f.Source(), nil
+	}
+
+	if f.HasWriteHandles() {
+		return nil, syscall.EBUSY
+	}
+	contents, err := fs.moveToHardLinkContents(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name of the file now points to the contents, which have an inode of
+	// their own.
+	f.Unlink()
+	return contents, nil
+}
+
+// moveToHardLinkContents copies the object backing the supplied file to a new
+// contents object with two links, and replaces the original with a pointer to
+// it. The inode of the file is left stale, so the caller must invalidate the
+// kernel's entry of the original name.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_REQUIRED(f)
+func (fs *fileSystem) moveToHardLinkContents(ctx context.Context, f *inode.FileInode) (*gcs.MinObject, error) {
+	bucket := f.Bucket()
+	src := f.Source()
+	var zero int64
+	o, err := bucket.CopyObject(ctx, &gcs.CopyObjectRequest{
+		SrcName:                       src.Name,
+		SrcGeneration:                 src.Generation,
+		SrcMetaGenerationPrecondition: &src.MetaGeneration,
+		DstName:                       inode.NewHardLinkContentsName(f.Name()).GcsObjectName(),
+		DstGenerationPrecondition:     &zero,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CopyObject: %w", err)
+	}
+
+	count := "2"
+	contents, err := bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name:                       o.Name,
+		Generation:                 o.Generation,
+		MetaGenerationPrecondition: &o.MetaGeneration,
+		Metadata:                   map[string]*string{inode.LinkCountMetadataKey: &count},
+	})
+	if err != nil {
+		fs.deleteHardLinkContents(ctx, bucket, o.Name)
+		return nil, fmt.Errorf("UpdateObject: %w", err)
+	}
+
+	// Replace the original object, unless it changed since it was copied.
+	_, err = bucket.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:                       src.Name,
+		Contents:                   strings.NewReader(""),
+		Metadata:                   map[string]string{inode.HardLinkMetadataKey: contents.Name},
+		GenerationPrecondition:     &src.Generation,
+		MetaGenerationPrecondition: &src.MetaGeneration,
+	})
+	if err != nil {
+		fs.deleteHardLinkContents(ctx, bucket, contents.Name)
+		return nil, fmt.Errorf("CreateObject: %w", err)
+	}
+
+	return storageutil.ConvertObjToMinObject(contents), nil
+}
+
+// deleteHardLinkContents deletes a contents object that no link points to,
+// logging any error.
+func (fs *fileSystem) deleteHardLinkContents(ctx context.Context, bucket gcs.Bucket, name string) {
+	if err := bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: name}); err != nil {
+		logger.Warnf("Deleting unused hard link contents %q: %v", name, err)
+	}
+}
+
+// dropHardLink removes a link from the count of the supplied contents of a
+// file with hard links, deleting them with the last link.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_REQUIRED(f)
+func (fs *fileSystem) dropHardLink(ctx context.Context, f *inode.FileInode) error {
+	if err := fs.flushFile(ctx, f); err != nil {
+		return fmt.Errorf("flushFile: %w", err)
+	}
+
+	src := f.Source()
+	if count := inode.LinkCount(src); count > 1 {
+		if err := f.SetLinkCount(ctx, count-1); err != nil {
+			return fmt.Errorf("SetLinkCount: %w", err)
+		}
+		return nil
+	}
+
+	f.Unlink()
+	err := f.Bucket().DeleteObject(ctx, &gcs.DeleteObjectRequest{
+		Name:                       src.Name,
+		Generation:                 src.Generation,
+		MetaGenerationPrecondition: &src.MetaGeneration,
+	})
+	if err != nil {
+		return fmt.Errorf("DeleteObject: %w", err)
+	}
+	return nil
+}
+
+// holdsOwnContents returns true if the supplied inode of a name is a file
+// whose cached source object holds its contents rather than pointing to the
+// contents of hard links, so that the name is known not to be a hard link
+// without looking it up. Hard links resolve to the inode of their contents, so
+// their names usually have no inode.
+//
+// LOCKS_EXCLUDED(in)
+func holdsOwnContents(in inode.Inode) bool {
+	f, ok := in.(*inode.FileInode)
+	if !ok {
+		return false
+	}
+	f.Lock()
+	defer f.Unlock()
+	_, isPointer := f.Source().Metadata[inode.HardLinkMetadataKey]
+	return !f.IsUnlinked() && !isPointer
+}
+
+// lookUpHardLink returns the pointer object of the child of the supplied name,
+// or nil if it's not a hard link.
+//
+// LOCKS_EXCLUDED(parent)
+func (fs *fileSystem) lookUpHardLink(ctx context.Context, parent inode.DirInode, name string) (*inode.Core, error) {
+	parent.Lock()
+	core, err := parent.LookUpChild(ctx, name)
+	parent.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("LookUpChild: %w", err)
+	}
+	if core == nil || core.HardLink == nil {
+		return nil, nil
+	}
+	return core, nil
+}
+
+// unlinkHardLink deletes the pointer object of a hard link and drops the link
+// from the count of its contents.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(parent)
+func (fs *fileSystem) unlinkHardLink(ctx context.Context, parent inode.DirInode, name string, core *inode.Core) (err error) {
+	parent.Lock()
+	err = parent.DeleteChildFile(ctx, name, core.HardLink.Generation, &core.HardLink.MetaGeneration)
+	parent.Unlock()
+	var preconditionErr *gcs.PreconditionError
+	if errors.As(err, &preconditionErr) {
+		// Someone else replaced the link; it's no longer ours to unlink.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("DeleteChildFile: %w", err)
+	}
+
+	return fs.dropHardLinkOf(ctx, parent, *core)
+}
+
+// dropHardLinkOf drops a link from the count of the contents with the supplied
+// core.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) dropHardLinkOf(ctx context.Context, parent inode.DirInode, core inode.Core) error {
+	in, err := fs.lookUpOrCreateInodeIfNotStale(parent.Context(), core)
+	if err != nil {
+		return err
+	}
+	if in == nil {
+		return fmt.Errorf("hard link contents %q are stale", core.FullName)
+	}
+	defer fs.unlockAndDecrementLookupCount(in, 1)
+
+	f, ok := in.(*inode.FileInode)
+	if !ok {
+		return fmt.Errorf("hard link contents %q are not a file", core.FullName)
+	}
+	return fs.dropHardLink(ctx, f)
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HardLinkTest struct {
+	suite.Suite
+	ctx      context.Context
+	bucket   gcs.Bucket
+	stats    *statCountingBucket
+	server   fuseutil.FileSystem
+	notifier *fuse.Notifier
+}
+
+func TestHardLinkSuite(t *testing.T) {
+	suite.Run(t, new(HardLinkTest))
+}
+
+func (t *HardLinkTest) newFileSystem(enableHardLinks bool) {
+	bucketName := "test-bucket"
+	t.bucket = fake.NewFakeBucket(timeutil.RealClock(), bucketName, gcs.BucketType{})
+	t.stats = &statCountingBucket{Bucket: t.bucket}
+	serverCfg := &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			Write:           cfg.WriteConfig{GlobalMaxBlocks: 1},
+			EnableNewReader: true,
+			MetadataCache:   cfg.MetadataCacheConfig{TypeCacheMaxSizeMb: 4},
+			FileSystem:      cfg.FileSystemConfig{EnableHardLinks: enableHardLinks},
+		},
+		CacheClock: &timeutil.SimulatedClock{},
+		BucketName: bucketName,
+		BucketManager: &fakeBucketManager{
+			buckets: map[string]gcs.Bucket{bucketName: t.stats},
+		},
+		SequentialReadSizeMb: 200,
+		Notifier:             t.notifier,
+		TraceHandle:          tracing.NewNoopTracer(),
+		MetricHandle:         metrics.NewNoopMetrics(),
+	}
+	var err error
+	t.server, err = fs.NewFileSystem(t.ctx, serverCfg)
+	require.NoError(t.T(), err)
+}
+
+func (t *HardLinkTest) SetupTest() {
+	t.ctx = context.Background()
+	t.notifier = nil
+	t.newFileSystem(true)
+	createWithContents(t.ctx, t.T(), t.bucket, "foo", "taco")
+}
+
+func (t *HardLinkTest) lookUp(name string) (fuseops.ChildInodeEntry, error) {
+	op := &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: name}
+	err := t.server.LookUpInode(t.ctx, op)
+	return op.Entry, err
+}
+
+func (t *HardLinkTest) link(target fuseops.InodeID, name string) (fuseops.ChildInodeEntry, error) {
+	op := &fuseops.CreateLinkOp{Parent: fuseops.RootInodeID, Name: name, Target: target}
+	err := t.server.CreateLink(t.ctx, op)
+	return op.Entry, err
+}
+
+func (t *HardLinkTest) unlink(name string) error {
+	return t.server.Unlink(t.ctx, &fuseops.UnlinkOp{Parent: fuseops.RootInodeID, Name: name})
+}
+
+func (t *HardLinkTest) readObject(name string) string {
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, name)
+	require.NoError(t.T(), err)
+	return string(contents)
+}
+
+func (t *HardLinkTest) TestCreateLink_Disabled() {
+	t.newFileSystem(false)
+	createWithContents(t.ctx, t.T(), t.bucket, "foo", "taco")
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+
+	_, err = t.link(foo.Child, "bar")
+
+	assert.ErrorIs(t.T(), err, fuse.ENOSYS)
+}
+
+func (t *HardLinkTest) TestCreateLink() {
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+
+	bar, err := t.link(foo.Child, "bar")
+
+	require.NoError(t.T(), err)
+	assert.EqualValues(t.T(), 2, bar.Attributes.Nlink)
+	assert.EqualValues(t.T(), len("taco"), bar.Attributes.Size)
+	// Both names now resolve to the same inode.
+	foo, err = t.lookUp("foo")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), bar.Child, foo.Child)
+	assert.EqualValues(t.T(), 2, foo.Attributes.Nlink)
+	// The contents are hidden.
+	_, err = t.lookUp(inode.HardLinksDirName)
+	assert.ErrorIs(t.T(), err, fuse.ENOENT)
+}
+
+func (t *HardLinkTest) TestCreateLink_SameDirectoryWithNotifier() {
+	// A notifier that isn't served blocks its notifications, like the kernel
+	// does while it holds the lock of the directory during the link.
+	t.notifier = fuse.NewNotifier()
+	t.newFileSystem(true)
+	createWithContents(t.ctx, t.T(), t.bucket, "dir/", "")
+	createWithContents(t.ctx, t.T(), t.bucket, "dir/foo", "taco")
+	dir, err := t.lookUp("dir")
+	require.NoError(t.T(), err)
+	lookUpOp := &fuseops.LookUpInodeOp{Parent: dir.Child, Name: "foo"}
+	require.NoError(t.T(), t.server.LookUpInode(t.ctx, lookUpOp))
+
+	linkOp := &fuseops.CreateLinkOp{Parent: dir.Child, Name: "bar", Target: lookUpOp.Entry.Child}
+	linked := make(chan error, 1)
+	go func() {
+		linked <- t.server.CreateLink(t.ctx, linkOp)
+	}()
+
+	select {
+	case err = <-linked:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t.T(), "CreateLink waited for the notification")
+	}
+	require.NoError(t.T(), err)
+	lookUpOp = &fuseops.LookUpInodeOp{Parent: dir.Child, Name: "foo"}
+	require.NoError(t.T(), t.server.LookUpInode(t.ctx, lookUpOp))
+	assert.Equal(t.T(), linkOp.Entry.Child, lookUpOp.Entry.Child)
+	assert.EqualValues(t.T(), 2, lookUpOp.Entry.Attributes.Nlink)
+}
+
+func (t *HardLinkTest) TestCreateLink_ThirdLink() {
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+	bar, err := t.link(foo.Child, "bar")
+	require.NoError(t.T(), err)
+
+	baz, err := t.link(bar.Child, "baz")
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), bar.Child, baz.Child)
+	assert.EqualValues(t.T(), 3, baz.Attributes.Nlink)
+}
+
+func (t *HardLinkTest) TestCreateLink_Exists() {
+	createWithContents(t.ctx, t.T(), t.bucket, "bar", "burrito")
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+
+	_, err = t.link(foo.Child, "bar")
+
+	assert.ErrorIs(t.T(), err, fuse.EEXIST)
+	assert.Equal(t.T(), "burrito", t.readObject("bar"))
+	foo, err = t.lookUp("foo")
+	require.NoError(t.T(), err)
+	assert.EqualValues(t.T(), 1, foo.Attributes.Nlink)
+}
+
+func (t *HardLinkTest) TestCreateLink_OpenForWriting() {
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+	openOp := &fuseops.OpenFileOp{Inode: foo.Child, OpenFlags: syscall.O_WRONLY}
+	require.NoError(t.T(), t.server.OpenFile(t.ctx, openOp))
+
+	_, err = t.link(foo.Child, "bar")
+
+	assert.ErrorIs(t.T(), err, syscall.EBUSY)
+	_, err = t.lookUp("bar")
+	assert.ErrorIs(t.T(), err, fuse.ENOENT)
+	assert.Equal(t.T(), "taco", t.readObject("foo"))
+	// Once the writer is gone, the link can be created.
+	require.NoError(t.T(), t.server.ReleaseFileHandle(t.ctx, &fuseops.ReleaseFileHandleOp{Handle: openOp.Handle}))
+	_, err = t.link(foo.Child, "bar")
+	assert.NoError(t.T(), err)
+}
+
+func (t *HardLinkTest) TestUnlink() {
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+	bar, err := t.link(foo.Child, "bar")
+	require.NoError(t.T(), err)
+
+	require.NoError(t.T(), t.unlink("foo"))
+
+	_, err = t.lookUp("foo")
+	assert.ErrorIs(t.T(), err, fuse.ENOENT)
+	bar, err = t.lookUp("bar")
+	require.NoError(t.T(), err)
+	assert.EqualValues(t.T(), 1, bar.Attributes.Nlink)
+	assert.EqualValues(t.T(), len("taco"), bar.Attributes.Size)
+}
+
+func (t *HardLinkTest) TestUnlink_NotALink() {
+	_, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+	t.stats.stats = 0
+
+	require.NoError(t.T(), t.unlink("foo"))
+
+	// The inode of the name shows that it's not a link without a stat.
+	assert.Zero(t.T(), t.stats.stats)
+	_, err = storageutil.ReadObject(t.ctx, t.bucket, "foo")
+	assert.ErrorAs(t.T(), err, new(*gcs.NotFoundError))
+}
+
+func (t *HardLinkTest) TestUnlink_LastLink() {
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+	_, err = t.link(foo.Child, "bar")
+	require.NoError(t.T(), err)
+
+	require.NoError(t.T(), t.unlink("foo"))
+	require.NoError(t.T(), t.unlink("bar"))
+
+	listing, err := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{})
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), listing.MinObjects)
+}
+
+func (t *HardLinkTest) TestRename() {
+	foo, err := t.lookUp("foo")
+	require.NoError(t.T(), err)
+	bar, err := t.link(foo.Child, "bar")
+	require.NoError(t.T(), err)
+
+	err = t.server.Rename(t.ctx, &fuseops.RenameOp{
+		OldParent: fuseops.RootInodeID,
+		OldName:   "bar",
+		NewParent: fuseops.RootInodeID,
+		NewName:   "baz",
+	})
+
+	require.NoError(t.T(), err)
+	_, err = t.lookUp("bar")
+	assert.ErrorIs(t.T(), err, fuse.ENOENT)
+	baz, err := t.lookUp("baz")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), bar.Child, baz.Child)
+	assert.EqualValues(t.T(), 2, baz.Attributes.Nlink)
+}
+
+// statCountingBucket counts the stats of the wrapped bucket.
+type statCountingBucket struct {
+	gcs.Bucket
+	stats int
+}
+
+func (b *statCountingBucket) StatObject(ctx context.Context, req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	b.stats++
+	return b.Bucket.StatObject(ctx, req)
+}
@@ -238,6 +238,10 @@ func (d *baseDirInode) CreateChildSymlink(ctx context.Context, name string, targ
 	return nil, fuse.ENOSYS
 }
 
+func (d *baseDirInode) CreateChildHardLink(ctx context.Context, name string, contents *gcs.MinObject) (*Core, error) {
+	return nil, fuse.ENOSYS
+}
+
 func (d *baseDirInode) CreateChildDir(ctx context.Context, name string) (*Core, error) {
 	return nil, fuse.ENOSYS
 }
@@ -65,19 +65,21 @@ func (t *BaseDirTest) SetUp(ti *TestInfo) {
 		buckets: make(map[string]gcsx.SyncerBucket),
 	}
 	t.bm.buckets["bucketA"] = gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
-		fake.NewFakeBucket(&t.clock, "bucketA", gcs.BucketType{}),
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		fake.NewFakeBucket(&t.clock, "bucketA", gcs.BucketType{}))
 	t.bm.buckets["bucketB"] = gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
-		fake.NewFakeBucket(&t.clock, "bucketB", gcs.BucketType{}),
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		fake.NewFakeBucket(&t.clock, "bucketB", gcs.BucketType{}))
 
 	// Create the inode. No implicit dirs by default.
 	t.resetInode()
@@ -110,6 +112,10 @@ func (bm *fakeBucketManager) SetUpBucket(
 	return
 }
 
+func (bm *fakeBucketManager) UpdateConfig(gcsx.BucketConfig) error { return nil }
+
+func (bm *fakeBucketManager) InvalidateStatCache(string, string) {}
+
 func (bm *fakeBucketManager) ShutDown() {}
 
 func (bm *fakeBucketManager) SetUpTimes() int {
@@ -40,6 +40,18 @@ type Core struct {
 
 	// Specifies a local object which is not yet synced to GCS.
 	Local bool
+
+	// Specifies an inode of the versions directory tree. For a generation file,
+	// MinObject is the generation, whose name is the path of the parent
+	// directory rather than FullName.
+	Versions bool
+
+	// Specifies a generation file of a soft-deleted generation.
+	SoftDeleted bool
+
+	// The hard link object through which the contents in MinObject were looked
+	// up, if any.
+	HardLink *gcs.MinObject
 }
 
 // Exists returns true iff the back object exists implicitly or explicitly.
@@ -69,7 +81,7 @@ func (c Core) SanityCheck() error {
 		return fmt.Errorf("inode name %q mismatches folder name %q", c.FullName, c.Folder.Name)
 	}
 
-	if c.MinObject != nil && c.FullName.objectName != c.MinObject.Name {
+	if c.MinObject != nil && !c.Versions && c.FullName.objectName != c.MinObject.Name {
 		return fmt.Errorf("inode name %q mismatches object name %q", c.FullName, c.MinObject.Name)
 	}
 
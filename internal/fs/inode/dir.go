@@ -131,6 +131,12 @@ type DirInode interface {
 	// Return the full name of the child and the GCS object it backs up.
 	CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error)
 
+	// Create a hard link object with the supplied (relative) name to the
+	// supplied contents of a file with hard links, failing with
+	// *gcs.PreconditionError if a backing object already exists in GCS.
+	// Return the full name of the contents and the GCS object it backs up.
+	CreateChildHardLink(ctx context.Context, name string, contents *gcs.MinObject) (*Core, error)
+
 	// Create a backing object for a child directory with the supplied (relative)
 	// name, failing with *gcs.PreconditionError if a backing object already
 	// exists in GCS.
@@ -281,6 +287,7 @@ type dirInode struct {
 	isStandardSymlinkRepresentationEnabled bool
 	isUnsupportedPathSupportEnabled        bool
 	isEnableTypeCacheDeprecation           bool
+	enableHardLinks                        bool
 
 	// Represents if folder has been unlinked in hierarchical bucket. This is not getting used in
 	// non-hierarchical bucket.
@@ -348,6 +355,7 @@ func NewDirInode(
 		isStandardSymlinkRepresentationEnabled: cfg.EnableStandardSymlinks,
 		isUnsupportedPathSupportEnabled:        cfg.EnableUnsupportedPathSupport,
 		isEnableTypeCacheDeprecation:           cfg.EnableTypeCacheDeprecation,
+		enableHardLinks:                        cfg.FileSystem.EnableHardLinks,
 		unlinked:                               false,
 		ctx:                                    ctx,
 		cancel:                                 cancel,
@@ -669,6 +677,17 @@ const ConflictingFileNameSuffix = "\n"
 
 // LOCKS_REQUIRED(d.mu.RLock)
 func (d *dirInode) LookUpChild(ctx context.Context, name string) (*Core, error) {
+	result, err := d.lookUpChild(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if d.enableHardLinks && result != nil && IsHardLink(result.MinObject) {
+		return d.resolveHardLink(ctx, result)
+	}
+	return result, nil
+}
+
+func (d *dirInode) lookUpChild(ctx context.Context, name string) (*Core, error) {
 	// Is this a conflict marker name?
 	if strings.HasSuffix(name, ConflictingFileNameSuffix) {
 		return d.lookUpConflicting(ctx, name)
@@ -1073,7 +1092,7 @@ func (d *dirInode) ReadEntries(
 	ctx context.Context,
 	tok string) (entries []fuseutil.Dirent, unsupportedPaths []string, newTok string, err error) {
 	var cores map[Name]*Core
-	cores, unsupportedPaths, newTok, err = d.ReadEntryCores(ctx, tok)
+	cores, unsupportedPaths, newTok, err = d.readEntryCores(ctx, tok)
 	if err != nil {
 		return
 	}
@@ -1099,12 +1118,34 @@ func (d *dirInode) ReadEntries(
 
 // LOCKS_REQUIRED(d)
 func (d *dirInode) ReadEntryCores(ctx context.Context, tok string) (cores map[Name]*Core, unsupportedPaths []string, newTok string, err error) {
+	cores, unsupportedPaths, newTok, err = d.readEntryCores(ctx, tok)
+	if err != nil {
+		return
+	}
+
+	// Unlike the plain entries, the cores carry the attributes of the files,
+	// which for hard links are the ones of their contents.
+	if d.enableHardLinks {
+		d.resolveHardLinks(ctx, cores)
+	}
+	return
+}
+
+// readEntryCores returns the cores of a page of entries, with hard links left
+// unresolved.
+//
+// LOCKS_REQUIRED(d)
+func (d *dirInode) readEntryCores(ctx context.Context, tok string) (cores map[Name]*Core, unsupportedPaths []string, newTok string, err error) {
 	cores, unsupportedPaths, newTok, err = d.readObjects(ctx, tok)
 	if err != nil {
 		err = fmt.Errorf("read objects: %w", err)
 		return
 	}
 
+	if d.enableHardLinks {
+		d.hideHardLinksDir(cores)
+	}
+
 	d.prevDirListingTimeStamp = d.cacheClock.Now()
 	return
 }
@@ -1569,6 +1610,39 @@ func (d *dirInode) RenameFolder(ctx context.Context, folderName string, destinat
 	return folder, nil
 }
 
+// SetTypeCacheTTL changes the TTL of the entries inserted into the type cache
+// from now on, e.g. when the config is reloaded.
+//
+// LOCKS_REQUIRED(d)
+func (d *dirInode) SetTypeCacheTTL(ttl time.Duration) {
+	if !d.IsTypeCacheDeprecated() {
+		d.cache.SetTTL(ttl)
+	}
+}
+
+// InvalidateTypeCache removes the type cache entries of the children whose
+// object names start with the given prefix, or that have descendants whose
+// object names start with it.
+//
+// LOCKS_REQUIRED(d)
+func (d *dirInode) InvalidateTypeCache(prefix string) {
+	if d.IsTypeCacheDeprecated() {
+		return
+	}
+	dirName := d.Name().GcsObjectName()
+	switch {
+	case strings.HasPrefix(dirName, prefix):
+		d.cache.EraseEntriesWithGivenPrefix("")
+	case strings.HasPrefix(prefix, dirName):
+		rest := prefix[len(dirName):]
+		if i := strings.Index(rest, "/"); i >= 0 {
+			d.cache.Erase(rest[:i])
+		} else {
+			d.cache.EraseEntriesWithGivenPrefix(rest)
+		}
+	}
+}
+
 func (d *dirInode) InvalidateKernelListCache() {
 	// Set prevDirListingTimeStamp to Zero time so that cache is invalidated.
 	d.prevDirListingTimeStamp = time.Time{}
@@ -37,10 +37,12 @@ func newBenchmarkDirInode(b *testing.B, implicitDirs bool) *dirInode {
 	clock.SetTime(time.Date(2026, 6, 18, 12, 0, 0, 0, time.Local))
 	bucket := fake.NewFakeBucket(&clock, "some_bucket", gcs.BucketType{Hierarchical: true})
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
 		bucket)
 
 	config := &cfg.Config{
@@ -52,11 +52,13 @@ func (t *DirPrefetchTest) setup(enablePrefetch bool, ttl time.Duration) (d *dirI
 	t.clock.SetTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 	t.fake = fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{})
 	t.bucket = gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/", t.fake,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		t.fake)
 
 	t.config = &cfg.Config{
 		MetadataCache: cfg.MetadataCacheConfig{
@@ -78,10 +78,12 @@ func (t *DirTest) SetupTest() {
 	t.clock.SetTime(time.Date(2015, 4, 5, 2, 15, 0, 0, time.Local))
 	bucket := fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{})
 	t.bucket = gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
 		bucket)
 	// Create the inode. No implicit dirs by default.
 	t.resetInode(false, false)
@@ -860,6 +862,61 @@ func (t *DirTest) TestLookUpChild_TypeCacheEnabled() {
 	}
 }
 
+func (t *DirTest) TestSetTypeCacheTTL() {
+	if t.in.IsTypeCacheDeprecated() {
+		t.T().Skip("type cache is deprecated")
+	}
+	const name = "qux"
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, path.Join(dirInodeName, name), []byte("taco"))
+	require.NoError(t.T(), err)
+
+	t.in.(*dirInode).SetTypeCacheTTL(2 * typeCacheTTL)
+	_, err = t.in.LookUpChild(t.ctx, name)
+	require.NoError(t.T(), err)
+
+	t.clock.AdvanceTime(typeCacheTTL + time.Nanosecond)
+	assert.Equal(t.T(), metadata.RegularFileType, t.getTypeFromCache(name))
+	t.clock.AdvanceTime(typeCacheTTL)
+	assert.Equal(t.T(), metadata.UnknownType, t.getTypeFromCache(name))
+}
+
+func (t *DirTest) TestInvalidateTypeCache() {
+	if t.in.IsTypeCacheDeprecated() {
+		t.T().Skip("type cache is deprecated")
+	}
+	for _, name := range []string{"qux", "quux", "taco"} {
+		_, err := storageutil.CreateObject(t.ctx, t.bucket, path.Join(dirInodeName, name), []byte("taco"))
+		require.NoError(t.T(), err)
+		_, err = t.in.LookUpChild(t.ctx, name)
+		require.NoError(t.T(), err)
+	}
+
+	// Prefixes of a descendant of a child erase that child only.
+	t.in.(*dirInode).InvalidateTypeCache(dirInodeName + "taco/x")
+	assert.Equal(t.T(), metadata.UnknownType, t.getTypeFromCache("taco"))
+	assert.Equal(t.T(), metadata.RegularFileType, t.getTypeFromCache("qux"))
+
+	t.in.(*dirInode).InvalidateTypeCache(dirInodeName + "qu")
+	assert.Equal(t.T(), metadata.UnknownType, t.getTypeFromCache("qux"))
+	assert.Equal(t.T(), metadata.UnknownType, t.getTypeFromCache("quux"))
+}
+
+func (t *DirTest) TestInvalidateTypeCache_PrefixOfDir() {
+	if t.in.IsTypeCacheDeprecated() {
+		t.T().Skip("type cache is deprecated")
+	}
+	const name = "qux"
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, path.Join(dirInodeName, name), []byte("taco"))
+	require.NoError(t.T(), err)
+	_, err = t.in.LookUpChild(t.ctx, name)
+	require.NoError(t.T(), err)
+
+	t.in.(*dirInode).InvalidateTypeCache("other/")
+	assert.Equal(t.T(), metadata.RegularFileType, t.getTypeFromCache(name))
+	t.in.(*dirInode).InvalidateTypeCache("foo/")
+	assert.Equal(t.T(), metadata.UnknownType, t.getTypeFromCache(name))
+}
+
 func (t *DirTest) TestLookUpChild_TypeCacheDisabled() {
 	inputs := []struct {
 		typeCacheMaxSizeMB int64
@@ -2231,11 +2288,13 @@ func (t *DirTest) TestLookUpChild_TypeCacheDeprecated_CacheMiss() {
 	mockBucket := new(storagemock.TestifyMockBucket)
 	mockBucket.On("BucketType").Return(gcs.BucketType{})
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/", mockBucket,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		mockBucket)
 	oldBucket := t.bucket
 	t.bucket = syncerBucket
 	defer func() { t.bucket = oldBucket }()
@@ -2281,11 +2340,13 @@ func (t *DirTest) TestLookUpChild_TypeCacheDeprecated_CacheHit() {
 	mockBucket := new(storagemock.TestifyMockBucket)
 	mockBucket.On("BucketType").Return(gcs.BucketType{})
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/", mockBucket,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		mockBucket)
 	oldBucket := t.bucket
 	t.bucket = syncerBucket
 	defer func() { t.bucket = oldBucket }()
@@ -112,6 +112,12 @@ type FileInode struct {
 	metricHandle              metrics.MetricHandle
 	traceHandle               tracing.TraceHandle
 
+	// POSIX attribute metadata set while the file had content not yet synced
+	// to GCS, written to the backing object after the next sync.
+	//
+	// GUARDED_BY(mu)
+	pendingPosixMetadata map[string]string
+
 	// Once write is started on the file i.e, bwh is initialized, any fileHandles
 	// opened in write mode before or after this and not yet closed are considered
 	// as writing to the file even though they are not writing.
@@ -497,6 +503,13 @@ func (f *FileInode) RegisterFileHandle(readOnly bool) {
 	}
 }
 
+// HasWriteHandles returns true if the file is open for writing.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) HasWriteHandles() bool {
+	return f.writeHandleCount > 0
+}
+
 // LOCKS_REQUIRED(f.mu)
 func (f *FileInode) DeRegisterFileHandle(readOnly bool) {
 	if readOnly {
@@ -596,6 +609,9 @@ func (f *FileInode) Attributes(
 	attrs.Atime = attrs.Mtime
 	attrs.Ctime = attrs.Mtime
 
+	// Mode, ownership and atime persisted in object metadata, if enabled.
+	f.applyPosixAttributes(&attrs)
+
 	if clobberedCheck {
 		// If the object has been clobbered, we reflect that as the inode being
 		// unlinked.
@@ -624,7 +640,7 @@ func (f *FileInode) Attributes(
 		}
 	}
 
-	attrs.Nlink = 1
+	attrs.Nlink = LinkCount(&f.src)
 
 	// For local files, also checking if file is unlinked locally.
 	if f.IsLocal() && f.IsUnlinked() {
@@ -775,6 +791,9 @@ func (f *FileInode) writeUsingBufferedWrites(ctx context.Context, data []byte, o
 //
 // LOCKS_REQUIRED(f.mu)
 func (f *FileInode) flushUsingBufferedWriteHandler(ctx context.Context) error {
+	// The pending POSIX attributes are part of the object created by the
+	// upload, unless it has already started.
+	metadataInUpload := len(f.pendingPosixMetadata) > 0 && f.bwh.SetMetadata(f.pendingPosixMetadata)
 	obj, err := f.bwh.Flush(ctx)
 	var preconditionErr *gcs.PreconditionError
 	if errors.As(err, &preconditionErr) {
@@ -788,6 +807,11 @@ func (f *FileInode) flushUsingBufferedWriteHandler(ctx context.Context) error {
 	}
 	// If we finalized the object, we need to update our state.
 	f.updateInodeStateAfterFlush(obj)
+	if metadataInUpload {
+		f.pendingPosixMetadata = nil
+	} else {
+		f.writePendingPosixMetadataAfterSync(ctx)
+	}
 	return nil
 }
 
@@ -1005,7 +1029,7 @@ func (f *FileInode) syncUsingContent(ctx context.Context) (err error) {
 	// Write out the contents if they are dirty.
 	// Object properties are also synced as part of content sync. Hence, passing
 	// the latest object fetched from gcs which has all the properties populated.
-	newObj, err := f.bucket.SyncObject(ctx, f.Name().GcsObjectName(), latestGcsObj, f.content)
+	newObj, err := f.bucket.SyncObject(ctx, f.Name().GcsObjectName(), latestGcsObj, f.pendingPosixMetadata, f.content)
 
 	var preconditionErr *gcs.PreconditionError
 	if errors.As(err, &preconditionErr) {
@@ -1034,6 +1058,13 @@ func (f *FileInode) syncUsingContent(ctx context.Context) (err error) {
 	minObj := storageutil.ConvertObjToMinObject(newObj)
 	// If we wrote out a new object, we need to update our state.
 	f.updateInodeStateAfterFlush(minObj)
+	// A new generation holds the pending POSIX attributes, otherwise the
+	// unchanged object still lacks them.
+	if newObj != nil {
+		f.pendingPosixMetadata = nil
+	} else {
+		f.writePendingPosixMetadataAfterSync(ctx)
+	}
 	return nil
 }
 
@@ -1158,6 +1189,24 @@ func (f *FileInode) Truncate(
 	return false, f.truncateUsingTempFile(ctx, size)
 }
 
+// Fallocate extends the file to the specified size, as Truncate does, unless
+// it's already at least that large. It returns true if the file has been
+// successfully synced to GCS.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) Fallocate(
+	ctx context.Context,
+	size int64) (bool, error) {
+	attrs, err := f.Attributes(ctx, false)
+	if err != nil {
+		return false, fmt.Errorf("Attributes: %w", err)
+	}
+	if size <= int64(attrs.Size) {
+		return false, nil
+	}
+	return f.Truncate(ctx, size)
+}
+
 // Ensures cache content on read if content cache enabled
 func (f *FileInode) CacheEnsureContent(ctx context.Context) (err error) {
 	if f.localFileCache {
@@ -1235,6 +1284,7 @@ func (f *FileInode) InitBufferedWriteHandlerIfEligible(ctx context.Context, open
 			ChunkRetryDeadlineSecs:   f.config.GcsRetries.ChunkRetryDeadlineSecs,
 			ChunkTransferTimeoutSecs: f.config.GcsRetries.ChunkTransferTimeoutSecs,
 			TraceHandle:              f.traceHandle,
+			OutOfOrderWindowBlocks:   f.config.Write.OutOfOrderWindowBlocks,
 		})
 		if errors.Is(err, block.CantAllocateAnyBlockError) {
 			logger.Warnf("File %s will use legacy staged writes because concurrent streaming write "+
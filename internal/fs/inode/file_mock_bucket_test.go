@@ -80,10 +80,12 @@ func (t *FileMockBucketTest) createLockedInode(fileName string, fileType string)
 		fileName,
 	)
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
 		t.bucket)
 
 	isLocal := false
@@ -135,10 +137,12 @@ func (t *FileMockBucketTest) createLockedInode(fileName string, fileType string)
 func (t *FileMockBucketTest) createGCSBackedFileInode(backingObj *gcs.MinObject) *FileInode {
 	t.T().Helper()
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
 		t.bucket)
 
 	f := NewFileInode(
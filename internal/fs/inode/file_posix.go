@@ -0,0 +1,165 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"maps"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// Object metadata keys holding POSIX attributes, compatible with the ones
+// written by gsutil -P.
+const (
+	// The permission bits of the file, in octal.
+	PosixModeMetadataKey = "goog-reserved-posix-mode"
+	// The owner and group of the file, in decimal.
+	PosixUidMetadataKey = "goog-reserved-posix-uid"
+	PosixGidMetadataKey = "goog-reserved-posix-gid"
+	// The access time of the file, in seconds since the epoch.
+	FileAtimeMetadataKey = "goog-reserved-file-atime"
+)
+
+// persistPosixAttributes returns true if POSIX attributes of files are
+// persisted in object metadata.
+func (f *FileInode) persistPosixAttributes() bool {
+	return f.config != nil && f.config.FileSystem.PersistPosixAttributes
+}
+
+// posixMetadata returns the value of the given POSIX metadata key, taking
+// changes not yet written to GCS into account.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) posixMetadata(key string) (value string, ok bool) {
+	if v, pending := f.pendingPosixMetadata[key]; pending {
+		return v, true
+	}
+	value, ok = f.src.Metadata[key]
+	return
+}
+
+// applyPosixAttributes overrides the mount-wide mode, owner, group and atime
+// in attrs with the ones persisted for the file, ignoring malformed values.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) applyPosixAttributes(attrs *fuseops.InodeAttributes) {
+	if !f.persistPosixAttributes() {
+		return
+	}
+
+	if s, ok := f.posixMetadata(PosixModeMetadataKey); ok {
+		if mode, err := strconv.ParseUint(s, 8, 32); err == nil {
+			attrs.Mode = attrs.Mode&^os.ModePerm | os.FileMode(mode)&os.ModePerm
+		}
+	}
+
+	if s, ok := f.posixMetadata(PosixUidMetadataKey); ok {
+		if uid, err := strconv.ParseUint(s, 10, 32); err == nil {
+			attrs.Uid = uint32(uid)
+		}
+	}
+
+	if s, ok := f.posixMetadata(PosixGidMetadataKey); ok {
+		if gid, err := strconv.ParseUint(s, 10, 32); err == nil {
+			attrs.Gid = uint32(gid)
+		}
+	}
+
+	if s, ok := f.posixMetadata(FileAtimeMetadataKey); ok {
+		if timestamp, err := strconv.ParseInt(s, 10, 64); err == nil {
+			attrs.Atime = time.Unix(timestamp, 0)
+		}
+	}
+}
+
+// SetPosixAttributes persists the given mode, owner, group and atime of the
+// file in the metadata of the backing object. Nil arguments are left
+// unchanged. May involve a round trip to GCS.
+//
+// Changes to files that have not been written to GCS yet or that have dirty
+// content are kept in memory and written with the next generation of the
+// object.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) SetPosixAttributes(
+	ctx context.Context,
+	mode *os.FileMode,
+	uid *uint32,
+	gid *uint32,
+	atime *time.Time) error {
+	if f.IsUnlinked() {
+		return nil
+	}
+
+	metadata := make(map[string]string)
+	if mode != nil {
+		metadata[PosixModeMetadataKey] = strconv.FormatUint(uint64(mode.Perm()), 8)
+	}
+	if uid != nil {
+		metadata[PosixUidMetadataKey] = strconv.FormatUint(uint64(*uid), 10)
+	}
+	if gid != nil {
+		metadata[PosixGidMetadataKey] = strconv.FormatUint(uint64(*gid), 10)
+	}
+	if atime != nil {
+		metadata[FileAtimeMetadataKey] = strconv.FormatInt(atime.Unix(), 10)
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	if f.pendingPosixMetadata == nil {
+		f.pendingPosixMetadata = make(map[string]string)
+	}
+	maps.Copy(f.pendingPosixMetadata, metadata)
+	if f.IsLocal() || f.content != nil || f.bwh != nil {
+		return nil
+	}
+	return f.writePendingPosixMetadata(ctx)
+}
+
+// writePendingPosixMetadata writes the POSIX attributes changed since the
+// last sync to the backing object. The changes are dropped if the write fails.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) writePendingPosixMetadata(ctx context.Context) error {
+	if len(f.pendingPosixMetadata) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]*string, len(f.pendingPosixMetadata))
+	for k, v := range f.pendingPosixMetadata {
+		metadata[k] = &v
+	}
+	f.pendingPosixMetadata = nil
+	return f.updateMetadata(ctx, metadata)
+}
+
+// writePendingPosixMetadataAfterSync writes the POSIX attributes changed
+// since the last sync to the backing object, when the sync didn't include
+// them in the new generation. As the contents were synced, a failure is only
+// logged.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) writePendingPosixMetadataAfterSync(ctx context.Context) {
+	if err := f.writePendingPosixMetadata(ctx); err != nil {
+		logger.Warnf("Dropping the POSIX attributes of %q changed before its sync: %v", f.name, err)
+	}
+}
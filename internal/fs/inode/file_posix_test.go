@@ -0,0 +1,169 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"os"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (t *FileTest) enablePersistPosixAttributes() {
+	t.in.config = &cfg.Config{FileSystem: cfg.FileSystemConfig{PersistPosixAttributes: true}}
+}
+
+func (t *FileTest) setBackingObjectMetadata(metadata map[string]*string) {
+	t.T().Helper()
+	_, err := t.bucket.UpdateObject(t.ctx, &gcs.UpdateObjectRequest{
+		Name:     fileName,
+		Metadata: metadata,
+	})
+	require.NoError(t.T(), err)
+	t.in.src = *t.statBackingObject()
+}
+
+func (t *FileTest) TestAttributesIgnorePosixMetadataWhenDisabled() {
+	mode, owner := "755", "1000"
+	t.setBackingObjectMetadata(map[string]*string{
+		PosixModeMetadataKey: &mode,
+		PosixUidMetadataKey:  &owner,
+		PosixGidMetadataKey:  &owner,
+	})
+
+	attrs, err := t.in.Attributes(t.ctx, true)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), fileMode, attrs.Mode)
+	assert.Equal(t.T(), uint32(uid), attrs.Uid)
+	assert.Equal(t.T(), uint32(gid), attrs.Gid)
+}
+
+func (t *FileTest) TestAttributesReadPosixMetadata() {
+	t.enablePersistPosixAttributes()
+	mode, owner, group, atime := "755", "1000", "2000", "1345071360"
+	t.setBackingObjectMetadata(map[string]*string{
+		PosixModeMetadataKey: &mode,
+		PosixUidMetadataKey:  &owner,
+		PosixGidMetadataKey:  &group,
+		FileAtimeMetadataKey: &atime,
+	})
+
+	attrs, err := t.in.Attributes(t.ctx, true)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), os.FileMode(0755), attrs.Mode)
+	assert.Equal(t.T(), uint32(1000), attrs.Uid)
+	assert.Equal(t.T(), uint32(2000), attrs.Gid)
+	assert.True(t.T(), attrs.Atime.Equal(time.Unix(1345071360, 0)))
+}
+
+func (t *FileTest) TestAttributesIgnoreMalformedPosixMetadata() {
+	t.enablePersistPosixAttributes()
+	mode, owner := "rwxr-xr-x", "-1"
+	t.setBackingObjectMetadata(map[string]*string{
+		PosixModeMetadataKey: &mode,
+		PosixUidMetadataKey:  &owner,
+	})
+
+	attrs, err := t.in.Attributes(t.ctx, true)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), fileMode, attrs.Mode)
+	assert.Equal(t.T(), uint32(uid), attrs.Uid)
+}
+
+func (t *FileTest) TestSetPosixAttributesUpdatesBackingObject() {
+	t.enablePersistPosixAttributes()
+	mode := os.FileMode(0750)
+	owner, group := uint32(1000), uint32(2000)
+	atime := time.Unix(1345071360, 0)
+
+	err := t.in.SetPosixAttributes(t.ctx, &mode, &owner, &group, &atime)
+
+	require.NoError(t.T(), err)
+	m := t.statBackingObject()
+	assert.Equal(t.T(), "750", m.Metadata[PosixModeMetadataKey])
+	assert.Equal(t.T(), "1000", m.Metadata[PosixUidMetadataKey])
+	assert.Equal(t.T(), "2000", m.Metadata[PosixGidMetadataKey])
+	assert.Equal(t.T(), "1345071360", m.Metadata[FileAtimeMetadataKey])
+	assert.Equal(t.T(), m.MetaGeneration, t.in.SourceGeneration().Metadata)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), mode, attrs.Mode)
+	assert.Equal(t.T(), owner, attrs.Uid)
+	assert.Equal(t.T(), group, attrs.Gid)
+}
+
+func (t *FileTest) TestSetPosixAttributesLeavesOtherKeysUnchanged() {
+	t.enablePersistPosixAttributes()
+	owner := uint32(1000)
+	require.NoError(t.T(), t.in.SetPosixAttributes(t.ctx, nil, &owner, nil, nil))
+	mode := os.FileMode(0700)
+
+	err := t.in.SetPosixAttributes(t.ctx, &mode, nil, nil, nil)
+
+	require.NoError(t.T(), err)
+	m := t.statBackingObject()
+	assert.Equal(t.T(), "700", m.Metadata[PosixModeMetadataKey])
+	assert.Equal(t.T(), "1000", m.Metadata[PosixUidMetadataKey])
+	assert.NotContains(t.T(), m.Metadata, PosixGidMetadataKey)
+}
+
+func (t *FileTest) TestSetPosixAttributesOnDirtyFileWaitsForSync() {
+	t.enablePersistPosixAttributes()
+	_, err := t.in.Write(t.ctx, []byte("burrito"), 0, WriteMode)
+	require.NoError(t.T(), err)
+	mode := os.FileMode(0755)
+
+	err = t.in.SetPosixAttributes(t.ctx, &mode, nil, nil, nil)
+
+	require.NoError(t.T(), err)
+	assert.NotContains(t.T(), t.statBackingObject().Metadata, PosixModeMetadataKey)
+	attrs, err := t.in.Attributes(t.ctx, false)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), mode, attrs.Mode)
+	// Syncing writes out the pending mode with the new generation.
+	_, err = t.in.Sync(t.ctx)
+	require.NoError(t.T(), err)
+	m := t.statBackingObject()
+	assert.Equal(t.T(), "755", m.Metadata[PosixModeMetadataKey])
+	assert.Equal(t.T(), int64(1), m.MetaGeneration)
+	assert.Equal(t.T(), m.Generation, t.in.SourceGeneration().Object)
+	assert.Equal(t.T(), m.MetaGeneration, t.in.SourceGeneration().Metadata)
+	assert.Empty(t.T(), t.in.pendingPosixMetadata)
+}
+
+func (t *FileTest) TestSetPosixAttributesOnLocalFileWaitsForSync() {
+	t.createInodeWithLocalParam("test", true)
+	t.enablePersistPosixAttributes()
+	require.NoError(t.T(), t.in.CreateEmptyTempFile(t.ctx))
+	mode := os.FileMode(0700)
+
+	err := t.in.SetPosixAttributes(t.ctx, &mode, nil, nil, nil)
+
+	require.NoError(t.T(), err)
+	attrs, err := t.in.Attributes(t.ctx, false)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), mode, attrs.Mode)
+	_, err = t.in.Sync(t.ctx)
+	require.NoError(t.T(), err)
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "test"})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "700", m.Metadata[PosixModeMetadataKey])
+}
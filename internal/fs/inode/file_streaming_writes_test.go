@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"os"
 	"testing"
 	"time"
 
@@ -115,10 +116,12 @@ func (t *FileStreamingWritesCommon) createInode(fileType string) {
 		fileName,
 	)
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
 		t.bucket)
 
 	isLocal := false
@@ -549,6 +552,24 @@ func (t *FileStreamingWritesTest) TestWriteToFileAndFlush() {
 	}
 }
 
+func (t *FileStreamingWritesTest) TestFlushWritesPendingPosixAttributesWithObject() {
+	t.in.config.FileSystem.PersistPosixAttributes = true
+	t.createBufferedWriteHandler()
+	_, err := t.in.Write(t.ctx, []byte("tacos"), 0, WriteMode)
+	require.NoError(t.T(), err)
+	mode := os.FileMode(0700)
+	require.NoError(t.T(), t.in.SetPosixAttributes(t.ctx, &mode, nil, nil, nil))
+
+	err = t.in.Flush(t.ctx)
+
+	require.NoError(t.T(), err)
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: t.in.Name().GcsObjectName()})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "700", m.Metadata[PosixModeMetadataKey])
+	assert.Equal(t.T(), int64(1), m.MetaGeneration)
+	assert.Empty(t.T(), t.in.pendingPosixMetadata)
+}
+
 func (t *FileStreamingWritesTest) TestFlushEmptyFile() {
 	testCases := []struct {
 		name    string
@@ -839,6 +860,7 @@ func (t *FakeBufferedWriteHandler) WriteFileInfo() bufferedwrites.WriteFileInfo
 
 func (t *FakeBufferedWriteHandler) Sync(ctx context.Context) (*gcs.MinObject, error) { return nil, nil }
 func (t *FakeBufferedWriteHandler) SetMtime(_ time.Time)                             {}
+func (t *FakeBufferedWriteHandler) SetMetadata(_ map[string]string) bool             { return false }
 func (t *FakeBufferedWriteHandler) Truncate(_ int64) error                           { return nil }
 func (t *FakeBufferedWriteHandler) Destroy() error                                   { return nil }
 func (t *FakeBufferedWriteHandler) Unlink()                                          {}
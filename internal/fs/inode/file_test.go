@@ -144,10 +144,12 @@ func (t *FileTest) createInodeWithLocalParam(fileName string, local bool) {
 		fileName,
 	)
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		".gcsfuse_tmp/",
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
 		t.bucket)
 
 	if local {
@@ -598,6 +600,65 @@ func (t *FileTest) TestTruncateNegative() {
 	assert.False(t.T(), gcsSynced)
 }
 
+func (t *FileTest) TestFallocateUpward() {
+	assert.Equal(t.T(), "taco", t.initialContents)
+
+	gcsSynced, err := t.in.Fallocate(t.ctx, 6)
+
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(6), attrs.Size)
+	gcsSynced, err = t.in.Sync(t.ctx)
+	require.NoError(t.T(), err)
+	assert.True(t.T(), gcsSynced)
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, t.in.Name().GcsObjectName())
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco\x00\x00", string(contents))
+}
+
+func (t *FileTest) TestFallocateWithinFile() {
+	assert.Equal(t.T(), "taco", t.initialContents)
+
+	gcsSynced, err := t.in.Fallocate(t.ctx, 2)
+
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	// The object isn't downloaded to a temp file.
+	assert.Nil(t.T(), t.in.content)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len("taco")), attrs.Size)
+}
+
+func (t *FileTest) TestFallocateWhenStreamingWritesAreEnabled() {
+	t.createInodeWithLocalParam("test", true)
+	t.in.config = &cfg.Config{Write: *getWriteConfig()}
+	t.createBufferedWriteHandler(true, WriteMode)
+	gcsSynced, err := t.in.Write(t.ctx, []byte("hi"), 0, WriteMode)
+	require.NoError(t.T(), err)
+	require.False(t.T(), gcsSynced)
+
+	gcsSynced, err = t.in.Fallocate(t.ctx, 10)
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	// Allocating within the new size doesn't shrink the file.
+	gcsSynced, err = t.in.Fallocate(t.ctx, 5)
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+
+	assert.NotNil(t.T(), t.in.bwh)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(10), attrs.Size)
+	// The zeros are written when the object is finalized.
+	require.NoError(t.T(), t.in.Flush(t.ctx))
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, t.in.Name().GcsObjectName())
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "hi\x00\x00\x00\x00\x00\x00\x00\x00", string(contents))
+}
+
 func (t *FileTest) TestDestroy_MrdInstanceDestroyed() {
 	if !t.in.bucket.BucketType().IsRapid() {
 		return
@@ -0,0 +1,253 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+)
+
+// Extended attribute namespaces supported on file inodes.
+const (
+	// UserXattrPrefix is the namespace of the extended attributes backed by the
+	// custom metadata of the object: "user.foo" maps to the metadata key "foo".
+	UserXattrPrefix = "user."
+
+	// GcsfuseXattrPrefix is the namespace of the read-only extended attributes
+	// exposing properties of the backing object.
+	GcsfuseXattrPrefix = "gcsfuse."
+)
+
+// Read-only extended attributes in the gcsfuse.* namespace.
+const (
+	GenerationXattr     = GcsfuseXattrPrefix + "generation"
+	MetaGenerationXattr = GcsfuseXattrPrefix + "metageneration"
+	// The CRC32C checksum of the object, base64 encoded in big-endian byte
+	// order as returned by the GCS JSON API.
+	CRC32CXattr       = GcsfuseXattrPrefix + "crc32c"
+	StorageClassXattr = GcsfuseXattrPrefix + "storage_class"
+	ContentTypeXattr  = GcsfuseXattrPrefix + "content_type"
+)
+
+// Flags accepted by setxattr(2).
+const (
+	xattrCreate  = 0x1
+	xattrReplace = 0x2
+)
+
+// GCS limits the total size of the custom metadata keys and values of an
+// object to 8 KiB.
+const maxCustomMetadataBytes = 8 * 1024
+
+// userXattrKey returns the object metadata key backing the extended attribute
+// with the given name, failing if the attribute can't be modified. Metadata
+// keys reserved by gcsfuse, such as the mtime and symlink target, are not
+// exposed as extended attributes.
+func userXattrKey(name string) (string, error) {
+	if strings.HasPrefix(name, GcsfuseXattrPrefix) {
+		return "", syscall.EPERM
+	}
+	key, ok := strings.CutPrefix(name, UserXattrPrefix)
+	if !ok {
+		return "", syscall.ENOTSUP
+	}
+	if key == "" {
+		return "", syscall.EINVAL
+	}
+	if gcs.IsReservedMetadataKey(key) {
+		return "", syscall.EPERM
+	}
+	return key, nil
+}
+
+// ListXattrs returns the names of the extended attributes of the file.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) ListXattrs() (names []string) {
+	for key := range f.src.Metadata {
+		if !gcs.IsReservedMetadataKey(key) {
+			names = append(names, UserXattrPrefix+key)
+		}
+	}
+	slices.Sort(names)
+
+	// Local files have no backing object yet.
+	if f.IsLocal() {
+		return
+	}
+
+	names = append(names, GenerationXattr, MetaGenerationXattr)
+	if f.src.CRC32C != nil {
+		names = append(names, CRC32CXattr)
+	}
+	names = append(names, StorageClassXattr, ContentTypeXattr)
+	return
+}
+
+// GetXattr returns the value of the extended attribute with the given name, or
+// fuse.ENOATTR if the file has no such attribute. The storage class and content
+// type require a round trip to GCS.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) GetXattr(ctx context.Context, name string) ([]byte, error) {
+	if key, ok := strings.CutPrefix(name, UserXattrPrefix); ok {
+		value, ok := f.src.Metadata[key]
+		if !ok || gcs.IsReservedMetadataKey(key) {
+			return nil, fuse.ENOATTR
+		}
+		return []byte(value), nil
+	}
+
+	if !strings.HasPrefix(name, GcsfuseXattrPrefix) || f.IsLocal() {
+		return nil, fuse.ENOATTR
+	}
+
+	switch name {
+	case GenerationXattr:
+		return []byte(strconv.FormatInt(f.src.Generation, 10)), nil
+
+	case MetaGenerationXattr:
+		return []byte(strconv.FormatInt(f.src.MetaGeneration, 10)), nil
+
+	case CRC32CXattr:
+		if f.src.CRC32C == nil {
+			return nil, fuse.ENOATTR
+		}
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], *f.src.CRC32C)
+		return []byte(base64.StdEncoding.EncodeToString(b[:])), nil
+
+	case StorageClassXattr, ContentTypeXattr:
+		// Extended attributes are not cached, so fetch them from GCS.
+		o, err := f.fetchLatestGcsObject(ctx)
+		if err != nil {
+			return nil, err
+		}
+		value := o.StorageClass
+		if name == ContentTypeXattr {
+			value = o.ContentType
+		}
+		if value == "" {
+			return nil, fuse.ENOATTR
+		}
+		return []byte(value), nil
+	}
+
+	return nil, fuse.ENOATTR
+}
+
+// SetXattr sets the user.* extended attribute with the given name by updating
+// the custom metadata of the backing object. flags are as for setxattr(2).
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) SetXattr(ctx context.Context, name string, value []byte, flags uint32) error {
+	key, err := userXattrKey(name)
+	if err != nil {
+		return err
+	}
+
+	_, exists := f.src.Metadata[key]
+	if flags&xattrCreate != 0 && exists {
+		return fuse.EEXIST
+	}
+	if flags&xattrReplace != 0 && !exists {
+		return fuse.ENOATTR
+	}
+
+	// Metadata values are strings in GCS.
+	if !utf8.Valid(value) {
+		return syscall.EINVAL
+	}
+
+	size := len(key) + len(value)
+	for k, v := range f.src.Metadata {
+		if k != key {
+			size += len(k) + len(v)
+		}
+	}
+	if size > maxCustomMetadataBytes {
+		return syscall.ENOSPC
+	}
+
+	v := string(value)
+	return f.updateMetadata(ctx, map[string]*string{key: &v})
+}
+
+// RemoveXattr removes the user.* extended attribute with the given name from
+// the custom metadata of the backing object.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) RemoveXattr(ctx context.Context, name string) error {
+	key, err := userXattrKey(name)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := f.src.Metadata[key]; !ok {
+		return fuse.ENOATTR
+	}
+
+	return f.updateMetadata(ctx, map[string]*string{key: nil})
+}
+
+// updateMetadata patches the custom metadata of the generation of the object
+// backing this inode, failing with *gcsfuse_errors.FileClobberedError if the
+// object has been modified remotely.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) updateMetadata(ctx context.Context, metadata map[string]*string) error {
+	// Local files and files with streaming writes in progress have no object
+	// generation that could be updated in place.
+	if f.IsLocal() || f.bwh != nil {
+		return syscall.ENOTSUP
+	}
+
+	srcGen := f.SourceGeneration()
+	o, err := f.bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name:                       f.src.Name,
+		Generation:                 srcGen.Object,
+		MetaGenerationPrecondition: &srcGen.Metadata,
+		Metadata:                   metadata,
+	})
+
+	var notFoundErr *gcs.NotFoundError
+	var preconditionErr *gcs.PreconditionError
+	if errors.As(err, &notFoundErr) || errors.As(err, &preconditionErr) {
+		return &gcsfuse_errors.FileClobberedError{
+			Err:        fmt.Errorf("UpdateObject: %w", err),
+			ObjectName: f.src.Name,
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("UpdateObject: %w", err)
+	}
+
+	f.src = *storageutil.ConvertObjToMinObject(o)
+	f.updateReaders()
+	return nil
+}
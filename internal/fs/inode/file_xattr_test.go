@@ -0,0 +1,225 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"errors"
+	"strconv"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (t *FileTest) statBackingObject() *gcs.MinObject {
+	t.T().Helper()
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: t.in.Name().GcsObjectName()})
+	require.NoError(t.T(), err)
+	return m
+}
+
+func (t *FileTest) TestListXattrs() {
+	names := t.in.ListXattrs()
+
+	assert.Equal(t.T(), []string{GenerationXattr, MetaGenerationXattr, CRC32CXattr, StorageClassXattr, ContentTypeXattr}, names)
+}
+
+func (t *FileTest) TestListXattrsIncludesUserAttributes() {
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.b", []byte("2"), 0))
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0))
+
+	names := t.in.ListXattrs()
+
+	assert.Equal(t.T(), []string{"user.a", "user.b", GenerationXattr, MetaGenerationXattr, CRC32CXattr, StorageClassXattr, ContentTypeXattr}, names)
+}
+
+func (t *FileTest) TestXattrsHideReservedMetadata() {
+	t.in.src.Metadata = map[string]string{FileMtimeMetadataKey: "x", LinkCountMetadataKey: "2", "a": "1"}
+
+	names := t.in.ListXattrs()
+	_, err := t.in.GetXattr(t.ctx, "user."+FileMtimeMetadataKey)
+
+	assert.Equal(t.T(), []string{"user.a", GenerationXattr, MetaGenerationXattr, CRC32CXattr, StorageClassXattr, ContentTypeXattr}, names)
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+	assert.ErrorIs(t.T(), t.in.RemoveXattr(t.ctx, "user."+LinkCountMetadataKey), syscall.EPERM)
+}
+
+func (t *FileTest) TestListXattrsForLocalFile() {
+	t.createInodeWithLocalParam("test", true)
+
+	assert.Empty(t.T(), t.in.ListXattrs())
+}
+
+func (t *FileTest) TestGetXattrGenerations() {
+	gen, err := t.in.GetXattr(t.ctx, GenerationXattr)
+	require.NoError(t.T(), err)
+	metaGen, err := t.in.GetXattr(t.ctx, MetaGenerationXattr)
+	require.NoError(t.T(), err)
+
+	assert.Equal(t.T(), strconv.FormatInt(t.backingObj.Generation, 10), string(gen))
+	assert.Equal(t.T(), strconv.FormatInt(t.backingObj.MetaGeneration, 10), string(metaGen))
+}
+
+func (t *FileTest) TestGetXattrCRC32C() {
+	value, err := t.in.GetXattr(t.ctx, CRC32CXattr)
+
+	require.NoError(t.T(), err)
+	// CRC32C of "taco".
+	assert.Equal(t.T(), "rmxLDw==", string(value))
+}
+
+func (t *FileTest) TestGetXattrContentType() {
+	contentType := "text/plain"
+	_, err := t.bucket.UpdateObject(t.ctx, &gcs.UpdateObjectRequest{
+		Name:        fileName,
+		ContentType: &contentType,
+	})
+	require.NoError(t.T(), err)
+	t.in.src = *t.statBackingObject()
+
+	value, err := t.in.GetXattr(t.ctx, ContentTypeXattr)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "text/plain", string(value))
+}
+
+func (t *FileTest) TestGetXattrContentTypeWhenClobbered() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, fileName, []byte("burrito"))
+	require.NoError(t.T(), err)
+
+	_, err = t.in.GetXattr(t.ctx, ContentTypeXattr)
+
+	var clobberedErr *gcsfuse_errors.FileClobberedError
+	assert.True(t.T(), errors.As(err, &clobberedErr))
+}
+
+func (t *FileTest) TestGetXattrMissing() {
+	for _, name := range []string{"user.missing", "gcsfuse.missing", "security.capability"} {
+		_, err := t.in.GetXattr(t.ctx, name)
+
+		assert.ErrorIs(t.T(), err, fuse.ENOATTR, name)
+	}
+}
+
+func (t *FileTest) TestSetXattrThenGetXattr() {
+	err := t.in.SetXattr(t.ctx, "user.provenance", []byte("pipeline-7"), 0)
+	require.NoError(t.T(), err)
+
+	value, err := t.in.GetXattr(t.ctx, "user.provenance")
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "pipeline-7", string(value))
+	m := t.statBackingObject()
+	assert.Equal(t.T(), "pipeline-7", m.Metadata["provenance"])
+	assert.Equal(t.T(), m.MetaGeneration, t.in.SourceGeneration().Metadata)
+	assert.Equal(t.T(), t.backingObj.Generation, t.in.SourceGeneration().Object)
+}
+
+func (t *FileTest) TestSetXattrFlags() {
+	assert.ErrorIs(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), xattrReplace), fuse.ENOATTR)
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), xattrCreate))
+	assert.ErrorIs(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("2"), xattrCreate), fuse.EEXIST)
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("2"), xattrReplace))
+
+	value, err := t.in.GetXattr(t.ctx, "user.a")
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "2", string(value))
+}
+
+func (t *FileTest) TestSetXattrRejectedNames() {
+	testCases := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: GenerationXattr, wantErr: syscall.EPERM},
+		{name: "security.selinux", wantErr: syscall.ENOTSUP},
+		{name: "user.", wantErr: syscall.EINVAL},
+		{name: "user." + FileMtimeMetadataKey, wantErr: syscall.EPERM},
+		{name: "user." + SymlinkMetadataKey, wantErr: syscall.EPERM},
+		{name: "user." + PosixModeMetadataKey, wantErr: syscall.EPERM},
+	}
+
+	for _, tc := range testCases {
+		err := t.in.SetXattr(t.ctx, tc.name, []byte("x"), 0)
+
+		assert.ErrorIs(t.T(), err, tc.wantErr, tc.name)
+	}
+}
+
+func (t *FileTest) TestSetXattrInvalidUTF8() {
+	err := t.in.SetXattr(t.ctx, "user.a", []byte{0xff, 0xfe}, 0)
+
+	assert.ErrorIs(t.T(), err, syscall.EINVAL)
+}
+
+func (t *FileTest) TestSetXattrTooLarge() {
+	err := t.in.SetXattr(t.ctx, "user.a", make([]byte, maxCustomMetadataBytes), 0)
+
+	assert.ErrorIs(t.T(), err, syscall.ENOSPC)
+}
+
+func (t *FileTest) TestSetXattrWhenClobbered() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, fileName, []byte("burrito"))
+	require.NoError(t.T(), err)
+
+	err = t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0)
+
+	var clobberedErr *gcsfuse_errors.FileClobberedError
+	assert.True(t.T(), errors.As(err, &clobberedErr))
+	assert.NotContains(t.T(), t.statBackingObject().Metadata, "a")
+}
+
+func (t *FileTest) TestSetXattrOnLocalFile() {
+	t.createInodeWithLocalParam("test", true)
+
+	err := t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0)
+
+	assert.ErrorIs(t.T(), err, syscall.ENOTSUP)
+}
+
+func (t *FileTest) TestSetXattrKeepsMetadataAcrossSync() {
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0))
+	_, err := t.in.Write(t.ctx, []byte("burrito"), 0, WriteMode)
+	require.NoError(t.T(), err)
+
+	_, err = t.in.Sync(t.ctx)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "1", t.statBackingObject().Metadata["a"])
+}
+
+func (t *FileTest) TestRemoveXattr() {
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0))
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.b", []byte("2"), 0))
+
+	err := t.in.RemoveXattr(t.ctx, "user.a")
+
+	require.NoError(t.T(), err)
+	_, err = t.in.GetXattr(t.ctx, "user.a")
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+	m := t.statBackingObject()
+	assert.NotContains(t.T(), m.Metadata, "a")
+	assert.Equal(t.T(), "2", m.Metadata["b"])
+}
+
+func (t *FileTest) TestRemoveXattrMissing() {
+	assert.ErrorIs(t.T(), t.in.RemoveXattr(t.ctx, "user.a"), fuse.ENOATTR)
+	assert.ErrorIs(t.T(), t.in.RemoveXattr(t.ctx, ContentTypeXattr), syscall.EPERM)
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"golang.org/x/sync/errgroup"
+)
+
+// Hard links are emulated with pointer objects. The contents of a file with
+// hard links live in an object under HardLinksDirName at the root of the
+// bucket, whose LinkCountMetadataKey records its number of links, and each of
+// its names is an empty object whose HardLinkMetadataKey holds the name of the
+// contents object.
+const (
+	HardLinksDirName       = ".gcsfuse_links"
+	HardLinkMetadataKey    = "gcsfuse_hard_link_target"
+	LinkCountMetadataKey   = "gcsfuse_link_count"
+	hardLinksObjectsPrefix = HardLinksDirName + "/"
+)
+
+// hardLinkResolutionParallelism is the number of hard links of a listing whose
+// contents are looked up concurrently.
+const hardLinkResolutionParallelism = 16
+
+// IsHardLink returns true if the supplied object is a name of a file with hard
+// links.
+func IsHardLink(m *gcs.MinObject) bool {
+	if m == nil {
+		return false
+	}
+	_, ok := m.Metadata[HardLinkMetadataKey]
+	return ok
+}
+
+// IsHardLinkContents returns true if the supplied name is the one of the
+// contents of a file with hard links.
+func IsHardLinkContents(name Name) bool {
+	return name.IsFile() && strings.HasPrefix(name.GcsObjectName(), hardLinksObjectsPrefix)
+}
+
+// NewHardLinkContentsName returns a new name for the contents of a file with
+// hard links in the bucket of the supplied name.
+func NewHardLinkContentsName(name Name) Name {
+	return NewDescendantName(name, hardLinksObjectsPrefix+uuid.NewString())
+}
+
+// LinkCount returns the number of hard links of the supplied object, which is
+// one unless it's the contents of a file with hard links.
+func LinkCount(m *gcs.MinObject) uint32 {
+	if m == nil {
+		return 1
+	}
+	count, err := strconv.ParseUint(m.Metadata[LinkCountMetadataKey], 10, 32)
+	if err != nil {
+		return 1
+	}
+	return uint32(count)
+}
+
+// resolveHardLink returns the core of the contents of the file with hard links
+// that the supplied core is a name of. A dangling name is returned as is, so
+// that it can be unlinked.
+func (d *dirInode) resolveHardLink(ctx context.Context, link *Core) (*Core, error) {
+	contentsName := NewDescendantName(d.Name(), link.MinObject.Metadata[HardLinkMetadataKey])
+	if !IsHardLinkContents(contentsName) {
+		logger.Warnf("Ignoring hard link %q to %q outside of %s", link.FullName, contentsName, HardLinksDirName)
+		return link, nil
+	}
+	contents, err := findExplicitInode(ctx, d.Bucket(), contentsName, false)
+	if err != nil {
+		return nil, err
+	}
+	if contents == nil {
+		logger.Warnf("Hard link %q points to missing %q", link.FullName, contentsName)
+		return link, nil
+	}
+	contents.HardLink = link.MinObject
+	return contents, nil
+}
+
+// hideHardLinksDir removes the directory holding the contents of the files
+// with hard links from the supplied listing of the root of the bucket.
+func (d *dirInode) hideHardLinksDir(cores map[Name]*Core) {
+	if d.Name().IsBucketRoot() {
+		delete(cores, NewDirName(d.Name(), HardLinksDirName))
+	}
+}
+
+// resolveHardLinks replaces the cores of the hard links in the supplied
+// listing with the ones of their contents, at most
+// hardLinkResolutionParallelism at a time. A hard link whose contents can't be
+// looked up keeps its own core, so that the listing doesn't fail.
+func (d *dirInode) resolveHardLinks(ctx context.Context, cores map[Name]*Core) {
+	var links []Name
+	for name, core := range cores {
+		if IsHardLink(core.MinObject) {
+			links = append(links, name)
+		}
+	}
+	if len(links) == 0 {
+		return
+	}
+
+	resolved := make([]*Core, len(links))
+	var group errgroup.Group
+	group.SetLimit(hardLinkResolutionParallelism)
+	for i, name := range links {
+		group.Go(func() error {
+			core, err := d.resolveHardLink(ctx, cores[name])
+			if err != nil {
+				logger.Warnf("Listing hard link %q without resolving it: %v", name, err)
+				return nil
+			}
+			resolved[i] = core
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	for i, name := range links {
+		if resolved[i] != nil {
+			cores[name] = resolved[i]
+		}
+	}
+}
+
+// LOCKS_REQUIRED(d)
+func (d *dirInode) CreateChildHardLink(ctx context.Context, name string, contents *gcs.MinObject) (*Core, error) {
+	fullName := NewFileName(d.Name(), name)
+	o, err := d.createNewObject(ctx, fullName, map[string]string{HardLinkMetadataKey: contents.Name}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.IsTypeCacheDeprecated() {
+		d.cache.Insert(d.cacheClock.Now(), name, metadata.RegularFileType)
+	}
+
+	return &Core{
+		Bucket:    d.Bucket(),
+		FullName:  NewDescendantName(d.Name(), contents.Name),
+		MinObject: contents,
+		HardLink:  storageutil.ConvertObjToMinObject(o),
+	}, nil
+}
+
+// SetLinkCount records the number of hard links of the file in the metadata
+// of its backing object, which must be the contents of a file with hard links.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) SetLinkCount(ctx context.Context, count uint32) error {
+	s := strconv.FormatUint(uint64(count), 10)
+	return f.updateMetadata(ctx, map[string]*string{LinkCountMetadataKey: &s})
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/sync/semaphore"
+)
+
+// contentsStatBucket counts the stats of the contents of files with hard links
+// and fails them with err, if set.
+type contentsStatBucket struct {
+	gcs.Bucket
+	stats atomic.Int32
+	err   error
+}
+
+func (b *contentsStatBucket) StatObject(ctx context.Context, req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	if strings.HasPrefix(req.Name, inode.HardLinksDirName+"/") {
+		b.stats.Add(1)
+		if b.err != nil {
+			return nil, nil, b.err
+		}
+	}
+	return b.Bucket.StatObject(ctx, req)
+}
+
+type HardLinkTest struct {
+	suite.Suite
+	ctx         context.Context
+	statCounter *contentsStatBucket
+	bucket      *gcsx.SyncerBucket
+	root        inode.DirInode
+	contents    *gcs.MinObject
+}
+
+func TestHardLinkSuite(t *testing.T) {
+	suite.Run(t, new(HardLinkTest))
+}
+
+func (t *HardLinkTest) SetupTest() {
+	t.ctx = context.Background()
+	t.statCounter = &contentsStatBucket{Bucket: fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})}
+	t.bucket = newSyncerBucket(t.statCounter)
+	t.root = inode.NewDirInode(
+		fuseops.RootInodeID,
+		inode.NewRootName(""),
+		nil,
+		fuseops.InodeAttributes{Mode: 0755},
+		false,
+		false,
+		time.Minute,
+		t.bucket,
+		timeutil.RealClock(),
+		timeutil.RealClock(),
+		semaphore.NewWeighted(10),
+		&cfg.Config{
+			MetadataCache: cfg.MetadataCacheConfig{TypeCacheMaxSizeMb: 4},
+			FileSystem:    cfg.FileSystemConfig{EnableHardLinks: true},
+		})
+	t.root.Lock()
+
+	o, err := storageutil.CreateObject(t.ctx, t.bucket, ".gcsfuse_links/abc", []byte("taco"))
+	require.NoError(t.T(), err)
+	t.contents = storageutil.ConvertObjToMinObject(o)
+}
+
+func (t *HardLinkTest) TearDownTest() {
+	t.root.Unlock()
+}
+
+func (t *HardLinkTest) TestLinkCount() {
+	assert.EqualValues(t.T(), 1, inode.LinkCount(nil))
+	assert.EqualValues(t.T(), 1, inode.LinkCount(&gcs.MinObject{}))
+	assert.EqualValues(t.T(), 3, inode.LinkCount(&gcs.MinObject{Metadata: map[string]string{inode.LinkCountMetadataKey: "3"}}))
+	assert.EqualValues(t.T(), 1, inode.LinkCount(&gcs.MinObject{Metadata: map[string]string{inode.LinkCountMetadataKey: "taco"}}))
+}
+
+func (t *HardLinkTest) TestIsHardLinkContents() {
+	root := inode.NewRootName("")
+
+	assert.True(t.T(), inode.IsHardLinkContents(inode.NewHardLinkContentsName(inode.NewFileName(root, "foo"))))
+	assert.False(t.T(), inode.IsHardLinkContents(inode.NewFileName(root, "foo")))
+	assert.False(t.T(), inode.IsHardLinkContents(inode.NewDirName(root, inode.HardLinksDirName)))
+}
+
+func (t *HardLinkTest) TestCreateChildHardLink() {
+	core, err := t.root.CreateChildHardLink(t.ctx, "foo", t.contents)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), ".gcsfuse_links/abc", core.FullName.GcsObjectName())
+	assert.Equal(t.T(), t.contents, core.MinObject)
+	require.NotNil(t.T(), core.HardLink)
+	assert.Equal(t.T(), "foo", core.HardLink.Name)
+	assert.True(t.T(), inode.IsHardLink(core.HardLink))
+	assert.EqualValues(t.T(), 0, core.HardLink.Size)
+}
+
+func (t *HardLinkTest) TestCreateChildHardLink_Exists() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("burrito"))
+	require.NoError(t.T(), err)
+
+	_, err = t.root.CreateChildHardLink(t.ctx, "foo", t.contents)
+
+	var preconditionErr *gcs.PreconditionError
+	assert.ErrorAs(t.T(), err, &preconditionErr)
+}
+
+func (t *HardLinkTest) TestLookUpChild_ResolvesHardLink() {
+	_, err := t.root.CreateChildHardLink(t.ctx, "foo", t.contents)
+	require.NoError(t.T(), err)
+
+	core, err := t.root.LookUpChild(t.ctx, "foo")
+
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), core)
+	assert.Equal(t.T(), ".gcsfuse_links/abc", core.FullName.GcsObjectName())
+	assert.EqualValues(t.T(), len("taco"), core.MinObject.Size)
+	require.NotNil(t.T(), core.HardLink)
+	assert.Equal(t.T(), "foo", core.HardLink.Name)
+}
+
+func (t *HardLinkTest) TestLookUpChild_DanglingHardLink() {
+	_, err := t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     "foo",
+		Contents: strings.NewReader(""),
+		Metadata: map[string]string{inode.HardLinkMetadataKey: ".gcsfuse_links/gone"},
+	})
+	require.NoError(t.T(), err)
+
+	core, err := t.root.LookUpChild(t.ctx, "foo")
+
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), core)
+	assert.Equal(t.T(), "foo", core.FullName.GcsObjectName())
+	assert.Nil(t.T(), core.HardLink)
+}
+
+func (t *HardLinkTest) TestReadEntryCores() {
+	_, err := t.root.CreateChildHardLink(t.ctx, "foo", t.contents)
+	require.NoError(t.T(), err)
+	_, err = t.root.CreateChildHardLink(t.ctx, "bar", t.contents)
+	require.NoError(t.T(), err)
+
+	cores, _, _, err := t.root.ReadEntryCores(t.ctx, "")
+
+	require.NoError(t.T(), err)
+	names := make(map[string]string)
+	for name, core := range cores {
+		names[name.LocalName()] = core.FullName.GcsObjectName()
+	}
+	assert.Equal(t.T(), map[string]string{
+		"foo": ".gcsfuse_links/abc",
+		"bar": ".gcsfuse_links/abc",
+	}, names)
+}
+
+func (t *HardLinkTest) TestReadEntryCores_ContentsStatFails() {
+	_, err := t.root.CreateChildHardLink(t.ctx, "foo", t.contents)
+	require.NoError(t.T(), err)
+	t.statCounter.err = errors.New("taco")
+
+	cores, _, _, err := t.root.ReadEntryCores(t.ctx, "")
+
+	require.NoError(t.T(), err)
+	require.Len(t.T(), cores, 1)
+	for _, core := range cores {
+		assert.Equal(t.T(), "foo", core.FullName.GcsObjectName())
+		assert.True(t.T(), inode.IsHardLink(core.MinObject))
+	}
+}
+
+func (t *HardLinkTest) TestReadEntries_DoesNotResolveHardLinks() {
+	_, err := t.root.CreateChildHardLink(t.ctx, "foo", t.contents)
+	require.NoError(t.T(), err)
+	_, err = t.root.CreateChildHardLink(t.ctx, "bar", t.contents)
+	require.NoError(t.T(), err)
+
+	entries, _, _, err := t.root.ReadEntries(t.ctx, "")
+
+	require.NoError(t.T(), err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+		assert.Equal(t.T(), fuseutil.DT_File, entry.Type)
+	}
+	assert.ElementsMatch(t.T(), []string{"foo", "bar"}, names)
+	assert.EqualValues(t.T(), 0, t.statCounter.stats.Load())
+}
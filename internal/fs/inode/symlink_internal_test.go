@@ -71,12 +71,13 @@ func (t *SymlinkInternalTest) createSymlinkInode(name string, target string, leg
 		m.Metadata[StandardSymlinkMetadataKey] = "true"
 	}
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/",
-		t.bucket,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		t.bucket)
 
 	s, err := NewSymlinkInode(
 		t.ctx,
@@ -163,12 +164,13 @@ func (t *SymlinkInternalTest) TestNewSymlinkInode_Legacy() {
 		},
 	}
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/",
-		t.bucket,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		t.bucket)
 
 	s, err := NewSymlinkInode(
 		t.ctx,
@@ -194,12 +196,13 @@ func (t *SymlinkInternalTest) TestNewSymlinkInode_Standard() {
 	}
 	m.Metadata[StandardSymlinkMetadataKey] = "true"
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/",
-		t.bucket,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		t.bucket)
 
 	s, err := NewSymlinkInode(
 		t.ctx,
@@ -225,12 +228,13 @@ func (t *SymlinkInternalTest) TestNewSymlinkInode_Standard_ReadError() {
 		Generation: 1,
 	}
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/",
-		t.bucket,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		t.bucket)
 
 	_, err := NewSymlinkInode(
 		t.ctx,
@@ -250,12 +254,13 @@ func (t *SymlinkInternalTest) TestNewSymlinkInode_InvalidMetadata() {
 		Metadata: map[string]string{},
 	}
 	syncerBucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/",
-		t.bucket,
-	)
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		t.bucket)
 
 	_, err := NewSymlinkInode(
 		t.ctx,
@@ -48,10 +48,13 @@ func init() { RegisterTestSuite(&SymlinkTest{}) }
 
 func (t *SymlinkTest) SetUp(ti *TestInfo) {
 	bucket := gcsx.NewSyncerBucket(
-		/*appendThreshold=*/ 1,
-		/*chunkRetryDeadlineSecs=*/ 120,
-		/*chunkTransferTimeoutSecs=*/ 10,
-		".gcsfuse_tmp/", fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{}))
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{}))
 	t.bucket = &bucket
 }
 
@@ -0,0 +1,659 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+)
+
+// VersionsDirName is the name of the hidden directory at the root of a bucket
+// that browses the generations of its objects: for each object "a/b", the
+// directory "<VersionsDirName>/a/b" contains a read-only file per generation,
+// named by its generation number.
+const VersionsDirName = ".gcsfuse_versions"
+
+// NewVersionsRootCore returns the core of the versions directory of the bucket
+// with the given root directory.
+func NewVersionsRootCore(bucket *gcsx.SyncerBucket, bucketRoot Name) Core {
+	return Core{
+		FullName: NewDirName(bucketRoot, VersionsDirName),
+		Bucket:   bucket,
+		Versions: true,
+	}
+}
+
+// IsVersionsInode returns true if the inode belongs to the versions directory
+// tree, which is read-only.
+func IsVersionsInode(in Inode) bool {
+	switch in.(type) {
+	case *versionsDirInode, *GenerationFileInode:
+		return true
+	}
+	return false
+}
+
+// A directory of the versions directory tree, which is read-only. It
+// corresponds to a path in the bucket and contains:
+//
+//   - a directory per object or prefix directly under that path, with at
+//     least one live, noncurrent or soft-deleted generation, and
+//   - a file per generation of the object at that path, if any.
+type versionsDirInode struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	id fuseops.InodeID
+
+	// INVARIANT: name.IsDir()
+	name Name
+
+	// The path in the bucket, without a trailing slash. Empty for the root of
+	// the versions directory.
+	path string
+
+	attrs  fuseops.InodeAttributes
+	bucket *gcsx.SyncerBucket
+	ctx    context.Context
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	mu locker.RWLocker
+
+	lc lookupCount
+}
+
+var _ BucketOwnedDirInode = &versionsDirInode{}
+
+// NewVersionsDirInode returns a directory of the versions directory tree with
+// the given name, which must have been returned by NewVersionsRootCore or by a
+// lookup in another directory of the tree.
+func NewVersionsDirInode(
+	id fuseops.InodeID,
+	name Name,
+	parentInodeCtx context.Context,
+	attrs fuseops.InodeAttributes,
+	bucket *gcsx.SyncerBucket) DirInode {
+	if parentInodeCtx == nil {
+		parentInodeCtx = context.Background()
+	}
+	p := strings.TrimPrefix(name.GcsObjectName(), VersionsDirName+"/")
+	d := &versionsDirInode{
+		id:     id,
+		name:   name,
+		path:   strings.TrimSuffix(p, "/"),
+		attrs:  attrs,
+		bucket: bucket,
+		ctx:    parentInodeCtx,
+	}
+	d.lc.Init(id)
+	d.mu = locker.NewRW("VersionsDirInode"+name.GcsObjectName(), func() {})
+	return d
+}
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// objectGenerationsPageSize is the number of generations listed per page to
+// find the generations of a single object.
+const objectGenerationsPageSize = 100
+
+// The prefix of the objects under the path of the directory.
+func (d *versionsDirInode) childPrefix() string {
+	if d.path == "" {
+		return ""
+	}
+	return d.path + "/"
+}
+
+// listGenerations returns the live, noncurrent and soft-deleted generations of
+// the objects whose names start with the given prefix and don't contain "/"
+// after it, and the prefixes of the other objects up to that "/".
+func (d *versionsDirInode) listGenerations(ctx context.Context, prefix string) (cores []*Core, collapsedRuns []string, err error) {
+	for _, softDeleted := range []bool{false, true} {
+		req := &gcs.ListObjectsRequest{
+			Prefix:      prefix,
+			Delimiter:   "/",
+			MaxResults:  MaxResultsForListObjectsCall,
+			Versions:    !softDeleted,
+			SoftDeleted: softDeleted,
+		}
+		for {
+			listing, err := d.bucket.ListObjects(ctx, req)
+			if err != nil {
+				return nil, nil, fmt.Errorf("ListObjects: %w", err)
+			}
+			for _, o := range listing.MinObjects {
+				cores = append(cores, &Core{Bucket: d.bucket, MinObject: o, Versions: true, SoftDeleted: softDeleted})
+			}
+			collapsedRuns = append(collapsedRuns, listing.CollapsedRuns...)
+			if listing.ContinuationToken == "" {
+				break
+			}
+			req.ContinuationToken = listing.ContinuationToken
+		}
+	}
+	return cores, collapsedRuns, nil
+}
+
+// objectGenerations returns the live, noncurrent and soft-deleted generations
+// of the object with the given name. Listings are ordered by name, so the
+// generations of the object come before those of the other objects with its
+// name as prefix, and the listings stop at the first of them.
+func (d *versionsDirInode) objectGenerations(ctx context.Context, name string) (cores []*Core, err error) {
+	for _, softDeleted := range []bool{false, true} {
+		req := &gcs.ListObjectsRequest{
+			Prefix:      name,
+			Delimiter:   "/",
+			MaxResults:  objectGenerationsPageSize,
+			Versions:    !softDeleted,
+			SoftDeleted: softDeleted,
+		}
+	pages:
+		for {
+			listing, err := d.bucket.ListObjects(ctx, req)
+			if err != nil {
+				return nil, fmt.Errorf("ListObjects: %w", err)
+			}
+			for _, o := range listing.MinObjects {
+				if o.Name != name {
+					break pages
+				}
+				cores = append(cores, &Core{Bucket: d.bucket, MinObject: o, Versions: true, SoftDeleted: softDeleted})
+			}
+			if len(listing.CollapsedRuns) > 0 || listing.ContinuationToken == "" {
+				break
+			}
+			req.ContinuationToken = listing.ContinuationToken
+		}
+	}
+	return cores, nil
+}
+
+// hasGenerationsUnder returns true if there is a live, noncurrent or
+// soft-deleted generation of an object whose name starts with the given
+// prefix.
+func (d *versionsDirInode) hasGenerationsUnder(ctx context.Context, prefix string) (bool, error) {
+	for _, softDeleted := range []bool{false, true} {
+		listing, err := d.bucket.ListObjects(ctx, &gcs.ListObjectsRequest{
+			Prefix:      prefix,
+			MaxResults:  1,
+			Versions:    !softDeleted,
+			SoftDeleted: softDeleted,
+		})
+		if err != nil {
+			return false, fmt.Errorf("ListObjects: %w", err)
+		}
+		if len(listing.MinObjects) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// generationCores returns the cores of the generation files of the directory.
+func (d *versionsDirInode) generationCores(ctx context.Context) ([]*Core, error) {
+	if d.path == "" {
+		return nil, nil
+	}
+	cores, err := d.objectGenerations(ctx, d.path)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cores {
+		c.FullName = NewFileName(d.name, strconv.FormatInt(c.MinObject.Generation, 10))
+	}
+	return cores, nil
+}
+
+// hasChildDir returns true if there is an object or a prefix with the given
+// name under the path of the directory.
+func (d *versionsDirInode) hasChildDir(ctx context.Context, name string) (bool, error) {
+	objectName := d.childPrefix() + name
+	cores, err := d.objectGenerations(ctx, objectName)
+	if err != nil {
+		return false, err
+	}
+	if len(cores) > 0 {
+		return true, nil
+	}
+	return d.hasGenerationsUnder(ctx, objectName+"/")
+}
+
+// LOCKS_REQUIRED(d.mu.RLock)
+func (d *versionsDirInode) readCores(ctx context.Context) (map[Name]*Core, error) {
+	cores := make(map[Name]*Core)
+	generations, err := d.generationCores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range generations {
+		cores[c.FullName] = c
+	}
+
+	prefix := d.childPrefix()
+	objects, collapsedRuns, err := d.listGenerations(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	childNames := collapsedRuns
+	for _, c := range objects {
+		childNames = append(childNames, c.MinObject.Name)
+	}
+	for _, childName := range childNames {
+		name := strings.TrimSuffix(strings.TrimPrefix(childName, prefix), "/")
+		if name == "" || (d.path == "" && name == VersionsDirName) {
+			continue
+		}
+		fullName := NewDirName(d.name, name)
+		cores[fullName] = &Core{FullName: fullName, Bucket: d.bucket, Versions: true}
+	}
+	return cores, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Public interface
+////////////////////////////////////////////////////////////////////////
+
+func (d *versionsDirInode) Lock() {
+	d.mu.Lock()
+}
+
+func (d *versionsDirInode) Unlock() {
+	d.mu.Unlock()
+}
+
+func (d *versionsDirInode) RLock() {
+	d.mu.RLock()
+}
+
+func (d *versionsDirInode) RUnlock() {
+	d.mu.RUnlock()
+}
+
+func (d *versionsDirInode) LockForChildLookup() {
+	d.mu.RLock()
+}
+
+func (d *versionsDirInode) UnlockForChildLookup() {
+	d.mu.RUnlock()
+}
+
+func (d *versionsDirInode) ID() fuseops.InodeID {
+	return d.id
+}
+
+func (d *versionsDirInode) Name() Name {
+	return d.name
+}
+
+func (d *versionsDirInode) Bucket() *gcsx.SyncerBucket {
+	return d.bucket
+}
+
+// LOCKS_REQUIRED(d)
+func (d *versionsDirInode) IncrementLookupCount() {
+	d.lc.Inc()
+}
+
+// LOCKS_REQUIRED(d)
+func (d *versionsDirInode) DecrementLookupCount(n uint64) (destroy bool) {
+	destroy = d.lc.Dec(n)
+	return
+}
+
+// LOCKS_REQUIRED(d)
+func (d *versionsDirInode) Destroy() (err error) {
+	// Nothing interesting to do.
+	return
+}
+
+// LOCKS_REQUIRED(d)
+func (d *versionsDirInode) Attributes(
+	ctx context.Context, clobberedCheck bool) (attrs fuseops.InodeAttributes, err error) {
+	attrs = d.attrs
+	attrs.Nlink = 1
+	return
+}
+
+func (d *versionsDirInode) UpdateSize(size uint64) {
+	// No-op for directories.
+}
+
+// LookUpChild returns the generation file with the given name, or else the
+// directory of the child object or prefix with the given name.
+//
+// LOCKS_REQUIRED(d.mu.RLock)
+func (d *versionsDirInode) LookUpChild(ctx context.Context, name string) (*Core, error) {
+	if _, err := strconv.ParseInt(name, 10, 64); err == nil {
+		generations, err := d.generationCores(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range generations {
+			if path.Base(c.FullName.GcsObjectName()) == name {
+				return c, nil
+			}
+		}
+	}
+
+	found, err := d.hasChildDir(ctx, name)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &Core{FullName: NewDirName(d.name, name), Bucket: d.bucket, Versions: true}, nil
+}
+
+// LOCKS_REQUIRED(d.mu.RLock)
+func (d *versionsDirInode) ReadEntries(
+	ctx context.Context,
+	tok string) (entries []fuseutil.Dirent, unsupportedPaths []string, newTok string, err error) {
+	cores, err := d.readCores(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	for fullName := range cores {
+		entry := fuseutil.Dirent{
+			Name: path.Base(fullName.LocalName()),
+			Type: fuseutil.DT_File,
+		}
+		if fullName.IsDir() {
+			entry.Type = fuseutil.DT_Directory
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil, "", nil
+}
+
+// LOCKS_REQUIRED(d.mu.RLock)
+func (d *versionsDirInode) ReadEntryCores(ctx context.Context, tok string) (cores map[Name]*Core, unsupportedPaths []string, newTok string, err error) {
+	cores, err = d.readCores(ctx)
+	return cores, nil, "", err
+}
+
+func (d *versionsDirInode) ReadDescendants(ctx context.Context, limit int) (map[Name]*Core, error) {
+	return nil, fuse.ENOSYS
+}
+
+func (d *versionsDirInode) LocalFileEntries(localFileInodes map[Name]Inode) (localEntries map[string]fuseutil.Dirent) {
+	return nil
+}
+
+func (d *versionsDirInode) ShouldInvalidateKernelListCache(ttl time.Duration) bool {
+	return true
+}
+
+func (d *versionsDirInode) InvalidateKernelListCache() {}
+
+func (d *versionsDirInode) InsertFileIntoTypeCache(_ string) {}
+
+func (d *versionsDirInode) EraseFromTypeCache(_ string) {}
+
+func (d *versionsDirInode) IsUnlinked() bool {
+	return false
+}
+
+func (d *versionsDirInode) Unlink() {}
+
+func (d *versionsDirInode) IsTypeCacheDeprecated() bool {
+	return false
+}
+
+func (d *versionsDirInode) CancelCurrDirPrefetcher() {}
+
+func (d *versionsDirInode) CancelSubdirectoryPrefetches() {}
+
+func (d *versionsDirInode) Context() context.Context {
+	return d.ctx
+}
+
+func (d *versionsDirInode) IncrementActiveWriters() {}
+
+func (d *versionsDirInode) DecrementActiveWriters() {}
+
+////////////////////////////////////////////////////////////////////////
+// Forbidden Public interface
+////////////////////////////////////////////////////////////////////////
+
+// The versions directory tree is read-only. When the user tries to mutate it,
+// they receive an EROFS error.
+
+func (d *versionsDirInode) CreateChildFile(ctx context.Context, name string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *versionsDirInode) CreateLocalChildFileCore(_ string) (Core, error) {
+	return Core{}, syscall.EROFS
+}
+
+func (d *versionsDirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.MinObject) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *versionsDirInode) CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *versionsDirInode) CreateChildHardLink(ctx context.Context, name string, contents *gcs.MinObject) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *versionsDirInode) CreateChildDir(ctx context.Context, name string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *versionsDirInode) DeleteChildFile(
+	ctx context.Context,
+	name string,
+	generation int64,
+	metaGeneration *int64) (err error) {
+	return syscall.EROFS
+}
+
+func (d *versionsDirInode) DeleteChildDir(
+	ctx context.Context,
+	name string,
+	isImplicitDir bool,
+	dirInode DirInode) (err error) {
+	return syscall.EROFS
+}
+
+func (d *versionsDirInode) DeleteObjects(ctx context.Context, objectNames []string) error {
+	return syscall.EROFS
+}
+
+func (d *versionsDirInode) RenameFile(ctx context.Context, fileToRename *gcs.MinObject, destinationFileName string) (*gcs.Object, error) {
+	return nil, syscall.EROFS
+}
+
+func (d *versionsDirInode) RenameFolder(ctx context.Context, folderName string, destinationFolderId string, folderInode DirInode) (*gcs.Folder, error) {
+	return nil, syscall.EROFS
+}
+
+// GenerationFileInode is a read-only file of the versions directory tree,
+// whose contents are a generation of an object.
+type GenerationFileInode struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	id          fuseops.InodeID
+	name        Name
+	bucket      *gcsx.SyncerBucket
+	src         gcs.MinObject
+	softDeleted bool
+	attrs       fuseops.InodeAttributes
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	lc lookupCount
+}
+
+var _ BucketOwnedInode = &GenerationFileInode{}
+var _ GenerationBackedInode = &GenerationFileInode{}
+
+// NewGenerationFileInode returns a file for the generation m of an object.
+// softDeleted is true if the generation is soft-deleted, in which case it
+// can't be read.
+func NewGenerationFileInode(
+	id fuseops.InodeID,
+	name Name,
+	bucket *gcsx.SyncerBucket,
+	m *gcs.MinObject,
+	softDeleted bool,
+	attrs fuseops.InodeAttributes) *GenerationFileInode {
+	f := &GenerationFileInode{
+		id:          id,
+		name:        name,
+		bucket:      bucket,
+		src:         *m,
+		softDeleted: softDeleted,
+		attrs: fuseops.InodeAttributes{
+			Size:  m.Size,
+			Nlink: 1,
+			Uid:   attrs.Uid,
+			Gid:   attrs.Gid,
+			Mode:  attrs.Mode,
+			Atime: m.Updated,
+			Ctime: m.Updated,
+			Mtime: m.Updated,
+		},
+	}
+	f.lc.Init(id)
+	return f
+}
+
+func (f *GenerationFileInode) Lock() {
+	f.mu.Lock()
+}
+
+func (f *GenerationFileInode) Unlock() {
+	f.mu.Unlock()
+}
+
+func (f *GenerationFileInode) ID() fuseops.InodeID {
+	return f.id
+}
+
+func (f *GenerationFileInode) Name() Name {
+	return f.name
+}
+
+func (f *GenerationFileInode) Bucket() *gcsx.SyncerBucket {
+	return f.bucket
+}
+
+// LOCKS_REQUIRED(f)
+func (f *GenerationFileInode) SourceGeneration() Generation {
+	return Generation{
+		Object:   f.src.Generation,
+		Metadata: f.src.MetaGeneration,
+		Size:     f.src.Size,
+	}
+}
+
+// Source returns the generation backing the file.
+func (f *GenerationFileInode) Source() *gcs.MinObject {
+	return &f.src
+}
+
+// IsSoftDeleted returns true if the generation is soft-deleted.
+func (f *GenerationFileInode) IsSoftDeleted() bool {
+	return f.softDeleted
+}
+
+// LOCKS_REQUIRED(f)
+func (f *GenerationFileInode) IncrementLookupCount() {
+	f.lc.Inc()
+}
+
+// LOCKS_REQUIRED(f)
+func (f *GenerationFileInode) DecrementLookupCount(n uint64) (destroy bool) {
+	destroy = f.lc.Dec(n)
+	return
+}
+
+// LOCKS_REQUIRED(f)
+func (f *GenerationFileInode) Attributes(
+	ctx context.Context, clobberedCheck bool) (attrs fuseops.InodeAttributes, err error) {
+	attrs = f.attrs
+	return
+}
+
+func (f *GenerationFileInode) UpdateSize(size uint64) {
+	// The generation is immutable.
+}
+
+// LOCKS_REQUIRED(f)
+func (f *GenerationFileInode) Destroy() (err error) {
+	// Nothing interesting to do.
+	return
+}
+
+func (f *GenerationFileInode) Unlink() {}
+
+// Read reads the contents of the generation at the given offset into dst,
+// returning io.EOF if the offset is at or past its end.
+//
+// LOCKS_EXCLUDED(f)
+func (f *GenerationFileInode) Read(ctx context.Context, dst []byte, offset int64) (n int, err error) {
+	if offset >= int64(f.src.Size) {
+		return 0, io.EOF
+	}
+	limit := min(uint64(offset)+uint64(len(dst)), f.src.Size)
+	rc, err := f.bucket.NewReaderWithReadHandle(
+		ctx,
+		&gcs.ReadObjectRequest{
+			Name:       f.src.Name,
+			Generation: f.src.Generation,
+			Range: &gcs.ByteRange{
+				Start: uint64(offset),
+				Limit: limit,
+			},
+		})
+	if err != nil {
+		return 0, fmt.Errorf("NewReader: %w", err)
+	}
+	defer rc.Close()
+
+	n, err = io.ReadFull(rc, dst[:limit-uint64(offset)])
+	if err != nil {
+		return n, fmt.Errorf("ReadFull: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type VersionsTest struct {
+	suite.Suite
+	ctx    context.Context
+	bucket *gcsx.SyncerBucket
+	// Generations of "dir/foo", oldest first.
+	generations []*gcs.Object
+}
+
+func TestVersionsSuite(t *testing.T) {
+	suite.Run(t, new(VersionsTest))
+}
+
+func newSyncerBucket(b gcs.Bucket) *gcsx.SyncerBucket {
+	bucket := gcsx.NewSyncerBucket(
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		b)
+	return &bucket
+}
+
+func (t *VersionsTest) SetupTest() {
+	t.ctx = context.Background()
+	t.bucket = newSyncerBucket(fake.NewFakeBucketWithVersioning(timeutil.RealClock(), "some-bucket", gcs.BucketType{}))
+
+	t.generations = nil
+	for _, contents := range []string{"taco", "burrito"} {
+		o, err := storageutil.CreateObject(t.ctx, t.bucket, "dir/foo", []byte(contents))
+		require.NoError(t.T(), err)
+		t.generations = append(t.generations, o)
+	}
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "dir/sub/bar", []byte("enchilada"))
+	require.NoError(t.T(), err)
+	// Deleted objects of a bucket with versioning have noncurrent generations.
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "gone", []byte("queso"))
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "gone"}))
+}
+
+func (t *VersionsTest) dir(core inode.Core) inode.DirInode {
+	return inode.NewVersionsDirInode(fuseops.RootInodeID+1, core.FullName, nil, fuseops.InodeAttributes{Mode: 0555}, t.bucket)
+}
+
+func (t *VersionsTest) lookUpDir(parent inode.DirInode, name string) inode.DirInode {
+	core, err := parent.LookUpChild(t.ctx, name)
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), core)
+	require.True(t.T(), core.Versions)
+	return t.dir(*core)
+}
+
+func entryNames(entries []fuseutil.Dirent) map[string]fuseutil.DirentType {
+	out := make(map[string]fuseutil.DirentType)
+	for _, e := range entries {
+		out[e.Name] = e.Type
+	}
+	return out
+}
+
+func (t *VersionsTest) TestReadEntries_Root() {
+	root := t.dir(inode.NewVersionsRootCore(t.bucket, inode.NewRootName("")))
+
+	entries, _, tok, err := root.ReadEntries(t.ctx, "")
+
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), tok)
+	assert.Equal(t.T(), map[string]fuseutil.DirentType{
+		"dir":  fuseutil.DT_Directory,
+		"gone": fuseutil.DT_Directory,
+	}, entryNames(entries))
+}
+
+func (t *VersionsTest) TestReadEntries_ObjectAndPrefix() {
+	root := t.dir(inode.NewVersionsRootCore(t.bucket, inode.NewRootName("")))
+	dir := t.lookUpDir(root, "dir")
+	foo := t.lookUpDir(dir, "foo")
+
+	dirEntries, _, _, err := dir.ReadEntries(t.ctx, "")
+	require.NoError(t.T(), err)
+	fooEntries, _, _, err := foo.ReadEntries(t.ctx, "")
+	require.NoError(t.T(), err)
+
+	assert.Equal(t.T(), map[string]fuseutil.DirentType{
+		"foo": fuseutil.DT_Directory,
+		"sub": fuseutil.DT_Directory,
+	}, entryNames(dirEntries))
+	assert.Equal(t.T(), map[string]fuseutil.DirentType{
+		strconv.FormatInt(t.generations[0].Generation, 10): fuseutil.DT_File,
+		strconv.FormatInt(t.generations[1].Generation, 10): fuseutil.DT_File,
+	}, entryNames(fooEntries))
+}
+
+func (t *VersionsTest) TestLookUpChild_Missing() {
+	root := t.dir(inode.NewVersionsRootCore(t.bucket, inode.NewRootName("")))
+
+	core, err := root.LookUpChild(t.ctx, "missing")
+
+	require.NoError(t.T(), err)
+	assert.Nil(t.T(), core)
+}
+
+// listCountingBucket counts the objects returned by listings.
+type listCountingBucket struct {
+	gcs.Bucket
+	listed int
+}
+
+func (b *listCountingBucket) ListObjects(ctx context.Context, req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	listing, err := b.Bucket.ListObjects(ctx, req)
+	if listing != nil {
+		b.listed += len(listing.MinObjects)
+	}
+	return listing, err
+}
+
+func (t *VersionsTest) TestLookUpChild_StopsAtSiblings() {
+	counter := &listCountingBucket{Bucket: fake.NewFakeBucketWithVersioning(timeutil.RealClock(), "some-bucket", gcs.BucketType{})}
+	t.bucket = newSyncerBucket(counter)
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	for i := range 1000 {
+		_, err = storageutil.CreateObject(t.ctx, t.bucket, fmt.Sprintf("foo-%04d", i), []byte("burrito"))
+		require.NoError(t.T(), err)
+	}
+	root := t.dir(inode.NewVersionsRootCore(t.bucket, inode.NewRootName("")))
+	counter.listed = 0
+
+	foo := t.lookUpDir(root, "foo")
+	entries, _, _, err := foo.ReadEntries(t.ctx, "")
+
+	require.NoError(t.T(), err)
+	assert.Len(t.T(), entries, 1)
+	assert.Less(t.T(), counter.listed, 500)
+}
+
+func (t *VersionsTest) TestGenerationFile_ReadNoncurrent() {
+	root := t.dir(inode.NewVersionsRootCore(t.bucket, inode.NewRootName("")))
+	foo := t.lookUpDir(t.lookUpDir(root, "dir"), "foo")
+	core, err := foo.LookUpChild(t.ctx, strconv.FormatInt(t.generations[0].Generation, 10))
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), core)
+	f := inode.NewGenerationFileInode(fuseops.RootInodeID+2, core.FullName, t.bucket, core.MinObject, core.SoftDeleted, fuseops.InodeAttributes{Mode: 0444})
+
+	buf := make([]byte, 3)
+	n, err := f.Read(t.ctx, buf, 1)
+	require.NoError(t.T(), err)
+	_, eofErr := f.Read(t.ctx, buf, 4)
+
+	assert.Equal(t.T(), "aco", string(buf[:n]))
+	assert.Equal(t.T(), io.EOF, eofErr)
+	assert.False(t.T(), f.IsSoftDeleted())
+	attrs, err := f.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.EqualValues(t.T(), 4, attrs.Size)
+}
+
+func (t *VersionsTest) TestGenerationFile_SoftDeleted() {
+	// Without versioning, deleted objects are soft-deleted.
+	t.bucket = newSyncerBucket(fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{}))
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "gone", []byte("queso"))
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "gone"}))
+	root := t.dir(inode.NewVersionsRootCore(t.bucket, inode.NewRootName("")))
+	gone := t.lookUpDir(root, "gone")
+
+	entries, _, _, err := gone.ReadEntryCores(t.ctx, "")
+
+	require.NoError(t.T(), err)
+	require.Len(t.T(), entries, 1)
+	for _, core := range entries {
+		assert.True(t.T(), core.SoftDeleted)
+		assert.Equal(t.T(), "gone", core.MinObject.Name)
+	}
+}
+
+func (t *VersionsTest) TestMutationsAreRejected() {
+	root := t.dir(inode.NewVersionsRootCore(t.bucket, inode.NewRootName("")))
+
+	_, createErr := root.CreateChildFile(t.ctx, "foo")
+	_, mkdirErr := root.CreateChildDir(t.ctx, "foo")
+	deleteErr := root.DeleteChildFile(t.ctx, "foo", 0, nil)
+
+	assert.ErrorIs(t.T(), createErr, syscall.EROFS)
+	assert.ErrorIs(t.T(), mkdirErr, syscall.EROFS)
+	assert.ErrorIs(t.T(), deleteErr, syscall.EROFS)
+	assert.True(t.T(), inode.IsVersionsInode(root))
+}
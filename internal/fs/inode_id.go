@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// stableInodeID returns the ID derived from the supplied core, which is the
+// same for the same bucket and object names in every mount. Each attempt to
+// find a free ID derives a different one.
+func stableInodeID(ic inode.Core, attempt uint64) fuseops.InodeID {
+	h := fnv.New64a()
+	if ic.Bucket != nil {
+		h.Write([]byte(ic.Bucket.Name()))
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(ic.FullName.GcsObjectName()))
+	// Entries of the versions directory tree share the names of the objects
+	// they are generations of.
+	if ic.Versions {
+		h.Write([]byte{0, 'v'})
+	}
+	if attempt > 0 {
+		h.Write([]byte{0, 'a'})
+		h.Write(binary.BigEndian.AppendUint64(nil, attempt))
+	}
+	return fuseops.InodeID(h.Sum64())
+}
+
+// allocateInodeID chooses the ID of a new inode for the supplied core. IDs are
+// handed out sequentially unless stable inode IDs are enabled, in which case
+// the ID is derived from the bucket and object names. If it's in use by a live
+// inode, e.g. by an earlier generation of the same object, the names are
+// hashed again with the number of the attempt, so that the IDs tried for a
+// name are always the same and don't run into those of other names.
+//
+// LOCKS_REQUIRED(fs.mu)
+func (fs *fileSystem) allocateInodeID(ic inode.Core) fuseops.InodeID {
+	if !fs.newConfig.FileSystem.EnableStableInodeIds {
+		id := fs.nextInodeID
+		fs.nextInodeID++
+		return id
+	}
+
+	for attempt := uint64(0); ; attempt++ {
+		id := stableInodeID(ic, attempt)
+		if id > fuseops.RootInodeID && fs.inodes[id] == nil {
+			return id
+		}
+	}
+}
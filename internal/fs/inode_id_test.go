@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFileSystemForInodeIDTest(enableStableInodeIds bool) *fileSystem {
+	return &fileSystem{
+		newConfig:   &cfg.Config{FileSystem: cfg.FileSystemConfig{EnableStableInodeIds: enableStableInodeIds}},
+		nextInodeID: fuseops.RootInodeID + 1,
+		inodes:      make(map[fuseops.InodeID]inode.Inode),
+	}
+}
+
+func newCoreForInodeIDTest(bucketName, objectName string) inode.Core {
+	bucket := gcsx.NewSyncerBucket(
+		gcsx.SyncerConfig{
+			AppendThreshold:          1,
+			ChunkRetryDeadlineSecs:   120,
+			ChunkTransferTimeoutSecs: 10,
+			TmpObjectPrefix:          ".gcsfuse_tmp/",
+		},
+		fake.NewFakeBucket(timeutil.RealClock(), bucketName, gcs.BucketType{}))
+	return inode.Core{
+		Bucket:   &bucket,
+		FullName: inode.NewFileName(inode.NewRootName(""), objectName),
+	}
+}
+
+func TestAllocateInodeID_Sequential(t *testing.T) {
+	fs := newFileSystemForInodeIDTest(false)
+	core := newCoreForInodeIDTest("some_bucket", "foo")
+
+	assert.Equal(t, fuseops.InodeID(fuseops.RootInodeID+1), fs.allocateInodeID(core))
+	assert.Equal(t, fuseops.InodeID(fuseops.RootInodeID+2), fs.allocateInodeID(core))
+}
+
+func TestAllocateInodeID_Stable(t *testing.T) {
+	foo := newCoreForInodeIDTest("some_bucket", "foo")
+
+	id := newFileSystemForInodeIDTest(true).allocateInodeID(foo)
+
+	// Another mount hands out the same ID for the same object.
+	assert.Equal(t, id, newFileSystemForInodeIDTest(true).allocateInodeID(foo))
+	assert.Greater(t, id, fuseops.InodeID(fuseops.RootInodeID))
+	assert.NotEqual(t, id, newFileSystemForInodeIDTest(true).allocateInodeID(newCoreForInodeIDTest("some_bucket", "bar")))
+	assert.NotEqual(t, id, newFileSystemForInodeIDTest(true).allocateInodeID(newCoreForInodeIDTest("other_bucket", "foo")))
+	versions := foo
+	versions.Versions = true
+	assert.NotEqual(t, id, newFileSystemForInodeIDTest(true).allocateInodeID(versions))
+}
+
+func TestAllocateInodeID_Collision(t *testing.T) {
+	fs := newFileSystemForInodeIDTest(true)
+	core := newCoreForInodeIDTest("some_bucket", "foo")
+	id := fs.allocateInodeID(core)
+	fs.inodes[id] = inode.NewVersionsDirInode(id, inode.NewRootName(""), nil, fuseops.InodeAttributes{}, core.Bucket)
+
+	second := fs.allocateInodeID(core)
+
+	assert.NotEqual(t, id, second)
+	// The ID tried next doesn't depend on the IDs in use around the first one.
+	other := newFileSystemForInodeIDTest(true)
+	other.inodes[id] = fs.inodes[id]
+	other.inodes[id+1] = fs.inodes[id]
+	assert.Equal(t, second, other.allocateInodeID(core))
+	fs.inodes[second] = fs.inodes[id]
+	assert.NotContains(t, []fuseops.InodeID{id, second}, fs.allocateInodeID(core))
+}
@@ -0,0 +1,262 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/handle"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// An Inspector lets an administrator look at the inodes and handles of a
+// mounted file system and invalidate its caches. It is attached to the file
+// system through ServerConfig.Inspector.
+type Inspector struct {
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	fs *fileSystem
+}
+
+func NewInspector() *Inspector {
+	return &Inspector{}
+}
+
+func (i *Inspector) attach(fs *fileSystem) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.fs = fs
+}
+
+// InodeInfo describes an inode known to the kernel.
+type InodeInfo struct {
+	ID uint64 `json:"id"`
+	// The name of the inode relative to the mount point.
+	Name string `json:"name"`
+	// One of "directory", "file" or "symlink".
+	Type string `json:"type"`
+}
+
+// HandleInfo describes an open file or directory handle.
+type HandleInfo struct {
+	ID      uint64 `json:"id"`
+	InodeID uint64 `json:"inode_id"`
+	// One of "directory" or "file".
+	Type string `json:"type"`
+}
+
+var errNotAttached = errors.New("the file system isn't mounted")
+
+func (i *Inspector) fileSystem() (*fileSystem, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.fs == nil {
+		return nil, errNotAttached
+	}
+	return i.fs, nil
+}
+
+// Inodes returns the inodes of the file system, sorted by ID.
+func (i *Inspector) Inodes() ([]InodeInfo, error) {
+	fs, err := i.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+	return fs.inodeInfos(), nil
+}
+
+// Handles returns the open handles of the file system, sorted by ID.
+func (i *Inspector) Handles() ([]HandleInfo, error) {
+	fs, err := i.fileSystem()
+	if err != nil {
+		return nil, err
+	}
+	return fs.handleInfos(), nil
+}
+
+// InvalidateMetadata removes the stat and type cache entries of the objects
+// and folders of the named bucket whose names start with the given prefix.
+func (i *Inspector) InvalidateMetadata(bucketName string, prefix string) error {
+	fs, err := i.fileSystem()
+	if err != nil {
+		return err
+	}
+	fs.invalidateMetadata(bucketName, prefix)
+	return nil
+}
+
+// EvictFileCache removes the named object from the file cache.
+func (i *Inspector) EvictFileCache(bucketName string, objectName string) error {
+	fs, err := i.fileSystem()
+	if err != nil {
+		return err
+	}
+	if fs.fileCacheHandler == nil {
+		return errors.New("the file cache is disabled")
+	}
+	return fs.fileCacheHandler.InvalidateCache(objectName, bucketName)
+}
+
+// InvalidateKernelCache asks the kernel to drop the cached attributes, data
+// and directory entry of the file or directory at the given path, relative to
+// the mount point.
+func (i *Inspector) InvalidateKernelCache(localPath string) error {
+	fs, err := i.fileSystem()
+	if err != nil {
+		return err
+	}
+	if fs.notifier == nil {
+		return errors.New("kernel notifications are disabled")
+	}
+	return fs.invalidateKernelCache(localPath)
+}
+
+func inodeType(in inode.Inode) string {
+	switch in.(type) {
+	case inode.DirInode:
+		return "directory"
+	case *inode.FileInode, *inode.GenerationFileInode:
+		return "file"
+	case *inode.SymlinkInode:
+		return "symlink"
+	default:
+		return "unknown"
+	}
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) inodeInfos() []InodeInfo {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	infos := make([]InodeInfo, 0, len(fs.inodes))
+	for id, in := range fs.inodes {
+		infos = append(infos, InodeInfo{
+			ID:   uint64(id),
+			Name: in.Name().LocalName(),
+			Type: inodeType(in),
+		})
+	}
+	sort.Slice(infos, func(a, b int) bool { return infos[a].ID < infos[b].ID })
+	return infos
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) handleInfos() []HandleInfo {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	infos := make([]HandleInfo, 0, len(fs.handles))
+	for id, h := range fs.handles {
+		info := HandleInfo{ID: uint64(id)}
+		switch h := h.(type) {
+		case *handle.FileHandle:
+			info.InodeID = uint64(h.Inode().ID())
+			info.Type = "file"
+		case *handle.GenerationFileHandle:
+			info.InodeID = uint64(h.Inode().ID())
+			info.Type = "file"
+		case *handle.DirHandle:
+			info.InodeID = uint64(h.Inode().ID())
+			info.Type = "directory"
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(a, b int) bool { return infos[a].ID < infos[b].ID })
+	return infos
+}
+
+// typeCacheInvalidator is implemented by the directory inodes that have a type
+// cache.
+type typeCacheInvalidator interface {
+	// LOCKS_REQUIRED(inode)
+	InvalidateTypeCache(prefix string)
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) invalidateMetadata(bucketName string, prefix string) {
+	if fs.bucketManager != nil {
+		fs.bucketManager.InvalidateStatCache(bucketName, prefix)
+	}
+
+	// Inode locks can't be acquired while holding the file system lock, so
+	// collect the directories first.
+	fs.mu.Lock()
+	var dirs []inode.BucketOwnedDirInode
+	for _, in := range fs.inodes {
+		if dir, ok := in.(inode.BucketOwnedDirInode); ok && dir.Bucket().Name() == bucketName {
+			dirs = append(dirs, dir)
+		}
+	}
+	fs.mu.Unlock()
+
+	for _, dir := range dirs {
+		if invalidator, ok := dir.(typeCacheInvalidator); ok {
+			dir.Lock()
+			invalidator.InvalidateTypeCache(prefix)
+			dir.Unlock()
+		}
+	}
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) invalidateKernelCache(localPath string) error {
+	localPath = strings.Trim(localPath, "/")
+	if localPath == "" {
+		return fs.notifier.InvalidateInode(fuseops.RootInodeID, 0, 0)
+	}
+	parentPath := path.Dir(localPath)
+
+	// The notifications block until the kernel has processed them, which may
+	// need the file system lock, so look the inodes up first.
+	fs.mu.Lock()
+	var childID, parentID fuseops.InodeID
+	if parentPath == "." {
+		parentID = fuseops.RootInodeID
+	}
+	for id, in := range fs.inodes {
+		switch strings.TrimSuffix(in.Name().LocalName(), "/") {
+		case localPath:
+			childID = id
+		case parentPath:
+			parentID = id
+		}
+	}
+	fs.mu.Unlock()
+
+	// The kernel can't have cached anything for a path whose parent it doesn't
+	// know.
+	if parentID == 0 {
+		return nil
+	}
+	if childID != 0 {
+		if err := fs.notifier.InvalidateInode(childID, 0, 0); err != nil {
+			return fmt.Errorf("InvalidateInode: %w", err)
+		}
+	}
+	if err := fs.notifier.InvalidateEntry(parentID, path.Base(localPath)); err != nil {
+		return fmt.Errorf("InvalidateEntry: %w", err)
+	}
+	return nil
+}
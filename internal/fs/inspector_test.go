@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/handle"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statCacheInvalidation struct {
+	bucketName string
+	prefix     string
+}
+
+type invalidationRecordingBucketManager struct {
+	gcsx.BucketManager
+	invalidations []statCacheInvalidation
+}
+
+func (bm *invalidationRecordingBucketManager) InvalidateStatCache(bucketName string, prefix string) {
+	bm.invalidations = append(bm.invalidations, statCacheInvalidation{bucketName, prefix})
+}
+
+func newInspectedFileSystem() (*Inspector, *fileSystem) {
+	fs := &fileSystem{
+		mu:      locker.New("FS", func() {}),
+		inodes:  make(map[fuseops.InodeID]inode.Inode),
+		handles: make(map[fuseops.HandleID]any),
+	}
+	i := NewInspector()
+	i.attach(fs)
+	return i, fs
+}
+
+func TestInspector_NotAttached(t *testing.T) {
+	i := NewInspector()
+
+	_, err := i.Inodes()
+	assert.ErrorIs(t, err, errNotAttached)
+	_, err = i.Handles()
+	assert.ErrorIs(t, err, errNotAttached)
+	assert.ErrorIs(t, i.InvalidateMetadata("bucket", "dir/"), errNotAttached)
+	assert.ErrorIs(t, i.EvictFileCache("bucket", "object"), errNotAttached)
+	assert.ErrorIs(t, i.InvalidateKernelCache("dir/file"), errNotAttached)
+}
+
+func TestInspector_InodesAndHandles(t *testing.T) {
+	i, fs := newInspectedFileSystem()
+	root := inode.NewBaseDirInode(fuseops.RootInodeID, inode.NewRootName(""), fuseops.InodeAttributes{}, nil, metrics.NewNoopMetrics(), false)
+	fs.inodes[fuseops.RootInodeID] = root
+	fs.handles[7] = handle.NewDirHandle(root, false)
+
+	inodes, err := i.Inodes()
+	require.NoError(t, err)
+	handles, err := i.Handles()
+	require.NoError(t, err)
+
+	assert.Equal(t, []InodeInfo{{ID: uint64(fuseops.RootInodeID), Name: "", Type: "directory"}}, inodes)
+	assert.Equal(t, []HandleInfo{{ID: 7, InodeID: uint64(fuseops.RootInodeID), Type: "directory"}}, handles)
+}
+
+func TestInspector_InvalidateMetadata(t *testing.T) {
+	i, fs := newInspectedFileSystem()
+	bm := &invalidationRecordingBucketManager{}
+	fs.bucketManager = bm
+
+	err := i.InvalidateMetadata("bucket", "dir/")
+
+	require.NoError(t, err)
+	assert.Equal(t, []statCacheInvalidation{{"bucket", "dir/"}}, bm.invalidations)
+}
+
+func TestInspector_EvictFileCacheDisabled(t *testing.T) {
+	i, _ := newInspectedFileSystem()
+
+	assert.Error(t, i.EvictFileCache("bucket", "object"))
+}
+
+func TestInspector_InvalidateKernelCacheWithoutNotifier(t *testing.T) {
+	i, _ := newInspectedFileSystem()
+
+	assert.Error(t, i.InvalidateKernelCache("dir/file"))
+}
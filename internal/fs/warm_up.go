@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file"
+	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// warmUpFileCache downloads the objects of the bucket matching the warm-up
+// manifest of the given config to the file cache.
+func warmUpFileCache(ctx context.Context, fileCacheHandler *file.CacheHandler, bucket gcs.Bucket, fileCacheCfg *cfg.FileCacheConfig) error {
+	patterns, err := file.ReadWarmUpManifest(string(fileCacheCfg.WarmUpManifest))
+	if err != nil {
+		return fmt.Errorf("warmUpFileCache: %w", err)
+	}
+	if _, err = fileCacheHandler.WarmUp(ctx, bucket, patterns, int(fileCacheCfg.WarmUpParallelism)); err != nil {
+		return fmt.Errorf("warmUpFileCache: %w", err)
+	}
+	return nil
+}
+
+// WarmUpFileCache downloads the objects of the bucket of the given config
+// matching its warm-up manifest to the file cache, without mounting it. The
+// index of the file cache is saved so that the next mount of the bucket reuses
+// the downloaded files, which requires file-cache:persist-index.
+func WarmUpFileCache(ctx context.Context, serverCfg *ServerConfig) (err error) {
+	fileCacheCfg := &serverCfg.NewConfig.FileCache
+	if fileCacheCfg.WarmUpManifest == "" {
+		return errors.New("WarmUpFileCache: file-cache:warm-up-manifest isn't set")
+	}
+	if !fileCacheCfg.PersistIndex {
+		return errors.New("WarmUpFileCache: file-cache:persist-index is required for the mount to reuse the file cache")
+	}
+
+	fileCacheHandler, err := createSingleMountFileCacheHandler(string(serverCfg.NewConfig.CacheDir), cacheutil.DefaultFilePerm, cacheutil.DefaultDirPerm, serverCfg)
+	if err != nil {
+		return fmt.Errorf("WarmUpFileCache: %w", err)
+	}
+	// Destroying the handler saves the index.
+	defer func() {
+		if destroyErr := fileCacheHandler.Destroy(); destroyErr != nil {
+			err = errors.Join(err, fmt.Errorf("WarmUpFileCache: %w", destroyErr))
+		}
+	}()
+
+	syncerBucket, err := serverCfg.BucketManager.SetUpBucket(ctx, serverCfg.BucketName, false, serverCfg.MetricHandle)
+	if err != nil {
+		return fmt.Errorf("WarmUpFileCache: SetUpBucket: %w", err)
+	}
+
+	// Files cached by a previous mount or warm-up are reused.
+	fileCacheHandler.RestoreIndex(ctx, syncerBucket)
+	return warmUpFileCache(ctx, fileCacheHandler, syncerBucket, fileCacheCfg)
+}
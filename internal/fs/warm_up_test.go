@@ -0,0 +1,174 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const warmUpTestBucketName = "warm-up-bucket"
+
+// newWarmUpServerConfig returns the config of a mount of a fake bucket with
+// the given objects, whose file cache is warmed up with a manifest of the
+// given patterns.
+func newWarmUpServerConfig(t *testing.T, objects map[string][]byte, patterns string) *fs.ServerConfig {
+	t.Helper()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), warmUpTestBucketName, gcs.BucketType{})
+	require.NoError(t, storageutil.CreateObjects(context.Background(), bucket, objects))
+	manifestPath := path.Join(t.TempDir(), "manifest")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(patterns), 0644))
+
+	return &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			CacheDir: cfg.ResolvedPath(t.TempDir()),
+			FileCache: cfg.FileCacheConfig{
+				MaxSizeMb:           -1,
+				DownloadChunkSizeMb: 1,
+				PersistIndex:        true,
+				WarmUpManifest:      cfg.ResolvedPath(manifestPath),
+				WarmUpParallelism:   2,
+			},
+			Write:           cfg.WriteConfig{GlobalMaxBlocks: 1},
+			EnableNewReader: true,
+			MetadataCache:   cfg.MetadataCacheConfig{TypeCacheMaxSizeMb: 4},
+		},
+		CacheClock: &timeutil.SimulatedClock{},
+		BucketName: warmUpTestBucketName,
+		MountPoint: "/some/mount/point",
+		BucketManager: &fakeBucketManager{
+			buckets: map[string]gcs.Bucket{warmUpTestBucketName: bucket},
+		},
+		SequentialReadSizeMb: 200,
+		TraceHandle:          tracing.NewNoopTracer(),
+		MetricHandle:         metrics.NewNoopMetrics(),
+	}
+}
+
+// cachedFilePath returns the path of the file in the file cache of the given
+// config for the given object.
+func cachedFilePath(serverCfg *fs.ServerConfig, objectName string) string {
+	cacheDir := path.Join(string(serverCfg.NewConfig.CacheDir), cacheutil.FileCache)
+	return cacheutil.GetDownloadPath(cacheDir, cacheutil.GetObjectPath(warmUpTestBucketName, objectName))
+}
+
+func TestWarmUpFileCache_SavesIndexForNextMount(t *testing.T) {
+	serverCfg := newWarmUpServerConfig(t, map[string][]byte{
+		"data/1": []byte("one"),
+		"data/2": []byte("two"),
+		"logs/1": []byte("log"),
+	}, "data/\n")
+
+	err := fs.WarmUpFileCache(context.Background(), serverCfg)
+
+	require.NoError(t, err)
+	content, err := os.ReadFile(cachedFilePath(serverCfg, "data/2"))
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(content))
+	assert.NoFileExists(t, cachedFilePath(serverCfg, "logs/1"))
+	indexDir := path.Join(string(serverCfg.NewConfig.CacheDir), cacheutil.FileCacheIndex)
+	index, err := os.ReadFile(cacheutil.GetIndexPath(indexDir, warmUpTestBucketName, serverCfg.MountPoint))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), `"object_name":"data/1"`)
+	assert.Contains(t, string(index), `"object_name":"data/2"`)
+}
+
+func TestNewFileSystem_RestoresIndexOfBucketsOfMultiBucketMount(t *testing.T) {
+	serverCfg := newWarmUpServerConfig(t, map[string][]byte{
+		"data/1": []byte("one"),
+		"data/2": []byte("two"),
+	}, "data/\n")
+	require.NoError(t, fs.WarmUpFileCache(context.Background(), serverCfg))
+	// Overwrite an object while unmounted.
+	bucket := serverCfg.BucketManager.(*fakeBucketManager).buckets[warmUpTestBucketName]
+	_, err := storageutil.CreateObject(context.Background(), bucket, "data/1", []byte("new"))
+	require.NoError(t, err)
+	serverCfg.BucketName = ""
+	serverCfg.NewConfig.FileCache.WarmUpManifest = ""
+	server, err := fs.NewFileSystem(context.Background(), serverCfg)
+	require.NoError(t, err)
+	t.Cleanup(server.Destroy)
+
+	err = server.LookUpInode(context.Background(), &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: warmUpTestBucketName})
+
+	require.NoError(t, err)
+	// The file of the overwritten object is removed once the index of the
+	// bucket is restored.
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(cachedFilePath(serverCfg, "data/1"))
+		return os.IsNotExist(err)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.FileExists(t, cachedFilePath(serverCfg, "data/2"))
+}
+
+func TestWarmUpFileCache_RequiresPersistIndex(t *testing.T) {
+	serverCfg := newWarmUpServerConfig(t, map[string][]byte{"data/1": []byte("one")}, "data/\n")
+	serverCfg.NewConfig.FileCache.PersistIndex = false
+
+	err := fs.WarmUpFileCache(context.Background(), serverCfg)
+
+	assert.ErrorContains(t, err, "persist-index")
+}
+
+func TestNewFileSystem_WarmsUpFileCacheBeforeReturning(t *testing.T) {
+	serverCfg := newWarmUpServerConfig(t, map[string][]byte{
+		"models/a.bin":  []byte("weights"),
+		"models/a.json": []byte("{}"),
+	}, "# Model weights\nmodels/*.bin\n")
+	serverCfg.NewConfig.FileCache.PersistIndex = false
+
+	server, err := fs.NewFileSystem(context.Background(), serverCfg)
+
+	require.NoError(t, err)
+	t.Cleanup(server.Destroy)
+	content, err := os.ReadFile(cachedFilePath(serverCfg, "models/a.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, "weights", string(content))
+	assert.NoFileExists(t, cachedFilePath(serverCfg, "models/a.json"))
+}
+
+func TestNewFileSystem_MountsIfWarmUpFails(t *testing.T) {
+	serverCfg := newWarmUpServerConfig(t, map[string][]byte{"models/a.bin": []byte("weights")}, "models/[\n")
+
+	server, err := fs.NewFileSystem(context.Background(), serverCfg)
+
+	require.NoError(t, err)
+	t.Cleanup(server.Destroy)
+	assert.NoFileExists(t, cachedFilePath(serverCfg, "models/a.bin"))
+}
+
+func TestWarmUpFileCache_FailsIfWarmUpFails(t *testing.T) {
+	serverCfg := newWarmUpServerConfig(t, nil, "models/[\n")
+
+	err := fs.WarmUpFileCache(context.Background(), serverCfg)
+
+	assert.ErrorContains(t, err, "warmUpFileCache")
+}
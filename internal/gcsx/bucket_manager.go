@@ -18,7 +18,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
@@ -31,6 +34,7 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/caching"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/local"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/util"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 	"github.com/jacobsa/timeutil"
@@ -70,31 +74,117 @@ type BucketConfig struct {
 	ChunkTransferTimeoutSecs int64
 	TmpObjectPrefix          string
 
+	// Files of at least ParallelCompositeUploadThreshold bytes that are written
+	// out in their entirety are uploaded as ParallelCompositeUploadParts
+	// temporary objects with names beginning with TmpObjectPrefix concurrently,
+	// which are then composed into the object. Zero disables this.
+	ParallelCompositeUploadThreshold int64
+	ParallelCompositeUploadParts     int
+
 	// Enable dummy I/O mode for testing purposes, simulated read without
 	// any data read from GCS.
 	// All the metadata operations like object listing and stats are real.
 	DummyIOCfg cfg.DummyIoConfig
 
+	// Inject faults into the operations of the bucket for testing purposes. See
+	// storage.NewChaosBucket.
+	ChaosCfg cfg.ChaosConfig
+
+	// If set, buckets are stored as local directories under LocalBucketRoot
+	// instead of in GCS. See package local.
+	LocalBucketRoot string
+
+	// If non-zero, buckets are mounted read-only as they were at PointInTime.
+	// See NewPointInTimeBucket.
+	PointInTime time.Time
+
+	// If set, the contents of the objects are encrypted client-side with the
+	// keys of this keyfile. See NewEncryptionBucket and LoadEncryptionKeys.
+	ClientSideEncryptionKeyFile string
+
+	// The contents of the objects whose names match any of these globs are
+	// gzip-compressed. See NewCompressionBucket.
+	GzipCompressionGlobs []string
+
+	// If set, the attributes of new objects are set from the rules of this
+	// file. See NewObjectMetadataBucket and LoadObjectMetadataRules.
+	ObjectMetadataRulesFile string
+
 	IsTypeCacheDeprecated bool
 
 	ImplicitDir bool
 }
 
+// newSyncerConfig returns the settings of the syncer of the buckets set up
+// with the given config.
+func newSyncerConfig(config *BucketConfig) SyncerConfig {
+	syncerConfig := SyncerConfig{
+		AppendThreshold:                  config.AppendThreshold,
+		ParallelCompositeUploadThreshold: config.ParallelCompositeUploadThreshold,
+		ParallelCompositeUploadParts:     config.ParallelCompositeUploadParts,
+		ChunkRetryDeadlineSecs:           config.ChunkRetryDeadlineSecs,
+		ChunkTransferTimeoutSecs:         config.ChunkTransferTimeoutSecs,
+		TmpObjectPrefix:                  config.TmpObjectPrefix,
+	}
+	// The encryption and compression buckets compose objects by reading back
+	// and rewriting the contents of the sources, which would download the parts
+	// of parallel composite uploads, and the whole object appended to, again.
+	if config.ClientSideEncryptionKeyFile != "" || len(config.GzipCompressionGlobs) > 0 {
+		if syncerConfig.ParallelCompositeUploadThreshold > 0 {
+			logger.Infof("Parallel composite uploads are disabled with client-side encryption and gzip compression")
+			syncerConfig.ParallelCompositeUploadThreshold = 0
+		}
+		// Objects of any size are written out in their entirety instead.
+		syncerConfig.AppendThreshold = math.MaxInt64
+	}
+	return syncerConfig
+}
+
 // BucketManager manages the lifecycle of buckets.
 type BucketManager interface {
 	SetUpBucket(
 		ctx context.Context,
 		name string, isMultibucketMount bool, metricHandle metrics.MetricHandle) (b SyncerBucket, err error)
 
+	// Applies the settings of the supplied config that can be changed while
+	// mounted, i.e. the stat cache TTLs and the rate limits, to the buckets set
+	// up so far and those set up later. Other settings are ignored. Returns an
+	// error without changing any setting if the rate limits are invalid, or if
+	// the config enables or disables the stat cache or rate limiting.
+	UpdateConfig(config BucketConfig) error
+
+	// Invalidates the stat cache entries of the objects and folders in the named
+	// bucket whose names start with the given prefix.
+	InvalidateStatCache(bucketName string, prefix string)
+
 	// Shuts down the bucket manager and its buckets
 	ShutDown()
 }
 
+// reloadableBucket holds the layers of a bucket set up by the bucket manager
+// whose settings can be changed by UpdateConfig. They are nil if the
+// corresponding feature was disabled when the bucket was set up.
+type reloadableBucket struct {
+	name           string
+	statBucket     caching.FastStatBucket
+	opThrottle     ratelimit.Throttle
+	egressThrottle ratelimit.Throttle
+}
+
 type bucketManager struct {
-	config          BucketConfig
 	storageHandle   storage.StorageHandle
 	sharedStatCache *lru.Cache
 
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	config BucketConfig
+
+	// The buckets set up so far.
+	//
+	// GUARDED_BY(mu)
+	buckets []reloadableBucket
+
 	// Garbage collector
 	gcCtx                 context.Context
 	stopGarbageCollecting func()
@@ -115,31 +205,28 @@ func NewBucketManager(config BucketConfig, storageHandle storage.StorageHandle)
 	return bm
 }
 
-func setUpRateLimiting(
-	in gcs.Bucket,
+// chooseThrottleRates returns the rates and token bucket capacities of the
+// throttles for the supplied limits.
+func chooseThrottleRates(
 	opRateLimitHz float64,
-	egressBandwidthLimit float64) (out gcs.Bucket, err error) {
-	// If no rate limiting has been requested, just return the bucket.
-	if !(opRateLimitHz > 0 || egressBandwidthLimit > 0) {
-		out = in
-		return
-	}
-
+	egressBandwidthLimit float64) (opRateHz float64, opCapacity uint64, egressRate float64, egressCapacity uint64, err error) {
 	// Treat a disabled limit as a very large one.
-	if !(opRateLimitHz > 0) {
-		opRateLimitHz = 1e15
+	opRateHz = opRateLimitHz
+	if !(opRateHz > 0) {
+		opRateHz = 1e15
 	}
 
-	if !(egressBandwidthLimit > 0) {
-		egressBandwidthLimit = 1e15
+	egressRate = egressBandwidthLimit
+	if !(egressRate > 0) {
+		egressRate = 1e15
 	}
 
 	// Choose token bucket capacities, targeting only a few percent error in each
 	// window of the given size.
 	const window = 8 * time.Hour
 
-	opCapacity, err := ratelimit.ChooseLimiterCapacity(
-		opRateLimitHz,
+	opCapacity, err = ratelimit.ChooseLimiterCapacity(
+		opRateHz,
 		window)
 
 	if err != nil {
@@ -147,8 +234,8 @@ func setUpRateLimiting(
 		return
 	}
 
-	egressCapacity, err := ratelimit.ChooseLimiterCapacity(
-		egressBandwidthLimit,
+	egressCapacity, err = ratelimit.ChooseLimiterCapacity(
+		egressRate,
 		window)
 
 	if err != nil {
@@ -156,9 +243,27 @@ func setUpRateLimiting(
 		return
 	}
 
+	return
+}
+
+func setUpRateLimiting(
+	in gcs.Bucket,
+	opRateLimitHz float64,
+	egressBandwidthLimit float64) (out gcs.Bucket, opThrottle ratelimit.Throttle, egressThrottle ratelimit.Throttle, err error) {
+	// If no rate limiting has been requested, just return the bucket.
+	if !(opRateLimitHz > 0 || egressBandwidthLimit > 0) {
+		out = in
+		return
+	}
+
+	opRateHz, opCapacity, egressRate, egressCapacity, err := chooseThrottleRates(opRateLimitHz, egressBandwidthLimit)
+	if err != nil {
+		return
+	}
+
 	// Create the throttles.
-	opThrottle := ratelimit.NewThrottle(opRateLimitHz, opCapacity)
-	egressThrottle := ratelimit.NewThrottle(egressBandwidthLimit, egressCapacity)
+	opThrottle = ratelimit.NewThrottle(opRateHz, opCapacity)
+	egressThrottle = ratelimit.NewThrottle(egressRate, egressCapacity)
 
 	// And the bucket.
 	out = ratelimit.NewThrottledBucket(
@@ -175,38 +280,70 @@ func (bm *bucketManager) SetUpBucket(
 	isMultibucketMount bool,
 	metricHandle metrics.MetricHandle,
 ) (sb SyncerBucket, err error) {
+	bm.mu.Lock()
+	config := bm.config
+	bm.mu.Unlock()
+
+	rb := reloadableBucket{name: name}
 	var b gcs.Bucket
 	// Set up the appropriate backing bucket.
 	if name == canned.FakeBucketName {
 		b = canned.MakeFakeBucket(ctx)
+	} else if config.LocalBucketRoot != "" {
+		b, err = local.NewBucket(timeutil.RealClock(), filepath.Join(config.LocalBucketRoot, name), name)
+		if err != nil {
+			err = fmt.Errorf("local.NewBucket: %w", err)
+			return
+		}
 	} else {
-		b, err = bm.storageHandle.BucketHandle(ctx, name, bm.config.BillingProject)
+		b, err = bm.storageHandle.BucketHandle(ctx, name, config.BillingProject)
 		if err != nil {
 			err = fmt.Errorf("BucketHandle: %w", err)
 			return
 		}
 	}
 
-	if bm.config.DummyIOCfg.Enable {
+	if config.DummyIOCfg.Enable {
 		logger.Infof("Enabling dummy I/O mode for bucket %q\n", name)
 		// Wrap in a dummy I/O bucket, which serves the data without actually going to network (GCS).
 		b = storage.NewDummyIOBucket(b, storage.DummyIOBucketParams{
-			ReaderLatency: bm.config.DummyIOCfg.ReaderLatency,
-			PerMBLatency:  bm.config.DummyIOCfg.PerMbLatency,
+			ReaderLatency: config.DummyIOCfg.ReaderLatency,
+			PerMBLatency:  config.DummyIOCfg.PerMbLatency,
+		})
+	}
+
+	if config.ChaosCfg.Enable {
+		logger.Warnf("Injecting faults into the operations of bucket %q\n", name)
+		b, err = storage.NewChaosBucket(b, storage.ChaosBucketParams{
+			Seed:              config.ChaosCfg.Seed,
+			ErrorRates:        config.ChaosCfg.ErrorRates,
+			ReadStallRate:     config.ChaosCfg.ReadStallRate,
+			ReadStallDuration: config.ChaosCfg.ReadStallDuration,
+			TruncatedReadRate: config.ChaosCfg.TruncatedReadRate,
+			WritePerMBLatency: config.ChaosCfg.WritePerMbLatency,
 		})
+		if err != nil {
+			return
+		}
 	}
 
 	// Enable monitoring.
 	b = monitor.NewMonitoringBucket(b, metricHandle)
 
-	if bm.config.LogSeverity == cfg.TraceLogSeverity {
+	if config.LogSeverity == cfg.TraceLogSeverity {
 		// Enable gcs logs.
 		b = storage.NewDebugBucket(b)
 	}
 
+	// Pin the objects to the generations live at a point in time, if requested.
+	if !config.PointInTime.IsZero() {
+		logger.Infof("Mounting bucket %q as it was at %v\n", name, config.PointInTime.Format(time.RFC3339))
+		b = NewPointInTimeBucket(config.PointInTime, b)
+	}
+
 	// Limit to a requested prefix of the bucket, if any.
-	if bm.config.OnlyDir != "" {
-		b, err = NewPrefixBucket(path.Clean(bm.config.OnlyDir)+"/", b)
+	if config.OnlyDir != "" {
+		b, err = NewPrefixBucket(path.Clean(config.OnlyDir)+"/", b)
 		if err != nil {
 			err = fmt.Errorf("NewPrefixBucket: %w", err)
 			return
@@ -214,10 +351,10 @@ func (bm *bucketManager) SetUpBucket(
 	}
 
 	// Enable rate limiting, if requested.
-	b, err = setUpRateLimiting(
+	b, rb.opThrottle, rb.egressThrottle, err = setUpRateLimiting(
 		b,
-		bm.config.OpRateLimitHz,
-		bm.config.EgressBandwidthLimitBytesPerSecond)
+		config.OpRateLimitHz,
+		config.EgressBandwidthLimitBytesPerSecond)
 
 	if err != nil {
 		err = fmt.Errorf("setUpRateLimiting: %w", err)
@@ -226,7 +363,9 @@ func (bm *bucketManager) SetUpBucket(
 
 	// Enable cached StatObject results based on stat cache config.
 	// Disabling stat cache with below config also disables negative stat cache.
-	if bm.config.StatCacheTTL != 0 && bm.sharedStatCache != nil {
+	var uncached gcs.Bucket
+	if bm.statCacheEnabled(&config) {
+		uncached = b
 		var statCache metadata.StatCache
 		if isMultibucketMount {
 			statCache = metadata.NewStatCacheBucketView(bm.sharedStatCache, name)
@@ -234,40 +373,139 @@ func (bm *bucketManager) SetUpBucket(
 			statCache = metadata.NewStatCacheBucketView(bm.sharedStatCache, "")
 		}
 
-		b = caching.NewFastStatBucket(
-			bm.config.StatCacheTTL,
+		rb.statBucket = caching.NewFastStatBucket(
+			config.StatCacheTTL,
 			statCache,
 			timeutil.RealClock(),
 			b,
-			bm.config.NegativeStatCacheTTL,
-			bm.config.IsTypeCacheDeprecated,
-			bm.config.ImplicitDir)
+			config.NegativeStatCacheTTL,
+			config.IsTypeCacheDeprecated,
+			config.ImplicitDir)
+		b = rb.statBucket
+	}
+
+	// Encrypt the contents of the objects client-side, if requested.
+	if config.ClientSideEncryptionKeyFile != "" {
+		var keys *EncryptionKeys
+		keys, err = LoadEncryptionKeys(config.ClientSideEncryptionKeyFile)
+		if err != nil {
+			return
+		}
+		if b.BucketType().IsRapid() {
+			err = fmt.Errorf("client-side encryption isn't supported for bucket %q: %w", name, ErrEncryptionUnsupported)
+			return
+		}
+		logger.Infof("Encrypting the objects of bucket %q client-side with key %q\n", name, keys.PrimaryID)
+		b = NewEncryptionBucket(keys, b)
+	}
+
+	// Compress the contents of the matching objects, before encrypting them.
+	if len(config.GzipCompressionGlobs) > 0 {
+		if b.BucketType().IsRapid() {
+			err = fmt.Errorf("gzip compression isn't supported for bucket %q: %w", name, ErrCompressionUnsupported)
+			return
+		}
+		b, err = NewCompressionBucket(config.GzipCompressionGlobs, config.TmpObjectPrefix, b)
+		if err != nil {
+			return
+		}
 	}
 
 	// Enable content type awareness
 	b = NewContentTypeBucket(b)
 
+	// Set the attributes of new objects by path, before guessing their content
+	// type.
+	if config.ObjectMetadataRulesFile != "" {
+		var rules []ObjectMetadataRule
+		rules, err = LoadObjectMetadataRules(config.ObjectMetadataRulesFile)
+		if err != nil {
+			return
+		}
+		b = NewObjectMetadataBucket(rules, config.TmpObjectPrefix, b)
+	}
+
 	// Enable Syncer
-	if bm.config.TmpObjectPrefix == "" {
+	if config.TmpObjectPrefix == "" {
 		err = errors.New("you must set TmpObjectPrefix")
 		return
 	}
-	sb = NewSyncerBucket(
-		bm.config.AppendThreshold,
-		bm.config.ChunkRetryDeadlineSecs,
-		bm.config.ChunkTransferTimeoutSecs,
-		bm.config.TmpObjectPrefix,
-		b)
+	sb = NewSyncerBucket(newSyncerConfig(&config), b)
+	sb.uncached = uncached
 
 	// Fetch bucket type from storage layout api and set bucket type.
 	b.BucketType()
 
-	// Periodically garbage collect temporary objects
-	go garbageCollect(bm.gcCtx, bm.config.TmpObjectPrefix, sb)
+	// Periodically garbage collect temporary objects, unless the bucket is
+	// read-only.
+	if config.PointInTime.IsZero() {
+		go garbageCollect(bm.gcCtx, config.TmpObjectPrefix, sb)
+	}
+
+	bm.mu.Lock()
+	bm.buckets = append(bm.buckets, rb)
+	bm.mu.Unlock()
 
 	return
 }
 
+func (bm *bucketManager) InvalidateStatCache(bucketName string, prefix string) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	for _, rb := range bm.buckets {
+		if rb.name == bucketName && rb.statBucket != nil {
+			rb.statBucket.EraseEntriesWithGivenPrefix(prefix)
+		}
+	}
+}
+
+// statCacheEnabled returns whether the buckets set up with the given config
+// have a stat cache.
+func (bm *bucketManager) statCacheEnabled(config *BucketConfig) bool {
+	return config.StatCacheTTL != 0 && bm.sharedStatCache != nil
+}
+
+// rateLimitingEnabled returns whether the buckets set up with the given config
+// are throttled.
+func rateLimitingEnabled(config *BucketConfig) bool {
+	return config.OpRateLimitHz > 0 || config.EgressBandwidthLimitBytesPerSecond > 0
+}
+
+func (bm *bucketManager) UpdateConfig(config BucketConfig) error {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	// Buckets set up before and after the update must behave the same, so
+	// features can only be turned on or off by remounting.
+	if bm.statCacheEnabled(&config) != bm.statCacheEnabled(&bm.config) {
+		return errors.New("the stat cache can't be enabled or disabled without remounting")
+	}
+	if rateLimitingEnabled(&config) != rateLimitingEnabled(&bm.config) {
+		return errors.New("rate limiting can't be enabled or disabled without remounting")
+	}
+	opRateHz, opCapacity, egressRate, egressCapacity, err := chooseThrottleRates(config.OpRateLimitHz, config.EgressBandwidthLimitBytesPerSecond)
+	if err != nil {
+		return fmt.Errorf("invalid rate limits: %w", err)
+	}
+
+	bm.config.StatCacheTTL = config.StatCacheTTL
+	bm.config.NegativeStatCacheTTL = config.NegativeStatCacheTTL
+	bm.config.OpRateLimitHz = config.OpRateLimitHz
+	bm.config.EgressBandwidthLimitBytesPerSecond = config.EgressBandwidthLimitBytesPerSecond
+
+	for _, rb := range bm.buckets {
+		if rb.statBucket != nil {
+			rb.statBucket.SetCacheTTLs(config.StatCacheTTL, config.NegativeStatCacheTTL)
+		}
+		if rb.opThrottle != nil {
+			rb.opThrottle.SetRate(opRateHz, opCapacity)
+			rb.egressThrottle.SetRate(egressRate, egressCapacity)
+		}
+	}
+	return nil
+}
+
 func (bm *bucketManager) ShutDown() {
 	bm.stopGarbageCollecting()
 }
@@ -16,17 +16,32 @@ package gcsx
 
 import (
 	"context"
+	"encoding/base64"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path"
 	"strings"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/storage/control/apiv2/controlpb"
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/ratelimit"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/local"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 	. "github.com/jacobsa/ogletest"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -204,3 +219,302 @@ func (t *BucketManagerTest) TestSetUpBucketMethodWhenBucketDoesNotExist_IsMultiB
 	ExpectTrue(strings.Contains(err.Error(), "code = NotFound desc = The specified bucket does not exist."))
 	ExpectEq(nil, bucket.Syncer)
 }
+
+type fakeFastStatBucket struct {
+	gcs.Bucket
+	primaryCacheTTL  time.Duration
+	negativeCacheTTL time.Duration
+	erasedPrefixes   []string
+}
+
+func (b *fakeFastStatBucket) SetCacheTTLs(primaryCacheTTL time.Duration, negativeCacheTTL time.Duration) {
+	b.primaryCacheTTL = primaryCacheTTL
+	b.negativeCacheTTL = negativeCacheTTL
+}
+
+func (b *fakeFastStatBucket) EraseEntriesWithGivenPrefix(prefix string) {
+	b.erasedPrefixes = append(b.erasedPrefixes, prefix)
+}
+
+func TestBucketManager_UpdateConfig(t *testing.T) {
+	statBucket := &fakeFastStatBucket{}
+	opThrottle := ratelimit.NewThrottle(1, 1)
+	egressThrottle := ratelimit.NewThrottle(1, 1)
+	bm := &bucketManager{
+		config:          BucketConfig{TmpObjectPrefix: "TmpObjectPrefix", StatCacheTTL: time.Minute, OpRateLimitHz: 1},
+		sharedStatCache: lru.NewCache(1 << 20),
+		buckets: []reloadableBucket{
+			{statBucket: statBucket, opThrottle: opThrottle, egressThrottle: egressThrottle},
+			{},
+		},
+	}
+
+	err := bm.UpdateConfig(BucketConfig{
+		TmpObjectPrefix:                    "Ignored",
+		StatCacheTTL:                       20 * time.Second,
+		NegativeStatCacheTTL:               5 * time.Second,
+		OpRateLimitHz:                      100,
+		EgressBandwidthLimitBytesPerSecond: 1 << 20,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, statBucket.primaryCacheTTL)
+	assert.Equal(t, 5*time.Second, statBucket.negativeCacheTTL)
+	_, opCapacity, _, egressCapacity, err := chooseThrottleRates(100, 1<<20)
+	assert.NoError(t, err)
+	assert.Equal(t, opCapacity, opThrottle.Capacity())
+	assert.Equal(t, egressCapacity, egressThrottle.Capacity())
+	assert.Equal(t, "TmpObjectPrefix", bm.config.TmpObjectPrefix)
+	assert.Equal(t, 20*time.Second, bm.config.StatCacheTTL)
+	assert.Equal(t, float64(100), bm.config.OpRateLimitHz)
+}
+
+func TestBucketManager_UpdateConfigRejected(t *testing.T) {
+	initialConfig := BucketConfig{StatCacheTTL: time.Minute, OpRateLimitHz: 1}
+	testCases := []struct {
+		name   string
+		config BucketConfig
+	}{
+		{
+			name:   "stat_cache_disabled",
+			config: BucketConfig{OpRateLimitHz: 2},
+		},
+		{
+			name:   "rate_limiting_disabled",
+			config: BucketConfig{StatCacheTTL: 2 * time.Minute},
+		},
+		{
+			name:   "invalid_rate_limit",
+			config: BucketConfig{StatCacheTTL: 2 * time.Minute, OpRateLimitHz: math.Inf(1)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statBucket := &fakeFastStatBucket{}
+			opThrottle := ratelimit.NewThrottle(1, 1)
+			bm := &bucketManager{
+				config:          initialConfig,
+				sharedStatCache: lru.NewCache(1 << 20),
+				buckets: []reloadableBucket{
+					{statBucket: statBucket, opThrottle: opThrottle, egressThrottle: ratelimit.NewThrottle(1, 1)},
+				},
+			}
+
+			err := bm.UpdateConfig(tc.config)
+
+			assert.Error(t, err)
+			assert.Equal(t, initialConfig, bm.config)
+			assert.Zero(t, statBucket.primaryCacheTTL)
+			assert.Equal(t, uint64(1), opThrottle.Capacity())
+		})
+	}
+}
+
+func TestBucketManager_UpdateConfigCannotEnableFeatures(t *testing.T) {
+	bm := &bucketManager{
+		sharedStatCache: lru.NewCache(1 << 20),
+		buckets:         []reloadableBucket{{}},
+	}
+
+	assert.Error(t, bm.UpdateConfig(BucketConfig{StatCacheTTL: time.Minute}))
+	assert.Error(t, bm.UpdateConfig(BucketConfig{EgressBandwidthLimitBytesPerSecond: 1 << 20}))
+	assert.Equal(t, BucketConfig{}, bm.config)
+}
+
+func TestBucketManager_InvalidateStatCache(t *testing.T) {
+	bucketA := &fakeFastStatBucket{}
+	bucketB := &fakeFastStatBucket{}
+	bm := &bucketManager{
+		buckets: []reloadableBucket{
+			{name: "a", statBucket: bucketA},
+			{name: "b", statBucket: bucketB},
+			{name: "a"},
+		},
+	}
+
+	bm.InvalidateStatCache("a", "dir/")
+
+	assert.Equal(t, []string{"dir/"}, bucketA.erasedPrefixes)
+	assert.Empty(t, bucketB.erasedPrefixes)
+}
+
+// setUpLocalBucket sets up the bucket "bucket" of a local bucket root with the
+// given config, and returns it along with the bucket it wraps.
+func setUpLocalBucket(t *testing.T, config BucketConfig) (bucket gcs.Bucket, rawBucket gcs.Bucket, err error) {
+	t.Helper()
+	config.LocalBucketRoot = t.TempDir()
+	config.TmpObjectPrefix = ".gcsfuse_tmp/"
+	require.NoError(t, os.Mkdir(path.Join(config.LocalBucketRoot, "bucket"), 0755))
+	rawBucket, err = local.NewBucket(timeutil.RealClock(), path.Join(config.LocalBucketRoot, "bucket"), "bucket")
+	require.NoError(t, err)
+	bm := NewBucketManager(config, nil)
+	t.Cleanup(bm.ShutDown)
+
+	bucket, err = bm.SetUpBucket(context.Background(), "bucket", false, metrics.NewNoopMetrics())
+	return bucket, rawBucket, err
+}
+
+func TestBucketManager_SetUpBucketWithClientSideEncryption(t *testing.T) {
+	keyFile := path.Join(t.TempDir(), "keys.json")
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", encryptionKeySize)))
+	require.NoError(t, os.WriteFile(keyFile, []byte(`{"primary": "k1", "keys": {"k1": "`+key+`"}}`), 0600))
+	bucket, rawBucket, err := setUpLocalBucket(t, BucketConfig{ClientSideEncryptionKeyFile: keyFile})
+	require.NoError(t, err)
+	_, err = storageutil.CreateObject(context.Background(), bucket, "foo", []byte("taco"))
+	require.NoError(t, err)
+
+	contents, err := storageutil.ReadObject(context.Background(), bucket, "foo")
+
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	raw, err := storageutil.ReadObject(context.Background(), rawBucket, "foo")
+	require.NoError(t, err)
+	assert.Len(t, raw, int(encryptedSize(4)))
+	assert.NotContains(t, string(raw), "taco")
+}
+
+func TestBucketManager_SetUpBucketWithObjectMetadataRules(t *testing.T) {
+	rulesFile := path.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`[{"match": "**/*.css", "cache-control": "no-cache"}]`), 0600))
+	bucket, _, err := setUpLocalBucket(t, BucketConfig{ObjectMetadataRulesFile: rulesFile})
+	require.NoError(t, err)
+
+	o, err := storageutil.CreateObject(context.Background(), bucket, "site/main.css", []byte("body {}"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "no-cache", o.CacheControl)
+	// The content type is still guessed from the extension.
+	assert.Equal(t, "text/css; charset=utf-8", o.ContentType)
+}
+
+func TestBucketManager_SetUpBucketWithGzipCompression(t *testing.T) {
+	bucket, rawBucket, err := setUpLocalBucket(t, BucketConfig{GzipCompressionGlobs: []string{"logs/**"}})
+	require.NoError(t, err)
+	contents := []byte(strings.Repeat("GET /index.html 200\n", 1000))
+
+	o, err := storageutil.CreateObject(context.Background(), bucket, "logs/access.log", contents)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(contents)), o.Size)
+	assert.Equal(t, "text/x-log; charset=utf-8", o.ContentType)
+	read, err := storageutil.ReadObject(context.Background(), bucket, "logs/access.log")
+	require.NoError(t, err)
+	assert.Equal(t, contents, read)
+	raw, err := storageutil.ReadObject(context.Background(), rawBucket, "logs/access.log")
+	require.NoError(t, err)
+	assert.Less(t, len(raw), len(contents)/10)
+}
+
+func TestBucketManager_SetUpBucketWithChaos(t *testing.T) {
+	bucket, _, err := setUpLocalBucket(t, BucketConfig{
+		ChaosCfg: cfg.ChaosConfig{Enable: true, ErrorRates: []string{"CreateObject:503:1"}},
+	})
+	require.NoError(t, err)
+
+	_, err = storageutil.CreateObject(context.Background(), bucket, "foo", []byte("taco"))
+
+	var apiErr *googleapi.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code)
+}
+
+func TestBucketManager_SetUpBucketWithInvalidConfig(t *testing.T) {
+	missingFile := path.Join(t.TempDir(), "missing")
+	testCases := []struct {
+		name    string
+		config  BucketConfig
+		wantErr string
+	}{
+		{
+			name:    "missing_encryption_key_file",
+			config:  BucketConfig{ClientSideEncryptionKeyFile: missingFile},
+			wantErr: "LoadEncryptionKeys",
+		},
+		{
+			name:    "missing_object_metadata_rules_file",
+			config:  BucketConfig{ObjectMetadataRulesFile: missingFile},
+			wantErr: "LoadObjectMetadataRules",
+		},
+		{
+			name:    "invalid_gzip_compression_glob",
+			config:  BucketConfig{GzipCompressionGlobs: []string{"logs/[a-"}},
+			wantErr: "NewCompressionBucket",
+		},
+		{
+			name:    "invalid_chaos_error_rates",
+			config:  BucketConfig{ChaosCfg: cfg.ChaosConfig{Enable: true, ErrorRates: []string{"CreateObject:500:1"}}},
+			wantErr: "NewChaosBucket",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := setUpLocalBucket(t, tc.config)
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestNewSyncerConfig(t *testing.T) {
+	testCases := []struct {
+		name                string
+		config              BucketConfig
+		wantThreshold       int64
+		wantAppendThreshold int64
+	}{
+		{"parallel_composite_uploads", BucketConfig{ParallelCompositeUploadThreshold: 100}, 100, 1 << 21},
+		{"client_side_encryption", BucketConfig{ParallelCompositeUploadThreshold: 100, ClientSideEncryptionKeyFile: "keys.yaml"}, 0, math.MaxInt64},
+		{"gzip_compression", BucketConfig{ParallelCompositeUploadThreshold: 100, GzipCompressionGlobs: []string{"*.log"}}, 0, math.MaxInt64},
+		{"gzip_compression_without_parallel_composite_uploads", BucketConfig{GzipCompressionGlobs: []string{"*.log"}}, 0, math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.AppendThreshold = 1 << 21
+			tc.config.ParallelCompositeUploadParts = 4
+			tc.config.TmpObjectPrefix = ".gcsfuse_tmp/"
+
+			syncerConfig := newSyncerConfig(&tc.config)
+
+			assert.Equal(t, tc.wantThreshold, syncerConfig.ParallelCompositeUploadThreshold)
+			assert.Equal(t, tc.wantAppendThreshold, syncerConfig.AppendThreshold)
+			assert.Equal(t, 4, syncerConfig.ParallelCompositeUploadParts)
+			assert.Equal(t, ".gcsfuse_tmp/", syncerConfig.TmpObjectPrefix)
+		})
+	}
+}
+
+// composeCountingBucket counts the compositions it's asked for.
+type composeCountingBucket struct {
+	gcs.Bucket
+	compositions int
+}
+
+func (b *composeCountingBucket) ComposeObjects(ctx context.Context, req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	b.compositions++
+	return b.Bucket.ComposeObjects(ctx, req)
+}
+
+func TestNewSyncerConfig_AppendToCompressedObject(t *testing.T) {
+	ctx := context.Background()
+	config := BucketConfig{AppendThreshold: 1, GzipCompressionGlobs: []string{"logs/**"}, TmpObjectPrefix: ".gcsfuse_tmp/"}
+	compressed, err := NewCompressionBucket(config.GzipCompressionGlobs, config.TmpObjectPrefix, fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{}))
+	require.NoError(t, err)
+	bucket := &composeCountingBucket{Bucket: compressed}
+	src, err := storageutil.CreateObject(ctx, bucket, "logs/a", []byte("taco"))
+	require.NoError(t, err)
+	content, err := NewTempFile(io.NopCloser(strings.NewReader("taco")), t.TempDir(), timeutil.RealClock())
+	require.NoError(t, err)
+	defer content.Destroy()
+	_, err = content.WriteAt([]byte("burrito"), 4)
+	require.NoError(t, err)
+
+	_, err = NewSyncer(newSyncerConfig(&config), bucket).SyncObject(ctx, "logs/a", src, nil, content)
+
+	require.NoError(t, err)
+	// The appended contents are written out in full rather than composed.
+	assert.Zero(t, bucket.compositions)
+	contents, err := storageutil.ReadObject(ctx, bucket, "logs/a")
+	require.NoError(t, err)
+	assert.Equal(t, "tacoburrito", string(contents))
+}
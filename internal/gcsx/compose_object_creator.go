@@ -56,6 +56,12 @@ type composeObjectCreator struct {
 }
 
 func (oc *composeObjectCreator) chooseName() (name string, err error) {
+	return chooseTmpObjectName(oc.prefix)
+}
+
+// chooseTmpObjectName returns a random name for a temporary object beginning
+// with the supplied prefix.
+func chooseTmpObjectName(prefix string) (name string, err error) {
 	// Generate a good 64-bit random number.
 	var buf [8]byte
 	_, err = io.ReadFull(rand.Reader, buf[:])
@@ -74,7 +80,7 @@ func (oc *composeObjectCreator) chooseName() (name string, err error) {
 		uint64(buf[7])<<56
 
 	// Turn it into a name.
-	name = fmt.Sprintf("%s%016x", oc.prefix, x)
+	name = fmt.Sprintf("%s%016x", prefix, x)
 
 	return
 }
@@ -87,6 +93,7 @@ func (oc *composeObjectCreator) Create(
 	objectName string,
 	srcObject *gcs.Object,
 	mtime *time.Time,
+	metadata map[string]string,
 	chunkRetryDeadlineSecs int64,
 	chunkTransferTimeoutSecs int64,
 	r io.Reader) (o *gcs.Object, err error) {
@@ -124,6 +131,7 @@ func (oc *composeObjectCreator) Create(
 
 	/* Copy Metadata fields from src object to new object generated by compose. */
 	maps.Copy(MetadataMap, srcObject.Metadata)
+	maps.Copy(MetadataMap, metadata)
 
 	if mtime != nil {
 		MetadataMap[gcs.MtimeMetadataKey] = mtime.UTC().Format(time.RFC3339Nano)
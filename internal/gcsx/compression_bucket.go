@@ -0,0 +1,685 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	storagev2 "cloud.google.com/go/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+)
+
+// UncompressedSizeMetadataKey is the metadata key of the size of the
+// uncompressed contents of an object compressed by a bucket created by
+// NewCompressionBucket, or of uncompressedSizeInTrailer if the size wasn't
+// known when the object was created.
+const UncompressedSizeMetadataKey = "gcsfuse-uncompressed-size"
+
+// uncompressedSizeInTrailer is recorded under UncompressedSizeMetadataKey until
+// the uncompressed size is, for the size in the trailer of the gzip stream to
+// be used meanwhile.
+const uncompressedSizeInTrailer = "trailer"
+
+// A compression bucket keeps up to maxPausedStreams decompression streams open
+// for pausedStreamTimeout after their readers are closed, so that reads
+// continuing from where they stopped don't decompress what precedes them again.
+const (
+	maxPausedStreams    = 16
+	pausedStreamTimeout = 10 * time.Second
+)
+
+// The trailer of a gzip stream ends with the size of the uncompressed
+// contents, modulo 4 GiB, in little-endian order.
+const (
+	gzipHeaderSize     = 10
+	gzipTrailerSize    = 8
+	gzipISizeFieldSize = 4
+)
+
+// ErrCompressionUnsupported is returned by the operations on appendable objects
+// and by the ranged downloads of compressed objects of a bucket created by
+// NewCompressionBucket.
+var ErrCompressionUnsupported = errors.New("appendable objects and multi-range downloads aren't supported with gzip compression")
+
+// compressedObject is what a compression bucket remembers of a generation of
+// an object.
+type compressedObject struct {
+	name       string
+	generation int64
+
+	// Whether the contents were compressed by a compression bucket, in which
+	// case size is the size of the uncompressed contents.
+	compressed bool
+	size       uint64
+
+	// The size of the contents as stored.
+	compressedSize uint64
+}
+
+// IsCompressed tells whether the given object, returned by a bucket created by
+// NewCompressionBucket, was compressed by it.
+func IsCompressed(o *gcs.MinObject) bool {
+	_, ok := o.Metadata[UncompressedSizeMetadataKey]
+	return ok && o.HasContentEncodingGzip()
+}
+
+func (o *compressedObject) contentsSize() (uint64, bool) {
+	return o.size, o.compressed
+}
+
+// knownSize returns the number of bytes left in the given contents, or -1 if
+// it can't be known before reading them.
+func knownSize(contents io.Reader) int64 {
+	s, ok := contents.(io.Seeker)
+	if !ok {
+		return -1
+	}
+	offset, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	if _, err = s.Seek(offset, io.SeekStart); err != nil {
+		return -1
+	}
+	return end - offset
+}
+
+////////////////////////////////////////////////////////////////////////
+// Bucket
+////////////////////////////////////////////////////////////////////////
+
+// NewCompressionBucket creates a wrapper bucket that gzip-compresses the
+// contents of the objects it creates or composes whose names match any of the
+// given globs, with the syntax of ObjectMetadataRule.Match, and of the
+// objects it previously compressed. It sets their content encoding to gzip and
+// records the size of their uncompressed contents in their metadata under
+// UncompressedSizeMetadataKey when they're created, or once they're uploaded if
+// it isn't known before, such as for streamed and composed objects. Until then,
+// the size in their gzip trailer is used, which is wrong for objects of 4 GiB or
+// more. Temporary objects, whose names begin with tmpObjectPrefix, aren't
+// compressed.
+//
+// The sizes of the compressed objects it returns are those of their
+// uncompressed contents, their checksums are dropped, and their contents are
+// decompressed when read. Since gzip streams can't be read from the middle,
+// reading a range of a compressed object decompresses everything before it,
+// unless it starts after where an earlier read of the object stopped, which is
+// then continued. Other objects, including gzip-encoded ones uploaded by other tools, are read
+// as is.
+//
+// Objects can't be composed server-side: the sources are read and the
+// composite object compressed anew. Appendable objects aren't supported.
+func NewCompressionBucket(globs []string, tmpObjectPrefix string, wrapped gcs.Bucket) (gcs.Bucket, error) {
+	b := &compressionBucket{
+		tmpObjectPrefix: tmpObjectPrefix,
+		wrapped:         wrapped,
+	}
+	b.objects = newTransformedObjects(b.describe, wrapped)
+	for _, glob := range globs {
+		re, err := compileGlob(glob)
+		if err != nil {
+			return nil, fmt.Errorf("NewCompressionBucket: glob %q: %w", glob, err)
+		}
+		b.globs = append(b.globs, re)
+	}
+	return b, nil
+}
+
+type compressionBucket struct {
+	globs           []*regexp.Regexp
+	tmpObjectPrefix string
+
+	// The compression of the generations of the objects returned so far.
+	objects *transformedObjects[*compressedObject]
+
+	mu sync.Mutex
+
+	// The decompression streams of closed readers, oldest first.
+	//
+	// GUARDED_BY(mu)
+	paused []*decompressionStream
+
+	wrapped gcs.Bucket
+}
+
+// compresses tells whether the contents of the object with the given name and
+// requested metadata are to be compressed.
+func (b *compressionBucket) compresses(name string, metadata map[string]string) bool {
+	if b.tmpObjectPrefix != "" && strings.HasPrefix(name, b.tmpObjectPrefix) {
+		return false
+	}
+	// Keep the objects compressed when they're rewritten.
+	if _, ok := metadata[UncompressedSizeMetadataKey]; ok {
+		return true
+	}
+	for _, re := range b.globs {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe returns the compression of the given object of the wrapped bucket.
+func (b *compressionBucket) describe(ctx context.Context, o *gcs.MinObject) (*compressedObject, error) {
+	c := &compressedObject{
+		name:           o.Name,
+		generation:     o.Generation,
+		compressedSize: o.Size,
+	}
+	value, ok := o.Metadata[UncompressedSizeMetadataKey]
+	if !ok || o.ContentEncoding != gcs.ContentEncodingGzip {
+		return c, nil
+	}
+
+	c.compressed = true
+	if size, err := strconv.ParseUint(value, 10, 64); err == nil {
+		c.size = size
+		return c, nil
+	}
+	size, err := b.trailerSize(ctx, o)
+	if err != nil {
+		return nil, fmt.Errorf("reading the uncompressed size of %q: %w", o.Name, err)
+	}
+	c.size = size
+	return c, nil
+}
+
+// trailerSize returns the size of the uncompressed contents of the given
+// compressed object in the trailer of its gzip stream.
+func (b *compressionBucket) trailerSize(ctx context.Context, o *gcs.MinObject) (uint64, error) {
+	if o.Size < gzipHeaderSize+gzipTrailerSize {
+		return 0, fmt.Errorf("%d bytes are too few for a gzip stream", o.Size)
+	}
+	r, err := b.wrapped.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{
+		Name:           o.Name,
+		Generation:     o.Generation,
+		Range:          &gcs.ByteRange{Start: o.Size - gzipISizeFieldSize, Limit: o.Size},
+		ReadCompressed: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	var isize [gzipISizeFieldSize]byte
+	if _, err = io.ReadFull(r, isize[:]); err != nil {
+		return 0, err
+	}
+	return uint64(binary.LittleEndian.Uint32(isize[:])), nil
+}
+
+// compressedRequest returns a copy of the given request for compressed
+// contents of the given size, -1 if unknown. The checksums of the request,
+// which are those of the uncompressed contents, are dropped.
+func compressedRequest(req *gcs.CreateObjectRequest, size int64) *gcs.CreateObjectRequest {
+	mReq := transformedRequest(req, UncompressedSizeMetadataKey)
+	if mReq.Metadata == nil {
+		mReq.Metadata = make(map[string]string)
+	}
+	mReq.Metadata[UncompressedSizeMetadataKey] = uncompressedSizeInTrailer
+	if size >= 0 {
+		mReq.Metadata[UncompressedSizeMetadataKey] = strconv.FormatInt(size, 10)
+	}
+	mReq.ContentEncoding = gcs.ContentEncodingGzip
+	return mReq
+}
+
+// compressContents writes the compressed contents of the given request to w,
+// failing before the end of the gzip stream if they don't match the checksums
+// of the request or the given size, unless -1, and returns their uncompressed
+// size.
+func compressContents(w io.Writer, req *gcs.CreateObjectRequest, wantSize int64) (int64, error) {
+	zw := gzip.NewWriter(w)
+	size, err := copyContents(zw, req)
+	if err != nil {
+		return 0, err
+	}
+	if wantSize >= 0 && size != wantSize {
+		return 0, fmt.Errorf("read %d bytes of contents, want %d", size, wantSize)
+	}
+	return size, zw.Close()
+}
+
+// recordUncompressedSize records the size of the uncompressed contents of the
+// given uploaded object in its metadata, and returns the updated object. Since
+// the size in the trailer is used meanwhile, the object is returned as is if
+// it can't be updated.
+func (b *compressionBucket) recordUncompressedSize(ctx context.Context, o *gcs.Object, size int64) (*gcs.Object, error) {
+	value := strconv.FormatInt(size, 10)
+	updated, err := b.wrapped.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name:                       o.Name,
+		Generation:                 o.Generation,
+		MetaGenerationPrecondition: &o.MetaGeneration,
+		Metadata:                   map[string]*string{UncompressedSizeMetadataKey: &value},
+	})
+	if err != nil {
+		logger.Warnf("Failed to record the uncompressed size of %q: %v", o.Name, err)
+		return b.objects.object(ctx, o)
+	}
+	return b.objects.object(ctx, updated)
+}
+
+func (b *compressionBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+func (b *compressionBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+func (b *compressionBucket) GCSName(object *gcs.MinObject) string {
+	return b.wrapped.GCSName(object)
+}
+
+func (b *compressionBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	o, err := b.objects.lookUp(ctx, req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	if !o.compressed {
+		return b.wrapped.NewReaderWithReadHandle(ctx, req)
+	}
+
+	start, limit := clampRange(req.Range, o.size)
+	mReq := *req
+	mReq.Generation = o.generation
+	mReq.ReadCompressed = true
+	if start == limit {
+		mReq.Range = &gcs.ByteRange{Start: o.compressedSize, Limit: o.compressedSize}
+		rd, err := b.wrapped.NewReaderWithReadHandle(ctx, &mReq)
+		if err != nil {
+			return nil, err
+		}
+		return &decompressingReader{stream: &decompressionStream{wrapped: rd}}, nil
+	}
+
+	stream := b.resume(o, start)
+	if stream == nil {
+		// The stream outlives the request if it's paused.
+		mReq.Range = nil
+		rd, err := b.wrapped.NewReaderWithReadHandle(context.WithoutCancel(ctx), &mReq)
+		if err != nil {
+			return nil, err
+		}
+		stream = &decompressionStream{object: o, wrapped: rd}
+		if stream.gzip, err = gzip.NewReader(rd); err != nil {
+			rd.Close()
+			return nil, fmt.Errorf("decompressing %q: %w", o.name, err)
+		}
+	}
+	if _, err = io.CopyN(io.Discard, stream.gzip, int64(start-stream.offset)); err != nil {
+		stream.wrapped.Close()
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("decompressing %q: %w", o.name, err)
+	}
+	stream.offset = start
+
+	return &decompressingReader{
+		bucket:    b,
+		stream:    stream,
+		remaining: limit - start,
+	}, nil
+}
+
+// resume returns the paused decompression stream of the given object which
+// stopped the closest before the given offset, if any.
+//
+// LOCKS_EXCLUDED(b.mu)
+func (b *compressionBucket) resume(o *compressedObject, offset uint64) *decompressionStream {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var resumed *decompressionStream
+	i := 0
+	for _, s := range b.paused {
+		switch {
+		case time.Since(s.pausedAt) > pausedStreamTimeout:
+			s.wrapped.Close()
+		case s.object.name == o.name && s.object.generation == o.generation && s.offset <= offset && (resumed == nil || s.offset > resumed.offset):
+			if resumed != nil {
+				b.paused[i] = resumed
+				i++
+			}
+			resumed = s
+		default:
+			b.paused[i] = s
+			i++
+		}
+	}
+	clear(b.paused[i:])
+	b.paused = b.paused[:i]
+	return resumed
+}
+
+// pause keeps the given decompression stream open to be resumed, closing the
+// oldest one if too many are.
+//
+// LOCKS_EXCLUDED(b.mu)
+func (b *compressionBucket) pause(s *decompressionStream) {
+	if s.offset == s.object.size {
+		s.wrapped.Close()
+		return
+	}
+	s.pausedAt = time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.paused = append(b.paused, s)
+	if len(b.paused) > maxPausedStreams {
+		b.paused[0].wrapped.Close()
+		b.paused[0] = nil
+		b.paused = b.paused[1:]
+	}
+}
+
+func (b *compressionBucket) NewMultiRangeDownloader(
+	ctx context.Context,
+	req *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	o, err := b.objects.lookUp(ctx, req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	if o.compressed {
+		return nil, ErrCompressionUnsupported
+	}
+	return b.wrapped.NewMultiRangeDownloader(ctx, req)
+}
+
+func (b *compressionBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	if !b.compresses(req.Name, req.Metadata) {
+		o, err := b.wrapped.CreateObject(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return b.objects.object(ctx, o)
+	}
+
+	wantSize := knownSize(req.Contents)
+	var size int64
+	o, err := createTransformedObject(ctx, b.wrapped, compressedRequest(req, wantSize), func(w io.Writer) (err error) {
+		size, err = compressContents(w, req, wantSize)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if wantSize >= 0 {
+		return b.objects.object(ctx, o)
+	}
+	return b.recordUncompressedSize(ctx, o, size)
+}
+
+// CreateObjectChunkWriter passes on the callback, which is thus called with
+// the number of compressed bytes uploaded so far for compressed objects.
+func (b *compressionBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	if !b.compresses(req.Name, req.Metadata) {
+		return b.wrapped.CreateObjectChunkWriter(ctx, req, chunkSize, callBack)
+	}
+
+	w, err := b.wrapped.CreateObjectChunkWriter(ctx, compressedRequest(req, -1), chunkSize, callBack)
+	if err != nil {
+		return nil, err
+	}
+	return &compressingWriter{Writer: w, gzip: gzip.NewWriter(w)}, nil
+}
+
+func (b *compressionBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	if b.compresses(req.Name, req.Metadata) {
+		return nil, ErrCompressionUnsupported
+	}
+	return b.wrapped.CreateAppendableObjectWriter(ctx, req)
+}
+
+func (b *compressionBucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	cw, ok := w.(*compressingWriter)
+	if !ok {
+		o, err := b.wrapped.FinalizeUpload(ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		return b.objects.minObject(ctx, o)
+	}
+
+	if err := cw.gzip.Close(); err != nil {
+		return nil, err
+	}
+	m, err := b.wrapped.FinalizeUpload(ctx, cw.Writer)
+	if err != nil {
+		return nil, err
+	}
+	o, err := b.recordUncompressedSize(ctx, &gcs.Object{
+		Name:            m.Name,
+		Generation:      m.Generation,
+		MetaGeneration:  m.MetaGeneration,
+		Size:            m.Size,
+		ContentEncoding: m.ContentEncoding,
+		Metadata:        m.Metadata,
+	}, cw.size)
+	if err != nil {
+		return nil, err
+	}
+	return storageutil.ConvertObjToMinObject(o), nil
+}
+
+func (b *compressionBucket) FlushPendingWrites(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	if _, ok := w.(*compressingWriter); ok {
+		return nil, ErrCompressionUnsupported
+	}
+	o, err := b.wrapped.FlushPendingWrites(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.minObject(ctx, o)
+}
+
+func (b *compressionBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	o, err := b.wrapped.CopyObject(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.object(ctx, o)
+}
+
+// ComposeObjects reads the sources and creates the composite object with their
+// contents if it's to be compressed, since the sources aren't necessarily
+// compressed.
+func (b *compressionBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	if !b.compresses(req.DstName, req.Metadata) {
+		o, err := b.wrapped.ComposeObjects(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return b.objects.object(ctx, o)
+	}
+
+	return composeByReading(ctx, b, req)
+}
+
+func (b *compressionBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	m, e, err := b.wrapped.StatObject(ctx, req)
+	if err != nil || m == nil {
+		return m, e, err
+	}
+	return b.objects.stat(ctx, m, e)
+}
+
+func (b *compressionBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	listing, err := b.wrapped.ListObjects(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.listing(ctx, listing)
+}
+
+func (b *compressionBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	o, err := b.wrapped.UpdateObject(ctx, withoutMetadataKey(req, UncompressedSizeMetadataKey))
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.object(ctx, o)
+}
+
+func (b *compressionBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	return b.wrapped.DeleteObject(ctx, req)
+}
+
+func (b *compressionBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	o, err := b.wrapped.MoveObject(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.object(ctx, o)
+}
+
+func (b *compressionBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	return b.wrapped.DeleteFolder(ctx, folderName)
+}
+
+func (b *compressionBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	return b.wrapped.GetFolder(ctx, req)
+}
+
+func (b *compressionBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return b.wrapped.RenameFolder(ctx, folderName, destinationFolderId)
+}
+
+func (b *compressionBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return b.wrapped.CreateFolder(ctx, folderName)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Readers and writers
+////////////////////////////////////////////////////////////////////////
+
+// decompressionStream decompresses the contents of a compressed object.
+type decompressionStream struct {
+	object  *compressedObject
+	wrapped gcs.StorageReader
+
+	// Nil for an empty range.
+	gzip *gzip.Reader
+
+	// The number of bytes decompressed so far.
+	offset uint64
+
+	pausedAt time.Time
+}
+
+// decompressingReader returns a range of the uncompressed contents of a
+// compressed object from a decompression stream at its start, which is paused
+// once the reader is closed.
+type decompressingReader struct {
+	bucket *compressionBucket
+	stream *decompressionStream
+
+	// The number of bytes still to return.
+	remaining uint64
+
+	err error
+}
+
+func (r *decompressingReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	p = p[:min(uint64(len(p)), r.remaining)]
+	n, err := r.stream.gzip.Read(p)
+	r.remaining -= uint64(n)
+	r.stream.offset += uint64(n)
+	if errors.Is(err, io.EOF) {
+		err = nil
+		if r.remaining > 0 {
+			err = fmt.Errorf("decompressing %q: %w", r.stream.object.name, io.ErrUnexpectedEOF)
+		}
+	}
+	r.err = err
+	return n, err
+}
+
+func (r *decompressingReader) Close() error {
+	if r.stream.gzip == nil || r.err != nil {
+		return r.stream.wrapped.Close()
+	}
+	r.bucket.pause(r.stream)
+	return nil
+}
+
+func (r *decompressingReader) ReadHandle() storagev2.ReadHandle {
+	return r.stream.wrapped.ReadHandle()
+}
+
+// compressingWriter writes the compressed contents of what's written to it to
+// the wrapped writer.
+type compressingWriter struct {
+	gcs.Writer
+	gzip *gzip.Writer
+
+	// The number of uncompressed bytes written.
+	size int64
+}
+
+func (w *compressingWriter) Write(p []byte) (int, error) {
+	n, err := w.gzip.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *compressingWriter) Close() error {
+	if err := w.gzip.Close(); err != nil {
+		return err
+	}
+	return w.Writer.Close()
+}
+
+func (w *compressingWriter) Flush() (int64, error) {
+	return 0, ErrCompressionUnsupported
+}
@@ -0,0 +1,464 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var compressionTestGlobs = []string{"logs/**"}
+
+type CompressionBucketTest struct {
+	suite.Suite
+	ctx      context.Context
+	wrapped  gcs.Bucket
+	bucket   gcs.Bucket
+	contents []byte
+}
+
+func TestCompressionBucket(t *testing.T) {
+	suite.Run(t, new(CompressionBucketTest))
+}
+
+func (t *CompressionBucketTest) newBucket() gcs.Bucket {
+	b, err := gcsx.NewCompressionBucket(compressionTestGlobs, ".gcsfuse_tmp/", t.wrapped)
+	require.NoError(t.T(), err)
+	return b
+}
+
+func (t *CompressionBucketTest) SetupTest() {
+	t.ctx = context.Background()
+	t.wrapped = fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	t.bucket = t.newBucket()
+	var buf bytes.Buffer
+	for i := 0; buf.Len() < 100000; i++ {
+		fmt.Fprintf(&buf, "%d GET /index.html 200\n", i)
+	}
+	t.contents = buf.Bytes()
+}
+
+func (t *CompressionBucketTest) createObject(name string, contents []byte) *gcs.Object {
+	o, err := storageutil.CreateObject(t.ctx, t.bucket, name, contents)
+	require.NoError(t.T(), err)
+	return o
+}
+
+func (t *CompressionBucketTest) readRange(bucket gcs.Bucket, name string, byteRange *gcs.ByteRange) ([]byte, error) {
+	r, err := bucket.NewReaderWithReadHandle(t.ctx, &gcs.ReadObjectRequest{Name: name, Range: byteRange})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// rawObject returns the named object of the wrapped bucket and its
+// decompressed contents.
+func (t *CompressionBucketTest) rawObject(name string) (*gcs.MinObject, []byte) {
+	o, _, err := t.wrapped.StatObject(t.ctx, &gcs.StatObjectRequest{Name: name})
+	require.NoError(t.T(), err)
+	raw, err := storageutil.ReadObject(t.ctx, t.wrapped, name)
+	require.NoError(t.T(), err)
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	require.NoError(t.T(), err)
+	contents, err := io.ReadAll(zr)
+	require.NoError(t.T(), err)
+	assert.Less(t.T(), len(raw), len(contents)/5)
+	return o, contents
+}
+
+func (t *CompressionBucketTest) TestCreateObject_CompressesContents() {
+	o := t.createObject("logs/access.log", t.contents)
+
+	assert.Equal(t.T(), uint64(len(t.contents)), o.Size)
+	assert.Nil(t.T(), o.CRC32C)
+	assert.Nil(t.T(), o.MD5)
+	assert.Equal(t.T(), gcs.ContentEncodingGzip, o.ContentEncoding)
+	raw, contents := t.rawObject("logs/access.log")
+	assert.Equal(t.T(), t.contents, contents)
+	assert.Equal(t.T(), gcs.ContentEncodingGzip, raw.ContentEncoding)
+	assert.Equal(t.T(), strconv.Itoa(len(t.contents)), raw.Metadata[gcsx.UncompressedSizeMetadataKey])
+}
+
+func (t *CompressionBucketTest) TestCreateObject_RecordsSizeWhenCreated() {
+	t.createObject("logs/access.log", t.contents)
+
+	raw, _, err := t.wrapped.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "logs/access.log"})
+
+	require.NoError(t.T(), err)
+	// The size is recorded without updating the object.
+	assert.Equal(t.T(), int64(1), raw.MetaGeneration)
+	assert.Equal(t.T(), strconv.Itoa(len(t.contents)), raw.Metadata[gcsx.UncompressedSizeMetadataKey])
+}
+
+func (t *CompressionBucketTest) TestCreateObject_ContentsOfUnknownSize() {
+	o, err := t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     "logs/access.log",
+		Contents: io.MultiReader(bytes.NewReader(t.contents)),
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), o.Size)
+	raw, contents := t.rawObject("logs/access.log")
+	assert.Equal(t.T(), t.contents, contents)
+	assert.Equal(t.T(), strconv.Itoa(len(t.contents)), raw.Metadata[gcsx.UncompressedSizeMetadataKey])
+}
+
+func (t *CompressionBucketTest) TestIsCompressed() {
+	t.createObject("logs/access.log", t.contents)
+	t.createObject("access.log", t.contents)
+
+	listing, err := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{})
+
+	require.NoError(t.T(), err)
+	require.Len(t.T(), listing.MinObjects, 2)
+	assert.False(t.T(), gcsx.IsCompressed(listing.MinObjects[0]))
+	assert.True(t.T(), gcsx.IsCompressed(listing.MinObjects[1]))
+}
+
+func (t *CompressionBucketTest) TestStatObject_SizeInTrailer() {
+	// An object whose upload was interrupted before its size was recorded.
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(t.contents)
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), zw.Close())
+	_, err = t.wrapped.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:            "logs/access.log",
+		ContentEncoding: gcs.ContentEncodingGzip,
+		Metadata:        map[string]string{gcsx.UncompressedSizeMetadataKey: "trailer"},
+		Contents:        bytes.NewReader(buf.Bytes()),
+	})
+	require.NoError(t.T(), err)
+
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "logs/access.log"})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), m.Size)
+	contents, err := t.readRange(t.bucket, "logs/access.log", &gcs.ByteRange{Start: 10, Limit: 20})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.contents[10:20], contents)
+}
+
+func (t *CompressionBucketTest) TestNoncurrentGeneration() {
+	t.wrapped = fake.NewFakeBucketWithVersioning(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	t.bucket = t.newBucket()
+	first := t.createObject("logs/a", t.contents)
+	t.createObject("logs/a", []byte("taco"))
+	// The size of the noncurrent generation isn't remembered.
+	t.bucket = t.newBucket()
+
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "logs/a", Generation: first.Generation})
+	require.NoError(t.T(), err)
+	r, err := t.bucket.NewReaderWithReadHandle(t.ctx, &gcs.ReadObjectRequest{Name: "logs/a", Generation: first.Generation})
+	require.NoError(t.T(), err)
+	defer r.Close()
+	contents, err := io.ReadAll(r)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), m.Size)
+	assert.Equal(t.T(), t.contents, contents)
+}
+
+func (t *CompressionBucketTest) TestCreateObject_LeavesUnmatchedAndTemporaryObjectsAlone() {
+	for _, name := range []string{"access.log", ".gcsfuse_tmp/logs/access.log"} {
+		t.Run(name, func() {
+			o := t.createObject(name, t.contents)
+
+			assert.Empty(t.T(), o.ContentEncoding)
+			assert.NotContains(t.T(), o.Metadata, gcsx.UncompressedSizeMetadataKey)
+			raw, err := storageutil.ReadObject(t.ctx, t.wrapped, name)
+			require.NoError(t.T(), err)
+			assert.Equal(t.T(), t.contents, raw)
+		})
+	}
+}
+
+func (t *CompressionBucketTest) TestStatAndListObjects_ReturnUncompressedSizes() {
+	t.createObject("logs/access.log", t.contents)
+	t.createObject("logs/empty.log", nil)
+
+	m, e, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "logs/access.log", ForceFetchFromGcs: true, ReturnExtendedObjectAttributes: true})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), m.Size)
+	assert.Nil(t.T(), m.CRC32C)
+	assert.Nil(t.T(), e.MD5)
+	listing, err := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{})
+	require.NoError(t.T(), err)
+	require.Len(t.T(), listing.MinObjects, 2)
+	assert.Equal(t.T(), uint64(len(t.contents)), listing.MinObjects[0].Size)
+	assert.Equal(t.T(), "logs/empty.log", listing.MinObjects[1].Name)
+	assert.Equal(t.T(), uint64(0), listing.MinObjects[1].Size)
+}
+
+func (t *CompressionBucketTest) TestNewReader_Ranges() {
+	t.createObject("logs/access.log", t.contents)
+	size := len(t.contents)
+	testCases := []struct {
+		name         string
+		byteRange    *gcs.ByteRange
+		start, limit int
+	}{
+		{"whole", nil, 0, size},
+		{"beginning", &gcs.ByteRange{Start: 0, Limit: 100}, 0, 100},
+		{"middle", &gcs.ByteRange{Start: 50000, Limit: 70000}, 50000, 70000},
+		{"past_the_end", &gcs.ByteRange{Start: uint64(size - 10), Limit: uint64(size + 100)}, size - 10, size},
+		{"after_the_end", &gcs.ByteRange{Start: uint64(size + 100), Limit: uint64(size + 110)}, 0, 0},
+		{"empty", &gcs.ByteRange{Start: 100, Limit: 100}, 0, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func() {
+			// A new bucket doesn't know the compression of the object yet.
+			for _, bucket := range []gcs.Bucket{t.bucket, t.newBucket()} {
+				contents, err := t.readRange(bucket, "logs/access.log", tc.byteRange)
+
+				require.NoError(t.T(), err)
+				assert.Equal(t.T(), t.contents[tc.start:tc.limit], contents)
+			}
+		})
+	}
+}
+
+// readCountingBucket counts the readers created by the wrapped bucket.
+type readCountingBucket struct {
+	gcs.Bucket
+	readers int
+}
+
+func (b *readCountingBucket) NewReaderWithReadHandle(ctx context.Context, req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	b.readers++
+	return b.Bucket.NewReaderWithReadHandle(ctx, req)
+}
+
+func (t *CompressionBucketTest) TestNewReader_ContinuesWhereReadsStopped() {
+	t.createObject("logs/access.log", t.contents)
+	wrapped := &readCountingBucket{Bucket: t.wrapped}
+	bucket, err := gcsx.NewCompressionBucket(compressionTestGlobs, "", wrapped)
+	require.NoError(t.T(), err)
+	var contents []byte
+
+	for offset := 0; offset < len(t.contents); offset += 30000 {
+		chunk, err := t.readRange(bucket, "logs/access.log", &gcs.ByteRange{Start: uint64(offset), Limit: uint64(min(offset+30000, len(t.contents)))})
+		require.NoError(t.T(), err)
+		contents = append(contents, chunk...)
+	}
+
+	assert.Equal(t.T(), t.contents, contents)
+	assert.Equal(t.T(), 1, wrapped.readers)
+	// Reading before where the reads stopped decompresses from the start.
+	chunk, err := t.readRange(bucket, "logs/access.log", &gcs.ByteRange{Start: 10, Limit: 20})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.contents[10:20], chunk)
+	assert.Equal(t.T(), 2, wrapped.readers)
+}
+
+func (t *CompressionBucketTest) TestNewReader_EmptyObject() {
+	t.createObject("logs/empty.log", nil)
+
+	contents, err := t.readRange(t.newBucket(), "logs/empty.log", nil)
+
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), contents)
+}
+
+func (t *CompressionBucketTest) TestNewReader_GzipEncodedObjectOfOtherTools() {
+	// Objects compressed elsewhere, without a recorded size, are read as is.
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte("taco"))
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), zw.Close())
+	_, err = t.wrapped.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:            "logs/other.log.gz",
+		ContentEncoding: gcs.ContentEncodingGzip,
+		Contents:        bytes.NewReader(buf.Bytes()),
+	})
+	require.NoError(t.T(), err)
+
+	contents, err := t.readRange(t.bucket, "logs/other.log.gz", nil)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), buf.Bytes(), contents)
+}
+
+func (t *CompressionBucketTest) TestNewReader_TruncatedObject() {
+	t.createObject("logs/access.log", t.contents)
+	raw, err := storageutil.ReadObject(t.ctx, t.wrapped, "logs/access.log")
+	require.NoError(t.T(), err)
+	o, _, err := t.wrapped.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "logs/access.log"})
+	require.NoError(t.T(), err)
+	_, err = t.wrapped.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:            "logs/access.log",
+		ContentEncoding: gcs.ContentEncodingGzip,
+		Metadata:        o.Metadata,
+		Contents:        bytes.NewReader(raw[:len(raw)/2]),
+	})
+	require.NoError(t.T(), err)
+
+	_, err = t.readRange(t.bucket, "logs/access.log", nil)
+
+	assert.ErrorIs(t.T(), err, io.ErrUnexpectedEOF)
+}
+
+func (t *CompressionBucketTest) TestCreateObject_ChecksumMismatch() {
+	_, err := t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     "logs/access.log",
+		Contents: bytes.NewReader(t.contents),
+		CRC32C:   storageutil.CRC32C([]byte("other")),
+	})
+
+	assert.ErrorContains(t.T(), err, "CRC32C mismatch")
+	_, _, err = t.wrapped.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "logs/access.log"})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFoundErr)
+}
+
+func (t *CompressionBucketTest) TestCreateObject_MatchingChecksums() {
+	o, err := t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     "logs/access.log",
+		Contents: bytes.NewReader(t.contents),
+		CRC32C:   storageutil.CRC32C(t.contents),
+		MD5:      storageutil.MD5(t.contents),
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), o.Size)
+}
+
+func (t *CompressionBucketTest) TestCreateObjectChunkWriter() {
+	w, err := t.bucket.CreateObjectChunkWriter(t.ctx, &gcs.CreateObjectRequest{Name: "logs/access.log"}, 1<<20, nil)
+	require.NoError(t.T(), err)
+	for offset := 0; offset < len(t.contents); offset += 30000 {
+		_, err = w.Write(t.contents[offset:min(offset+30000, len(t.contents))])
+		require.NoError(t.T(), err)
+	}
+
+	o, err := t.bucket.FinalizeUpload(t.ctx, w)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), o.Size)
+	_, contents := t.rawObject("logs/access.log")
+	assert.Equal(t.T(), t.contents, contents)
+}
+
+func (t *CompressionBucketTest) TestCreateObjectChunkWriter_FlushIsUnsupported() {
+	w, err := t.bucket.CreateObjectChunkWriter(t.ctx, &gcs.CreateObjectRequest{Name: "logs/access.log"}, 1<<20, nil)
+	require.NoError(t.T(), err)
+
+	_, err = t.bucket.FlushPendingWrites(t.ctx, w)
+
+	assert.ErrorIs(t.T(), err, gcsx.ErrCompressionUnsupported)
+}
+
+func (t *CompressionBucketTest) TestAppendableObjectsAreUnsupported() {
+	_, err := t.bucket.CreateAppendableObjectWriter(t.ctx, &gcs.CreateObjectChunkWriterRequest{CreateObjectRequest: gcs.CreateObjectRequest{Name: "logs/access.log"}})
+
+	assert.ErrorIs(t.T(), err, gcsx.ErrCompressionUnsupported)
+}
+
+func (t *CompressionBucketTest) TestComposeObjects_AppendsToCompressedObject() {
+	// As the syncer appends to an object: the new contents are uploaded to a
+	// temporary object, which is composed after the existing one.
+	half := len(t.contents) / 2
+	first := t.createObject("logs/access.log", t.contents[:half])
+	t.createObject(".gcsfuse_tmp/part", t.contents[half:])
+
+	o, err := t.bucket.ComposeObjects(t.ctx, &gcs.ComposeObjectsRequest{
+		DstName:                       "logs/access.log",
+		DstGenerationPrecondition:     &first.Generation,
+		DstMetaGenerationPrecondition: &first.MetaGeneration,
+		Sources: []gcs.ComposeSource{
+			{Name: "logs/access.log", Generation: first.Generation},
+			{Name: ".gcsfuse_tmp/part"},
+		},
+		Metadata: map[string]string{"foo": "bar"},
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), o.Size)
+	assert.Equal(t.T(), "bar", o.Metadata["foo"])
+	_, contents := t.rawObject("logs/access.log")
+	assert.Equal(t.T(), t.contents, contents)
+}
+
+func (t *CompressionBucketTest) TestComposeObjects_PreconditionFailure() {
+	t.createObject("logs/access.log", t.contents)
+	var generation int64 = 0
+
+	_, err := t.bucket.ComposeObjects(t.ctx, &gcs.ComposeObjectsRequest{
+		DstName:                   "logs/access.log",
+		DstGenerationPrecondition: &generation,
+		Sources:                   []gcs.ComposeSource{{Name: "logs/access.log"}},
+	})
+
+	var preconditionErr *gcs.PreconditionError
+	assert.ErrorAs(t.T(), err, &preconditionErr)
+}
+
+func (t *CompressionBucketTest) TestCopyObject() {
+	t.createObject("logs/access.log", t.contents)
+
+	o, err := t.bucket.CopyObject(t.ctx, &gcs.CopyObjectRequest{SrcName: "logs/access.log", DstName: "archive/access.log"})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.contents)), o.Size)
+	contents, err := t.readRange(t.bucket, "archive/access.log", &gcs.ByteRange{Start: 50000, Limit: 50010})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.contents[50000:50010], contents)
+}
+
+func (t *CompressionBucketTest) TestUpdateObject_KeepsUncompressedSize() {
+	t.createObject("logs/access.log", t.contents)
+	size, value := "1", "bar"
+
+	o, err := t.bucket.UpdateObject(t.ctx, &gcs.UpdateObjectRequest{
+		Name:     "logs/access.log",
+		Metadata: map[string]*string{gcsx.UncompressedSizeMetadataKey: &size, "foo": &value},
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), strconv.Itoa(len(t.contents)), o.Metadata[gcsx.UncompressedSizeMetadataKey])
+	assert.Equal(t.T(), "bar", o.Metadata["foo"])
+	assert.Equal(t.T(), uint64(len(t.contents)), o.Size)
+}
+
+func (t *CompressionBucketTest) TestNewMultiRangeDownloader_CompressedObject() {
+	t.createObject("logs/access.log", t.contents)
+
+	_, err := t.bucket.NewMultiRangeDownloader(t.ctx, &gcs.MultiRangeDownloaderRequest{Name: "logs/access.log"})
+
+	assert.ErrorIs(t.T(), err, gcsx.ErrCompressionUnsupported)
+}
+
+func TestNewCompressionBucket_InvalidGlob(t *testing.T) {
+	_, err := gcsx.NewCompressionBucket([]string{"logs/[a"}, ".gcsfuse_tmp/", fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{}))
+
+	assert.ErrorContains(t, err, `glob "logs/[a"`)
+}
@@ -0,0 +1,337 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"strconv"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// This file holds what's shared by the buckets that store the contents of
+// objects transformed, such as encrypted or compressed, and return them as
+// they were written.
+
+// transformedObjectsCacheSize is the number of generations of objects whose
+// transformation a bucket remembers, so that reading them doesn't require a
+// stat.
+const transformedObjectsCacheSize = 100000
+
+// transformedObject describes how the contents of a generation of an object are
+// transformed.
+type transformedObject interface {
+	// contentsSize returns the size of the contents as written, or false if
+	// they're stored as is.
+	contentsSize() (uint64, bool)
+}
+
+// transformedObjects remembers the transformation of the generations of the
+// objects of a bucket.
+type transformedObjects[T transformedObject] struct {
+	// Values are *transformedObjectEntry[T], keyed by objectsKey.
+	cache *lru.Cache
+
+	// Describes the transformation of an object of the wrapped bucket.
+	describe func(ctx context.Context, o *gcs.MinObject) (T, error)
+
+	wrapped gcs.Bucket
+}
+
+type transformedObjectEntry[T transformedObject] struct {
+	object T
+}
+
+// Size counts the entries of the cache.
+func (e *transformedObjectEntry[T]) Size() uint64 {
+	return 1
+}
+
+func newTransformedObjects[T transformedObject](describe func(ctx context.Context, o *gcs.MinObject) (T, error), wrapped gcs.Bucket) *transformedObjects[T] {
+	return &transformedObjects[T]{
+		cache:    lru.NewCache(transformedObjectsCacheSize),
+		describe: describe,
+		wrapped:  wrapped,
+	}
+}
+
+func objectsKey(name string, generation int64) string {
+	return name + "#" + strconv.FormatInt(generation, 10)
+}
+
+// record remembers the transformation of the given object of the wrapped
+// bucket.
+func (t *transformedObjects[T]) record(ctx context.Context, o *gcs.MinObject) (T, error) {
+	key := objectsKey(o.Name, o.Generation)
+	if e, ok := t.cache.LookUpWithoutChangingOrder(key).(*transformedObjectEntry[T]); ok {
+		return e.object, nil
+	}
+
+	object, err := t.describe(ctx, o)
+	if err != nil {
+		return object, err
+	}
+	if _, err := t.cache.Insert(key, &transformedObjectEntry[T]{object: object}); err != nil {
+		logger.Warnf("Failed to remember the transformation of %q: %v", o.Name, err)
+	}
+	return object, nil
+}
+
+// lookUp returns the transformation of the given generation of the named
+// object, which may be noncurrent, or of its latest generation if zero.
+func (t *transformedObjects[T]) lookUp(ctx context.Context, name string, generation int64) (T, error) {
+	if generation != 0 {
+		if e, ok := t.cache.LookUp(objectsKey(name, generation)).(*transformedObjectEntry[T]); ok {
+			return e.object, nil
+		}
+	}
+
+	o, _, err := t.wrapped.StatObject(ctx, &gcs.StatObjectRequest{Name: name, Generation: generation})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return t.record(ctx, o)
+}
+
+// minObject returns the given object of the wrapped bucket, or a copy of it
+// with the size of its contents as written if they're transformed. Their
+// checksums, which are those of the stored contents, are dropped.
+func (t *transformedObjects[T]) minObject(ctx context.Context, o *gcs.MinObject) (*gcs.MinObject, error) {
+	if o == nil {
+		return nil, nil
+	}
+	transformed, err := t.record(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	size, ok := transformed.contentsSize()
+	if !ok {
+		return o, nil
+	}
+	m := *o
+	m.Size = size
+	m.CRC32C = nil
+	return &m, nil
+}
+
+// object is minObject for full objects.
+func (t *transformedObjects[T]) object(ctx context.Context, o *gcs.Object) (*gcs.Object, error) {
+	if o == nil {
+		return nil, nil
+	}
+	transformed, err := t.record(ctx, &gcs.MinObject{
+		Name:            o.Name,
+		Generation:      o.Generation,
+		Metadata:        o.Metadata,
+		Size:            o.Size,
+		ContentEncoding: o.ContentEncoding,
+	})
+	if err != nil {
+		return nil, err
+	}
+	size, ok := transformed.contentsSize()
+	if !ok {
+		return o, nil
+	}
+	m := *o
+	m.Size = size
+	m.CRC32C = nil
+	m.MD5 = nil
+	return &m, nil
+}
+
+// stat is minObject for the results of StatObject.
+func (t *transformedObjects[T]) stat(ctx context.Context, m *gcs.MinObject, e *gcs.ExtendedObjectAttributes) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	transformed, err := t.minObject(ctx, m)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e != nil && transformed != m {
+		mE := *e
+		mE.MD5 = nil
+		e = &mE
+	}
+	return transformed, e, nil
+}
+
+// listing is minObject for the objects of a listing.
+func (t *transformedObjects[T]) listing(ctx context.Context, l *gcs.Listing) (*gcs.Listing, error) {
+	m := *l
+	m.MinObjects = make([]*gcs.MinObject, len(l.MinObjects))
+	for i, o := range l.MinObjects {
+		var err error
+		if m.MinObjects[i], err = t.minObject(ctx, o); err != nil {
+			return nil, err
+		}
+	}
+	return &m, nil
+}
+
+// clampRange returns the bounds of the given range, the whole contents if nil,
+// clamped to contents of the given size, like the wrapped buckets do.
+func clampRange(r *gcs.ByteRange, size uint64) (start, limit uint64) {
+	if r == nil {
+		return 0, size
+	}
+	start = min(r.Start, size)
+	return start, max(min(r.Limit, size), start)
+}
+
+// transformedRequest returns a copy of the given request for transformed
+// contents, without the given metadata key. The checksums of the request,
+// which are those of the contents as written, are dropped.
+func transformedRequest(req *gcs.CreateObjectRequest, key string) *gcs.CreateObjectRequest {
+	mReq := *req
+	mReq.Metadata = maps.Clone(req.Metadata)
+	delete(mReq.Metadata, key)
+	mReq.CRC32C = nil
+	mReq.MD5 = nil
+	return &mReq
+}
+
+// withoutMetadataKey returns the given request, or a copy of it without the
+// given metadata key if it has it, so that the key can't be changed or
+// removed.
+func withoutMetadataKey(req *gcs.UpdateObjectRequest, key string) *gcs.UpdateObjectRequest {
+	if _, ok := req.Metadata[key]; !ok {
+		return req
+	}
+	mReq := *req
+	mReq.Metadata = maps.Clone(req.Metadata)
+	delete(mReq.Metadata, key)
+	return &mReq
+}
+
+// copyContents copies the contents of the given request to w, and returns
+// their size. It fails if they don't match the checksums of the request, before
+// the transformation writing to w is completed.
+func copyContents(w io.Writer, req *gcs.CreateObjectRequest) (int64, error) {
+	writers := []io.Writer{w}
+	crc := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	if req.CRC32C != nil {
+		writers = append(writers, crc)
+	}
+	md := md5.New()
+	if req.MD5 != nil {
+		writers = append(writers, md)
+	}
+
+	size, err := io.Copy(io.MultiWriter(writers...), req.Contents)
+	if err != nil {
+		return 0, err
+	}
+	if req.CRC32C != nil && crc.Sum32() != *req.CRC32C {
+		return 0, fmt.Errorf("CRC32C mismatch: got %d, want %d", crc.Sum32(), *req.CRC32C)
+	}
+	if req.MD5 != nil && !bytes.Equal(md.Sum(nil), req.MD5[:]) {
+		return 0, fmt.Errorf("MD5 mismatch: got %x, want %x", md.Sum(nil), req.MD5[:])
+	}
+	return size, nil
+}
+
+// createTransformedObject creates an object of the wrapped bucket with the given
+// request, whose contents are written by transform as they're uploaded.
+func createTransformedObject(ctx context.Context, wrapped gcs.Bucket, req *gcs.CreateObjectRequest, transform func(w io.Writer) error) (*gcs.Object, error) {
+	pr, pw := io.Pipe()
+	mReq := *req
+	mReq.Contents = pr
+	transformed := make(chan struct{})
+	go func() {
+		defer close(transformed)
+		pw.CloseWithError(transform(pw))
+	}()
+
+	o, err := wrapped.CreateObject(ctx, &mReq)
+	// Stop the transformation if the wrapped bucket returned without reading
+	// all of the contents, so that they are no longer read once this returns.
+	pr.Close()
+	<-transformed
+	return o, err
+}
+
+// composeByReading creates the composite object of the given request with
+// the contents of its sources, read from the given bucket, since the wrapped
+// bucket can't concatenate transformed contents.
+func composeByReading(ctx context.Context, b gcs.Bucket, req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	// Open all the sources before creating the object, so that a missing one
+	// fails the composition without reading the others.
+	contents := &composedContents{}
+	defer contents.Close()
+	for _, source := range req.Sources {
+		r, err := b.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{Name: source.Name, Generation: source.Generation})
+		if err != nil {
+			return nil, fmt.Errorf("reading source %q: %w", source.Name, err)
+		}
+		contents.sources = append(contents.sources, r)
+	}
+
+	return b.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:                       req.DstName,
+		ContentType:                req.ContentType,
+		ContentLanguage:            req.ContentLanguage,
+		ContentEncoding:            req.ContentEncoding,
+		CacheControl:               req.CacheControl,
+		Metadata:                   req.Metadata,
+		ContentDisposition:         req.ContentDisposition,
+		CustomTime:                 req.CustomTime,
+		EventBasedHold:             req.EventBasedHold,
+		StorageClass:               req.StorageClass,
+		Acl:                        req.Acl,
+		Contents:                   contents,
+		GenerationPrecondition:     req.DstGenerationPrecondition,
+		MetaGenerationPrecondition: req.DstMetaGenerationPrecondition,
+	})
+}
+
+// composedContents reads the contents of the sources of a composition one
+// after the other.
+type composedContents struct {
+	sources []gcs.StorageReader
+}
+
+func (c *composedContents) Read(p []byte) (int, error) {
+	for len(c.sources) > 0 {
+		n, err := c.sources[0].Read(p)
+		if err == io.EOF {
+			c.sources[0].Close()
+			c.sources = c.sources[1:]
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+	return 0, io.EOF
+}
+
+func (c *composedContents) Close() error {
+	var err error
+	for _, r := range c.sources {
+		err = errors.Join(err, r.Close())
+	}
+	c.sources = nil
+	return err
+}
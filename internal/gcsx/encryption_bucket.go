@@ -0,0 +1,796 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	storagev2 "cloud.google.com/go/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// EncryptionKeyIDMetadataKey is the metadata key of the ID of the key the
+// contents of an object written by a bucket created by NewEncryptionBucket are
+// encrypted with.
+const EncryptionKeyIDMetadataKey = "gcsfuse-encryption-key-id"
+
+// The encrypted contents of an object are a header, made of encryptionMagic and
+// a random salt, followed by the plaintext in chunks of encryptionChunkSize
+// bytes, each sealed with AES-256-GCM under a key derived from the salt and the
+// key of the object. The header is authenticated along with each chunk, whose
+// nonce is its index. The nonce of the last chunk, which is the only one that
+// may be shorter and is empty for an empty object, is marked so that
+// truncations at chunk boundaries are detected.
+const (
+	encryptionMagic           = "GFE\x01"
+	encryptionHeaderSize      = 32
+	encryptionChunkSize       = 64 << 10
+	encryptionTagSize         = 16
+	encryptionSealedChunkSize = encryptionChunkSize + encryptionTagSize
+
+	// encryptionKeyInfo binds the keys derived for the objects to this format.
+	encryptionKeyInfo = "gcsfuse client-side encryption v1"
+)
+
+// ErrEncryptionUnsupported is returned by the operations on appendable objects
+// of a bucket created by NewEncryptionBucket.
+var ErrEncryptionUnsupported = errors.New("appendable objects aren't supported with client-side encryption")
+
+// encryptedSize returns the size of the encrypted contents of an object of the
+// given size.
+func encryptedSize(size uint64) uint64 {
+	chunks := max(1, (size+encryptionChunkSize-1)/encryptionChunkSize)
+	return encryptionHeaderSize + size + chunks*encryptionTagSize
+}
+
+// decryptedSize returns the size of the plaintext of an object whose encrypted
+// contents have the given size, or false if no plaintext encrypts to that size.
+func decryptedSize(size uint64) (uint64, bool) {
+	if size < encryptionHeaderSize+encryptionTagSize {
+		return 0, false
+	}
+	chunks := (size - encryptionHeaderSize + encryptionSealedChunkSize - 1) / encryptionSealedChunkSize
+	plaintextSize := size - encryptionHeaderSize - chunks*encryptionTagSize
+	return plaintextSize, encryptedSize(plaintextSize) == size
+}
+
+// decryptedOffset returns the number of bytes of plaintext sealed in the given
+// number of bytes of encrypted contents.
+func decryptedOffset(offset int64) int64 {
+	if offset <= encryptionHeaderSize {
+		return 0
+	}
+	offset -= encryptionHeaderSize
+	return offset/encryptionSealedChunkSize*encryptionChunkSize + min(offset%encryptionSealedChunkSize, encryptionChunkSize)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Chunks
+////////////////////////////////////////////////////////////////////////
+
+// objectCipher seals and opens the chunks of the encrypted contents of an
+// object.
+type objectCipher struct {
+	aead   cipher.AEAD
+	header []byte
+}
+
+// newEncryptionHeader returns a header for new encrypted contents.
+func newEncryptionHeader() []byte {
+	header := make([]byte, encryptionHeaderSize)
+	copy(header, encryptionMagic)
+	rand.Read(header[len(encryptionMagic):])
+	return header
+}
+
+func newObjectCipher(key []byte, header []byte) (*objectCipher, error) {
+	if len(header) != encryptionHeaderSize || string(header[:len(encryptionMagic)]) != encryptionMagic {
+		return nil, errors.New("invalid header of encrypted contents")
+	}
+	objectKey, err := hkdf.Key(sha256.New, key, header[len(encryptionMagic):], encryptionKeyInfo, encryptionKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("hkdf.Key: %w", err)
+	}
+	block, err := aes.NewCipher(objectKey)
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM: %w", err)
+	}
+	return &objectCipher{aead: aead, header: header}, nil
+}
+
+func (c *objectCipher) nonce(index uint64, last bool) []byte {
+	nonce := make([]byte, c.aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce, index)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// seal appends the given chunk of plaintext, sealed, to dst.
+func (c *objectCipher) seal(dst []byte, chunk []byte, index uint64, last bool) []byte {
+	return c.aead.Seal(dst, c.nonce(index, last), chunk, c.header)
+}
+
+// open appends the plaintext of the given sealed chunk to dst, which may be
+// sealed[:0].
+func (c *objectCipher) open(dst []byte, sealed []byte, index uint64, last bool) ([]byte, error) {
+	chunk, err := c.aead.Open(dst, c.nonce(index, last), sealed, c.header)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting chunk %d: %w", index, err)
+	}
+	return chunk, nil
+}
+
+// chunkSealer writes the encrypted contents of the plaintext written to it to
+// the wrapped writer.
+type chunkSealer struct {
+	wrapped io.Writer
+	cipher  *objectCipher
+
+	// The index of the next chunk to seal.
+	index uint64
+
+	// The plaintext of the next chunk. A full chunk is sealed once more
+	// plaintext is written, as it can only then be known not to be the last.
+	chunk  []byte
+	sealed []byte
+
+	closed bool
+
+	// The first error of the wrapped writer, returned by all later calls.
+	err error
+}
+
+func newChunkSealer(wrapped io.Writer, cipher *objectCipher) *chunkSealer {
+	return &chunkSealer{
+		wrapped: wrapped,
+		cipher:  cipher,
+		chunk:   make([]byte, 0, encryptionChunkSize),
+	}
+}
+
+func (s *chunkSealer) Write(p []byte) (n int, err error) {
+	if s.closed {
+		return 0, errors.New("write after close")
+	}
+	for len(p) > 0 && s.err == nil {
+		if len(s.chunk) == encryptionChunkSize {
+			s.sealChunk(false)
+			continue
+		}
+		copied := copy(s.chunk[len(s.chunk):encryptionChunkSize], p)
+		s.chunk = s.chunk[:len(s.chunk)+copied]
+		p = p[copied:]
+		n += copied
+	}
+	return n, s.err
+}
+
+func (s *chunkSealer) sealChunk(last bool) {
+	if s.index == 0 {
+		if _, s.err = s.wrapped.Write(s.cipher.header); s.err != nil {
+			return
+		}
+	}
+	s.sealed = s.cipher.seal(s.sealed[:0], s.chunk, s.index, last)
+	_, s.err = s.wrapped.Write(s.sealed)
+	s.chunk = s.chunk[:0]
+	s.index++
+}
+
+// Close seals the last chunk. It doesn't close the wrapped writer.
+func (s *chunkSealer) Close() error {
+	if !s.closed && s.err == nil {
+		s.sealChunk(true)
+		s.closed = true
+	}
+	return s.err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Objects
+////////////////////////////////////////////////////////////////////////
+
+// encryptedObject is what an encryption bucket remembers of a generation of an
+// object.
+type encryptedObject struct {
+	name       string
+	generation int64
+
+	// The ID of the key the contents are encrypted with, empty if they aren't.
+	keyID string
+
+	// The size of the encrypted contents.
+	encryptedSize uint64
+
+	// Set once the header of the contents is read.
+	cipher atomic.Pointer[objectCipher]
+}
+
+// describeEncryption returns the encryption of the given object of the wrapped
+// bucket.
+func describeEncryption(_ context.Context, o *gcs.MinObject) (*encryptedObject, error) {
+	e := &encryptedObject{
+		name:          o.Name,
+		generation:    o.Generation,
+		keyID:         o.Metadata[EncryptionKeyIDMetadataKey],
+		encryptedSize: o.Size,
+	}
+	if e.keyID != "" {
+		if _, err := e.decryptedSize(); err != nil {
+			logger.Warnf("%v", err)
+		}
+	}
+	return e, nil
+}
+
+func (o *encryptedObject) contentsSize() (uint64, bool) {
+	if o.keyID == "" {
+		return 0, false
+	}
+	size, _ := o.decryptedSize()
+	return size, true
+}
+
+// lastChunk returns the index of the last chunk of the encrypted contents.
+func (o *encryptedObject) lastChunk() uint64 {
+	return (o.encryptedSize - encryptionHeaderSize - 1) / encryptionSealedChunkSize
+}
+
+// sealedChunkSize returns the size of the sealed chunk of the given index.
+func (o *encryptedObject) sealedChunkSize(index uint64) uint64 {
+	return min(encryptionSealedChunkSize, o.encryptedSize-encryptionHeaderSize-index*encryptionSealedChunkSize)
+}
+
+// decryptedSize returns the size of the plaintext, or an error if the size of
+// the encrypted contents is invalid.
+func (o *encryptedObject) decryptedSize() (uint64, error) {
+	size, ok := decryptedSize(o.encryptedSize)
+	if !ok {
+		return 0, fmt.Errorf("encrypted object %q has an invalid size of %d bytes", o.name, o.encryptedSize)
+	}
+	return size, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Bucket
+////////////////////////////////////////////////////////////////////////
+
+// NewEncryptionBucket creates a wrapper bucket that encrypts the contents of
+// the objects it creates or composes with the primary key of the given keys,
+// recording its ID in their metadata under EncryptionKeyIDMetadataKey, and
+// decrypts the contents of the objects it reads with the key of the recorded
+// ID. Objects without a key ID are read as is.
+//
+// The contents are encrypted in chunks that are authenticated and decrypted
+// independently, so that ranges of them can be read. The sizes of the objects
+// it returns are those of their plaintext, and their checksums are dropped.
+//
+// Objects can't be composed server-side: the sources are read and their
+// contents encrypted anew as the composite object. Appendable objects aren't
+// supported.
+func NewEncryptionBucket(keys *EncryptionKeys, wrapped gcs.Bucket) gcs.Bucket {
+	return &encryptionBucket{
+		keys:    keys,
+		objects: newTransformedObjects(describeEncryption, wrapped),
+		wrapped: wrapped,
+	}
+}
+
+type encryptionBucket struct {
+	keys *EncryptionKeys
+
+	// The encryption of the generations of the objects returned so far.
+	objects *transformedObjects[*encryptedObject]
+
+	wrapped gcs.Bucket
+}
+
+// objectCipher returns the cipher of the given encrypted object, reading its
+// header if the header isn't given.
+func (b *encryptionBucket) objectCipher(ctx context.Context, o *encryptedObject, header []byte) (*objectCipher, error) {
+	if c := o.cipher.Load(); c != nil {
+		return c, nil
+	}
+
+	key, ok := b.keys.Keys[o.keyID]
+	if !ok {
+		return nil, fmt.Errorf("object %q is encrypted with the unknown key %q", o.name, o.keyID)
+	}
+	if header == nil {
+		r, err := b.wrapped.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{
+			Name:       o.name,
+			Generation: o.generation,
+			Range:      &gcs.ByteRange{Start: 0, Limit: encryptionHeaderSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		header = make([]byte, encryptionHeaderSize)
+		if _, err = io.ReadFull(r, header); err != nil {
+			return nil, fmt.Errorf("reading the header of %q: %w", o.name, err)
+		}
+	}
+
+	c, err := newObjectCipher(key, header)
+	if err != nil {
+		return nil, fmt.Errorf("object %q: %w", o.name, err)
+	}
+	o.cipher.Store(c)
+	return c, nil
+}
+
+// encryptedRequest returns a copy of the given request that records the ID of
+// the primary key in the metadata of the object, along with the cipher to
+// encrypt its contents with. The checksums of the request, which are those of
+// the plaintext, are dropped.
+func (b *encryptionBucket) encryptedRequest(req *gcs.CreateObjectRequest) (*gcs.CreateObjectRequest, *objectCipher, error) {
+	c, err := newObjectCipher(b.keys.Keys[b.keys.PrimaryID], newEncryptionHeader())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mReq := transformedRequest(req, EncryptionKeyIDMetadataKey)
+	if mReq.Metadata == nil {
+		mReq.Metadata = make(map[string]string)
+	}
+	mReq.Metadata[EncryptionKeyIDMetadataKey] = b.keys.PrimaryID
+	return mReq, c, nil
+}
+
+// encryptContents writes the encrypted contents of the given request to w,
+// failing before the last chunk if they don't match the checksums of the
+// request.
+func encryptContents(w io.Writer, c *objectCipher, req *gcs.CreateObjectRequest) error {
+	sealer := newChunkSealer(w, c)
+	if _, err := copyContents(sealer, req); err != nil {
+		return err
+	}
+	return sealer.Close()
+}
+
+func (b *encryptionBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+func (b *encryptionBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+func (b *encryptionBucket) GCSName(object *gcs.MinObject) string {
+	return b.wrapped.GCSName(object)
+}
+
+func (b *encryptionBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	o, err := b.objects.lookUp(ctx, req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	if o.keyID == "" {
+		return b.wrapped.NewReaderWithReadHandle(ctx, req)
+	}
+	size, err := o.decryptedSize()
+	if err != nil {
+		return nil, err
+	}
+
+	start, limit := clampRange(req.Range, size)
+
+	mReq := *req
+	mReq.Generation = o.generation
+	if start == limit {
+		mReq.Range = &gcs.ByteRange{Start: o.encryptedSize, Limit: o.encryptedSize}
+		rd, err := b.wrapped.NewReaderWithReadHandle(ctx, &mReq)
+		if err != nil {
+			return nil, err
+		}
+		return &decryptingReader{wrapped: rd}, nil
+	}
+
+	// Read the chunks covering the range.
+	firstChunk := start / encryptionChunkSize
+	lastChunk := (limit - 1) / encryptionChunkSize
+	c := o.cipher.Load()
+	if c == nil && firstChunk > 0 {
+		if c, err = b.objectCipher(ctx, o, nil); err != nil {
+			return nil, err
+		}
+	}
+	mReq.Range = &gcs.ByteRange{
+		Start: encryptionHeaderSize + firstChunk*encryptionSealedChunkSize,
+		Limit: min(encryptionHeaderSize+(lastChunk+1)*encryptionSealedChunkSize, o.encryptedSize),
+	}
+	// Read the header along with the first chunk if it's still needed.
+	if c == nil {
+		mReq.Range.Start = 0
+	}
+	rd, err := b.wrapped.NewReaderWithReadHandle(ctx, &mReq)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		header := make([]byte, encryptionHeaderSize)
+		if _, err = io.ReadFull(rd, header); err == nil {
+			c, err = b.objectCipher(ctx, o, header)
+		}
+		if err != nil {
+			rd.Close()
+			return nil, fmt.Errorf("reading the header of %q: %w", o.name, err)
+		}
+	}
+
+	return &decryptingReader{
+		wrapped:   rd,
+		object:    o,
+		cipher:    c,
+		index:     firstChunk,
+		skip:      start - firstChunk*encryptionChunkSize,
+		remaining: limit - start,
+		sealed:    make([]byte, encryptionSealedChunkSize),
+	}, nil
+}
+
+func (b *encryptionBucket) NewMultiRangeDownloader(
+	ctx context.Context,
+	req *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	o, err := b.objects.lookUp(ctx, req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	if o.keyID == "" {
+		return b.wrapped.NewMultiRangeDownloader(ctx, req)
+	}
+	size, err := o.decryptedSize()
+	if err != nil {
+		return nil, err
+	}
+	c, err := b.objectCipher(ctx, o, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	mReq := *req
+	mReq.Generation = o.generation
+	mrd, err := b.wrapped.NewMultiRangeDownloader(ctx, &mReq)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingMultiRangeDownloader{
+		MultiRangeDownloader: mrd,
+		object:               o,
+		cipher:               c,
+		size:                 size,
+	}, nil
+}
+
+func (b *encryptionBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	mReq, c, err := b.encryptedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := createTransformedObject(ctx, b.wrapped, mReq, func(w io.Writer) error {
+		return encryptContents(w, c, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.object(ctx, o)
+}
+
+func (b *encryptionBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	mReq, c, err := b.encryptedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var mCallBack func(int64)
+	if callBack != nil {
+		mCallBack = func(bytesUploadedSoFar int64) {
+			callBack(decryptedOffset(bytesUploadedSoFar))
+		}
+	}
+	w, err := b.wrapped.CreateObjectChunkWriter(ctx, mReq, chunkSize, mCallBack)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{Writer: w, sealer: newChunkSealer(w, c)}, nil
+}
+
+func (b *encryptionBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	return nil, ErrEncryptionUnsupported
+}
+
+func (b *encryptionBucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	ew, ok := w.(*encryptingWriter)
+	if !ok {
+		return nil, fmt.Errorf("FinalizeUpload: unexpected writer of type %T", w)
+	}
+	if err := ew.sealer.Close(); err != nil {
+		return nil, err
+	}
+	o, err := b.wrapped.FinalizeUpload(ctx, ew.Writer)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.minObject(ctx, o)
+}
+
+func (b *encryptionBucket) FlushPendingWrites(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	return nil, ErrEncryptionUnsupported
+}
+
+func (b *encryptionBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	o, err := b.wrapped.CopyObject(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.object(ctx, o)
+}
+
+func (b *encryptionBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	return composeByReading(ctx, b, req)
+}
+
+func (b *encryptionBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	m, e, err := b.wrapped.StatObject(ctx, req)
+	if err != nil || m == nil {
+		return m, e, err
+	}
+	return b.objects.stat(ctx, m, e)
+}
+
+func (b *encryptionBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	listing, err := b.wrapped.ListObjects(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.listing(ctx, listing)
+}
+
+func (b *encryptionBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	o, err := b.wrapped.UpdateObject(ctx, withoutMetadataKey(req, EncryptionKeyIDMetadataKey))
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.object(ctx, o)
+}
+
+func (b *encryptionBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	return b.wrapped.DeleteObject(ctx, req)
+}
+
+func (b *encryptionBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	o, err := b.wrapped.MoveObject(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.objects.object(ctx, o)
+}
+
+func (b *encryptionBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	return b.wrapped.DeleteFolder(ctx, folderName)
+}
+
+func (b *encryptionBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	return b.wrapped.GetFolder(ctx, req)
+}
+
+func (b *encryptionBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return b.wrapped.RenameFolder(ctx, folderName, destinationFolderId)
+}
+
+func (b *encryptionBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return b.wrapped.CreateFolder(ctx, folderName)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Readers and writers
+////////////////////////////////////////////////////////////////////////
+
+// decryptingReader returns the plaintext of a range of an encrypted object from
+// the wrapped reader of the chunks covering it.
+type decryptingReader struct {
+	wrapped gcs.StorageReader
+	object  *encryptedObject
+	cipher  *objectCipher
+
+	// The index of the next chunk.
+	index uint64
+
+	// The number of bytes of plaintext to skip at the start of the next chunk,
+	// and to return overall.
+	skip      uint64
+	remaining uint64
+
+	sealed []byte
+
+	// Plaintext not returned yet, within sealed.
+	plaintext []byte
+
+	err error
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plaintext) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.remaining == 0 {
+			return 0, io.EOF
+		}
+		r.err = r.readChunk()
+	}
+	n := copy(p, r.plaintext)
+	r.plaintext = r.plaintext[n:]
+	return n, nil
+}
+
+func (r *decryptingReader) readChunk() error {
+	if r.index > r.object.lastChunk() {
+		return io.ErrUnexpectedEOF
+	}
+	sealed := r.sealed[:r.object.sealedChunkSize(r.index)]
+	if _, err := io.ReadFull(r.wrapped, sealed); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("reading chunk %d of %q: %w", r.index, r.object.name, err)
+	}
+	chunk, err := r.cipher.open(sealed[:0], sealed, r.index, r.index == r.object.lastChunk())
+	if err != nil {
+		return fmt.Errorf("object %q: %w", r.object.name, err)
+	}
+
+	chunk = chunk[min(r.skip, uint64(len(chunk))):]
+	chunk = chunk[:min(r.remaining, uint64(len(chunk)))]
+	r.skip = 0
+	r.remaining -= uint64(len(chunk))
+	r.plaintext = chunk
+	r.index++
+	return nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.wrapped.Close()
+}
+
+func (r *decryptingReader) ReadHandle() storagev2.ReadHandle {
+	return r.wrapped.ReadHandle()
+}
+
+// decryptingMultiRangeDownloader downloads the chunks covering the ranges added
+// to it with the wrapped downloader, and writes their plaintext to the outputs.
+type decryptingMultiRangeDownloader struct {
+	gcs.MultiRangeDownloader
+	object *encryptedObject
+	cipher *objectCipher
+
+	// The size of the plaintext.
+	size uint64
+}
+
+func (d *decryptingMultiRangeDownloader) Add(output io.Writer, offset, length int64, callback func(int64, int64, error)) {
+	if callback == nil {
+		callback = func(int64, int64, error) {}
+	}
+	if offset < 0 || length < 0 || uint64(offset) > d.size {
+		callback(offset, 0, fmt.Errorf("invalid range of %d bytes at offset %d of %q of %d bytes", length, offset, d.object.name, d.size))
+		return
+	}
+	length = min(length, int64(d.size)-offset)
+	if length == 0 {
+		callback(offset, 0, nil)
+		return
+	}
+
+	firstChunk := uint64(offset) / encryptionChunkSize
+	lastChunk := uint64(offset+length-1) / encryptionChunkSize
+	start := encryptionHeaderSize + firstChunk*encryptionSealedChunkSize
+	limit := min(encryptionHeaderSize+(lastChunk+1)*encryptionSealedChunkSize, d.object.encryptedSize)
+	sealed := bytes.NewBuffer(make([]byte, 0, limit-start))
+	d.MultiRangeDownloader.Add(sealed, int64(start), int64(limit-start), func(_ int64, n int64, err error) {
+		if err == nil && uint64(n) != limit-start {
+			err = fmt.Errorf("downloaded %d bytes of chunks %d to %d of %q, want %d", n, firstChunk, lastChunk, d.object.name, limit-start)
+		}
+		var written int64
+		if err == nil {
+			written, err = d.decrypt(output, sealed.Bytes(), firstChunk, uint64(offset)-firstChunk*encryptionChunkSize, uint64(length))
+		}
+		callback(offset, written, err)
+	})
+}
+
+// decrypt writes the plaintext of the given chunks, starting at the given
+// index, to output, skipping and limiting it to the given number of bytes.
+func (d *decryptingMultiRangeDownloader) decrypt(output io.Writer, sealed []byte, index, skip, length uint64) (int64, error) {
+	var written int64
+	for ; len(sealed) > 0; index++ {
+		size := d.object.sealedChunkSize(index)
+		chunk, err := d.cipher.open(sealed[:0], sealed[:size], index, index == d.object.lastChunk())
+		if err != nil {
+			return written, fmt.Errorf("object %q: %w", d.object.name, err)
+		}
+		sealed = sealed[size:]
+
+		chunk = chunk[min(skip, uint64(len(chunk))):]
+		chunk = chunk[:min(length, uint64(len(chunk)))]
+		skip = 0
+		n, err := output.Write(chunk)
+		written += int64(n)
+		length -= uint64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// encryptingWriter writes the encrypted contents of the plaintext written to it
+// to the wrapped writer.
+type encryptingWriter struct {
+	gcs.Writer
+	sealer *chunkSealer
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	return w.sealer.Write(p)
+}
+
+func (w *encryptingWriter) Close() error {
+	if err := w.sealer.Close(); err != nil {
+		return err
+	}
+	return w.Writer.Close()
+}
+
+func (w *encryptingWriter) Flush() (int64, error) {
+	offset, err := w.Writer.Flush()
+	return decryptedOffset(offset), err
+}
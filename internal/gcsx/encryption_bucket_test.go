@@ -0,0 +1,475 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// encryptionTestObjectSize spans several chunks of encrypted contents, the last
+// one partial.
+const encryptionTestObjectSize = 200000
+
+type EncryptionBucketTest struct {
+	suite.Suite
+	ctx      context.Context
+	keys     *gcsx.EncryptionKeys
+	wrapped  gcs.Bucket
+	bucket   gcs.Bucket
+	contents []byte
+}
+
+func TestEncryptionBucket(t *testing.T) {
+	suite.Run(t, new(EncryptionBucketTest))
+}
+
+func newEncryptionKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+func (t *EncryptionBucketTest) SetupTest() {
+	t.ctx = context.Background()
+	t.keys = &gcsx.EncryptionKeys{
+		PrimaryID: "k1",
+		Keys:      map[string][]byte{"k1": newEncryptionKey()},
+	}
+	t.wrapped = fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	t.bucket = gcsx.NewEncryptionBucket(t.keys, t.wrapped)
+	t.contents = make([]byte, encryptionTestObjectSize)
+	rand.Read(t.contents)
+}
+
+func (t *EncryptionBucketTest) createObject(name string, contents []byte) *gcs.Object {
+	o, err := storageutil.CreateObject(t.ctx, t.bucket, name, contents)
+	require.NoError(t.T(), err)
+	return o
+}
+
+func (t *EncryptionBucketTest) readRange(bucket gcs.Bucket, name string, byteRange *gcs.ByteRange) ([]byte, error) {
+	r, err := bucket.NewReaderWithReadHandle(t.ctx, &gcs.ReadObjectRequest{Name: name, Range: byteRange})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// overwriteRaw replaces the encrypted contents of the named object in the
+// wrapped bucket, keeping its metadata.
+func (t *EncryptionBucketTest) overwriteRaw(name string, modify func([]byte) []byte) {
+	o, _, err := t.wrapped.StatObject(t.ctx, &gcs.StatObjectRequest{Name: name})
+	require.NoError(t.T(), err)
+	raw, err := storageutil.ReadObject(t.ctx, t.wrapped, name)
+	require.NoError(t.T(), err)
+	_, err = t.wrapped.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     name,
+		Metadata: o.Metadata,
+		Contents: bytes.NewReader(modify(raw)),
+	})
+	require.NoError(t.T(), err)
+}
+
+func (t *EncryptionBucketTest) TestCreateObject_EncryptsContents() {
+	o := t.createObject("foo", t.contents)
+
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), o.Size)
+	assert.Nil(t.T(), o.CRC32C)
+	assert.Nil(t.T(), o.MD5)
+	raw, err := storageutil.ReadObject(t.ctx, t.wrapped, "foo")
+	require.NoError(t.T(), err)
+	assert.Greater(t.T(), len(raw), encryptionTestObjectSize)
+	assert.NotContains(t.T(), string(raw), string(t.contents[:64]))
+	rawObject, _, err := t.wrapped.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "foo"})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "k1", rawObject.Metadata[gcsx.EncryptionKeyIDMetadataKey])
+}
+
+func (t *EncryptionBucketTest) TestStatAndListObjects_ReturnPlaintextSizes() {
+	t.createObject("foo", t.contents)
+	t.createObject("empty", nil)
+
+	m, e, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "foo", ForceFetchFromGcs: true, ReturnExtendedObjectAttributes: true})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), m.Size)
+	assert.Nil(t.T(), m.CRC32C)
+	assert.Nil(t.T(), e.MD5)
+	listing, err := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{})
+	require.NoError(t.T(), err)
+	require.Len(t.T(), listing.MinObjects, 2)
+	assert.Equal(t.T(), "empty", listing.MinObjects[0].Name)
+	assert.Equal(t.T(), uint64(0), listing.MinObjects[0].Size)
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), listing.MinObjects[1].Size)
+}
+
+func (t *EncryptionBucketTest) TestNoncurrentGeneration() {
+	t.wrapped = fake.NewFakeBucketWithVersioning(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	t.bucket = gcsx.NewEncryptionBucket(t.keys, t.wrapped)
+	first := t.createObject("foo", t.contents)
+	t.createObject("foo", []byte("taco"))
+	// The transformation of the noncurrent generation isn't remembered.
+	t.bucket = gcsx.NewEncryptionBucket(t.keys, t.wrapped)
+
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "foo", Generation: first.Generation})
+	require.NoError(t.T(), err)
+	r, err := t.bucket.NewReaderWithReadHandle(t.ctx, &gcs.ReadObjectRequest{Name: "foo", Generation: first.Generation})
+	require.NoError(t.T(), err)
+	defer r.Close()
+	contents, err := io.ReadAll(r)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), m.Size)
+	assert.Equal(t.T(), t.contents, contents)
+}
+
+func (t *EncryptionBucketTest) TestNewReader_Ranges() {
+	t.createObject("foo", t.contents)
+	testCases := []struct {
+		name         string
+		byteRange    *gcs.ByteRange
+		start, limit int
+	}{
+		{"whole", nil, 0, encryptionTestObjectSize},
+		{"within_a_chunk", &gcs.ByteRange{Start: 10, Limit: 100}, 10, 100},
+		{"across_chunks", &gcs.ByteRange{Start: 65530, Limit: 131080}, 65530, 131080},
+		{"chunk_boundaries", &gcs.ByteRange{Start: 65536, Limit: 131072}, 65536, 131072},
+		{"last_chunk", &gcs.ByteRange{Start: 196608, Limit: encryptionTestObjectSize}, 196608, encryptionTestObjectSize},
+		{"past_the_end", &gcs.ByteRange{Start: 199990, Limit: 300000}, 199990, encryptionTestObjectSize},
+		{"after_the_end", &gcs.ByteRange{Start: 300000, Limit: 300010}, 0, 0},
+		{"empty", &gcs.ByteRange{Start: 100, Limit: 100}, 0, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func() {
+			// A new bucket doesn't know the header of the object yet.
+			for _, bucket := range []gcs.Bucket{t.bucket, gcsx.NewEncryptionBucket(t.keys, t.wrapped)} {
+				contents, err := t.readRange(bucket, "foo", tc.byteRange)
+
+				require.NoError(t.T(), err)
+				assert.Equal(t.T(), t.contents[tc.start:tc.limit], contents)
+			}
+		})
+	}
+}
+
+func (t *EncryptionBucketTest) TestNewReader_EmptyObject() {
+	t.createObject("foo", nil)
+
+	contents, err := t.readRange(gcsx.NewEncryptionBucket(t.keys, t.wrapped), "foo", nil)
+
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), contents)
+}
+
+func (t *EncryptionBucketTest) TestNewReader_PlaintextObject() {
+	_, err := storageutil.CreateObject(t.ctx, t.wrapped, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	contents, err := t.readRange(t.bucket, "foo", &gcs.ByteRange{Start: 1, Limit: 3})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "ac", string(contents))
+}
+
+func (t *EncryptionBucketTest) TestNewReader_UnknownKey() {
+	t.createObject("foo", t.contents)
+	otherKeys := &gcsx.EncryptionKeys{PrimaryID: "k2", Keys: map[string][]byte{"k2": newEncryptionKey()}}
+
+	_, err := t.readRange(gcsx.NewEncryptionBucket(otherKeys, t.wrapped), "foo", nil)
+
+	assert.ErrorContains(t.T(), err, `unknown key "k1"`)
+}
+
+func (t *EncryptionBucketTest) TestNewReader_WrongKey() {
+	t.createObject("foo", t.contents)
+	otherKeys := &gcsx.EncryptionKeys{PrimaryID: "k1", Keys: map[string][]byte{"k1": newEncryptionKey()}}
+
+	_, err := t.readRange(gcsx.NewEncryptionBucket(otherKeys, t.wrapped), "foo", nil)
+
+	assert.ErrorContains(t.T(), err, "message authentication failed")
+}
+
+func (t *EncryptionBucketTest) TestNewReader_RotatedKey() {
+	t.createObject("foo", t.contents)
+	rotatedKeys := &gcsx.EncryptionKeys{
+		PrimaryID: "k2",
+		Keys:      map[string][]byte{"k1": t.keys.Keys["k1"], "k2": newEncryptionKey()},
+	}
+	rotated := gcsx.NewEncryptionBucket(rotatedKeys, t.wrapped)
+	_, err := storageutil.CreateObject(t.ctx, rotated, "bar", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	foo, err := t.readRange(rotated, "foo", nil)
+	require.NoError(t.T(), err)
+	bar, err := t.readRange(rotated, "bar", nil)
+	require.NoError(t.T(), err)
+
+	assert.Equal(t.T(), t.contents, foo)
+	assert.Equal(t.T(), "taco", string(bar))
+	_, err = t.readRange(t.bucket, "bar", nil)
+	assert.ErrorContains(t.T(), err, `unknown key "k2"`)
+}
+
+func (t *EncryptionBucketTest) TestNewReader_TamperedChunk() {
+	t.createObject("foo", t.contents)
+	t.overwriteRaw("foo", func(raw []byte) []byte {
+		raw[100000] ^= 1
+		return raw
+	})
+
+	// The chunks before the tampered one are still readable.
+	contents, err := t.readRange(t.bucket, "foo", &gcs.ByteRange{Start: 0, Limit: 65536})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.contents[:65536], contents)
+	_, err = t.readRange(t.bucket, "foo", nil)
+	assert.ErrorContains(t.T(), err, "decrypting chunk 1")
+}
+
+func (t *EncryptionBucketTest) TestNewReader_TruncatedAtChunkBoundary() {
+	t.createObject("foo", t.contents)
+	// Keep the header and the first sealed chunk only.
+	t.overwriteRaw("foo", func(raw []byte) []byte {
+		return raw[:32+65536+16]
+	})
+
+	_, err := t.readRange(t.bucket, "foo", nil)
+
+	assert.ErrorContains(t.T(), err, "decrypting chunk 0")
+}
+
+func (t *EncryptionBucketTest) TestNewReader_TamperedHeader() {
+	t.createObject("foo", t.contents)
+	t.overwriteRaw("foo", func(raw []byte) []byte {
+		raw[10] ^= 1
+		return raw
+	})
+
+	_, err := t.readRange(t.bucket, "foo", &gcs.ByteRange{Start: 70000, Limit: 70001})
+
+	assert.ErrorContains(t.T(), err, "message authentication failed")
+}
+
+func (t *EncryptionBucketTest) TestCreateObject_ChecksumMismatch() {
+	crc := storageutil.CRC32C([]byte("other"))
+
+	_, err := t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     "foo",
+		Contents: bytes.NewReader(t.contents),
+		CRC32C:   crc,
+	})
+
+	assert.ErrorContains(t.T(), err, "CRC32C mismatch")
+	_, _, err = t.wrapped.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "foo"})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFoundErr)
+}
+
+func (t *EncryptionBucketTest) TestCreateObject_MatchingChecksums() {
+	o, err := t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:     "foo",
+		Contents: bytes.NewReader(t.contents),
+		CRC32C:   storageutil.CRC32C(t.contents),
+		MD5:      storageutil.MD5(t.contents),
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), o.Size)
+}
+
+func (t *EncryptionBucketTest) TestCreateObjectChunkWriter() {
+	var uploaded int64
+	w, err := t.bucket.CreateObjectChunkWriter(t.ctx, &gcs.CreateObjectRequest{Name: "foo"}, 1<<20, func(n int64) { uploaded = n })
+	require.NoError(t.T(), err)
+	for offset := 0; offset < len(t.contents); offset += 30000 {
+		_, err = w.Write(t.contents[offset:min(offset+30000, len(t.contents))])
+		require.NoError(t.T(), err)
+	}
+
+	o, err := t.bucket.FinalizeUpload(t.ctx, w)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), o.Size)
+	assert.LessOrEqual(t.T(), uploaded, int64(encryptionTestObjectSize))
+	contents, err := t.readRange(gcsx.NewEncryptionBucket(t.keys, t.wrapped), "foo", nil)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.contents, contents)
+}
+
+func (t *EncryptionBucketTest) TestAppendableObjectsAreUnsupported() {
+	_, err := t.bucket.CreateAppendableObjectWriter(t.ctx, &gcs.CreateObjectChunkWriterRequest{CreateObjectRequest: gcs.CreateObjectRequest{Name: "foo"}})
+
+	assert.ErrorIs(t.T(), err, gcsx.ErrEncryptionUnsupported)
+}
+
+func (t *EncryptionBucketTest) TestComposeObjects() {
+	first := t.createObject("first", t.contents[:70000])
+	t.createObject("second", t.contents[70000:])
+	var generation int64 = 0
+
+	o, err := t.bucket.ComposeObjects(t.ctx, &gcs.ComposeObjectsRequest{
+		DstName:                   "composite",
+		DstGenerationPrecondition: &generation,
+		Sources: []gcs.ComposeSource{
+			{Name: "first", Generation: first.Generation},
+			{Name: "second"},
+		},
+		Metadata: map[string]string{"foo": "bar"},
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), o.Size)
+	assert.Equal(t.T(), "bar", o.Metadata["foo"])
+	contents, err := t.readRange(t.bucket, "composite", nil)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.contents, contents)
+}
+
+func (t *EncryptionBucketTest) TestComposeObjects_PreconditionFailure() {
+	t.createObject("first", t.contents)
+	var generation int64 = 0
+
+	_, err := t.bucket.ComposeObjects(t.ctx, &gcs.ComposeObjectsRequest{
+		DstName:                   "first",
+		DstGenerationPrecondition: &generation,
+		Sources:                   []gcs.ComposeSource{{Name: "first"}},
+	})
+
+	var preconditionErr *gcs.PreconditionError
+	assert.ErrorAs(t.T(), err, &preconditionErr)
+}
+
+func (t *EncryptionBucketTest) TestCopyObject() {
+	t.createObject("foo", t.contents)
+
+	o, err := t.bucket.CopyObject(t.ctx, &gcs.CopyObjectRequest{SrcName: "foo", DstName: "bar"})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), o.Size)
+	contents, err := t.readRange(t.bucket, "bar", &gcs.ByteRange{Start: 100000, Limit: 100010})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.contents[100000:100010], contents)
+}
+
+func (t *EncryptionBucketTest) TestUpdateObject_KeepsKeyID() {
+	t.createObject("foo", t.contents)
+	keyID, value := "k2", "bar"
+
+	o, err := t.bucket.UpdateObject(t.ctx, &gcs.UpdateObjectRequest{
+		Name:     "foo",
+		Metadata: map[string]*string{gcsx.EncryptionKeyIDMetadataKey: &keyID, "foo": &value},
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "k1", o.Metadata[gcsx.EncryptionKeyIDMetadataKey])
+	assert.Equal(t.T(), "bar", o.Metadata["foo"])
+	assert.Equal(t.T(), uint64(encryptionTestObjectSize), o.Size)
+}
+
+func (t *EncryptionBucketTest) TestNewMultiRangeDownloader() {
+	t.createObject("foo", t.contents)
+	mrd, err := gcsx.NewEncryptionBucket(t.keys, t.wrapped).NewMultiRangeDownloader(t.ctx, &gcs.MultiRangeDownloaderRequest{Name: "foo"})
+	require.NoError(t.T(), err)
+	ranges := [][2]int64{{0, 10}, {65530, 70000}, {150000, 60000}, {encryptionTestObjectSize, 10}}
+	outputs := make([]bytes.Buffer, len(ranges))
+	var mu sync.Mutex
+	results := make(map[int64]int64)
+
+	for i, r := range ranges {
+		mrd.Add(&outputs[i], r[0], r[1], func(offset, n int64, err error) {
+			assert.NoError(t.T(), err)
+			mu.Lock()
+			results[offset] = n
+			mu.Unlock()
+		})
+	}
+	mrd.Wait()
+
+	require.NoError(t.T(), mrd.Close())
+	assert.Equal(t.T(), t.contents[0:10], outputs[0].Bytes())
+	assert.Equal(t.T(), t.contents[65530:135530], outputs[1].Bytes())
+	assert.Equal(t.T(), t.contents[150000:], outputs[2].Bytes())
+	assert.Empty(t.T(), outputs[3].Bytes())
+	assert.Equal(t.T(), map[int64]int64{0: 10, 65530: 70000, 150000: 50000, encryptionTestObjectSize: 0}, results)
+}
+
+func (t *EncryptionBucketTest) TestNewMultiRangeDownloader_OutOfRange() {
+	t.createObject("foo", t.contents)
+	mrd, err := t.bucket.NewMultiRangeDownloader(t.ctx, &gcs.MultiRangeDownloaderRequest{Name: "foo"})
+	require.NoError(t.T(), err)
+	var addErr error
+
+	mrd.Add(io.Discard, encryptionTestObjectSize+1, 10, func(_, _ int64, err error) { addErr = err })
+	mrd.Wait()
+
+	assert.ErrorContains(t.T(), addErr, "invalid range")
+}
+
+func writeEncryptionKeyFile(t *testing.T, contents string) string {
+	t.Helper()
+	keyFile := path.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(keyFile, []byte(contents), 0600))
+	return keyFile
+}
+
+func TestLoadEncryptionKeys(t *testing.T) {
+	key := newEncryptionKey()
+	keyFile := writeEncryptionKeyFile(t, `{"primary": "k2", "keys": {"k1": "`+base64.StdEncoding.EncodeToString(newEncryptionKey())+`", "k2": "`+base64.StdEncoding.EncodeToString(key)+`"}}`)
+
+	keys, err := gcsx.LoadEncryptionKeys(keyFile)
+
+	require.NoError(t, err)
+	assert.Equal(t, "k2", keys.PrimaryID)
+	assert.Len(t, keys.Keys, 2)
+	assert.Equal(t, key, keys.Keys["k2"])
+}
+
+func TestLoadEncryptionKeys_Invalid(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(newEncryptionKey())
+	testCases := []struct {
+		name     string
+		contents string
+		err      string
+	}{
+		{"not_json", `keys`, "parsing"},
+		{"no_primary", `{"keys": {"k1": "` + key + `"}}`, "no primary key ID"},
+		{"missing_primary", `{"primary": "k2", "keys": {"k1": "` + key + `"}}`, `primary key "k2" isn't among the keys`},
+		{"not_base64", `{"primary": "k1", "keys": {"k1": "!"}}`, `key "k1"`},
+		{"short_key", `{"primary": "k1", "keys": {"k1": "` + base64.StdEncoding.EncodeToString([]byte("short")) + `"}}`, `key "k1" is 5 bytes long, want 32`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := gcsx.LoadEncryptionKeys(writeEncryptionKeyFile(t, tc.contents))
+
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
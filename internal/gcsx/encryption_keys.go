@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// encryptionKeySize is the size of the keys of a client-side encryption
+// keyfile, for AES-256.
+const encryptionKeySize = 32
+
+// EncryptionKeys are the keys of a bucket created by NewEncryptionBucket.
+type EncryptionKeys struct {
+	// PrimaryID is the ID of the key the contents of new objects are encrypted
+	// with.
+	PrimaryID string
+
+	// Keys are the keys by ID. Objects encrypted with any of them can be read.
+	//
+	// INVARIANT: Keys[PrimaryID] exists
+	// INVARIANT: Each key is encryptionKeySize bytes long
+	Keys map[string][]byte
+}
+
+// encryptionKeyFile is the JSON content of a client-side encryption keyfile.
+type encryptionKeyFile struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LoadEncryptionKeys reads the client-side encryption keyfile at the given
+// path, of the form:
+//
+//	{"primary": "2026-01", "keys": {"2025-06": "<base64>", "2026-01": "<base64>"}}
+//
+// where each key is 256 bits encoded in standard base64. Keeping the former
+// keys in the file after rotating the primary one keeps the objects encrypted
+// with them readable.
+func LoadEncryptionKeys(path string) (*EncryptionKeys, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadEncryptionKeys: %w", err)
+	}
+
+	var file encryptionKeyFile
+	if err = json.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("LoadEncryptionKeys: parsing %s: %w", path, err)
+	}
+	if file.Primary == "" {
+		return nil, fmt.Errorf("LoadEncryptionKeys: %s: no primary key ID", path)
+	}
+
+	keys := &EncryptionKeys{
+		PrimaryID: file.Primary,
+		Keys:      make(map[string][]byte, len(file.Keys)),
+	}
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("LoadEncryptionKeys: %s: key %q: %w", path, id, err)
+		}
+		if len(key) != encryptionKeySize {
+			return nil, fmt.Errorf("LoadEncryptionKeys: %s: key %q is %d bytes long, want %d", path, id, len(key), encryptionKeySize)
+		}
+		keys.Keys[id] = key
+	}
+	if _, ok := keys.Keys[keys.PrimaryID]; !ok {
+		return nil, fmt.Errorf("LoadEncryptionKeys: %s: primary key %q isn't among the keys", path, keys.PrimaryID)
+	}
+	return keys, nil
+}
@@ -124,6 +124,7 @@ func (fc *FileCacheReader) tryReadingFromFileCache(ctx context.Context, p []byte
 	var bytesRead int
 	var cacheHit bool
 	var err error
+	var ramTierHit bool
 
 	defer func() {
 		executionTime := time.Since(startTime)
@@ -145,7 +146,7 @@ func (fc *FileCacheReader) tryReadingFromFileCache(ctx context.Context, p []byte
 		if isSequential {
 			readType = metrics.ReadTypeSequential
 		}
-		captureFileCacheMetrics(ctx, fc.metricHandle, metrics.ReadTypeNames[readType], bytesRead, cacheHit, executionTime)
+		captureFileCacheMetrics(ctx, fc.metricHandle, metrics.ReadTypeNames[readType], bytesRead, cacheHit, ramTierHit, executionTime)
 		fc.traceHandle.SetCacheReadAttributes(span, cacheHit, bytesRead)
 		if err != nil {
 			fc.traceHandle.RecordError(span, err)
@@ -153,6 +154,16 @@ func (fc *FileCacheReader) tryReadingFromFileCache(ctx context.Context, p []byte
 		fc.traceHandle.EndSpan(span)
 	}()
 
+	// Serve the data from memory if all of it is there, falling through to the
+	// file in cache otherwise.
+	ramTier := fc.fileCacheHandler.RAMTier()
+	if ramTier != nil {
+		if bytesRead, cacheHit = ramTier.ReadAt(fc.bucket, fc.object, offset, p); cacheHit {
+			ramTierHit = true
+			return bytesRead, cacheHit, nil
+		}
+	}
+
 	// Create fileCacheHandle if not already.
 	fc.mu.Lock()
 	if fc.fileCacheHandle == nil {
@@ -191,6 +202,10 @@ func (fc *FileCacheReader) tryReadingFromFileCache(ctx context.Context, p []byte
 		return 0, false, nil
 	}
 	bytesRead, cacheHit, err = fc.fileCacheHandle.Read(ctx, fc.bucket, fc.object, offset, p)
+	// Data read again from the file in cache is hot, so keep it in memory.
+	if err == nil && cacheHit && ramTier != nil {
+		ramTier.Promote(fc.bucket, fc.object, offset, bytesRead, fc.fileCacheHandle)
+	}
 	fc.mu.RUnlock()
 	if err == nil {
 		return bytesRead, cacheHit, nil
@@ -244,8 +259,11 @@ func (fc *FileCacheReader) ReadAt(ctx context.Context, req *ReadRequest) (ReadRe
 	return readResponse, FallbackToAnotherReader
 }
 
-func captureFileCacheMetrics(ctx context.Context, metricHandle metrics.MetricHandle, readType metrics.ReadType, readDataSize int, cacheHit bool, readLatency time.Duration) {
+func captureFileCacheMetrics(ctx context.Context, metricHandle metrics.MetricHandle, readType metrics.ReadType, readDataSize int, cacheHit bool, ramTierHit bool, readLatency time.Duration) {
 	metricHandle.FileCacheReadCount(1, cacheHit, readType)
+	if ramTierHit {
+		metricHandle.FileCacheRamTierReadCount(1, readType)
+	}
 	metricHandle.FileCacheReadBytesCount(int64(readDataSize), readType)
 	metricHandle.FileCacheReadLatencies(ctx, readLatency, cacheHit)
 }
@@ -43,6 +43,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
 )
 
 const (
@@ -922,3 +925,20 @@ func (t *fileCacheReaderTest) Test_Concurrent_ReadAt_And_Destroy() {
 
 	wg.Wait()
 }
+
+func TestCaptureFileCacheMetrics_RAMTierHit(t *testing.T) {
+	ctx := context.Background()
+	reader := metric.NewManualReader()
+	otel.SetMeterProvider(metric.NewMeterProvider(metric.WithReader(reader)))
+	metricHandle, err := metrics.NewOTelMetrics(ctx, 1, 100)
+	require.NoError(t, err)
+
+	captureFileCacheMetrics(ctx, metricHandle, metrics.ReadTypeRandomAttr, 10, true, true, time.Millisecond)
+	captureFileCacheMetrics(ctx, metricHandle, metrics.ReadTypeRandomAttr, 10, true, false, time.Millisecond)
+
+	// Reads served from memory are also file cache hits.
+	metrics.VerifyCounterMetric(t, ctx, reader, "file_cache/read_count",
+		attribute.NewSet(attribute.Bool("cache_hit", true), attribute.String("read_type", string(metrics.ReadTypeRandomAttr))), 2)
+	metrics.VerifyCounterMetric(t, ctx, reader, "file_cache/ram_tier_read_count",
+		attribute.NewSet(attribute.String("read_type", string(metrics.ReadTypeRandomAttr))), 1)
+}
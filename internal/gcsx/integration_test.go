@@ -91,10 +91,12 @@ func (t *IntegrationTest) SetUp(ti *TestInfo) {
 	const tmpObjectPrefix = ".gcsfuse_tmp/"
 
 	t.syncer = gcsx.NewSyncer(
-		appendThreshold,
-		chunkRetryDeadlineSecs,
-		chunkTransferTimeoutSecs,
-		tmpObjectPrefix,
+		gcsx.SyncerConfig{
+			AppendThreshold:          appendThreshold,
+			ChunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
+			ChunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
+			TmpObjectPrefix:          tmpObjectPrefix,
+		},
 		t.bucket)
 }
 
@@ -149,7 +151,7 @@ func (t *IntegrationTest) objectGeneration(name string) (gen int64) {
 }
 
 func (t *IntegrationTest) sync(src *gcs.Object) (o *gcs.Object, err error) {
-	o, err = t.syncer.SyncObject(t.ctx, src.Name, src, t.tf)
+	o, err = t.syncer.SyncObject(t.ctx, src.Name, src, nil, t.tf)
 	if err == nil && o != nil {
 		t.tf = nil
 	}
@@ -189,7 +191,7 @@ func (t *IntegrationTest) SyncEmptyLocalFile() {
 	AssertEq(nil, err)
 
 	// Sync should update the object in GCS.
-	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, tf)
+	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, nil, tf)
 
 	AssertEq(nil, err)
 	ExpectEq(t.objectGeneration("test"), newObj.Generation)
@@ -223,7 +225,7 @@ func (t *IntegrationTest) SyncNonEmptyLocalFile() {
 	t.clock.AdvanceTime(time.Second)
 
 	// Sync should update the object in GCS.
-	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, tf)
+	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, nil, tf)
 
 	AssertEq(nil, err)
 	ExpectEq(t.objectGeneration("test"), newObj.Generation)
@@ -0,0 +1,161 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/net/context"
+)
+
+// NewObjectMetadataBucket creates a wrapper bucket that sets the attributes of
+// newly created or composed objects from the first of the given rules whose
+// glob matches their names. Rules only fill in the attributes and custom
+// metadata keys the requests leave unset, so rewrites of existing objects
+// keep their attributes, as do the objects written by other tools. Temporary
+// objects, whose names begin with tmpObjectPrefix, are left alone.
+func NewObjectMetadataBucket(rules []ObjectMetadataRule, tmpObjectPrefix string, b gcs.Bucket) gcs.Bucket {
+	return objectMetadataBucket{
+		Bucket:          b,
+		rules:           rules,
+		tmpObjectPrefix: tmpObjectPrefix,
+	}
+}
+
+type objectMetadataBucket struct {
+	gcs.Bucket
+	rules           []ObjectMetadataRule
+	tmpObjectPrefix string
+}
+
+// objectAttributes points at the attributes of a create or compose request
+// that rules set.
+type objectAttributes struct {
+	contentType        *string
+	cacheControl       *string
+	contentEncoding    *string
+	contentDisposition *string
+	storageClass       *string
+	customTime         *string
+	eventBasedHold     *bool
+	metadata           *map[string]string
+}
+
+func createRequestAttributes(req *gcs.CreateObjectRequest) objectAttributes {
+	return objectAttributes{
+		contentType:        &req.ContentType,
+		cacheControl:       &req.CacheControl,
+		contentEncoding:    &req.ContentEncoding,
+		contentDisposition: &req.ContentDisposition,
+		storageClass:       &req.StorageClass,
+		customTime:         &req.CustomTime,
+		eventBasedHold:     &req.EventBasedHold,
+		metadata:           &req.Metadata,
+	}
+}
+
+func composeRequestAttributes(req *gcs.ComposeObjectsRequest) objectAttributes {
+	return objectAttributes{
+		contentType:        &req.ContentType,
+		cacheControl:       &req.CacheControl,
+		contentEncoding:    &req.ContentEncoding,
+		contentDisposition: &req.ContentDisposition,
+		storageClass:       &req.StorageClass,
+		customTime:         &req.CustomTime,
+		eventBasedHold:     &req.EventBasedHold,
+		metadata:           &req.Metadata,
+	}
+}
+
+// fillString sets *s to v if it's empty.
+func fillString(s *string, v string) {
+	if *s == "" {
+		*s = v
+	}
+}
+
+// isCustomTimeUnset tells whether the custom time of a request is unset,
+// which the requests copied from existing objects express with the zero time.
+func isCustomTimeUnset(customTime string) bool {
+	t, err := time.Parse(time.RFC3339, customTime)
+	return err != nil || t.IsZero()
+}
+
+// apply fills in the unset attributes of the request for the object with the
+// given name from the first matching rule, if any.
+func (b objectMetadataBucket) apply(name string, attrs objectAttributes) {
+	if b.tmpObjectPrefix != "" && strings.HasPrefix(name, b.tmpObjectPrefix) {
+		return
+	}
+
+	for i := range b.rules {
+		r := &b.rules[i]
+		if !r.matches(name) {
+			continue
+		}
+
+		fillString(attrs.contentType, r.ContentType)
+		fillString(attrs.cacheControl, r.CacheControl)
+		fillString(attrs.contentEncoding, r.ContentEncoding)
+		fillString(attrs.contentDisposition, r.ContentDisposition)
+		fillString(attrs.storageClass, r.StorageClass)
+		if r.CustomTime != "" && isCustomTimeUnset(*attrs.customTime) {
+			*attrs.customTime = r.CustomTime
+		}
+		*attrs.eventBasedHold = *attrs.eventBasedHold || r.EventBasedHold
+
+		// Don't modify the caller's map.
+		if len(r.Metadata) > 0 {
+			metadata := maps.Clone(*attrs.metadata)
+			if metadata == nil {
+				metadata = make(map[string]string, len(r.Metadata))
+			}
+			for k, v := range r.Metadata {
+				if _, ok := metadata[k]; !ok {
+					metadata[k] = v
+				}
+			}
+			*attrs.metadata = metadata
+		}
+		return
+	}
+}
+
+func (b objectMetadataBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	b.apply(req.Name, createRequestAttributes(req))
+	return b.Bucket.CreateObject(ctx, req)
+}
+
+func (b objectMetadataBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	b.apply(req.DstName, composeRequestAttributes(req))
+	return b.Bucket.ComposeObjects(ctx, req)
+}
+
+func (b objectMetadataBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	b.apply(req.Name, createRequestAttributes(req))
+	return b.Bucket.CreateObjectChunkWriter(ctx, req, chunkSize, callBack)
+}
+
+func (b objectMetadataBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	b.apply(req.Name, createRequestAttributes(&req.CreateObjectRequest))
+	return b.Bucket.CreateAppendableObjectWriter(ctx, req)
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const objectMetadataTestRules = `
+- match: "**/*.html"
+  content-type: "text/html; charset=utf-8"
+  cache-control: "no-cache"
+  content-encoding: "identity"
+  content-disposition: "inline"
+  storage-class: "STANDARD"
+  custom-time: "2026-01-02T03:04:05Z"
+  event-based-hold: true
+  metadata:
+    team: web
+- match: "assets/**"
+  cache-control: "public, max-age=31536000, immutable"
+`
+
+// recordingBucket records the last create and compose requests it receives.
+type recordingBucket struct {
+	gcs.Bucket
+	createReq  *gcs.CreateObjectRequest
+	composeReq *gcs.ComposeObjectsRequest
+}
+
+func (b *recordingBucket) CreateObject(ctx context.Context, req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	b.createReq = req
+	return b.Bucket.CreateObject(ctx, req)
+}
+
+func (b *recordingBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	b.createReq = req
+	return b.Bucket.CreateObjectChunkWriter(ctx, req, chunkSize, callBack)
+}
+
+func (b *recordingBucket) ComposeObjects(ctx context.Context, req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	b.composeReq = req
+	return b.Bucket.ComposeObjects(ctx, req)
+}
+
+func writeObjectMetadataRulesFile(t *testing.T, contents string) string {
+	t.Helper()
+	p := path.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(contents), 0600))
+	return p
+}
+
+func newObjectMetadataTestBucket(t *testing.T, rules string) (gcs.Bucket, *recordingBucket) {
+	t.Helper()
+	loaded, err := gcsx.LoadObjectMetadataRules(writeObjectMetadataRulesFile(t, rules))
+	require.NoError(t, err)
+	recorder := &recordingBucket{Bucket: fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})}
+	return gcsx.NewObjectMetadataBucket(loaded, ".gcsfuse_tmp/", recorder), recorder
+}
+
+func TestObjectMetadataBucket_CreateObjectSetsAttributes(t *testing.T) {
+	b, recorder := newObjectMetadataTestBucket(t, objectMetadataTestRules)
+
+	_, err := b.CreateObject(context.Background(), &gcs.CreateObjectRequest{
+		Name:     "site/index.html",
+		Contents: strings.NewReader("<html>"),
+		Metadata: map[string]string{gcs.MtimeMetadataKey: "2026-05-06T07:08:09Z"},
+	})
+
+	require.NoError(t, err)
+	req := recorder.createReq
+	assert.Equal(t, "text/html; charset=utf-8", req.ContentType)
+	assert.Equal(t, "no-cache", req.CacheControl)
+	assert.Equal(t, "identity", req.ContentEncoding)
+	assert.Equal(t, "inline", req.ContentDisposition)
+	assert.Equal(t, "STANDARD", req.StorageClass)
+	assert.Equal(t, "2026-01-02T03:04:05Z", req.CustomTime)
+	assert.True(t, req.EventBasedHold)
+	assert.Equal(t, map[string]string{gcs.MtimeMetadataKey: "2026-05-06T07:08:09Z", "team": "web"}, req.Metadata)
+}
+
+func TestObjectMetadataBucket_KeepsRequestAttributes(t *testing.T) {
+	b, recorder := newObjectMetadataTestBucket(t, objectMetadataTestRules)
+	metadata := map[string]string{"team": "data"}
+
+	_, err := b.CreateObject(context.Background(), &gcs.CreateObjectRequest{
+		Name:        "index.html",
+		Contents:    strings.NewReader("<html>"),
+		ContentType: "text/plain",
+		// As copied from an existing object without a custom time.
+		CustomTime: "0001-01-01T00:00:00Z",
+		Metadata:   metadata,
+	})
+
+	require.NoError(t, err)
+	req := recorder.createReq
+	assert.Equal(t, "text/plain", req.ContentType)
+	assert.Equal(t, "no-cache", req.CacheControl)
+	assert.Equal(t, "2026-01-02T03:04:05Z", req.CustomTime)
+	assert.Equal(t, map[string]string{"team": "data"}, req.Metadata)
+	assert.Equal(t, map[string]string{"team": "data"}, metadata)
+}
+
+func TestObjectMetadataBucket_FirstMatchingRuleApplies(t *testing.T) {
+	b, recorder := newObjectMetadataTestBucket(t, objectMetadataTestRules)
+
+	_, err := b.CreateObject(context.Background(), &gcs.CreateObjectRequest{
+		Name:     "assets/logo.html",
+		Contents: strings.NewReader(""),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "no-cache", recorder.createReq.CacheControl)
+}
+
+func TestObjectMetadataBucket_LeavesUnmatchedAndTemporaryObjectsAlone(t *testing.T) {
+	for _, name := range []string{"index.htm", ".gcsfuse_tmp/index.html"} {
+		t.Run(name, func(t *testing.T) {
+			b, recorder := newObjectMetadataTestBucket(t, objectMetadataTestRules)
+
+			_, err := b.CreateObject(context.Background(), &gcs.CreateObjectRequest{
+				Name:     name,
+				Contents: strings.NewReader(""),
+			})
+
+			require.NoError(t, err)
+			assert.Empty(t, recorder.createReq.CacheControl)
+			assert.Empty(t, recorder.createReq.Metadata)
+		})
+	}
+}
+
+func TestObjectMetadataBucket_CreateObjectChunkWriter(t *testing.T) {
+	b, recorder := newObjectMetadataTestBucket(t, objectMetadataTestRules)
+
+	_, err := b.CreateObjectChunkWriter(context.Background(), &gcs.CreateObjectRequest{Name: "assets/app.js"}, 1024, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "public, max-age=31536000, immutable", recorder.createReq.CacheControl)
+}
+
+func TestObjectMetadataBucket_ComposeObjects(t *testing.T) {
+	ctx := context.Background()
+	b, recorder := newObjectMetadataTestBucket(t, objectMetadataTestRules)
+	_, err := b.CreateObject(ctx, &gcs.CreateObjectRequest{Name: ".gcsfuse_tmp/part", Contents: strings.NewReader("<html>")})
+	require.NoError(t, err)
+
+	o, err := b.ComposeObjects(ctx, &gcs.ComposeObjectsRequest{
+		DstName: "docs/index.html",
+		Sources: []gcs.ComposeSource{{Name: ".gcsfuse_tmp/part"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "no-cache", recorder.composeReq.CacheControl)
+	assert.True(t, recorder.composeReq.EventBasedHold)
+	assert.Equal(t, "text/html; charset=utf-8", o.ContentType)
+	assert.Equal(t, "web", o.Metadata["team"])
+}
+
+func TestObjectMetadataBucket_Globs(t *testing.T) {
+	testCases := []struct {
+		glob    string
+		name    string
+		matches bool
+	}{
+		{"*.html", "index.html", true},
+		{"*.html", "site/index.html", false},
+		{"**/*.html", "index.html", true},
+		{"**/*.html", "a/b/index.html", true},
+		{"site/**", "site/a/b.css", true},
+		{"site/**", "other/a.css", false},
+		{"site/*.css", "site/a/b.css", false},
+		{"img?.png", "img1.png", true},
+		{"img?.png", "img/.png", false},
+		{"[ab].txt", "a.txt", true},
+		{"[!ab].txt", "a.txt", false},
+		{"[!ab].txt", "c.txt", true},
+		{`\*.txt`, "*.txt", true},
+		{`\*.txt`, "a.txt", false},
+		{"a+b.txt", "a+b.txt", true},
+		{"a+b.txt", "aab.txt", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.glob+" "+tc.name, func(t *testing.T) {
+			b, recorder := newObjectMetadataTestBucket(t, `[{"match": "`+strings.ReplaceAll(tc.glob, `\`, `\\`)+`", "cache-control": "matched"}]`)
+
+			_, err := b.CreateObject(context.Background(), &gcs.CreateObjectRequest{
+				Name:     tc.name,
+				Contents: strings.NewReader(""),
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.matches, recorder.createReq.CacheControl == "matched")
+		})
+	}
+}
+
+func TestLoadObjectMetadataRules_Empty(t *testing.T) {
+	rules, err := gcsx.LoadObjectMetadataRules(writeObjectMetadataRulesFile(t, ""))
+
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestLoadObjectMetadataRules_Invalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		contents string
+		err      string
+	}{
+		{"not_a_list", `match: "*"`, "parsing"},
+		{"unknown_field", `[{"match": "*", "content_type": "text/plain"}]`, "parsing"},
+		{"no_match", `[{"cache-control": "no-cache"}]`, "rule 1: no match glob"},
+		{"bad_glob", `[{"match": "*"}, {"match": "[a"}]`, "rule 2: match glob"},
+		{"bad_custom_time", `[{"match": "*", "custom-time": "yesterday"}]`, "custom-time"},
+		{"reserved_metadata", `[{"match": "*", "metadata": {"gcsfuse_mtime": "x"}}]`, `metadata key "gcsfuse_mtime" is reserved`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := gcsx.LoadObjectMetadataRules(writeObjectMetadataRulesFile(t, tc.contents))
+
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoadObjectMetadataRules_MissingFile(t *testing.T) {
+	_, err := gcsx.LoadObjectMetadataRules(path.Join(t.TempDir(), "missing.yaml"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
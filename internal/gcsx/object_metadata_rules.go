@@ -0,0 +1,153 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"gopkg.in/yaml.v3"
+)
+
+// ObjectMetadataRule sets the attributes of the new objects whose names match
+// a glob. Rules are obtained from LoadObjectMetadataRules, which compiles
+// their globs. See NewObjectMetadataBucket.
+type ObjectMetadataRule struct {
+	// Match is the glob the names of the objects must match, in which "*"
+	// matches any sequence of characters but "/", "**" any sequence of
+	// characters, "**/" any sequence of directories, "?" any character but "/"
+	// and "[...]" a character class, negated by a leading "!".
+	Match string `yaml:"match"`
+
+	ContentType        string            `yaml:"content-type"`
+	CacheControl       string            `yaml:"cache-control"`
+	ContentEncoding    string            `yaml:"content-encoding"`
+	ContentDisposition string            `yaml:"content-disposition"`
+	StorageClass       string            `yaml:"storage-class"`
+	CustomTime         string            `yaml:"custom-time"`
+	EventBasedHold     bool              `yaml:"event-based-hold"`
+	Metadata           map[string]string `yaml:"metadata"`
+
+	// The compiled Match.
+	match *regexp.Regexp
+}
+
+// matches tells whether the rule applies to the object with the given name.
+func (r *ObjectMetadataRule) matches(name string) bool {
+	return r.match.MatchString(name)
+}
+
+// compile validates the rule and compiles its glob.
+func (r *ObjectMetadataRule) compile() (err error) {
+	if r.Match == "" {
+		return errors.New("no match glob")
+	}
+	if r.match, err = compileGlob(r.Match); err != nil {
+		return fmt.Errorf("match glob %q: %w", r.Match, err)
+	}
+	if r.CustomTime != "" {
+		if _, err = time.Parse(time.RFC3339, r.CustomTime); err != nil {
+			return fmt.Errorf("custom-time: %w", err)
+		}
+	}
+	for key := range r.Metadata {
+		if gcs.IsReservedMetadataKey(key) {
+			return fmt.Errorf("metadata key %q is reserved", key)
+		}
+	}
+	return nil
+}
+
+// compileGlob returns a regexp matching the same names as the given glob. See
+// ObjectMetadataRule.Match for the syntax.
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if !strings.HasPrefix(glob[i:], "**") {
+				re.WriteString("[^/]*")
+			} else if strings.HasPrefix(glob[i:], "**/") {
+				re.WriteString("(?:.*/)?")
+				i += 2
+			} else {
+				re.WriteString(".*")
+				i++
+			}
+		case '?':
+			re.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, errors.New("unterminated character class")
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 == len(glob) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	return regexp.Compile(re.String())
+}
+
+// LoadObjectMetadataRules reads the YAML file of object metadata rules at the
+// given path, an ordered list of the form:
+//
+//	# The first matching rule applies.
+//	- match: "**/*.html"
+//	  content-type: "text/html; charset=utf-8"
+//	  cache-control: "no-cache"
+//	- match: "assets/**"
+//	  cache-control: "public, max-age=31536000, immutable"
+//	  metadata:
+//	    team: web
+func LoadObjectMetadataRules(path string) ([]ObjectMetadataRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadObjectMetadataRules: %w", err)
+	}
+	defer f.Close()
+
+	var rules []ObjectMetadataRule
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err = dec.Decode(&rules); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("LoadObjectMetadataRules: parsing %s: %w", path, err)
+	}
+	for i := range rules {
+		if err = rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("LoadObjectMetadataRules: %s: rule %d: %w", path, i+1, err)
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+)
+
+// sizedReaderAt is the random access to the full contents that the parallel
+// composite object creator needs, e.g. an *io.SectionReader.
+type sizedReaderAt interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// Create an objectCreator that accepts a source object (or nil) and the full
+// contents with which it should be overwritten. The contents, which must
+// implement sizedReaderAt, are split into the given number of parts that are
+// uploaded concurrently as temporary objects using the supplied prefix, and
+// then composed into the destination object.
+//
+// Note that the Create method will attempt to remove the temporary objects, but
+// it may fail to do so. Users should arrange for garbage collection.
+//
+// Create guarantees to return *gcs.PreconditionError when the source object
+// has been clobbered.
+func newParallelCompositeObjectCreator(
+	prefix string,
+	parts int,
+	bucket gcs.Bucket) (oc objectCreator) {
+	oc = &parallelCompositeObjectCreator{
+		prefix: prefix,
+		parts:  max(1, min(parts, gcs.MaxSourcesPerComposeRequest)),
+		bucket: bucket,
+	}
+
+	return
+}
+
+////////////////////////////////////////////////////////////////////////
+// Implementation
+////////////////////////////////////////////////////////////////////////
+
+type parallelCompositeObjectCreator struct {
+	prefix string
+	parts  int
+	bucket gcs.Bucket
+}
+
+func (oc *parallelCompositeObjectCreator) Create(
+	ctx context.Context,
+	objectName string,
+	srcObject *gcs.Object,
+	mtime *time.Time,
+	metadata map[string]string,
+	chunkRetryDeadlineSecs int64,
+	chunkTransferTimeoutSecs int64,
+	r io.Reader) (o *gcs.Object, err error) {
+	contents, ok := r.(sizedReaderAt)
+	if !ok {
+		err = errors.New("contents don't support random access")
+		return
+	}
+
+	// Split the contents into parts of equal size, except maybe for the last.
+	size := contents.Size()
+	partSize := max(1, (size+int64(oc.parts)-1)/int64(oc.parts))
+	tmpObjects := make([]*gcs.Object, max(1, (size+partSize-1)/partSize))
+
+	// Attempt to delete the temporary objects when we're done. Any that are left
+	// behind will be garbage collected.
+	defer func() {
+		for _, tmp := range tmpObjects {
+			if tmp == nil {
+				continue
+			}
+
+			deleteErr := oc.bucket.DeleteObject(
+				ctx,
+				&gcs.DeleteObjectRequest{
+					Name:       tmp.Name,
+					Generation: 0, // Delete the latest generation of temporary object.
+				})
+			if deleteErr != nil {
+				logger.Warnf("Failed to delete temporary object %q: %v", tmp.Name, deleteErr)
+			}
+		}
+	}()
+
+	// Upload the parts concurrently.
+	group, groupCtx := errgroup.WithContext(ctx)
+	for i := range tmpObjects {
+		offset := int64(i) * partSize
+		length := min(partSize, size-offset)
+		group.Go(func() (err error) {
+			tmpName, err := chooseTmpObjectName(oc.prefix)
+			if err != nil {
+				err = fmt.Errorf("chooseName: %w", err)
+				return
+			}
+
+			req := gcs.NewCreateObjectRequest(nil, tmpName, nil, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs)
+			req.Contents = io.NewSectionReader(contents, offset, length)
+			tmpObjects[i], err = oc.bucket.CreateObject(groupCtx, req)
+			if err != nil {
+				err = fmt.Errorf("CreateObject: %w", err)
+				return
+			}
+
+			return
+		})
+	}
+
+	if err = group.Wait(); err != nil {
+		return
+	}
+
+	// Compose the parts over the source object, with the same preconditions and
+	// attributes as a full upload.
+	createReq := gcs.NewCreateObjectRequest(srcObject, objectName, mtime, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs)
+	maps.Copy(createReq.Metadata, metadata)
+	composeReq := &gcs.ComposeObjectsRequest{
+		DstName:                       createReq.Name,
+		DstGenerationPrecondition:     createReq.GenerationPrecondition,
+		DstMetaGenerationPrecondition: createReq.MetaGenerationPrecondition,
+		Metadata:                      createReq.Metadata,
+		CacheControl:                  createReq.CacheControl,
+		ContentDisposition:            createReq.ContentDisposition,
+		ContentEncoding:               createReq.ContentEncoding,
+		ContentType:                   createReq.ContentType,
+		CustomTime:                    createReq.CustomTime,
+		EventBasedHold:                createReq.EventBasedHold,
+		StorageClass:                  createReq.StorageClass,
+	}
+	for _, tmp := range tmpObjects {
+		composeReq.Sources = append(composeReq.Sources, gcs.ComposeSource{
+			Name:       tmp.Name,
+			Generation: tmp.Generation,
+		})
+	}
+
+	o, err = oc.bucket.ComposeObjects(ctx, composeReq)
+	if err != nil {
+		err = fmt.Errorf("ComposeObjects: %w", err)
+		return
+	}
+
+	return
+}
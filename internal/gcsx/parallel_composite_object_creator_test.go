@@ -0,0 +1,133 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+const parallelTmpObjectPrefix = ".gcsfuse_tmp/"
+
+type ParallelCompositeObjectCreatorTest struct {
+	suite.Suite
+	ctx     context.Context
+	bucket  gcs.Bucket
+	creator objectCreator
+}
+
+func TestParallelCompositeObjectCreator(t *testing.T) {
+	suite.Run(t, new(ParallelCompositeObjectCreatorTest))
+}
+
+func (t *ParallelCompositeObjectCreatorTest) SetupTest() {
+	t.ctx = context.Background()
+	t.bucket = fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	t.creator = newParallelCompositeObjectCreator(parallelTmpObjectPrefix, 4, t.bucket)
+}
+
+func (t *ParallelCompositeObjectCreatorTest) create(srcObject *gcs.Object, contents string) (*gcs.Object, error) {
+	mtime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return t.creator.Create(
+		t.ctx,
+		"foo",
+		srcObject,
+		&mtime,
+		nil,
+		chunkRetryDeadlineSecs,
+		chunkTransferTimeoutSecs,
+		io.NewSectionReader(strings.NewReader(contents), 0, int64(len(contents))))
+}
+
+func (t *ParallelCompositeObjectCreatorTest) tmpObjects() []*gcs.MinObject {
+	objects, _, err := storageutil.ListAll(t.ctx, t.bucket, &gcs.ListObjectsRequest{Prefix: parallelTmpObjectPrefix})
+	require.NoError(t.T(), err)
+	return objects
+}
+
+func (t *ParallelCompositeObjectCreatorTest) TestCreatesNewObject() {
+	contents := "abcdefghijklmnopqrstuvwxyz"
+
+	o, err := t.create(nil, contents)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "foo", o.Name)
+	assert.EqualValues(t.T(), len(contents), o.Size)
+	assert.EqualValues(t.T(), 4, o.ComponentCount)
+	assert.Equal(t.T(), "2026-01-02T03:04:05Z", o.Metadata[gcs.MtimeMetadataKey])
+	actual, err := storageutil.ReadObject(t.ctx, t.bucket, "foo")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), contents, string(actual))
+	assert.Empty(t.T(), t.tmpObjects())
+}
+
+func (t *ParallelCompositeObjectCreatorTest) TestOverwritesSourceObject() {
+	src, err := storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	o, err := t.create(src, "burrito")
+
+	require.NoError(t.T(), err)
+	assert.Greater(t.T(), o.Generation, src.Generation)
+	actual, err := storageutil.ReadObject(t.ctx, t.bucket, "foo")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "burrito", string(actual))
+	assert.Empty(t.T(), t.tmpObjects())
+}
+
+func (t *ParallelCompositeObjectCreatorTest) TestFewerPartsThanBytes() {
+	o, err := t.create(nil, "ab")
+
+	require.NoError(t.T(), err)
+	assert.EqualValues(t.T(), 2, o.ComponentCount)
+	actual, err := storageutil.ReadObject(t.ctx, t.bucket, "foo")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "ab", string(actual))
+}
+
+func (t *ParallelCompositeObjectCreatorTest) TestSourceObjectClobbered() {
+	src, err := storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("enchilada"))
+	require.NoError(t.T(), err)
+
+	_, err = t.create(src, "burrito")
+
+	var preconditionErr *gcs.PreconditionError
+	assert.True(t.T(), errors.As(err, &preconditionErr))
+	actual, err := storageutil.ReadObject(t.ctx, t.bucket, "foo")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "enchilada", string(actual))
+	assert.Empty(t.T(), t.tmpObjects())
+}
+
+func (t *ParallelCompositeObjectCreatorTest) TestContentsWithoutRandomAccess() {
+	_, err := t.creator.Create(t.ctx, "foo", nil, nil, nil, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs, bytes.NewBufferString("taco"))
+
+	assert.ErrorContains(t.T(), err, "random access")
+}
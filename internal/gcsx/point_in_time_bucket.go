@@ -0,0 +1,318 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// ErrPointInTimeReadOnly is returned by the mutations of a bucket created by
+// NewPointInTimeBucket.
+var ErrPointInTimeReadOnly = errors.New("the bucket is mounted at a point in time and is read-only")
+
+// pinnedGenerationsCacheSize is the number of names whose generation at the
+// time of a point-in-time bucket is remembered.
+const pinnedGenerationsCacheSize = 10000
+
+// NewPointInTimeBucket creates a read-only view on the wrapped bucket as it was
+// at the given time: each object is the generation that was live then, found
+// among its live and noncurrent generations. Soft-deleted generations can't be
+// read without restoring them, so objects whose generation at the given time
+// has since been soft-deleted are skipped.
+//
+// Each page of a listing holds the generations live at the given time among
+// those of the corresponding page of the wrapped bucket, so pages may be short
+// or empty before the last one. A prefix is listed as a collapsed run if any
+// generation of an object under it exists, even if none was live at the given
+// time.
+func NewPointInTimeBucket(at time.Time, wrapped gcs.Bucket) gcs.Bucket {
+	return &pointInTimeBucket{
+		at:          at,
+		generations: lru.NewCache(pinnedGenerationsCacheSize),
+		wrapped:     wrapped,
+	}
+}
+
+type pointInTimeBucket struct {
+	at time.Time
+
+	// The generations live at the time of the bucket, keyed by name. They never
+	// change since the time is in the past.
+	generations *lru.Cache
+
+	wrapped gcs.Bucket
+}
+
+// pinnedGeneration is the generation of an object that was live at the time of
+// the bucket, or nil if none was.
+type pinnedGeneration struct {
+	o *gcs.MinObject
+}
+
+// Size counts the entries of the cache of generations.
+func (g *pinnedGeneration) Size() uint64 {
+	return 1
+}
+
+// pin remembers the generation of the given name at the time of the bucket.
+// The object is copied, since callers may modify those they are returned.
+func (b *pointInTimeBucket) pin(name string, o *gcs.MinObject) {
+	g := &pinnedGeneration{}
+	if o != nil {
+		copied := *o
+		g.o = &copied
+	}
+	if _, err := b.generations.Insert(name, g); err != nil {
+		panic(fmt.Sprintf("pinnedGeneration has an invalid size: %v", err))
+	}
+}
+
+// liveAt returns true if the generation with the given times was live at the
+// time of the bucket.
+func (b *pointInTimeBucket) liveAt(t gcs.GenerationTimes) bool {
+	return !t.Created.After(b.at) && (t.Deleted.IsZero() || t.Deleted.After(b.at))
+}
+
+// statGenerationsPageSize is the number of generations listed per page to find
+// the generation of an object live at the time of a point-in-time bucket.
+const statGenerationsPageSize = 100
+
+// listGenerations returns the generations in a page of the objects matching the
+// request that were live at the time of the bucket, along with its collapsed
+// runs and the continuation token of the next page.
+func (b *pointInTimeBucket) listGenerations(ctx context.Context, req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	mReq := *req
+	mReq.Versions = true
+	page, err := b.wrapped.ListObjects(ctx, &mReq)
+	if err != nil {
+		return nil, err
+	}
+	if len(page.GenerationTimes) != len(page.MinObjects) {
+		return nil, fmt.Errorf("listing of %d generations with %d times", len(page.MinObjects), len(page.GenerationTimes))
+	}
+
+	listing := &gcs.Listing{
+		CollapsedRuns:     page.CollapsedRuns,
+		ContinuationToken: page.ContinuationToken,
+	}
+	for i, o := range page.MinObjects {
+		if b.liveAt(page.GenerationTimes[i]) {
+			listing.MinObjects = append(listing.MinObjects, o)
+			b.pin(o.Name, o)
+		}
+	}
+	return listing, nil
+}
+
+// statGeneration returns the generation of the object with the given name that
+// was live at the time of the bucket.
+func (b *pointInTimeBucket) statGeneration(ctx context.Context, name string) (*gcs.MinObject, error) {
+	g, ok := b.generations.LookUp(name).(*pinnedGeneration)
+	if !ok {
+		var err error
+		if g, err = b.findGeneration(ctx, name); err != nil {
+			return nil, err
+		}
+		b.pin(name, g.o)
+	}
+
+	if g.o == nil {
+		return nil, &gcs.NotFoundError{
+			Err: fmt.Errorf("object %q didn't exist at %v", name, b.at.Format(time.RFC3339)),
+		}
+	}
+	o := *g.o
+	return &o, nil
+}
+
+// findGeneration lists the generations of the object with the given name to
+// find the one that was live at the time of the bucket. Listings are ordered by
+// name, so the generations of the object come before those of the other
+// objects with its name as prefix, and the listing stops at the first of them.
+func (b *pointInTimeBucket) findGeneration(ctx context.Context, name string) (*pinnedGeneration, error) {
+	g := &pinnedGeneration{}
+	req := &gcs.ListObjectsRequest{
+		Prefix:     name,
+		Delimiter:  "/",
+		MaxResults: statGenerationsPageSize,
+		Versions:   true,
+	}
+	for {
+		page, err := b.wrapped.ListObjects(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.GenerationTimes) != len(page.MinObjects) {
+			return nil, fmt.Errorf("listing of %d generations with %d times", len(page.MinObjects), len(page.GenerationTimes))
+		}
+		for i, o := range page.MinObjects {
+			if o.Name != name {
+				return g, nil
+			}
+			if b.liveAt(page.GenerationTimes[i]) {
+				g.o = o
+			}
+		}
+		if len(page.CollapsedRuns) > 0 || page.ContinuationToken == "" {
+			return g, nil
+		}
+		req.ContinuationToken = page.ContinuationToken
+	}
+}
+
+// pinGeneration returns the generation that was live at the time of the bucket
+// if no generation is requested.
+func (b *pointInTimeBucket) pinGeneration(ctx context.Context, name string, generation int64) (int64, error) {
+	if generation != 0 {
+		return generation, nil
+	}
+	o, err := b.statGeneration(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	return o.Generation, nil
+}
+
+func (b *pointInTimeBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+func (b *pointInTimeBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+func (b *pointInTimeBucket) GCSName(object *gcs.MinObject) string {
+	return b.wrapped.GCSName(object)
+}
+
+func (b *pointInTimeBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	generation, err := b.pinGeneration(ctx, req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	mReq := *req
+	mReq.Generation = generation
+	return b.wrapped.NewReaderWithReadHandle(ctx, &mReq)
+}
+
+func (b *pointInTimeBucket) NewMultiRangeDownloader(
+	ctx context.Context,
+	req *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	generation, err := b.pinGeneration(ctx, req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	mReq := *req
+	mReq.Generation = generation
+	return b.wrapped.NewMultiRangeDownloader(ctx, &mReq)
+}
+
+func (b *pointInTimeBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	// Like reads, stats of a given generation aren't pinned.
+	if req.Generation != 0 {
+		return b.wrapped.StatObject(ctx, req)
+	}
+	o, err := b.statGeneration(ctx, req.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	var extendedAttributes *gcs.ExtendedObjectAttributes
+	if req.ReturnExtendedObjectAttributes {
+		// Listings don't return the extended attributes of the generations.
+		extendedAttributes = &gcs.ExtendedObjectAttributes{}
+	}
+	return o, extendedAttributes, nil
+}
+
+func (b *pointInTimeBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	// Listings of generations, e.g. of the versions directory, aren't pinned.
+	if req.Versions || req.SoftDeleted {
+		return b.wrapped.ListObjects(ctx, req)
+	}
+	return b.listGenerations(ctx, req)
+}
+
+func (b *pointInTimeBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	// Folders aren't versioned.
+	return b.wrapped.GetFolder(ctx, req)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Forbidden mutations
+////////////////////////////////////////////////////////////////////////
+
+func (b *pointInTimeBucket) CreateObject(ctx context.Context, req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) FinalizeUpload(ctx context.Context, writer gcs.Writer) (*gcs.MinObject, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) FlushPendingWrites(ctx context.Context, writer gcs.Writer) (*gcs.MinObject, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) CopyObject(ctx context.Context, req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) ComposeObjects(ctx context.Context, req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) UpdateObject(ctx context.Context, req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) DeleteObject(ctx context.Context, req *gcs.DeleteObjectRequest) error {
+	return ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	return ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return nil, ErrPointInTimeReadOnly
+}
+
+func (b *pointInTimeBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return nil, ErrPointInTimeReadOnly
+}
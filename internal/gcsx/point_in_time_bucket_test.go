@@ -0,0 +1,191 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PointInTimeBucketTest struct {
+	suite.Suite
+	ctx     context.Context
+	clock   timeutil.SimulatedClock
+	wrapped gcs.Bucket
+	bucket  gcs.Bucket
+}
+
+func TestPointInTimeBucket(t *testing.T) {
+	suite.Run(t, new(PointInTimeBucketTest))
+}
+
+func (t *PointInTimeBucketTest) SetupTest() {
+	t.ctx = context.Background()
+	t.clock.SetTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	t.wrapped = fake.NewFakeBucketWithVersioning(&t.clock, "some_bucket", gcs.BucketType{})
+	t.bucket = gcsx.NewPointInTimeBucket(t.clock.Now().Add(30*time.Minute), t.wrapped)
+
+	// Before the point in time.
+	t.createObject("a", "taco")
+	t.createObject("b", "burrito")
+	t.createObject("dir/c", "enchilada")
+
+	// After the point in time.
+	t.clock.AdvanceTime(time.Hour)
+	t.createObject("a", "queso")
+	require.NoError(t.T(), t.wrapped.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "b"}))
+	t.createObject("d", "nachos")
+}
+
+func (t *PointInTimeBucketTest) createObject(name, contents string) {
+	_, err := storageutil.CreateObject(t.ctx, t.wrapped, name, []byte(contents))
+	require.NoError(t.T(), err)
+}
+
+func (t *PointInTimeBucketTest) read(name string, generation int64) string {
+	r, err := t.bucket.NewReaderWithReadHandle(t.ctx, &gcs.ReadObjectRequest{Name: name, Generation: generation})
+	require.NoError(t.T(), err)
+	defer r.Close()
+	contents, err := io.ReadAll(r)
+	require.NoError(t.T(), err)
+	return string(contents)
+}
+
+func (t *PointInTimeBucketTest) TestListObjects() {
+	req := &gcs.ListObjectsRequest{Delimiter: "/", MaxResults: 1}
+	var names, collapsedRuns []string
+	pages := 0
+	for {
+		listing, err := t.bucket.ListObjects(t.ctx, req)
+		require.NoError(t.T(), err)
+		pages++
+		for _, o := range listing.MinObjects {
+			names = append(names, o.Name)
+		}
+		collapsedRuns = append(collapsedRuns, listing.CollapsedRuns...)
+		if listing.ContinuationToken == "" {
+			break
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+
+	assert.Equal(t.T(), []string{"a", "b"}, names)
+	assert.Equal(t.T(), []string{"dir/"}, collapsedRuns)
+	// The generations of the wrapped bucket are listed a page at a time.
+	assert.Greater(t.T(), pages, 3)
+}
+
+func (t *PointInTimeBucketTest) TestStatObject() {
+	a, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+	require.NoError(t.T(), err)
+	_, _, err = t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "d"})
+
+	assert.EqualValues(t.T(), len("taco"), a.Size)
+	assert.ErrorAs(t.T(), err, new(*gcs.NotFoundError))
+}
+
+func (t *PointInTimeBucketTest) TestReadPinnedGenerations() {
+	a, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+	require.NoError(t.T(), err)
+
+	assert.Equal(t.T(), "taco", t.read("a", a.Generation))
+	// Without a generation, the one live at the point in time is read.
+	assert.Equal(t.T(), "taco", t.read("a", 0))
+	assert.Equal(t.T(), "burrito", t.read("b", 0))
+}
+
+func (t *PointInTimeBucketTest) TestSoftDeletedGenerationsAreSkipped() {
+	t.clock.SetTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	t.wrapped = fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{})
+	t.bucket = gcsx.NewPointInTimeBucket(t.clock.Now().Add(30*time.Minute), t.wrapped)
+	t.createObject("b", "burrito")
+	t.clock.AdvanceTime(time.Hour)
+	require.NoError(t.T(), t.wrapped.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "b"}))
+
+	_, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "b"})
+	listing, listErr := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{})
+
+	// Soft-deleted generations can't be read without restoring them.
+	assert.ErrorAs(t.T(), err, new(*gcs.NotFoundError))
+	require.NoError(t.T(), listErr)
+	assert.Empty(t.T(), listing.MinObjects)
+}
+
+func (t *PointInTimeBucketTest) TestResolvedGenerationsAreCached() {
+	counting := &listCountingBucket{Bucket: t.wrapped}
+	t.bucket = gcsx.NewPointInTimeBucket(t.clock.Now().Add(-30*time.Minute), counting)
+	a, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+	require.NoError(t.T(), err)
+	_, _, err = t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "d"})
+	require.ErrorAs(t.T(), err, new(*gcs.NotFoundError))
+	listings := counting.listings
+	// Modifying what was returned doesn't affect what is cached.
+	a.Name = "modified"
+
+	a, _, err = t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+	_, _, notFoundErr := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "d"})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "a", a.Name)
+	assert.EqualValues(t.T(), len("taco"), a.Size)
+	assert.ErrorAs(t.T(), notFoundErr, new(*gcs.NotFoundError))
+	assert.Equal(t.T(), listings, counting.listings)
+}
+
+func (t *PointInTimeBucketTest) TestStatObjectListsOnlyItsGenerations() {
+	for i := range 250 {
+		t.createObject(fmt.Sprintf("a%03d", i), "salsa")
+	}
+	counting := &listCountingBucket{Bucket: t.wrapped}
+	t.bucket = gcsx.NewPointInTimeBucket(t.clock.Now().Add(-30*time.Minute), counting)
+
+	a, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+
+	require.NoError(t.T(), err)
+	assert.EqualValues(t.T(), len("taco"), a.Size)
+	// The listing stops at the first of the objects with the name as prefix.
+	assert.Equal(t.T(), 1, counting.listings)
+}
+
+// listCountingBucket counts the listings of the wrapped bucket.
+type listCountingBucket struct {
+	gcs.Bucket
+	listings int
+}
+
+func (b *listCountingBucket) ListObjects(ctx context.Context, req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	b.listings++
+	return b.Bucket.ListObjects(ctx, req)
+}
+
+func (t *PointInTimeBucketTest) TestMutationsAreRejected() {
+	_, createErr := t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{Name: "e"})
+	deleteErr := t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "a"})
+
+	assert.ErrorIs(t.T(), createErr, gcsx.ErrPointInTimeReadOnly)
+	assert.ErrorIs(t.T(), deleteErr, gcsx.ErrPointInTimeReadOnly)
+}
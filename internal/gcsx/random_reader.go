@@ -255,6 +255,7 @@ func (rr *randomReader) tryReadingFromFileCache(ctx context.Context,
 
 	// By default, consider read type random if the offset is non-zero.
 	isSeq := offset == 0
+	var ramTierHit bool
 
 	// Request log and start the execution timer.
 	requestId := uuid.New()
@@ -283,9 +284,19 @@ func (rr *randomReader) tryReadingFromFileCache(ctx context.Context,
 		if isSeq {
 			readType = metrics.ReadTypeSequential
 		}
-		captureFileCacheMetrics(ctx, rr.metricHandle, metrics.ReadTypeNames[readType], n, cacheHit, executionTime)
+		captureFileCacheMetrics(ctx, rr.metricHandle, metrics.ReadTypeNames[readType], n, cacheHit, ramTierHit, executionTime)
 	}()
 
+	// Serve the data from memory if all of it is there, falling through to the
+	// file in cache otherwise.
+	ramTier := rr.fileCacheHandler.RAMTier()
+	if ramTier != nil {
+		if n, cacheHit = ramTier.ReadAt(rr.bucket, rr.object, offset, p); cacheHit {
+			ramTierHit = true
+			return
+		}
+	}
+
 	// Create fileCacheHandle if not already.
 	rr.fileCacheMu.Lock()
 	if rr.fileCacheHandle == nil {
@@ -317,6 +328,10 @@ func (rr *randomReader) tryReadingFromFileCache(ctx context.Context,
 		return
 	}
 	n, cacheHit, err = rr.fileCacheHandle.Read(ctx, rr.bucket, rr.object, offset, p)
+	// Data read again from the file in cache is hot, so keep it in memory.
+	if err == nil && cacheHit && ramTier != nil {
+		ramTier.Promote(rr.bucket, rr.object, offset, n, rr.fileCacheHandle)
+	}
 	rr.fileCacheMu.RUnlock()
 	if err == nil {
 		return
@@ -127,6 +127,13 @@ func NewReadManager(object *gcs.MinObject, bucket gcs.Bucket, config *ReadManage
 			ReadTypeClassifier: readClassifier,
 			HandleID:           config.HandleID,
 		}
+		// The in-memory tier of the file cache gives its blocks up to buffered
+		// reads.
+		if config.FileCacheHandler != nil {
+			if ramTier := config.FileCacheHandler.RAMTier(); ramTier != nil {
+				opts.ReclaimBlocks = ramTier.ReleaseBlocks
+			}
+		}
 		bufferedReader, err := bufferedread.NewBufferedReader(opts)
 		if err != nil {
 			logger.Tracef("Failed to create bufferedReader: %v. Buffered reading will be disabled for this file handle.", err)
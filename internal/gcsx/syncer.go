@@ -17,6 +17,7 @@ package gcsx
 import (
 	"fmt"
 	"io"
+	"maps"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
@@ -32,27 +33,44 @@ type Syncer interface {
 	//
 	// *   Otherwise, write out a new generation in the bucket (failing with
 	//     *gcs.PreconditionError if the source generation is no longer current).
+	//
+	// The given metadata keys, if any, are set on the new generation in
+	// addition to the ones of the source object.
 	SyncObject(
 		ctx context.Context,
 		fileName string,
 		srcObject *gcs.Object,
+		metadata map[string]string,
 		content TempFile) (o *gcs.Object, err error)
 }
 
+// SyncerConfig holds the settings of the syncers created by NewSyncer.
+type SyncerConfig struct {
+	// When the source object has been changed only by appending, and the source
+	// object's size is at least AppendThreshold, we will "append" to it by
+	// writing out a temporary blob and composing it with the source object.
+	AppendThreshold int64
+
+	// When the content is at least ParallelCompositeUploadThreshold bytes long
+	// and must be written out in its entirety, we upload it as
+	// ParallelCompositeUploadParts temporary blobs concurrently and compose
+	// them into the object. A threshold of zero disables this.
+	ParallelCompositeUploadThreshold int64
+	ParallelCompositeUploadParts     int
+
+	ChunkRetryDeadlineSecs   int64
+	ChunkTransferTimeoutSecs int64
+
+	// Temporary blobs have names beginning with TmpObjectPrefix. We make an
+	// effort to delete them, but if we are interrupted for some reason we may
+	// not be able to do so. Therefore the user should arrange for garbage
+	// collection.
+	TmpObjectPrefix string
+}
+
 // NewSyncer creates a syncer that syncs into the supplied bucket.
-//
-// When the source object has been changed only by appending, and the source
-// object's size is at least composeThreshold, we will "append" to it by writing
-// out a temporary blob and composing it with the source object.
-//
-// Temporary blobs have names beginning with tmpObjectPrefix. We make an effort
-// to delete them, but if we are interrupted for some reason we may not be able
-// to do so. Therefore the user should arrange for garbage collection.
 func NewSyncer(
-	composeThreshold int64,
-	chunkRetryDeadlineSecs int64,
-	chunkTransferTimeoutSecs int64,
-	tmpObjectPrefix string,
+	config SyncerConfig,
 	bucket gcs.Bucket) (os Syncer) {
 	// Create the object creators.
 	fullCreator := &fullObjectCreator{
@@ -62,14 +80,22 @@ func NewSyncer(
 	// Rapid buckets do not currently support Compose,
 	// so we always write objects in their entirety.
 	var composeCreator objectCreator
+	var parallelCreator objectCreator
 	if !bucket.BucketType().RapidWritesEnabled() {
 		composeCreator = newComposeObjectCreator(
-			tmpObjectPrefix,
+			config.TmpObjectPrefix,
 			bucket)
+
+		if config.ParallelCompositeUploadThreshold > 0 {
+			parallelCreator = newParallelCompositeObjectCreator(
+				config.TmpObjectPrefix,
+				config.ParallelCompositeUploadParts,
+				bucket)
+		}
 	}
 
 	// And the syncer.
-	os = newSyncer(composeThreshold, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs, fullCreator, composeCreator)
+	os = newSyncer(config.AppendThreshold, config.ParallelCompositeUploadThreshold, config.ChunkRetryDeadlineSecs, config.ChunkTransferTimeoutSecs, fullCreator, composeCreator, parallelCreator)
 
 	return
 }
@@ -87,10 +113,12 @@ func (oc *fullObjectCreator) Create(
 	objectName string,
 	srcObject *gcs.Object,
 	mtime *time.Time,
+	metadata map[string]string,
 	chunkRetryDeadlineSecs int64,
 	chunkTransferTimeoutSecs int64,
 	r io.Reader) (o *gcs.Object, err error) {
 	req := gcs.NewCreateObjectRequest(srcObject, objectName, mtime, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs)
+	maps.Copy(req.Metadata, metadata)
 	req.Contents = r
 	o, err = oc.bucket.CreateObject(ctx, req)
 	if err != nil {
@@ -112,13 +140,14 @@ type objectCreator interface {
 		objectName string,
 		srcObject *gcs.Object,
 		mtime *time.Time,
+		metadata map[string]string,
 		chunkRetryDeadlineSecs int64,
 		chunkTransferTimeoutSecs int64,
 		r io.Reader) (o *gcs.Object, err error)
 }
 
 // Create a syncer that stats the mutable content to see if it's dirty before
-// calling through to one of two object creators if the content is dirty:
+// calling through to one of the object creators if the content is dirty:
 //
 //   - fullCreator accepts the source object and the full contents with which it
 //     should be overwritten.
@@ -126,22 +155,30 @@ type objectCreator interface {
 //   - composeCreator accepts the source object and the contents that should be
 //     "appended" to it.
 //
+//   - parallelCreator, if non-nil, is used instead of fullCreator when the
+//     content is at least parallelThreshold bytes long. It accepts the source
+//     object and the full contents as an *io.SectionReader.
+//
 // composeThreshold controls the source object length at which we consider it
 // worthwhile to make the append optimization. It should be set to a value on
 // the order of the bandwidth to GCS times three times the round trip latency
 // to GCS (for a small create, a compose, and a delete).
 func newSyncer(
 	composeThreshold int64,
+	parallelThreshold int64,
 	chunkRetryDeadlineSecs int64,
 	chunkTransferTimeoutSecs int64,
 	fullCreator objectCreator,
-	composeCreator objectCreator) (os Syncer) {
+	composeCreator objectCreator,
+	parallelCreator objectCreator) (os Syncer) {
 	os = &syncer{
 		composeThreshold:         composeThreshold,
+		parallelThreshold:        parallelThreshold,
 		chunkRetryDeadlineSecs:   chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs: chunkTransferTimeoutSecs,
 		fullCreator:              fullCreator,
 		composeCreator:           composeCreator,
+		parallelCreator:          parallelCreator,
 	}
 
 	return
@@ -149,16 +186,25 @@ func newSyncer(
 
 type syncer struct {
 	composeThreshold         int64
+	parallelThreshold        int64
 	chunkRetryDeadlineSecs   int64
 	chunkTransferTimeoutSecs int64
 	fullCreator              objectCreator
 	composeCreator           objectCreator
+	parallelCreator          objectCreator
+}
+
+// useParallelCreator returns true if content of the given size should be
+// written out in its entirety by parallelCreator rather than fullCreator.
+func (os *syncer) useParallelCreator(size int64) bool {
+	return os.parallelCreator != nil && size >= os.parallelThreshold
 }
 
 func (os *syncer) SyncObject(
 	ctx context.Context,
 	objectName string,
 	srcObject *gcs.Object,
+	metadata map[string]string,
 	content TempFile) (o *gcs.Object, err error) {
 	// Stat the content.
 	sr, err := content.Stat()
@@ -177,7 +223,10 @@ func (os *syncer) SyncObject(
 			err = fmt.Errorf("error in seeking: %w", err)
 			return
 		}
-		return os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
+		if os.useParallelCreator(sr.Size) {
+			return os.parallelCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, io.NewSectionReader(content, 0, sr.Size))
+		}
+		return os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
 	}
 
 	// TODO(b/520290147): Remove unfinalized checks once stat results become consistent on zonal buckets.
@@ -237,7 +286,9 @@ func (os *syncer) SyncObject(
 			return
 		}
 
-		o, err = os.composeCreator.Create(ctx, objectName, srcObject, sr.Mtime, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
+		o, err = os.composeCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
+	} else if os.useParallelCreator(sr.Size) {
+		o, err = os.parallelCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, io.NewSectionReader(content, 0, sr.Size))
 	} else {
 		_, err = content.Seek(0, 0)
 		if err != nil {
@@ -245,7 +296,7 @@ func (os *syncer) SyncObject(
 			return
 		}
 
-		o, err = os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
+		o, err = os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
 	}
 
 	// Deal with errors.
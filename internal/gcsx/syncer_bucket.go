@@ -21,17 +21,27 @@ import (
 type SyncerBucket struct {
 	gcs.Bucket
 	Syncer
+
+	// The layers of Bucket below its stat cache, if any, for listings that
+	// shouldn't fill it. Nil if Bucket has no stat cache.
+	uncached gcs.Bucket
+}
+
+// Uncached returns the layers of the bucket below its stat cache, which see the
+// objects as stored in GCS, or the bucket itself if it has no stat cache.
+func (sb SyncerBucket) Uncached() gcs.Bucket {
+	if sb.uncached == nil {
+		return sb.Bucket
+	}
+	return sb.uncached
 }
 
 // NewSyncerBucket creates a SyncerBucket, which can be used either as
 // a gcs.Bucket, or as a Syncer.
 func NewSyncerBucket(
-	appendThreshold int64,
-	chunkRetryDeadlineSecs int64,
-	chunkTransferTimeoutSecs int64,
-	tmpObjectPrefix string,
+	config SyncerConfig,
 	bucket gcs.Bucket,
 ) SyncerBucket {
-	syncer := NewSyncer(appendThreshold, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs, tmpObjectPrefix, bucket)
-	return SyncerBucket{bucket, syncer}
+	syncer := NewSyncer(config, bucket)
+	return SyncerBucket{Bucket: bucket, Syncer: syncer}
 }
@@ -67,6 +67,7 @@ func (t *FullObjectCreatorTest) call() (o *gcs.Object, err error) {
 		t.srcObject.Name,
 		&t.srcObject,
 		&t.mtime,
+		nil,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		strings.NewReader(t.srcContents))
@@ -164,6 +165,37 @@ func (t *FullObjectCreatorTest) CallsCreateObjectsWithObjectProperties() {
 	ExpectEq("test_value", req.Metadata["test_key"])
 }
 
+func (t *FullObjectCreatorTest) CallsCreateObjectWithGivenMetadata() {
+	t.srcObject.Name = "foo"
+	t.srcObject.Metadata = map[string]string{
+		"test_key":  "test_value",
+		"other_key": "old_value",
+	}
+	t.mtime = time.Now().Add(123 * time.Second).UTC()
+
+	var req *gcs.CreateObjectRequest
+	ExpectCall(t.bucket, "CreateObject")(Any(), Any()).
+		WillOnce(DoAll(SaveArg(1, &req), Return(nil, errors.New(""))))
+
+	// Call
+	t.creator.Create(
+		t.ctx,
+		t.srcObject.Name,
+		&t.srcObject,
+		&t.mtime,
+		map[string]string{"other_key": "new_value", "new_key": "value"},
+		chunkRetryDeadlineSecs,
+		chunkTransferTimeoutSecs,
+		strings.NewReader(t.srcContents))
+
+	AssertNe(nil, req)
+	ExpectEq(4, len(req.Metadata))
+	ExpectEq(t.mtime.Format(time.RFC3339Nano), req.Metadata["gcsfuse_mtime"])
+	ExpectEq("test_value", req.Metadata["test_key"])
+	ExpectEq("new_value", req.Metadata["other_key"])
+	ExpectEq("value", req.Metadata["new_key"])
+}
+
 func (t *FullObjectCreatorTest) CallsCreateObjectWhenSrcObjectIsNil() {
 	t.srcContents = "taco"
 	// CreateObject
@@ -177,6 +209,7 @@ func (t *FullObjectCreatorTest) CallsCreateObjectWhenSrcObjectIsNil() {
 		t.srcObject.Name,
 		nil,
 		&t.mtime,
+		nil,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		strings.NewReader(t.srcContents))
@@ -198,6 +231,7 @@ func (t *FullObjectCreatorTest) CallsCreateObjectWhenSrcObjectAndMtimeAreNil() {
 		t.srcObject.Name,
 		nil,
 		nil,
+		nil,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		strings.NewReader(t.srcContents))
@@ -237,6 +271,7 @@ type fakeObjectCreator struct {
 	// Supplied arguments
 	srcObject *gcs.Object
 	mtime     time.Time
+	metadata  map[string]string
 	contents  []byte
 
 	// Canned results
@@ -249,6 +284,7 @@ func (oc *fakeObjectCreator) Create(
 	fileName string,
 	srcObject *gcs.Object,
 	mtime *time.Time,
+	metadata map[string]string,
 	chunkRetryDeadlineSecs int64,
 	chunkTransferTimeoutSecs int64,
 	r io.Reader) (o *gcs.Object, err error) {
@@ -261,6 +297,7 @@ func (oc *fakeObjectCreator) Create(
 	if mtime != nil {
 		oc.mtime = *mtime
 	}
+	oc.metadata = metadata
 	oc.contents, err = io.ReadAll(r)
 	AssertEq(nil, err)
 
@@ -277,12 +314,14 @@ const srcObjectContents = "taco"
 const appendThreshold = int64(len(srcObjectContents))
 const chunkRetryDeadlineSecs = 120
 const chunkTransferTimeoutSecs = 10
+const parallelThreshold = int64(1 << 20)
 
 type SyncerTest struct {
 	ctx context.Context
 
-	fullCreator   fakeObjectCreator
-	appendCreator fakeObjectCreator
+	fullCreator     fakeObjectCreator
+	appendCreator   fakeObjectCreator
+	parallelCreator fakeObjectCreator
 
 	bucket gcs.Bucket
 	syncer Syncer
@@ -304,10 +343,12 @@ func (t *SyncerTest) SetUp(ti *TestInfo) {
 	t.bucket = fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{})
 	t.syncer = newSyncer(
 		appendThreshold,
+		parallelThreshold,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		&t.fullCreator,
-		&t.appendCreator)
+		&t.appendCreator,
+		&t.parallelCreator)
 
 	t.clock.SetTime(time.Date(2015, 4, 5, 2, 15, 0, 0, time.Local))
 
@@ -333,10 +374,11 @@ func (t *SyncerTest) SetUp(ti *TestInfo) {
 	// Return errors from the fakes by default.
 	t.fullCreator.err = errors.New("Fake error")
 	t.appendCreator.err = errors.New("Fake error")
+	t.parallelCreator.err = errors.New("Fake error")
 }
 
 func (t *SyncerTest) call() (o *gcs.Object, err error) {
-	o, err = t.syncer.SyncObject(t.ctx, t.srcObject.Name, t.srcObject, t.content)
+	o, err = t.syncer.SyncObject(t.ctx, t.srcObject.Name, t.srcObject, nil, t.content)
 	return
 }
 
@@ -355,7 +397,7 @@ func (rc dummyReadCloser) Close() error {
 func (t *SyncerTest) SyncObjectShouldInvokeFullObjectCreatorWhenSrcObjectIsNil() {
 	// It doesn't make sense to validate returned object or error since fake
 	// is not handling them.
-	_, _ = t.syncer.SyncObject(t.ctx, t.srcObject.Name, nil, t.content)
+	_, _ = t.syncer.SyncObject(t.ctx, t.srcObject.Name, nil, nil, t.content)
 
 	ExpectTrue(t.fullCreator.called)
 	ExpectFalse(t.appendCreator.called)
@@ -526,10 +568,12 @@ func (t *SyncerTest) SourceTooShortForAppend() {
 	// Recreate the syncer with a higher append threshold.
 	t.syncer = newSyncer(
 		int64(len(srcObjectContents)+1),
+		parallelThreshold,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		&t.fullCreator,
-		&t.appendCreator)
+		&t.appendCreator,
+		&t.parallelCreator)
 
 	// Extend the length of the content.
 	err = t.content.Truncate(int64(len(srcObjectContents) + 1))
@@ -593,6 +637,20 @@ func (t *SyncerTest) CallsFullCreator() {
 	ExpectEq(srcObjectContents[:2], string(t.fullCreator.contents))
 }
 
+func (t *SyncerTest) PassesMetadataToCreator() {
+	metadata := map[string]string{"taco": "burrito"}
+
+	// Append some data.
+	_, err := t.content.WriteAt([]byte("burrito"), int64(t.srcObject.Size))
+	AssertEq(nil, err)
+
+	// Call
+	t.syncer.SyncObject(t.ctx, t.srcObject.Name, t.srcObject, metadata, t.content)
+
+	AssertTrue(t.appendCreator.called)
+	ExpectThat(t.appendCreator.metadata, DeepEquals(metadata))
+}
+
 func (t *SyncerTest) FullCreatorFails() {
 	var err error
 	t.fullCreator.err = errors.New("taco")
@@ -704,3 +762,92 @@ func (t *SyncerTest) AppendCreatorSucceeds() {
 	AssertEq(nil, err)
 	ExpectEq(t.appendCreator.o, o)
 }
+
+func (t *SyncerTest) setParallelThreshold(threshold int64) {
+	t.syncer = newSyncer(
+		appendThreshold,
+		threshold,
+		chunkRetryDeadlineSecs,
+		chunkTransferTimeoutSecs,
+		&t.fullCreator,
+		&t.appendCreator,
+		&t.parallelCreator)
+}
+
+func (t *SyncerTest) CallsParallelCreator() {
+	var err error
+	t.setParallelThreshold(2)
+
+	// Dirty the content.
+	_, err = t.content.WriteAt([]byte("b"), 0)
+	AssertEq(nil, err)
+
+	// Set up an expected mtime.
+	mtime := time.Now().Add(123 * time.Second)
+	t.content.SetMtime(mtime)
+
+	// Call
+	t.call()
+
+	ExpectFalse(t.fullCreator.called)
+	AssertTrue(t.parallelCreator.called)
+	ExpectEq(t.srcObject, t.parallelCreator.srcObject)
+	ExpectThat(t.parallelCreator.mtime, timeutil.TimeEq(mtime))
+	ExpectEq("baco", string(t.parallelCreator.contents))
+}
+
+func (t *SyncerTest) CallsParallelCreatorWhenSrcObjectIsNil() {
+	t.setParallelThreshold(2)
+
+	_, _ = t.syncer.SyncObject(t.ctx, t.srcObject.Name, nil, nil, t.content)
+
+	ExpectFalse(t.fullCreator.called)
+	AssertTrue(t.parallelCreator.called)
+	ExpectEq(srcObjectContents, string(t.parallelCreator.contents))
+}
+
+func (t *SyncerTest) ContentBelowParallelThreshold() {
+	var err error
+	t.setParallelThreshold(int64(len(srcObjectContents) + 1))
+
+	// Dirty the content.
+	_, err = t.content.WriteAt([]byte("b"), 0)
+	AssertEq(nil, err)
+
+	// Call
+	t.call()
+
+	ExpectTrue(t.fullCreator.called)
+	ExpectFalse(t.parallelCreator.called)
+}
+
+func (t *SyncerTest) AppendCreatorPreferredOverParallelCreator() {
+	var err error
+	t.setParallelThreshold(2)
+
+	// Append some data.
+	_, err = t.content.WriteAt([]byte("burrito"), int64(t.srcObject.Size))
+	AssertEq(nil, err)
+
+	// Call
+	t.call()
+
+	ExpectTrue(t.appendCreator.called)
+	ExpectFalse(t.parallelCreator.called)
+}
+
+func (t *SyncerTest) ParallelCreatorFails() {
+	var err error
+	t.setParallelThreshold(2)
+	t.parallelCreator.err = errors.New("taco")
+
+	// Dirty the content.
+	_, err = t.content.WriteAt([]byte("b"), 0)
+	AssertEq(nil, err)
+
+	// Call
+	_, err = t.call()
+
+	ExpectThat(err, Error(HasSubstr("create")))
+	ExpectThat(err, Error(HasSubstr("taco")))
+}
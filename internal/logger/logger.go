@@ -173,6 +173,13 @@ func UpdateDefaultLogger(format, fsName string) {
 	defaultLogger = defaultLoggerFactory.newLoggerWithMountInstanceID(defaultLoggerFactory.level, fsName)
 }
 
+// SetLogSeverity changes the severity of the logs printed from now on, e.g. when
+// the config is reloaded.
+func SetLogSeverity(severity cfg.LogSeverity) {
+	defaultLoggerFactory.level = string(severity)
+	setLoggingLevel(string(severity))
+}
+
 // Tracef prints the message with TRACE severity in the specified format.
 func Tracef(format string, v ...any) {
 	if LevelTrace >= programLevel.Level() {
@@ -322,6 +322,19 @@ func TestSetLoggingLevel(t *testing.T) {
 	}
 }
 
+func TestSetLogSeverity(t *testing.T) {
+	var buf bytes.Buffer
+	redirectLogsToGivenBuffer(&buf, cfg.INFO)
+	t.Cleanup(func() { setLoggingLevel(cfg.INFO) })
+
+	SetLogSeverity(cfg.DebugLogSeverity)
+	Debugf("www.debugExample.com")
+
+	assert.Equal(t, LevelDebug, programLevel.Level())
+	assert.Equal(t, cfg.DEBUG, defaultLoggerFactory.level)
+	assert.Contains(t, buf.String(), "www.debugExample.com")
+}
+
 func TestInitLogFile(t *testing.T) {
 	filePath := filepath.Join(t.TempDir(), "log.txt")
 	format := "text"
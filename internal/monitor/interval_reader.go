@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// exportTimeout bounds each periodic export, like the default of
+// metric.PeriodicReader.
+const exportTimeout = 30 * time.Second
+
+// intervalReader is a metric.Reader that periodically exports the collected
+// metrics to an exporter. Unlike metric.PeriodicReader, the interval can be
+// changed while it's running.
+type intervalReader struct {
+	*metric.ManualReader
+	exporter metric.Exporter
+
+	// Sends new intervals to the export loop.
+	intervals chan time.Duration
+
+	// Closed to stop the export loop, which closes stopped when it has returned.
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newIntervalReader(exporter metric.Exporter, interval time.Duration) *intervalReader {
+	r := &intervalReader{
+		ManualReader: metric.NewManualReader(
+			metric.WithTemporalitySelector(exporter.Temporality),
+			metric.WithAggregationSelector(exporter.Aggregation)),
+		exporter:  exporter,
+		intervals: make(chan time.Duration),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go r.exportPeriodically(interval)
+	return r
+}
+
+func (r *intervalReader) exportPeriodically(interval time.Duration) {
+	defer close(r.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			if err := r.collectAndExport(ctx); err != nil {
+				otel.Handle(err)
+			}
+			cancel()
+		case interval = <-r.intervals:
+			ticker.Reset(interval)
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *intervalReader) collectAndExport(ctx context.Context) error {
+	var rm metricdata.ResourceMetrics
+	if err := r.Collect(ctx, &rm); err != nil {
+		return err
+	}
+	return r.exporter.Export(ctx, &rm)
+}
+
+// SetInterval changes the interval between exports, starting from now. It has
+// no effect once the reader has been shut down.
+func (r *intervalReader) SetInterval(interval time.Duration) {
+	select {
+	case r.intervals <- interval:
+	case <-r.stopped:
+	}
+}
+
+// ForceFlush exports the metrics collected so far.
+func (r *intervalReader) ForceFlush(ctx context.Context) error {
+	if err := r.collectAndExport(ctx); err != nil {
+		return err
+	}
+	return r.exporter.ForceFlush(ctx)
+}
+
+// Shutdown stops the periodic exports, exports the metrics collected so far
+// and shuts down the exporter.
+func (r *intervalReader) Shutdown(ctx context.Context) error {
+	r.stopOnce.Do(func() { close(r.done) })
+	<-r.stopped
+
+	exportErr := r.collectAndExport(ctx)
+	return errors.Join(exportErr, r.ManualReader.Shutdown(ctx), r.exporter.Shutdown(ctx))
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func newCountingReader(interval time.Duration) (*intervalReader, chan struct{}) {
+	exports := make(chan struct{}, 100)
+	mock := &mockExporter{
+		exportFunc: func(context.Context, *metricdata.ResourceMetrics) error {
+			exports <- struct{}{}
+			return nil
+		},
+	}
+	r := newIntervalReader(mock, interval)
+	metric.NewMeterProvider(metric.WithReader(r))
+	return r, exports
+}
+
+func TestIntervalReader_ExportsPeriodically(t *testing.T) {
+	r, exports := newCountingReader(10 * time.Millisecond)
+	defer r.Shutdown(context.Background())
+
+	for range 2 {
+		select {
+		case <-exports:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an export")
+		}
+	}
+}
+
+func TestIntervalReader_SetInterval(t *testing.T) {
+	r, exports := newCountingReader(time.Hour)
+	defer r.Shutdown(context.Background())
+
+	r.SetInterval(10 * time.Millisecond)
+
+	select {
+	case <-exports:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an export")
+	}
+}
+
+func TestIntervalReader_ShutdownExportsAndStops(t *testing.T) {
+	r, exports := newCountingReader(time.Hour)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	assert.Len(t, exports, 1)
+	// Changing the interval after shutdown must not block.
+	r.SetInterval(time.Millisecond)
+}
+
+func TestSetCloudMetricsExportInterval_Disabled(t *testing.T) {
+	cloudMetricsReader.Store(nil)
+
+	assert.NoError(t, SetCloudMetricsExportInterval(0))
+	assert.Error(t, SetCloudMetricsExportInterval(10))
+}
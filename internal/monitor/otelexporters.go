@@ -44,6 +44,9 @@ const cloudMonitoringMetricPrefix = "custom.googleapis.com/gcsfuse/"
 
 var allowedMetricPrefixes = []string{"fs/", "gcs/", "file_cache/", "buffered_read/", "grpc.", "read/"}
 
+// cloudMetricsReader exports the metrics to Cloud Monitoring, if enabled.
+var cloudMetricsReader atomic.Pointer[intervalReader]
+
 // SetupOTelMetricExporters sets up the metrics exporters
 func SetupOTelMetricExporters(ctx context.Context, c *cfg.Config, mountID string) (shutdownFn common.ShutdownFn) {
 	var shutdownFns []common.ShutdownFn
@@ -104,10 +107,25 @@ func setupCloudMonitoring(secs int64) []metric.Option {
 		Exporter: exporter,
 	}
 
-	reader := metric.NewPeriodicReader(wrappedExporter, metric.WithInterval(time.Duration(secs)*time.Second))
+	reader := newIntervalReader(wrappedExporter, time.Duration(secs)*time.Second)
+	cloudMetricsReader.Store(reader)
 	return []metric.Option{metric.WithReader(reader)}
 }
 
+// SetCloudMetricsExportInterval changes the interval at which metrics are
+// exported to Cloud Monitoring. The export can't be enabled or disabled after
+// the metric exporters have been set up.
+func SetCloudMetricsExportInterval(secs int64) error {
+	reader := cloudMetricsReader.Load()
+	if (reader != nil) != (secs > 0) {
+		return fmt.Errorf("cloud metrics export can't be enabled or disabled without remounting")
+	}
+	if reader != nil {
+		reader.SetInterval(time.Duration(secs) * time.Second)
+	}
+	return nil
+}
+
 // permissionAwareExporter wraps a metric.Exporter and disables itself if it encounters
 // a PermissionDenied error. This prevents log spam when the environment lacks
 // necessary permissions.
@@ -47,6 +47,14 @@ func (m *mockExporter) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+func (m *mockExporter) Temporality(k metric.InstrumentKind) metricdata.Temporality {
+	return metric.DefaultTemporalitySelector(k)
+}
+
+func (m *mockExporter) Aggregation(k metric.InstrumentKind) metric.Aggregation {
+	return metric.DefaultAggregationSelector(k)
+}
+
 func TestPermissionAwareExporter_ExportSuccess(t *testing.T) {
 	mock := &mockExporter{}
 	exporter := &permissionAwareExporter{Exporter: mock}
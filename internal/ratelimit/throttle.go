@@ -33,7 +33,13 @@ type Throttle interface {
 	// return early with an error.
 	//
 	// REQUIRES: tokens <= capacity
+	//
+	// Fails if the capacity is concurrently lowered below tokens by SetRate.
 	Wait(ctx context.Context, tokens uint64) (err error)
+
+	// Change the rate and capacity of the underlying token bucket. Calls to Wait
+	// that are already sleeping are not affected.
+	SetRate(rateHz float64, capacity uint64)
 }
 
 type limiter struct {
@@ -56,3 +62,10 @@ func (l *limiter) Wait(
 	tokens uint64) (err error) {
 	return l.WaitN(ctx, int(tokens))
 }
+
+func (l *limiter) SetRate(
+	rateHz float64,
+	capacity uint64) {
+	l.SetLimit(rate.Limit(rateHz))
+	l.SetBurst(int(capacity))
+}
@@ -40,11 +40,15 @@ func (fr *funcReader) Read(p []byte) (n int, err error) {
 
 // A throttler that defers to a function.
 type funcThrottle struct {
-	f func(context.Context, uint64) error
+	f        func(context.Context, uint64) error
+	capacity uint64
 }
 
 func (ft *funcThrottle) Capacity() (c uint64) {
-	return 1024
+	return ft.capacity
+}
+
+func (ft *funcThrottle) SetRate(rateHz float64, capacity uint64) {
 }
 
 func (ft *funcThrottle) Wait(
@@ -79,6 +83,7 @@ func (t *ThrottledReaderTest) SetupTest() {
 	t.throttle.f = func(ctx context.Context, tokens uint64) (err error) {
 		return
 	}
+	t.throttle.capacity = 1024
 
 	// Set up the reader.
 	t.reader = ThrottledReader(t.ctx, &t.wrapped, &t.throttle)
@@ -113,6 +118,32 @@ func (t *ThrottledReaderTest) TestCallsThrottle() {
 	assert.True(t.T(), throttleCalled)
 }
 
+func (t *ThrottledReaderTest) TestCapacityLoweredWhileWaiting() {
+	// Throttle
+	var calls []uint64
+	t.throttle.f = func(ctx context.Context, tokens uint64) (err error) {
+		calls = append(calls, tokens)
+		if len(calls) == 1 {
+			t.throttle.capacity = 10
+			err = errors.New("exceeds burst")
+		}
+		return
+	}
+
+	// Wrapped
+	t.wrapped.f = func(p []byte) (n int, err error) {
+		n = len(p)
+		return
+	}
+
+	// Call
+	n, err := t.reader.Read(make([]byte, 100))
+
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), 10, n)
+	assert.Equal(t.T(), []uint64{100, 10}, calls)
+}
+
 func (t *ThrottledReaderTest) TestThrottleReturnsError() {
 	// Throttle
 	expectedErr := errors.New("taco")
@@ -202,3 +202,16 @@ func (t *ThrottleTest) TestIntegration() {
 			fmt.Sprintf("Test case %d. expected: %f", i, expected))
 	}
 }
+
+func (t *ThrottleTest) TestSetRate() {
+	throttle := ratelimit.NewThrottle(1, 1)
+
+	throttle.SetRate(1000, 10)
+
+	assert.Equal(t.T(), uint64(10), throttle.Capacity())
+	// Waiting for the full capacity shouldn't take long at the new rate.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t.T(), throttle.Wait(ctx, 10))
+	assert.NoError(t.T(), throttle.Wait(ctx, 10))
+}
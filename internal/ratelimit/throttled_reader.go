@@ -41,15 +41,22 @@ type throttledReader struct {
 }
 
 func (tr *throttledReader) Read(p []byte) (n int, err error) {
-	// We can't serve a read larger than the throttle's capacity.
-	if uint64(len(p)) > tr.throttle.Capacity() {
-		p = p[:tr.throttle.Capacity()]
-	}
+	// Wait for permission to continue. We can't serve a read larger than the
+	// throttle's capacity, which may be lowered by SetRate while we wait, in
+	// which case we try again with the new capacity.
+	for {
+		capacity := tr.throttle.Capacity()
+		if uint64(len(p)) > capacity {
+			p = p[:capacity]
+		}
 
-	// Wait for permission to continue.
-	err = tr.throttle.Wait(tr.ctx, uint64(len(p)))
-	if err != nil {
-		return
+		err = tr.throttle.Wait(tr.ctx, uint64(len(p)))
+		if err == nil {
+			break
+		}
+		if uint64(len(p)) <= tr.throttle.Capacity() {
+			return
+		}
 	}
 
 	// Serve the full amount we acquired from the throttle (unless we hit an
@@ -33,6 +33,7 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
 	"google.golang.org/api/iterator"
+	storagev1 "google.golang.org/api/storage/v1"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -41,12 +42,18 @@ const FullBucketPathHNS = "projects/_/buckets/%s"
 
 type bucketHandle struct {
 	gcs.Bucket
-	bucket         *storage.BucketHandle
+	bucket *storage.BucketHandle
+	// rawObjects sends the JSON API requests that the storage client can't
+	// express.
+	rawObjects     *storagev1.ObjectsService
 	bucketName     string
 	bucketType     *gcs.BucketType
 	controlClient  StorageControlClient
 	billingProject string
 	writeConfig    *cfg.WriteConfig
+	// encryptionKey is the customer-supplied encryption key sent with every
+	// object request, if non-nil.
+	encryptionKey []byte
 }
 
 func (bh *bucketHandle) Name() string {
@@ -57,6 +64,16 @@ func (bh *bucketHandle) BucketType() gcs.BucketType {
 	return *bh.bucketType
 }
 
+// object returns a handle to the object with the given name, set up to use the
+// customer-supplied encryption key of the bucket if there is one.
+func (bh *bucketHandle) object(name string) *storage.ObjectHandle {
+	obj := bh.bucket.Object(name)
+	if bh.encryptionKey != nil {
+		obj = obj.Key(bh.encryptionKey)
+	}
+	return obj
+}
+
 func (bh *bucketHandle) NewReaderWithReadHandle(
 	ctx context.Context,
 	req *gcs.ReadObjectRequest) (reader gcs.StorageReader, err error) {
@@ -79,7 +96,7 @@ func (bh *bucketHandle) NewReaderWithReadHandle(
 		length = end - start
 	}
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// Switching to the requested generation of object.
 	if req.Generation != 0 {
@@ -111,7 +128,7 @@ func (bh *bucketHandle) DeleteObject(ctx context.Context, req *gcs.DeleteObjectR
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// Switching to the requested generation of the object. By default, generation
 	// is 0 which signifies the latest generation. Note: GCS will delete the
@@ -138,9 +155,14 @@ func (bh *bucketHandle) StatObject(ctx context.Context,
 		err = gcs.GetGCSError(err)
 	}()
 
+	obj := bh.object(req.Name)
+	if req.Generation != 0 {
+		obj = obj.Generation(req.Generation)
+	}
+
 	var attrs *storage.ObjectAttrs
 	// Retrieving object attrs through Go Storage Client.
-	attrs, err = bh.bucket.Object(req.Name).Attrs(ctx)
+	attrs, err = obj.Attrs(ctx)
 	if err != nil {
 		err = fmt.Errorf("error in fetching object attributes: %w", err)
 		return
@@ -157,7 +179,7 @@ func (bh *bucketHandle) StatObject(ctx context.Context,
 }
 
 func (bh *bucketHandle) getObjectHandleWithPreconditionsSet(req *gcs.CreateObjectRequest) *storage.ObjectHandle {
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// GenerationPrecondition - If non-nil, the object will be created/overwritten
 	// only if the current generation for the object name is equal to the given value.
@@ -326,8 +348,8 @@ func (bh *bucketHandle) CopyObject(ctx context.Context, req *gcs.CopyObjectReque
 		err = gcs.GetGCSError(err)
 	}()
 
-	srcObj := bh.bucket.Object(req.SrcName)
-	dstObj := bh.bucket.Object(req.DstName)
+	srcObj := bh.object(req.SrcName)
+	dstObj := bh.object(req.DstName)
 
 	// Switching to the requested generation of source object.
 	if req.SrcGeneration != 0 {
@@ -377,6 +399,8 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 		IncludeTrailingDelimiter: req.IncludeTrailingDelimiter,
 		IncludeFoldersAsPrefixes: req.IncludeFoldersAsPrefixes,
 		StartOffset:              req.StartOffset,
+		Versions:                 req.Versions,
+		SoftDeleted:              req.SoftDeleted,
 		//MaxResults: , (Field not present in storage.Query of Go Storage Library but present in ListObjectsQuery in Jacobsa code.)
 	}
 	minObjAttrs := []string{"Name", "Size", "Generation", "Metageneration", "Updated", "Metadata", "ContentEncoding", "CRC32C"}
@@ -385,6 +409,9 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 		// For objects in regional buckets, this field will be *unset*.
 		minObjAttrs = append(minObjAttrs, "Finalized")
 	}
+	if req.Versions || req.SoftDeleted {
+		minObjAttrs = append(minObjAttrs, "Created", "Deleted", "SoftDeleteTime")
+	}
 	err = query.SetAttrSelection(minObjAttrs)
 
 	if err != nil {
@@ -425,6 +452,15 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 			// Converting attrs to *Object type.
 			currMinObject := storageutil.ObjectAttrsToMinObject(attrs)
 			list.MinObjects = append(list.MinObjects, currMinObject)
+			if req.Versions || req.SoftDeleted {
+				// Noncurrent generations have a deletion time, soft-deleted ones a
+				// soft delete time.
+				deleted := attrs.Deleted
+				if deleted.IsZero() {
+					deleted = attrs.SoftDeleteTime
+				}
+				list.GenerationTimes = append(list.GenerationTimes, gcs.GenerationTimes{Created: attrs.Created, Deleted: deleted})
+			}
 		}
 
 		// itr.next returns all the objects present in the bucket. Hence adding a
@@ -449,7 +485,7 @@ func (bh *bucketHandle) UpdateObject(ctx context.Context, req *gcs.UpdateObjectR
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	if req.Generation != 0 {
 		obj = obj.Generation(req.Generation)
@@ -482,6 +518,9 @@ func (bh *bucketHandle) UpdateObject(ctx context.Context, req *gcs.UpdateObjectR
 		for key, element := range req.Metadata {
 			if element != nil {
 				updateQuery.Metadata[key] = *element
+			} else {
+				// The storage client can't delete single metadata keys.
+				return bh.deleteMetadataKeys(ctx, req)
 			}
 		}
 	}
@@ -498,12 +537,72 @@ func (bh *bucketHandle) UpdateObject(ctx context.Context, req *gcs.UpdateObjectR
 	return
 }
 
+// deleteMetadataKeys applies an update that deletes some metadata keys, those
+// mapped to nil in req. The storage client merges metadata maps into the
+// existing metadata and can only delete all keys at once, so the update is
+// sent as a single JSON API patch that nulls the deleted keys.
+func (bh *bucketHandle) deleteMetadataKeys(ctx context.Context, req *gcs.UpdateObjectRequest) (o *gcs.Object, err error) {
+	patch := &storagev1.Object{}
+	if req.ContentType != nil {
+		patch.ContentType = *req.ContentType
+		// As in the storage client, an empty content type deletes it.
+		if patch.ContentType == "" {
+			patch.NullFields = append(patch.NullFields, "ContentType")
+		}
+	}
+	if req.ContentEncoding != nil {
+		patch.ContentEncoding = *req.ContentEncoding
+		patch.ForceSendFields = append(patch.ForceSendFields, "ContentEncoding")
+	}
+	if req.ContentLanguage != nil {
+		patch.ContentLanguage = *req.ContentLanguage
+		patch.ForceSendFields = append(patch.ForceSendFields, "ContentLanguage")
+	}
+	if req.CacheControl != nil {
+		patch.CacheControl = *req.CacheControl
+		patch.ForceSendFields = append(patch.ForceSendFields, "CacheControl")
+	}
+	// The map must be sent, even if empty, for the nulled keys to be sent.
+	patch.Metadata = make(map[string]string)
+	patch.ForceSendFields = append(patch.ForceSendFields, "Metadata")
+	for key, element := range req.Metadata {
+		if element == nil {
+			patch.NullFields = append(patch.NullFields, "Metadata."+key)
+		} else {
+			patch.Metadata[key] = *element
+		}
+	}
+
+	call := bh.rawObjects.Patch(bh.bucketName, req.Name, patch).Projection("full").Context(ctx)
+	if req.Generation != 0 {
+		call = call.Generation(req.Generation)
+	}
+	if req.MetaGenerationPrecondition != nil {
+		call = call.IfMetagenerationMatch(*req.MetaGenerationPrecondition)
+	}
+	if bh.billingProject != "" {
+		call = call.UserProject(bh.billingProject)
+	}
+	if bh.encryptionKey != nil {
+		storageutil.SetEncryptionKeyHeaders(call.Header(), bh.encryptionKey)
+	}
+
+	obj, err := call.Do()
+	if err != nil {
+		err = fmt.Errorf("error in updating object: %w", err)
+		return
+	}
+
+	o, err = storageutil.ObjectToBucketObject(obj)
+	return
+}
+
 func (bh *bucketHandle) ComposeObjects(ctx context.Context, req *gcs.ComposeObjectsRequest) (o *gcs.Object, err error) {
 	defer func() {
 		err = gcs.GetGCSError(err)
 	}()
 
-	dstObj := bh.bucket.Object(req.DstName)
+	dstObj := bh.object(req.DstName)
 
 	dstObjConds := storage.Conditions{}
 	if req.DstMetaGenerationPrecondition != nil {
@@ -529,6 +628,8 @@ func (bh *bucketHandle) ComposeObjects(ctx context.Context, req *gcs.ComposeObje
 	// Converting the req.Sources list to a list of storage.ObjectHandle as expected by the Go Storage Client.
 	var srcObjList []*storage.ObjectHandle
 	for _, src := range req.Sources {
+		// The customer-supplied encryption key of the destination, if any, is
+		// used for the sources too; the client library rejects keys on them.
 		currSrcObj := bh.bucket.Object(src.Name)
 		// Switching to requested Generation of the object.
 		// Zero src generation is the latest generation, we are skipping it because by default it will take the latest one
@@ -539,7 +640,8 @@ func (bh *bucketHandle) ComposeObjects(ctx context.Context, req *gcs.ComposeObje
 	}
 
 	// Composing Source Objects to Destination Object using Composer created through Go Storage Client.
-	attrs, err := dstObj.ComposerFrom(srcObjList...).Run(ctx)
+	composer := storageutil.SetAttrsInComposer(dstObj.ComposerFrom(srcObjList...), req)
+	attrs, err := composer.Run(ctx)
 	if err != nil {
 		err = fmt.Errorf("error in composing object: %w", err)
 		return
@@ -569,7 +671,7 @@ func (bh *bucketHandle) MoveObject(ctx context.Context, req *gcs.MoveObjectReque
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.SrcName)
+	obj := bh.object(req.SrcName)
 
 	// Switching to the requested generation of source object.
 	if req.SrcGeneration != 0 {
@@ -675,7 +777,7 @@ func (bh *bucketHandle) NewMultiRangeDownloader(
 		err = gcs.GetGCSError(err)
 	}()
 
-	obj := bh.bucket.Object(req.Name)
+	obj := bh.object(req.Name)
 
 	// Switching to the requested generation of object.
 	if req.Generation != 0 {
@@ -15,11 +15,19 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,6 +40,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -1147,6 +1157,43 @@ func (testSuite *BucketHandleTest) TestComposeObjectMethodWithOneSrcObject() {
 	assert.Equal(testSuite.T(), srcBuffer, dstBuffer)
 	assert.NotNil(testSuite.T(), composedObj)
 	assert.Equal(testSuite.T(), srcMinObj.Size, composedObj.Size)
+	assert.Equal(testSuite.T(), ContentType, composedObj.ContentType)
+	assert.Equal(testSuite.T(), MetaDataValue, composedObj.Metadata[MetaDataKey])
+}
+
+func (testSuite *BucketHandleTest) TestComposeObjectMethodSetsDstAttributes() {
+	createBucketHandle(testSuite, &controlpb.StorageLayout{})
+	mtime := "2026-01-02T03:04:05Z"
+
+	_, err := testSuite.bucketHandle.ComposeObjects(context.Background(),
+		&gcs.ComposeObjectsRequest{
+			DstName: dstObjectName,
+			Sources: []gcs.ComposeSource{
+				{Name: TestObjectName},
+				{Name: TestSubObjectName},
+			},
+			ContentType:     ContentType,
+			ContentLanguage: ContentLanguage,
+			CacheControl:    CacheControl,
+			Metadata: map[string]string{
+				MetaDataKey:          MetaDataValue,
+				gcs.MtimeMetadataKey: mtime,
+			},
+		})
+
+	require.NoError(testSuite.T(), err)
+	// The attributes of a composed object are those of the request, as for
+	// an object composed from the parts of a parallel composite upload.
+	minObj, extendedAttrs, err := testSuite.bucketHandle.StatObject(context.Background(),
+		&gcs.StatObjectRequest{
+			Name:                           dstObjectName,
+			ReturnExtendedObjectAttributes: true,
+		})
+	require.NoError(testSuite.T(), err)
+	assert.Equal(testSuite.T(), map[string]string{MetaDataKey: MetaDataValue, gcs.MtimeMetadataKey: mtime}, minObj.Metadata)
+	assert.Equal(testSuite.T(), ContentType, extendedAttrs.ContentType)
+	assert.Equal(testSuite.T(), ContentLanguage, extendedAttrs.ContentLanguage)
+	assert.Equal(testSuite.T(), CacheControl, extendedAttrs.CacheControl)
 }
 
 func (testSuite *BucketHandleTest) TestComposeObjectMethodWithTwoSrcObjects() {
@@ -1731,3 +1778,230 @@ func (testSuite *BucketHandleTest) TestCreateFolderWithGivenName() {
 	assert.NoError(testSuite.T(), err)
 	assert.Equal(testSuite.T(), gcs.GCSFolder(TestBucketName, &mockFolder), folder)
 }
+
+// encryptionKeyRecorder is an HTTP handler answering every request with a 404,
+// which records the customer-supplied encryption key headers of the requests.
+type encryptionKeyRecorder struct {
+	mu   sync.Mutex
+	keys map[string]string // GUARDED_BY(mu)
+}
+
+func (r *encryptionKeyRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.keys[req.Method+" "+req.URL.Path] = req.Header.Get("X-Goog-Encryption-Key")
+	if copySourceKey := req.Header.Get("X-Goog-Copy-Source-Encryption-Key"); copySourceKey != "" {
+		r.keys["copy source"] = copySourceKey
+	}
+	r.mu.Unlock()
+	http.Error(w, `{"error": {"code": 404, "message": "Not Found"}}`, http.StatusNotFound)
+}
+
+func TestBucketHandle_SendsCustomerSuppliedEncryptionKey(t *testing.T) {
+	ctx := context.Background()
+	recorder := &encryptionKeyRecorder{keys: make(map[string]string)}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+	client, err := storage.NewClient(ctx, option.WithEndpoint(server.URL+"/storage/v1/"), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+	key := bytes.Repeat([]byte{0x2a}, 32)
+	bh := &bucketHandle{
+		bucket:        client.Bucket(TestBucketName),
+		bucketName:    TestBucketName,
+		bucketType:    &gcs.BucketType{},
+		writeConfig:   &cfg.WriteConfig{},
+		encryptionKey: key,
+	}
+
+	_, _, err = bh.StatObject(ctx, &gcs.StatObjectRequest{Name: TestObjectName})
+	assert.Error(t, err)
+	_, err = bh.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{Name: TestObjectName})
+	assert.Error(t, err)
+	_, err = bh.CreateObject(ctx, &gcs.CreateObjectRequest{Name: TestObjectName, Contents: strings.NewReader(ContentInTestObject)})
+	assert.Error(t, err)
+	_, err = bh.CopyObject(ctx, &gcs.CopyObjectRequest{SrcName: TestObjectName, DstName: dstObjectName})
+	assert.Error(t, err)
+	_, err = bh.ComposeObjects(ctx, &gcs.ComposeObjectsRequest{DstName: dstObjectName, Sources: []gcs.ComposeSource{{Name: TestObjectName}}})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.NotEmpty(t, recorder.keys)
+	for request, gotKey := range recorder.keys {
+		assert.Equal(t, encodedKey, gotKey, request)
+	}
+	assert.Contains(t, recorder.keys, "copy source")
+}
+
+// metadataPatchServer is an HTTP handler serving a single object through the
+// JSON API, which applies metadata patches the way GCS does and records the
+// generation and meta-generation conditions of the requests.
+type metadataPatchServer struct {
+	mu             sync.Mutex
+	generation     int64             // GUARDED_BY(mu)
+	metaGeneration int64             // GUARDED_BY(mu)
+	metadata       map[string]string // GUARDED_BY(mu)
+	requests       []string          // GUARDED_BY(mu)
+	lastHeader     http.Header       // GUARDED_BY(mu)
+	lastQuery      url.Values        // GUARDED_BY(mu)
+}
+
+func (s *metadataPatchServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := req.URL.Query()
+	s.requests = append(s.requests, fmt.Sprintf("%s generation=%q ifMetagenerationMatch=%q", req.Method, query.Get("generation"), query.Get("ifMetagenerationMatch")))
+	if query.Has("generation") && query.Get("generation") != fmt.Sprint(s.generation) {
+		http.Error(w, `{"error": {"code": 404, "message": "Not Found"}}`, http.StatusNotFound)
+		return
+	}
+	if query.Has("ifMetagenerationMatch") && query.Get("ifMetagenerationMatch") != fmt.Sprint(s.metaGeneration) {
+		http.Error(w, `{"error": {"code": 412, "message": "Precondition Failed"}}`, http.StatusPreconditionFailed)
+		return
+	}
+
+	if req.Method == http.MethodPatch {
+		var patch struct {
+			Metadata map[string]*string `json:"metadata"`
+		}
+		raw := make(map[string]json.RawMessage)
+		body, _ := io.ReadAll(req.Body)
+		if json.Unmarshal(body, &raw) != nil || json.Unmarshal(body, &patch) != nil {
+			http.Error(w, `{"error": {"code": 400, "message": "Bad Request"}}`, http.StatusBadRequest)
+			return
+		}
+		if value, ok := raw["metadata"]; ok && string(value) == "null" {
+			s.metadata = make(map[string]string)
+		}
+		for key, value := range patch.Metadata {
+			if value == nil {
+				delete(s.metadata, key)
+			} else {
+				s.metadata[key] = *value
+			}
+		}
+		s.metaGeneration++
+	}
+	s.lastHeader = req.Header.Clone()
+	s.lastQuery = query
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"bucket":         TestBucketName,
+		"name":           TestObjectName,
+		"generation":     fmt.Sprint(s.generation),
+		"metageneration": fmt.Sprint(s.metaGeneration),
+		"metadata":       s.metadata,
+	})
+}
+
+func newMetadataPatchBucketHandle(t *testing.T, server *metadataPatchServer) *bucketHandle {
+	t.Helper()
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	clientOpts := []option.ClientOption{option.WithEndpoint(httpServer.URL + "/storage/v1/"), option.WithoutAuthentication()}
+	client, err := storage.NewClient(context.Background(), clientOpts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	rawService, err := storagev1.NewService(context.Background(), clientOpts...)
+	require.NoError(t, err)
+	return &bucketHandle{
+		bucket:      client.Bucket(TestBucketName),
+		rawObjects:  rawService.Objects,
+		bucketName:  TestBucketName,
+		bucketType:  &gcs.BucketType{},
+		writeConfig: &cfg.WriteConfig{},
+	}
+}
+
+func TestBucketHandle_UpdateObjectDeletingMetadataKeepsGeneration(t *testing.T) {
+	server := &metadataPatchServer{
+		generation:     TestObjectGeneration,
+		metaGeneration: 3,
+		metadata:       map[string]string{MetaDataKey: MetaDataValue, "other_key": "other"},
+	}
+	bh := newMetadataPatchBucketHandle(t, server)
+	metaGeneration := int64(3)
+
+	o, err := bh.UpdateObject(context.Background(), &gcs.UpdateObjectRequest{
+		Name:                       TestObjectName,
+		Generation:                 TestObjectGeneration,
+		MetaGenerationPrecondition: &metaGeneration,
+		Metadata:                   map[string]*string{MetaDataKey: nil},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"other_key": "other"}, o.Metadata)
+	assert.EqualValues(t, TestObjectGeneration, o.Generation)
+	assert.EqualValues(t, 4, o.MetaGeneration)
+	assert.Equal(t, []string{
+		fmt.Sprintf("PATCH generation=\"%d\" ifMetagenerationMatch=\"3\"", TestObjectGeneration),
+	}, server.requests)
+}
+
+func TestBucketHandle_UpdateObjectDeletingMetadataOfLatestGeneration(t *testing.T) {
+	server := &metadataPatchServer{
+		generation:     TestObjectGeneration,
+		metaGeneration: 1,
+		metadata:       map[string]string{MetaDataKey: MetaDataValue},
+	}
+	bh := newMetadataPatchBucketHandle(t, server)
+
+	o, err := bh.UpdateObject(context.Background(), &gcs.UpdateObjectRequest{
+		Name:     TestObjectName,
+		Metadata: map[string]*string{MetaDataKey: nil},
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, o.Metadata)
+	assert.EqualValues(t, TestObjectGeneration, o.Generation)
+	assert.Equal(t, []string{
+		"PATCH generation=\"\" ifMetagenerationMatch=\"\"",
+	}, server.requests)
+}
+
+func TestBucketHandle_UpdateObjectDeletingMetadataWithStaleMetaGeneration(t *testing.T) {
+	server := &metadataPatchServer{
+		generation:     TestObjectGeneration,
+		metaGeneration: 2,
+		metadata:       map[string]string{MetaDataKey: MetaDataValue},
+	}
+	bh := newMetadataPatchBucketHandle(t, server)
+	staleMetaGeneration := int64(1)
+
+	_, err := bh.UpdateObject(context.Background(), &gcs.UpdateObjectRequest{
+		Name:                       TestObjectName,
+		Generation:                 TestObjectGeneration,
+		MetaGenerationPrecondition: &staleMetaGeneration,
+		Metadata:                   map[string]*string{MetaDataKey: nil},
+	})
+
+	var preconditionErr *gcs.PreconditionError
+	assert.ErrorAs(t, err, &preconditionErr)
+	assert.Equal(t, map[string]string{MetaDataKey: MetaDataValue}, server.metadata)
+	assert.EqualValues(t, 2, server.metaGeneration)
+}
+
+func TestBucketHandle_UpdateObjectDeletingMetadataSetsOtherKeys(t *testing.T) {
+	server := &metadataPatchServer{
+		generation:     TestObjectGeneration,
+		metaGeneration: 1,
+		metadata:       map[string]string{MetaDataKey: MetaDataValue, "other_key": "other"},
+	}
+	bh := newMetadataPatchBucketHandle(t, server)
+	bh.billingProject = "billing-project"
+	bh.encryptionKey = bytes.Repeat([]byte{0x01}, 32)
+	newValue := "new"
+
+	o, err := bh.UpdateObject(context.Background(), &gcs.UpdateObjectRequest{
+		Name:     TestObjectName,
+		Metadata: map[string]*string{MetaDataKey: nil, "new_key": &newValue},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"other_key": "other", "new_key": "new"}, o.Metadata)
+	assert.Equal(t, "billing-project", server.lastQuery.Get("userProject"))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(bh.encryptionKey), server.lastHeader.Get("x-goog-encryption-key"))
+}
@@ -40,6 +40,21 @@ func (cme *CacheMissError) Error() string {
 	return fmt.Sprintf("CacheMissError: %v", cme.Err)
 }
 
+// A FastStatBucket is a bucket that caches object records. See
+// NewFastStatBucket.
+type FastStatBucket interface {
+	gcs.Bucket
+
+	// SetCacheTTLs changes the TTLs of the records cached from now on, e.g. when
+	// the config is reloaded. Records that are already cached keep their
+	// expiration.
+	SetCacheTTLs(primaryCacheTTL time.Duration, negativeCacheTTL time.Duration)
+
+	// EraseEntriesWithGivenPrefix invalidates the cached records of the objects
+	// and folders whose names start with the given prefix.
+	EraseEntriesWithGivenPrefix(prefix string)
+}
+
 // Create a bucket that caches object records returned by the supplied wrapped
 // bucket. Records are invalidated when modifications are made through this
 // bucket, and after the supplied TTL.
@@ -51,7 +66,7 @@ func NewFastStatBucket(
 	negativeCacheTTL time.Duration,
 	isTypeCacheDeprecated bool,
 	implicitDir bool,
-) (b gcs.Bucket) {
+) (b FastStatBucket) {
 	fsb := &fastStatBucket{
 		cache:                 cache,
 		clock:                 clock,
@@ -79,15 +94,19 @@ type fastStatBucket struct {
 	clock   timeutil.Clock
 	wrapped gcs.Bucket
 
-	/////////////////////////
-	// Constant data
-	/////////////////////////
-
 	// TTL for entries for existing files and folders in the cache.
+	//
+	// GUARDED_BY(mu)
 	primaryCacheTTL time.Duration
 	// TTL for entries for non-existing files and folders in the cache.
+	//
+	// GUARDED_BY(mu)
 	negativeCacheTTL time.Duration
 
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
 	// Flag to enable deprecation logic of Type cache.
 	isTypeCacheDeprecated bool
 
@@ -98,6 +117,15 @@ type fastStatBucket struct {
 // Helpers
 ////////////////////////////////////////////////////////////////////////
 
+// LOCKS_EXCLUDED(b.mu)
+func (b *fastStatBucket) SetCacheTTLs(primaryCacheTTL time.Duration, negativeCacheTTL time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.primaryCacheTTL = primaryCacheTTL
+	b.negativeCacheTTL = negativeCacheTTL
+}
+
 // LOCKS_EXCLUDED(b.mu)
 func (b *fastStatBucket) insertMultiple(objs []*gcs.Object) {
 	b.mu.Lock()
@@ -180,7 +208,7 @@ func (b *fastStatBucket) insertMultipleMinObjects(ctx context.Context, minObjs [
 }
 
 // LOCKS_EXCLUDED(b.mu)
-func (b *fastStatBucket) eraseEntriesWithGivenPrefix(folderName string) {
+func (b *fastStatBucket) EraseEntriesWithGivenPrefix(folderName string) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -409,6 +437,11 @@ func (b *fastStatBucket) StatObject(
 	if !req.ForceFetchFromGcs && req.ReturnExtendedObjectAttributes {
 		panic("invalid StatObjectRequest: ForceFetchFromGcs: false and ReturnExtendedObjectAttributes: true")
 	}
+	// The cache holds the latest generations only, so others are fetched from
+	// GCS and not cached.
+	if req.Generation != 0 {
+		return b.wrapped.StatObject(ctx, req)
+	}
 	// If fetching from gcs is enabled, directly make a call to GCS.
 	if req.ForceFetchFromGcs {
 		m, e, err = b.StatObjectFromGcs(ctx, req)
@@ -455,6 +488,12 @@ func (b *fastStatBucket) ListObjects(
 		return
 	}
 
+	// Listings of other generations than the live ones say nothing about the
+	// current state of the bucket.
+	if req.Versions || req.SoftDeleted {
+		return
+	}
+
 	if b.BucketType().Hierarchical {
 		b.insertHierarchicalListing(ctx, listing)
 		return
@@ -619,7 +658,7 @@ func (b *fastStatBucket) RenameFolder(ctx context.Context, folderName string, de
 	}
 
 	// Invalidate cache for old directory.
-	b.eraseEntriesWithGivenPrefix(folderName)
+	b.EraseEntriesWithGivenPrefix(folderName)
 	// Insert destination folder.
 	b.insertFolder(f)
 
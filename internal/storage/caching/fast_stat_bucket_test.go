@@ -680,6 +680,35 @@ func (t *StatObjectTest) IgnoresCacheEntryWhenForceFetchFromGcsIsTrue() {
 	ExpectEq(extObjAttrFromGcs, e)
 }
 
+func (t *StatObjectTest) GenerationIsNotCached() {
+	const name = "taco"
+
+	// Lookup
+	ExpectCall(t.cache, "LookUp")(Any(), Any()).Times(0)
+
+	// Request
+	req := &gcs.StatObjectRequest{
+		Name:       name,
+		Generation: 17,
+	}
+
+	// Wrapped
+	minObjFromGcs := &gcs.MinObject{
+		Name:       name,
+		Generation: 17,
+	}
+
+	ExpectCall(t.wrapped, "StatObject")(Any(), req).
+		WillOnce(Return(minObjFromGcs, nil, nil))
+
+	// Insert
+	ExpectCall(t.cache, "Insert")(Any(), Any()).Times(0)
+
+	m, _, err := t.bucket.StatObject(context.TODO(), req)
+	AssertEq(nil, err)
+	ExpectEq(minObjFromGcs, m)
+}
+
 func (t *StatObjectTest) TestStatObject_ForceFetchFromGcsTrueAndReturnExtendedObjectAttributesFalse() {
 	const name = "taco"
 
@@ -822,6 +851,40 @@ func (t *StatObjectTest) WrappedSucceeds() {
 	ExpectEq(minObj, m)
 }
 
+func (t *StatObjectTest) UsesUpdatedCacheTTLs() {
+	const name = "taco"
+	t.bucket.(caching.FastStatBucket).SetCacheTTLs(2*primaryCacheTTL, 2*negativeCacheTTL)
+
+	// LookUp
+	ExpectCall(t.cache, "LookUp")(Any(), Any()).
+		WillRepeatedly(Return(false, nil))
+
+	// Wrapped
+	minObj := &gcs.MinObject{
+		Name: name,
+	}
+
+	ExpectCall(t.wrapped, "StatObject")(Any(), Any()).
+		WillOnce(Return(minObj, nil, nil)).
+		WillOnce(Return(nil, nil, &gcs.NotFoundError{Err: errors.New("burrito")}))
+
+	// Insert and AddNegativeEntry
+	ExpectCall(t.cache, "Insert")(Any(), timeutil.TimeEq(t.clock.Now().Add(2*primaryCacheTTL)))
+	ExpectCall(t.cache, "AddNegativeEntry")(
+		name,
+		timeutil.TimeEq(t.clock.Now().Add(2*negativeCacheTTL)))
+
+	// Call
+	req := &gcs.StatObjectRequest{
+		Name: name,
+	}
+
+	_, _, err := t.bucket.StatObject(context.TODO(), req)
+	AssertEq(nil, err)
+	_, _, err = t.bucket.StatObject(context.TODO(), req)
+	ExpectThat(err, HasSameTypeAs(&gcs.NotFoundError{}))
+}
+
 ////////////////////////////////////////////////////////////////////////
 // ListObjects
 ////////////////////////////////////////////////////////////////////////
@@ -902,6 +965,22 @@ func (t *ListObjectsTest) NonEmptyListing() {
 	ExpectEq(expected, listing)
 }
 
+func (t *ListObjectsTest) VersionsListingNotCached() {
+	// Wrapped
+	expected := &gcs.Listing{
+		MinObjects: []*gcs.MinObject{{Name: "taco", Generation: 1}, {Name: "taco", Generation: 2}},
+	}
+
+	ExpectCall(t.wrapped, "ListObjects")(Any(), Any()).
+		WillOnce(Return(expected, nil))
+
+	// Call
+	listing, err := t.bucket.ListObjects(context.TODO(), &gcs.ListObjectsRequest{Versions: true})
+
+	AssertEq(nil, err)
+	ExpectEq(expected, listing)
+}
+
 func (t *ListObjectsTest) NonEmptyListingForHNS() {
 	// wrapped
 	o0 := &gcs.MinObject{Name: "taco"}
@@ -1743,3 +1822,19 @@ func (t *GetFolderTest) FetchOnlyFromCacheTrue_CacheMiss() {
 
 	ExpectThat(err, HasSameTypeAs(&caching.CacheMissError{}))
 }
+
+////////////////////////////////////////////////////////////////////////
+// EraseEntriesWithGivenPrefix
+////////////////////////////////////////////////////////////////////////
+
+type EraseEntriesWithGivenPrefixTest struct {
+	fastStatBucketTest
+}
+
+func init() { RegisterTestSuite(&EraseEntriesWithGivenPrefixTest{}) }
+
+func (t *EraseEntriesWithGivenPrefixTest) CallsCache() {
+	ExpectCall(t.cache, "EraseEntriesWithGivenPrefix")("foo/")
+
+	t.bucket.(caching.FastStatBucket).EraseEntriesWithGivenPrefix("foo/")
+}
@@ -0,0 +1,462 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"google.golang.org/api/googleapi"
+)
+
+// ChaosBucketParams configures the faults injected by a bucket created by
+// NewChaosBucket.
+type ChaosBucketParams struct {
+	// Seed of the random faults.
+	Seed int64
+
+	// Rates of the errors to inject into the operations, each as
+	// "<method>:<HTTP status>:<rate>", where the method is that of gcs.Bucket
+	// or * for all of them, and the status one of 404, 412, 429 and 503.
+	ErrorRates []string
+
+	// Fraction of the readers that stall at a random offset, and for how long.
+	ReadStallRate     float64
+	ReadStallDuration time.Duration
+
+	// Fraction of the readers that fail with io.ErrUnexpectedEOF at a random
+	// offset.
+	TruncatedReadRate float64
+
+	// Latency added to the uploads of each MiB.
+	WritePerMBLatency time.Duration
+}
+
+// chaosError returns the error the wrapped bucket returns for the given HTTP
+// status.
+func chaosError(method string, name string, code int) error {
+	err := &googleapi.Error{
+		Code:    code,
+		Message: fmt.Sprintf("chaos: injected into %s(%q)", method, name),
+	}
+	switch code {
+	case http.StatusNotFound:
+		return &gcs.NotFoundError{Err: err}
+	case http.StatusPreconditionFailed:
+		return &gcs.PreconditionError{Err: err}
+	}
+	return err
+}
+
+// chaosBucket is a wrapper over gcs.Bucket that injects random faults into the
+// operations of the wrapped bucket: errors, stalled and truncated readers and
+// slow uploads.
+type chaosBucket struct {
+	wrapped    gcs.Bucket
+	params     ChaosBucketParams
+	errorRates []cfg.ChaosErrorRate
+
+	mu   sync.Mutex
+	rand *rand.Rand // GUARDED_BY(mu)
+}
+
+// NewChaosBucket creates a new chaosBucket wrapping the given gcs.Bucket. The
+// faults are drawn from a generator seeded with params.Seed, so that the same
+// sequence of operations meets the same faults; concurrent operations draw in
+// the order they are issued in.
+func NewChaosBucket(wrapped gcs.Bucket, params ChaosBucketParams) (gcs.Bucket, error) {
+	b := &chaosBucket{
+		wrapped: wrapped,
+		params:  params,
+		rand:    rand.New(rand.NewPCG(uint64(params.Seed), 0)),
+	}
+	for _, spec := range params.ErrorRates {
+		r, err := cfg.ParseChaosErrorRate(spec)
+		if err != nil {
+			return nil, fmt.Errorf("NewChaosBucket: %w", err)
+		}
+		b.errorRates = append(b.errorRates, r)
+	}
+	return b, nil
+}
+
+// draw tells whether a fault with the given rate occurs.
+func (b *chaosBucket) draw(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rand.Float64() < rate
+}
+
+// offset returns a random offset within a range of the given length, or zero
+// if it's unknown.
+func (b *chaosBucket) offset(length uint64) int64 {
+	if length == 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rand.Int64N(int64(min(length, math.MaxInt64)))
+}
+
+// injectedError returns the error to inject into a call of the given method
+// on the named object or folder, if any.
+func (b *chaosBucket) injectedError(method string, name string) error {
+	for _, r := range b.errorRates {
+		if (r.Method == "*" || r.Method == method) && b.draw(r.Rate) {
+			logger.Warnf("Chaos: injecting a %d error into %s(%q)", r.Code, method, name)
+			return chaosError(method, name, r.Code)
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the bucket.
+func (b *chaosBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+// BucketType returns the type of the bucket.
+func (b *chaosBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+// NewReaderWithReadHandle creates a reader for reading object contents, which
+// may stall or be truncated at a random offset of the requested range.
+func (b *chaosBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	if err := b.injectedError("NewReaderWithReadHandle", req.Name); err != nil {
+		return nil, err
+	}
+	rd, err := b.wrapped.NewReaderWithReadHandle(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var length uint64
+	if req.Range != nil && req.Range.Limit > req.Range.Start {
+		length = req.Range.Limit - req.Range.Start
+	}
+	r := &chaosReader{
+		StorageReader: rd,
+		ctx:           ctx,
+		name:          req.Name,
+		stallAt:       -1,
+		truncateAt:    -1,
+	}
+	if b.draw(b.params.ReadStallRate) {
+		r.stallAt = b.offset(length)
+		r.stallDuration = b.params.ReadStallDuration
+	}
+	if b.draw(b.params.TruncatedReadRate) {
+		r.truncateAt = b.offset(length)
+	}
+	return r, nil
+}
+
+// NewMultiRangeDownloader creates a multi-range downloader for object contents.
+// Only errors are injected into it.
+func (b *chaosBucket) NewMultiRangeDownloader(
+	ctx context.Context,
+	req *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	if err := b.injectedError("NewMultiRangeDownloader", req.Name); err != nil {
+		return nil, err
+	}
+	return b.wrapped.NewMultiRangeDownloader(ctx, req)
+}
+
+// CreateObject creates or overwrites an object, reading its contents slowly.
+func (b *chaosBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	if err := b.injectedError("CreateObject", req.Name); err != nil {
+		return nil, err
+	}
+	if b.params.WritePerMBLatency > 0 {
+		mReq := *req
+		mReq.Contents = &slowReader{Reader: req.Contents, perMBLatency: b.params.WritePerMBLatency}
+		req = &mReq
+	}
+	return b.wrapped.CreateObject(ctx, req)
+}
+
+// CreateObjectChunkWriter creates a writer for resumable uploads, which writes
+// slowly.
+func (b *chaosBucket) CreateObjectChunkWriter(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest,
+	chunkSize int,
+	callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	if err := b.injectedError("CreateObjectChunkWriter", req.Name); err != nil {
+		return nil, err
+	}
+	w, err := b.wrapped.CreateObjectChunkWriter(ctx, req, chunkSize, callBack)
+	if err != nil {
+		return nil, err
+	}
+	return b.slowDown(w), nil
+}
+
+// CreateAppendableObjectWriter creates a writer to append to an existing
+// object, which writes slowly.
+func (b *chaosBucket) CreateAppendableObjectWriter(
+	ctx context.Context,
+	req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	if err := b.injectedError("CreateAppendableObjectWriter", req.Name); err != nil {
+		return nil, err
+	}
+	w, err := b.wrapped.CreateAppendableObjectWriter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return b.slowDown(w), nil
+}
+
+// FinalizeUpload completes the write operation and creates the object on GCS.
+// An injected error leaves the upload unfinalized.
+func (b *chaosBucket) FinalizeUpload(
+	ctx context.Context,
+	writer gcs.Writer) (*gcs.MinObject, error) {
+	if err := b.injectedError("FinalizeUpload", writer.ObjectName()); err != nil {
+		return nil, err
+	}
+	return b.wrapped.FinalizeUpload(ctx, unwrapSlowWriter(writer))
+}
+
+// FlushPendingWrites flushes pending data in the writer buffer for zonal buckets.
+func (b *chaosBucket) FlushPendingWrites(
+	ctx context.Context,
+	writer gcs.Writer) (*gcs.MinObject, error) {
+	if err := b.injectedError("FlushPendingWrites", writer.ObjectName()); err != nil {
+		return nil, err
+	}
+	return b.wrapped.FlushPendingWrites(ctx, unwrapSlowWriter(writer))
+}
+
+// CopyObject copies an object to a new name.
+func (b *chaosBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	if err := b.injectedError("CopyObject", req.DstName); err != nil {
+		return nil, err
+	}
+	return b.wrapped.CopyObject(ctx, req)
+}
+
+// ComposeObjects composes one or more source objects into a single destination object.
+func (b *chaosBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	if err := b.injectedError("ComposeObjects", req.DstName); err != nil {
+		return nil, err
+	}
+	return b.wrapped.ComposeObjects(ctx, req)
+}
+
+// StatObject returns current information about the object.
+func (b *chaosBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	if err := b.injectedError("StatObject", req.Name); err != nil {
+		return nil, nil, err
+	}
+	return b.wrapped.StatObject(ctx, req)
+}
+
+// ListObjects lists the objects in the bucket that meet the criteria.
+func (b *chaosBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	if err := b.injectedError("ListObjects", req.Prefix); err != nil {
+		return nil, err
+	}
+	return b.wrapped.ListObjects(ctx, req)
+}
+
+// UpdateObject updates the object specified by request.
+func (b *chaosBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	if err := b.injectedError("UpdateObject", req.Name); err != nil {
+		return nil, err
+	}
+	return b.wrapped.UpdateObject(ctx, req)
+}
+
+// DeleteObject deletes an object.
+func (b *chaosBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	if err := b.injectedError("DeleteObject", req.Name); err != nil {
+		return err
+	}
+	return b.wrapped.DeleteObject(ctx, req)
+}
+
+// MoveObject moves an object to a new name.
+func (b *chaosBucket) MoveObject(
+	ctx context.Context,
+	req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	if err := b.injectedError("MoveObject", req.SrcName); err != nil {
+		return nil, err
+	}
+	return b.wrapped.MoveObject(ctx, req)
+}
+
+// DeleteFolder deletes a folder.
+func (b *chaosBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	if err := b.injectedError("DeleteFolder", folderName); err != nil {
+		return err
+	}
+	return b.wrapped.DeleteFolder(ctx, folderName)
+}
+
+// GetFolder retrieves folder information.
+func (b *chaosBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	if err := b.injectedError("GetFolder", req.Name); err != nil {
+		return nil, err
+	}
+	return b.wrapped.GetFolder(ctx, req)
+}
+
+// RenameFolder atomically renames a folder for Hierarchical bucket.
+func (b *chaosBucket) RenameFolder(
+	ctx context.Context,
+	folderName string,
+	destinationFolderId string) (*gcs.Folder, error) {
+	if err := b.injectedError("RenameFolder", folderName); err != nil {
+		return nil, err
+	}
+	return b.wrapped.RenameFolder(ctx, folderName, destinationFolderId)
+}
+
+// CreateFolder creates a new folder.
+func (b *chaosBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	if err := b.injectedError("CreateFolder", folderName); err != nil {
+		return nil, err
+	}
+	return b.wrapped.CreateFolder(ctx, folderName)
+}
+
+// GCSName returns the original GCS name for the object.
+func (b *chaosBucket) GCSName(object *gcs.MinObject) string {
+	return b.wrapped.GCSName(object)
+}
+
+////////////////////////////////////////////////////////////////////////
+// chaosReader
+////////////////////////////////////////////////////////////////////////
+
+// chaosReader is a reader that stalls and fails at the given offsets, if not
+// negative.
+type chaosReader struct {
+	gcs.StorageReader
+
+	// The context of the reader, which interrupts stalls.
+	ctx  context.Context
+	name string
+
+	stallAt       int64
+	stallDuration time.Duration
+	truncateAt    int64
+
+	// The number of bytes read so far.
+	offset int64
+}
+
+func (r *chaosReader) Read(p []byte) (int, error) {
+	// Stop at the next fault.
+	if r.stallAt == r.offset {
+		r.stallAt = -1
+		logger.Warnf("Chaos: stalling the read of %q at offset %d for %v", r.name, r.offset, r.stallDuration)
+		select {
+		case <-time.After(r.stallDuration):
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		}
+	} else if r.stallAt > r.offset {
+		p = p[:min(int64(len(p)), r.stallAt-r.offset)]
+	}
+
+	if r.truncateAt >= 0 {
+		if r.truncateAt == r.offset {
+			logger.Warnf("Chaos: truncating the read of %q at offset %d", r.name, r.offset)
+			return 0, fmt.Errorf("chaos: truncated read of %q at offset %d: %w", r.name, r.offset, io.ErrUnexpectedEOF)
+		}
+		p = p[:min(int64(len(p)), r.truncateAt-r.offset)]
+	}
+
+	n, err := r.StorageReader.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+////////////////////////////////////////////////////////////////////////
+// Slow uploads
+////////////////////////////////////////////////////////////////////////
+
+// slowReader is a reader that sleeps for the time its reads would take at the
+// given latency.
+type slowReader struct {
+	io.Reader
+	perMBLatency time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	time.Sleep(calculateLatency(int64(n), r.perMBLatency))
+	return n, err
+}
+
+// slowWriter is a writer that sleeps for the time its writes would take at
+// the given latency.
+type slowWriter struct {
+	gcs.Writer
+	perMBLatency time.Duration
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(calculateLatency(int64(len(p)), w.perMBLatency))
+	return w.Writer.Write(p)
+}
+
+// slowDown returns the given writer, slowed down if requested.
+func (b *chaosBucket) slowDown(w gcs.Writer) gcs.Writer {
+	if b.params.WritePerMBLatency <= 0 {
+		return w
+	}
+	return &slowWriter{Writer: w, perMBLatency: b.params.WritePerMBLatency}
+}
+
+// unwrapSlowWriter returns the writer of the wrapped bucket behind the given
+// one.
+func unwrapSlowWriter(w gcs.Writer) gcs.Writer {
+	if cw, ok := w.(*slowWriter); ok {
+		return cw.Writer
+	}
+	return w
+}
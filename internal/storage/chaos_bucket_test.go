@@ -0,0 +1,204 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+var chaosTestContents = bytes.Repeat([]byte("0123456789"), 10000)
+
+func newChaosTestBucket(t *testing.T, params ChaosBucketParams) (gcs.Bucket, gcs.Bucket) {
+	t.Helper()
+	wrapped := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	_, err := storageutil.CreateObject(context.Background(), wrapped, "foo", chaosTestContents)
+	require.NoError(t, err)
+	b, err := NewChaosBucket(wrapped, params)
+	require.NoError(t, err)
+	return b, wrapped
+}
+
+func readChaosTestObject(ctx context.Context, b gcs.Bucket) ([]byte, error) {
+	r, err := b.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{
+		Name:  "foo",
+		Range: &gcs.ByteRange{Start: 0, Limit: uint64(len(chaosTestContents))},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func TestNewChaosBucket_InvalidErrorRates(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{"missing_rate", "StatObject:503", "isn't of the form"},
+		{"unknown_method", "Stat:503:0.1", `unknown method "Stat"`},
+		{"invalid_status", "StatObject:abc:0.1", "invalid syntax"},
+		{"unsupported_status", "StatObject:500:0.1", "unsupported status 500"},
+		{"invalid_rate", "StatObject:503:often", "invalid syntax"},
+		{"rate_above_one", "*:429:2", "isn't between 0 and 1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewChaosBucket(&TestifyMockBucket{}, ChaosBucketParams{ErrorRates: []string{tc.spec}})
+
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestChaosBucket_InjectsErrors(t *testing.T) {
+	b, _ := newChaosTestBucket(t, ChaosBucketParams{
+		ErrorRates: []string{"StatObject:503:1", "ListObjects:404:1", "DeleteObject:412:1", "CopyObject:429:1"},
+	})
+	ctx := context.Background()
+
+	_, _, err := b.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	var apiErr *googleapi.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code)
+	_, err = b.ListObjects(ctx, &gcs.ListObjectsRequest{})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+	err = b.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: "foo"})
+	var preconditionErr *gcs.PreconditionError
+	assert.ErrorAs(t, err, &preconditionErr)
+	_, err = b.CopyObject(ctx, &gcs.CopyObjectRequest{SrcName: "foo", DstName: "bar"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.Code)
+	// Other operations are left alone.
+	contents, err := readChaosTestObject(ctx, b)
+	require.NoError(t, err)
+	assert.Equal(t, chaosTestContents, contents)
+}
+
+func TestChaosBucket_AllMethods(t *testing.T) {
+	b, _ := newChaosTestBucket(t, ChaosBucketParams{ErrorRates: []string{"*:503:1"}})
+
+	_, err := readChaosTestObject(context.Background(), b)
+
+	var apiErr *googleapi.Error
+	assert.ErrorAs(t, err, &apiErr)
+}
+
+func TestChaosBucket_SeedMakesErrorsDeterministic(t *testing.T) {
+	outcomes := func(seed int64) []bool {
+		b, _ := newChaosTestBucket(t, ChaosBucketParams{Seed: seed, ErrorRates: []string{"StatObject:503:0.5"}})
+		var failed []bool
+		for range 100 {
+			_, _, err := b.StatObject(context.Background(), &gcs.StatObjectRequest{Name: "foo"})
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+
+	first := outcomes(42)
+
+	assert.Equal(t, first, outcomes(42))
+	assert.NotEqual(t, first, outcomes(43))
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
+
+func TestChaosBucket_TruncatedRead(t *testing.T) {
+	b, _ := newChaosTestBucket(t, ChaosBucketParams{TruncatedReadRate: 1})
+
+	contents, err := readChaosTestObject(context.Background(), b)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Less(t, len(contents), len(chaosTestContents))
+	assert.Equal(t, chaosTestContents[:len(contents)], contents)
+}
+
+func TestChaosBucket_StalledRead(t *testing.T) {
+	b, _ := newChaosTestBucket(t, ChaosBucketParams{ReadStallRate: 1, ReadStallDuration: 50 * time.Millisecond})
+	start := time.Now()
+
+	contents, err := readChaosTestObject(context.Background(), b)
+
+	require.NoError(t, err)
+	assert.Equal(t, chaosTestContents, contents)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestChaosBucket_StalledReadIsInterruptedByContext(t *testing.T) {
+	b, _ := newChaosTestBucket(t, ChaosBucketParams{ReadStallRate: 1, ReadStallDuration: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := readChaosTestObject(ctx, b)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestChaosBucket_SlowWrites(t *testing.T) {
+	// 100 KB at 1s per MiB take around 95ms.
+	b, wrapped := newChaosTestBucket(t, ChaosBucketParams{WritePerMBLatency: time.Second})
+	ctx := context.Background()
+	start := time.Now()
+	_, err := storageutil.CreateObject(ctx, b, "bar", chaosTestContents)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	start = time.Now()
+	w, err := b.CreateObjectChunkWriter(ctx, &gcs.CreateObjectRequest{Name: "baz"}, 1<<20, nil)
+	require.NoError(t, err)
+	_, err = w.Write(chaosTestContents)
+	require.NoError(t, err)
+	o, err := b.FinalizeUpload(ctx, w)
+
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, uint64(len(chaosTestContents)), o.Size)
+	contents, err := storageutil.ReadObject(ctx, wrapped, "baz")
+	require.NoError(t, err)
+	assert.Equal(t, chaosTestContents, contents)
+}
+
+func TestChaosBucket_FinalizeUploadFailure(t *testing.T) {
+	b, wrapped := newChaosTestBucket(t, ChaosBucketParams{ErrorRates: []string{"FinalizeUpload:503:1"}})
+	ctx := context.Background()
+	w, err := b.CreateObjectChunkWriter(ctx, &gcs.CreateObjectRequest{Name: "bar"}, 1<<20, nil)
+	require.NoError(t, err)
+	_, err = w.Write(chaosTestContents)
+	require.NoError(t, err)
+
+	_, err = b.FinalizeUpload(ctx, w)
+
+	var apiErr *googleapi.Error
+	assert.ErrorAs(t, err, &apiErr)
+	_, _, err = wrapped.StatObject(ctx, &gcs.StatObjectRequest{Name: "bar"})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
@@ -26,6 +26,7 @@ import (
 	"maps"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -45,6 +46,16 @@ func NewFakeBucket(clock timeutil.Clock, name string, bucketType gcs.BucketType)
 	return b
 }
 
+// NewFakeBucketWithVersioning is like NewFakeBucket, except that the bucket
+// has object versioning enabled: the replaced and deleted generations of the
+// objects remain readable as noncurrent generations instead of being
+// soft-deleted.
+func NewFakeBucketWithVersioning(clock timeutil.Clock, name string, bucketType gcs.BucketType) gcs.Bucket {
+	b := &bucket{clock: clock, name: name, bucketType: bucketType, versioning: true}
+	b.mu = syncutil.NewInvariantMutex(b.checkInvariants)
+	return b
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Helper types
 ////////////////////////////////////////////////////////////////////////
@@ -179,6 +190,15 @@ type bucket struct {
 	//
 	// INVARIANT: This is an upper bound for generation numbers in objects.
 	prevGeneration int64 // GUARDED_BY(mu)
+
+	// Whether replaced and deleted generations are kept as noncurrent ones
+	// rather than soft-deleted.
+	versioning bool
+
+	// The replaced and deleted generations of objects, with their contents if
+	// versioning is enabled, in the order they were archived.
+	noncurrent  []fakeObject // GUARDED_BY(mu)
+	softDeleted []gcs.Object // GUARDED_BY(mu)
 }
 
 func checkName(name string) (err error) {
@@ -412,6 +432,9 @@ func createOrUpdateFakeObject(b *bucket, req *gcs.CreateObjectRequest, contents
 			content = contents
 		}
 		fo = b.mintObject(req, content)
+		if !isAppend {
+			b.archiveLocked(existingIndex)
+		}
 		b.objects[existingIndex] = fo
 	} else {
 		fo = b.mintObject(req, contents)
@@ -475,12 +498,17 @@ func (b *bucket) newReaderLocked(
 		return
 	}
 
-	// Extract the requested range.
-	result := o.data
+	r = rangeReader(o.data, req.Range)
+	return
+}
+
+// Extract the requested range of the supplied contents.
+func rangeReader(data []byte, byteRange *gcs.ByteRange) io.Reader {
+	result := data
 
-	if req.Range != nil {
-		start := req.Range.Start
-		limit := req.Range.Limit
+	if byteRange != nil {
+		start := byteRange.Start
+		limit := byteRange.Limit
 		l := uint64(len(result))
 
 		if start > limit {
@@ -500,9 +528,106 @@ func (b *bucket) newReaderLocked(
 		result = result[start:limit]
 	}
 
-	r = bytes.NewReader(result)
+	return bytes.NewReader(result)
+}
 
-	return
+// archiveLocked records the live generation at the given index of b.objects,
+// which is about to be replaced or deleted, as noncurrent if versioning is
+// enabled and as soft-deleted otherwise.
+//
+// LOCKS_REQUIRED(b.mu)
+func (b *bucket) archiveLocked(index int) {
+	o := b.objects[index]
+	o.metadata.Deleted = b.clock.Now()
+	if b.versioning {
+		b.noncurrent = append(b.noncurrent, o)
+		return
+	}
+	b.softDeleted = append(b.softDeleted, o.metadata)
+}
+
+// Create a reader for a noncurrent generation of an object.
+//
+// LOCKS_REQUIRED(b.mu)
+func (b *bucket) newNoncurrentReaderLocked(req *gcs.ReadObjectRequest) (io.Reader, bool) {
+	for _, o := range b.noncurrent {
+		if o.metadata.Name == req.Name && o.metadata.Generation == req.Generation {
+			return rangeReader(o.data, req.Range), true
+		}
+	}
+	return nil, false
+}
+
+// List the live and noncurrent generations of the objects, or only the
+// soft-deleted ones, ordered by name and generation.
+//
+// LOCKS_REQUIRED(b.mu)
+func (b *bucket) listGenerationsLocked(req *gcs.ListObjectsRequest) *gcs.Listing {
+	var candidates []*gcs.Object
+	if req.SoftDeleted {
+		for i := range b.softDeleted {
+			candidates = append(candidates, &b.softDeleted[i])
+		}
+	} else {
+		for i := range b.objects {
+			candidates = append(candidates, &b.objects[i].metadata)
+		}
+		for i := range b.noncurrent {
+			candidates = append(candidates, &b.noncurrent[i].metadata)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Name != candidates[j].Name {
+			return candidates[i].Name < candidates[j].Name
+		}
+		return candidates[i].Generation < candidates[j].Generation
+	})
+
+	// Handle defaults.
+	maxResults := req.MaxResults
+	if maxResults == 0 {
+		maxResults = 1000
+	}
+
+	// The continuation token is the number of results of the previous pages,
+	// each generation and collapsed run being a result.
+	start, _ := strconv.Atoi(req.ContinuationToken)
+	results := 0
+	lastRun := ""
+	listing := new(gcs.Listing)
+	for _, o := range candidates {
+		if !strings.HasPrefix(o.Name, req.Prefix) {
+			continue
+		}
+		run := ""
+		if req.Delimiter != "" {
+			if i := strings.Index(o.Name[len(req.Prefix):], req.Delimiter); i >= 0 {
+				run = o.Name[:len(req.Prefix)+i+len(req.Delimiter)]
+				if run == lastRun {
+					continue
+				}
+				lastRun = run
+			}
+		}
+
+		results++
+		if results <= start {
+			continue
+		}
+		if results > start+maxResults {
+			listing.ContinuationToken = strconv.Itoa(start + maxResults)
+			break
+		}
+		if run != "" {
+			listing.CollapsedRuns = append(listing.CollapsedRuns, run)
+			continue
+		}
+		listing.MinObjects = append(listing.MinObjects, copyMinObject(o))
+		// The fake doesn't track creation times, objects are created when last
+		// updated.
+		listing.GenerationTimes = append(listing.GenerationTimes, gcs.GenerationTimes{Created: o.Updated, Deleted: o.Deleted})
+	}
+	return listing
 }
 
 func minInt(a, b int) int {
@@ -563,6 +688,11 @@ func (b *bucket) ListObjects(
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	if req.Versions || req.SoftDeleted {
+		listing = b.listGenerationsLocked(req)
+		return
+	}
+
 	// Set up the result object.
 	listing = new(gcs.Listing)
 
@@ -681,6 +811,12 @@ func (b *bucket) NewReaderWithReadHandle(ctx context.Context, req *gcs.ReadObjec
 	defer b.mu.Unlock()
 
 	r, _, err := b.newReaderLocked(req)
+	var notFoundErr *gcs.NotFoundError
+	if errors.As(err, &notFoundErr) && req.Generation != 0 {
+		if noncurrent, ok := b.newNoncurrentReaderLocked(req); ok {
+			r, err = noncurrent, nil
+		}
+	}
 	if err != nil {
 		return
 	}
@@ -812,6 +948,7 @@ func (b *bucket) CopyObject(
 	// Insert into our array.
 	existingIndex := b.objects.find(req.DstName)
 	if existingIndex < len(b.objects) {
+		b.archiveLocked(existingIndex)
 		b.objects[existingIndex] = dst
 	} else {
 		b.objects = append(b.objects, dst)
@@ -909,7 +1046,17 @@ func (b *bucket) StatObject(ctx context.Context,
 
 	// Does the object exist?
 	index := b.objects.find(req.Name)
-	if index == len(b.objects) {
+	var found *gcs.Object
+	if index < len(b.objects) && (req.Generation == 0 || b.objects[index].metadata.Generation == req.Generation) {
+		found = &b.objects[index].metadata
+	} else if req.Generation != 0 {
+		for i := range b.noncurrent {
+			if b.noncurrent[i].metadata.Name == req.Name && b.noncurrent[i].metadata.Generation == req.Generation {
+				found = &b.noncurrent[i].metadata
+			}
+		}
+	}
+	if found == nil {
 		err = &gcs.NotFoundError{
 			Err: fmt.Errorf("object %s not found", req.Name),
 		}
@@ -918,7 +1065,7 @@ func (b *bucket) StatObject(ctx context.Context,
 	}
 
 	// Make a copy to avoid handing back internal state.
-	o := copyObject(&b.objects[index].metadata)
+	o := copyObject(found)
 	m = storageutil.ConvertObjToMinObject(o)
 	if req.ReturnExtendedObjectAttributes {
 		e = storageutil.ConvertObjToExtendedObjectAttributes(o)
@@ -1048,6 +1195,7 @@ func (b *bucket) DeleteObject(
 	}
 
 	// Remove the object.
+	b.archiveLocked(index)
 	b.objects = append(b.objects[:index], b.objects[index+1:]...)
 
 	return
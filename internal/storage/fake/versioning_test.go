@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readGeneration(ctx context.Context, b gcs.Bucket, name string, generation int64) ([]byte, error) {
+	r, err := b.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{Name: name, Generation: generation})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func generations(listing *gcs.Listing) (out []int64) {
+	for _, o := range listing.MinObjects {
+		out = append(out, o.Generation)
+	}
+	return
+}
+
+func TestVersioning_NoncurrentGenerations(t *testing.T) {
+	ctx := context.Background()
+	b := NewFakeBucketWithVersioning(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	first, err := storageutil.CreateObject(ctx, b, "dir/foo", []byte("taco"))
+	require.NoError(t, err)
+	second, err := storageutil.CreateObject(ctx, b, "dir/foo", []byte("burrito"))
+	require.NoError(t, err)
+	_, err = storageutil.CreateObject(ctx, b, "dir/sub/bar", []byte("enchilada"))
+	require.NoError(t, err)
+
+	listing, err := b.ListObjects(ctx, &gcs.ListObjectsRequest{Prefix: "dir/", Delimiter: "/", Versions: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{first.Generation, second.Generation}, generations(listing))
+	assert.Equal(t, []string{"dir/sub/"}, listing.CollapsedRuns)
+	contents, err := readGeneration(ctx, b, "dir/foo", first.Generation)
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	// Nothing is soft-deleted.
+	listing, err = b.ListObjects(ctx, &gcs.ListObjectsRequest{SoftDeleted: true})
+	require.NoError(t, err)
+	assert.Empty(t, listing.MinObjects)
+}
+
+func TestVersioning_StatGeneration(t *testing.T) {
+	ctx := context.Background()
+	b := NewFakeBucketWithVersioning(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	first, err := storageutil.CreateObject(ctx, b, "foo", []byte("taco"))
+	require.NoError(t, err)
+	second, err := storageutil.CreateObject(ctx, b, "foo", []byte("burrito"))
+	require.NoError(t, err)
+
+	noncurrent, _, err := b.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo", Generation: first.Generation})
+	require.NoError(t, err)
+	live, _, err := b.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo", Generation: second.Generation})
+	require.NoError(t, err)
+	_, _, err = b.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo", Generation: second.Generation + 1})
+
+	assert.EqualValues(t, len("taco"), noncurrent.Size)
+	assert.EqualValues(t, len("burrito"), live.Size)
+	assert.ErrorAs(t, err, new(*gcs.NotFoundError))
+}
+
+func TestVersioning_SoftDeletedGenerations(t *testing.T) {
+	ctx := context.Background()
+	b := NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	first, err := storageutil.CreateObject(ctx, b, "foo", []byte("taco"))
+	require.NoError(t, err)
+	second, err := storageutil.CreateObject(ctx, b, "foo", []byte("burrito"))
+	require.NoError(t, err)
+	require.NoError(t, b.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: "foo"}))
+
+	softDeleted, err := b.ListObjects(ctx, &gcs.ListObjectsRequest{SoftDeleted: true})
+	require.NoError(t, err)
+	versions, err := b.ListObjects(ctx, &gcs.ListObjectsRequest{Versions: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{first.Generation, second.Generation}, generations(softDeleted))
+	assert.Empty(t, versions.MinObjects)
+	_, err = readGeneration(ctx, b, "foo", first.Generation)
+	assert.ErrorAs(t, err, new(*gcs.NotFoundError))
+}
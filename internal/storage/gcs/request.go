@@ -18,6 +18,7 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"time"
 
 	storagev2 "cloud.google.com/go/storage"
 	storagev1 "google.golang.org/api/storage/v1"
@@ -249,6 +250,10 @@ type StatObjectRequest struct {
 	// The name of the object in question.
 	Name string
 
+	// The generation of the object to stat, which may be noncurrent. Zero means
+	// the latest generation.
+	Generation int64
+
 	// Relevant only when fast_stat_bucket is used. This field controls whether
 	// to fetch from gcs or from cache.
 	ForceFetchFromGcs bool
@@ -367,6 +372,15 @@ type ListObjectsRequest struct {
 	// StartOffset is used to filter results to objects whose names are
 	// lexicographically equal to or after startOffset.
 	StartOffset string
+
+	// Versions lists all the generations of the objects, including the
+	// noncurrent ones of a bucket with object versioning, instead of only the
+	// live ones.
+	Versions bool
+
+	// SoftDeleted lists only the soft-deleted generations of the objects. They
+	// can't be read without restoring them first.
+	SoftDeleted bool
 }
 
 // Listing contains a set of objects and delimter-based collapsed runs returned
@@ -404,6 +418,19 @@ type Listing struct {
 	// and deleted concurrently with a single or multiple listing requests may or
 	// may not be returned.
 	ContinuationToken string
+
+	// Set only by listings of generations, see ListObjectsRequest.Versions and
+	// SoftDeleted: GenerationTimes[i] are the times of MinObjects[i]. They are
+	// kept apart from MinObject to keep it small.
+	GenerationTimes []GenerationTimes
+}
+
+// GenerationTimes are the times a generation of an object was created and
+// stopped being live, i.e. was overwritten or deleted. Deleted is zero for live
+// generations.
+type GenerationTimes struct {
+	Created time.Time
+	Deleted time.Time
 }
 
 // A request to update the metadata of an object, accepted by
@@ -16,6 +16,7 @@ package gcs
 
 import (
 	"maps"
+	"strings"
 	"time"
 )
 
@@ -24,6 +25,22 @@ import (
 // by time.RFC3339Nano.
 const MtimeMetadataKey = "gcsfuse_mtime"
 
+// reservedMetadataKeyPrefixes are the prefixes of the custom metadata keys
+// gcsfuse interprets, such as gcsfuse_mtime, gcsfuse_symlink_target and
+// goog-reserved-posix-mode.
+var reservedMetadataKeyPrefixes = []string{"gcsfuse", "goog-reserved-"}
+
+// IsReservedMetadataKey tells whether the custom metadata key is interpreted
+// by gcsfuse, and so mustn't be set or removed on behalf of users.
+func IsReservedMetadataKey(key string) bool {
+	for _, prefix := range reservedMetadataKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func NewCreateObjectRequest(srcObject *Object, objectName string, mtime *time.Time, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs int64) *CreateObjectRequest {
 	metadataMap := make(map[string]string)
 	var req *CreateObjectRequest
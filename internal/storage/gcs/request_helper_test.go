@@ -110,3 +110,11 @@ func TestCreateObjectRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReservedMetadataKey(t *testing.T) {
+	assert.True(t, IsReservedMetadataKey(MtimeMetadataKey))
+	assert.True(t, IsReservedMetadataKey("gcsfuse-encryption-key-id"))
+	assert.True(t, IsReservedMetadataKey("goog-reserved-posix-mode"))
+	assert.False(t, IsReservedMetadataKey("owner"))
+	assert.False(t, IsReservedMetadataKey("goog-other"))
+}
@@ -0,0 +1,783 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package local provides a gcs.Bucket which stores objects as files in a local
+// directory. Unlike the in-memory fake bucket, its state survives restarts and
+// can be shared by several processes, which makes it possible to run mounts
+// against a persistent store without access to GCS.
+//
+// The directory of a bucket is laid out as follows:
+//
+//	bucket.json           optional type of the bucket, e.g. {"hierarchical": true}
+//	lock                  lock file serializing access across processes
+//	generation            the last generation number minted
+//	objects/<hash>        contents of the latest generation of an object
+//	objects/<hash>.json   record of the object, i.e. its gcs.Object
+//	folders/<hash>.json   record of a folder, in hierarchical buckets
+//	tmp/                  staging area for contents and records being written
+//
+// where <hash> is the hex encoded SHA-256 of the object or folder name, since
+// object names can't always be mapped to file names.
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+)
+
+const (
+	// BucketConfigFile is the name of the optional file in the directory of a
+	// bucket holding its type. Buckets are flat when it is absent.
+	BucketConfigFile = "bucket.json"
+
+	lockFile       = "lock"
+	generationFile = "generation"
+	objectsDir     = "objects"
+	foldersDir     = "folders"
+	tmpDir         = "tmp"
+	recordSuffix   = ".json"
+
+	filePerm = os.FileMode(0600)
+	dirPerm  = os.FileMode(0700)
+)
+
+// bucketConfig is the content of BucketConfigFile.
+type bucketConfig struct {
+	Hierarchical bool `json:"hierarchical,omitempty"`
+	Zonal        bool `json:"zonal,omitempty"`
+}
+
+type bucket struct {
+	clock      timeutil.Clock
+	dir        string
+	name       string
+	bucketType gcs.BucketType
+
+	// The checksums of the latest generation of the objects read or written,
+	// by name, so that the records of a generation share their checksums like
+	// those returned by the fake bucket.
+	//
+	// GUARDED_BY(checksumsMu)
+	checksumsMu sync.Mutex
+	checksums   map[string]objectChecksums
+}
+
+type objectChecksums struct {
+	generation int64
+	md5        *[16]byte
+	crc32c     *uint32
+}
+
+// NewBucket returns a bucket with the given name whose objects are stored in
+// dir, which must exist. The type of the bucket is read from BucketConfigFile.
+func NewBucket(clock timeutil.Clock, dir string, name string) (gcs.Bucket, error) {
+	fileInfo, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("NewBucket: %w", err)
+	}
+	if !fileInfo.IsDir() {
+		return nil, fmt.Errorf("NewBucket: %s is not a directory", dir)
+	}
+
+	var config bucketConfig
+	content, err := os.ReadFile(filepath.Join(dir, BucketConfigFile))
+	if err == nil {
+		if err = json.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("NewBucket: while parsing %s: %w", BucketConfigFile, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("NewBucket: %w", err)
+	}
+
+	for _, d := range []string{objectsDir, foldersDir, tmpDir} {
+		if err = os.MkdirAll(filepath.Join(dir, d), dirPerm); err != nil {
+			return nil, fmt.Errorf("NewBucket: %w", err)
+		}
+	}
+
+	b := &bucket{
+		clock: clock,
+		dir:   dir,
+		name:  name,
+		bucketType: gcs.BucketType{
+			// Zonal buckets are always hierarchical.
+			Hierarchical: config.Hierarchical || config.Zonal,
+			Zonal:        config.Zonal,
+		},
+		checksums: make(map[string]objectChecksums),
+	}
+	return b, nil
+}
+
+// InitBucket creates the directory of a bucket of the given type.
+func InitBucket(dir string, bucketType gcs.BucketType) error {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return fmt.Errorf("InitBucket: %w", err)
+	}
+	content, err := json.Marshal(bucketConfig{
+		Hierarchical: bucketType.Hierarchical,
+		Zonal:        bucketType.Zonal,
+	})
+	if err != nil {
+		return fmt.Errorf("InitBucket: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, BucketConfigFile), content, filePerm); err != nil {
+		return fmt.Errorf("InitBucket: %w", err)
+	}
+	return nil
+}
+
+func checkName(name string) (err error) {
+	if len(name) == 0 || len(name) > 1024 {
+		err = errors.New("invalid object name: length must be in [1, 1024]")
+		return
+	}
+
+	if !utf8.ValidString(name) {
+		err = errors.New("invalid object name: not valid UTF-8")
+		return
+	}
+
+	for _, r := range name {
+		if r == 0x0a || r == 0x0d {
+			err = errors.New("invalid object name: must not contain CR or LF")
+			return
+		}
+	}
+
+	return
+}
+
+// checkPreconditions returns a *gcs.PreconditionError if existing, the record
+// of the object being overwritten or nil, doesn't satisfy the given
+// preconditions.
+func checkPreconditions(existing *gcs.Object, generation, metaGeneration *int64) error {
+	if generation != nil {
+		if *generation == 0 && existing != nil {
+			return &gcs.PreconditionError{Err: errors.New("precondition failed: object exists")}
+		}
+		if *generation > 0 {
+			if existing == nil {
+				return &gcs.PreconditionError{Err: errors.New("precondition failed: object doesn't exist")}
+			}
+			if existing.Generation != *generation {
+				return &gcs.PreconditionError{
+					Err: fmt.Errorf("precondition failed: object has generation %v", existing.Generation),
+				}
+			}
+		}
+	}
+
+	if metaGeneration != nil {
+		if existing == nil {
+			return &gcs.PreconditionError{Err: errors.New("precondition failed: object doesn't exist")}
+		}
+		if existing.MetaGeneration != *metaGeneration {
+			return &gcs.PreconditionError{
+				Err: fmt.Errorf("precondition failed: object has meta-generation %v", existing.MetaGeneration),
+			}
+		}
+	}
+
+	return nil
+}
+
+// findObject returns the record of the latest generation of an object, which
+// must match the given generation unless it is zero.
+//
+// LOCKS_REQUIRED(b.lock)
+func (b *bucket) findObject(name string, generation int64) (*gcs.Object, error) {
+	o, err := b.readObjectRecord(name)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, &gcs.NotFoundError{Err: fmt.Errorf("object %s not found", name)}
+	}
+	if generation != 0 && o.Generation != generation {
+		return nil, &gcs.NotFoundError{Err: fmt.Errorf("object %s generation %v not found", name, generation)}
+	}
+	return o, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Public interface
+////////////////////////////////////////////////////////////////////////
+
+func (b *bucket) Name() string {
+	return b.name
+}
+
+func (b *bucket) BucketType() gcs.BucketType {
+	return b.bucketType
+}
+
+func (b *bucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (gcs.StorageReader, error) {
+	unlock, err := b.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	o, err := b.findObject(req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(b.objectPath(o.Name))
+	if err != nil {
+		return nil, fmt.Errorf("while opening contents of %s: %w", o.Name, err)
+	}
+
+	// Extract the requested range, following the semantics of gcs.ByteRange.
+	start, limit := uint64(0), o.Size
+	if req.Range != nil {
+		start = min(req.Range.Start, o.Size)
+		limit = min(req.Range.Limit, o.Size)
+		if start > limit {
+			start, limit = 0, 0
+		}
+	}
+
+	return &objectReader{
+		Reader: io.NewSectionReader(f, int64(start), int64(limit-start)),
+		file:   f,
+	}, nil
+}
+
+func (b *bucket) NewMultiRangeDownloader(
+	ctx context.Context,
+	req *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	unlock, err := b.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	o, err := b.findObject(req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(b.objectPath(o.Name))
+	if err != nil {
+		return nil, fmt.Errorf("while opening contents of %s: %w", o.Name, err)
+	}
+	return &multiRangeDownloader{file: f, name: o.Name, size: int64(o.Size)}, nil
+}
+
+func (b *bucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	if err := checkName(req.Name); err != nil {
+		return nil, err
+	}
+
+	// Stage the contents before taking the lock, since reading them may take a
+	// while.
+	staged, err := b.newStagedContents()
+	if err != nil {
+		return nil, err
+	}
+	defer staged.discard()
+	if _, err = io.Copy(staged, req.Contents); err != nil {
+		return nil, fmt.Errorf("while reading contents: %w", err)
+	}
+	if err = staged.checkHashes(req.CRC32C, req.MD5); err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return b.commitObject(req, staged, true)
+}
+
+func (b *bucket) CreateObjectChunkWriter(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest,
+	_ int,
+	callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	if err := checkName(req.Name); err != nil {
+		return nil, err
+	}
+	return b.newObjectWriter(req, b.bucketType.RapidWritesEnabled(), callBack), nil
+}
+
+func (b *bucket) CreateAppendableObjectWriter(
+	ctx context.Context,
+	req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	if req.GenerationPrecondition == nil {
+		return nil, errors.New("CreateAppendableObjectWriter: generation of the object must be set")
+	}
+
+	unlock, err := b.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	o, err := b.findObject(req.Name, *req.GenerationPrecondition)
+	if err != nil {
+		return nil, err
+	}
+	if !o.IsUnfinalized() {
+		return nil, &gcs.PreconditionError{Err: fmt.Errorf("object %s is finalized", o.Name)}
+	}
+	if int64(o.Size) != req.Offset {
+		return nil, &gcs.PreconditionError{
+			Err: fmt.Errorf("takeover offset %d for the appendable object writer does not match the requested offset %d", o.Size, req.Offset),
+		}
+	}
+
+	w := b.newObjectWriter(&req.CreateObjectRequest, true, req.CallBack)
+	w.setObject(o)
+	return w, nil
+}
+
+func (b *bucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("error in closing writer : %w", err)
+	}
+	return storageutil.ObjectAttrsToMinObject(w.Attrs()), nil
+}
+
+func (b *bucket) FlushPendingWrites(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	if _, err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("error in FlushPendingWrites : %w", err)
+	}
+	return storageutil.ObjectAttrsToMinObject(w.Attrs()), nil
+}
+
+func (b *bucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	if err := checkName(req.DstName); err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	src, err := b.findObject(req.SrcName, req.SrcGeneration)
+	if err != nil {
+		return nil, err
+	}
+	if req.SrcMetaGenerationPrecondition != nil && src.MetaGeneration != *req.SrcMetaGenerationPrecondition {
+		return nil, &gcs.PreconditionError{
+			Err: fmt.Errorf("object %q has meta-generation %d", req.SrcName, src.MetaGeneration),
+		}
+	}
+	existing, err := b.readObjectRecord(req.DstName)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPreconditions(existing, req.DstGenerationPrecondition, nil); err != nil {
+		return nil, err
+	}
+
+	staged, err := b.newStagedContents()
+	if err != nil {
+		return nil, err
+	}
+	defer staged.discard()
+	if err = b.copyContents(staged, src.Name); err != nil {
+		return nil, err
+	}
+
+	// The destination keeps all the attributes of the source, except for those
+	// identifying the new object.
+	dst := *src
+	dst.Name = req.DstName
+	dst.MediaLink = b.mediaLink(req.DstName)
+	dst.MetaGeneration = 1
+	if dst.Generation, err = b.mintGeneration(); err != nil {
+		return nil, err
+	}
+	if err = b.storeObject(&dst, staged); err != nil {
+		return nil, err
+	}
+	return copyObject(&dst), nil
+}
+
+func (b *bucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	// GCS doesn't like too few or too many sources.
+	if len(req.Sources) < 1 {
+		return nil, errors.New("you must provide at least one source component")
+	}
+	if len(req.Sources) > gcs.MaxSourcesPerComposeRequest {
+		return nil, errors.New("you have provided too many source components")
+	}
+	if err := checkName(req.DstName); err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	staged, err := b.newStagedContents()
+	if err != nil {
+		return nil, err
+	}
+	defer staged.discard()
+
+	var componentCount int64
+	for _, source := range req.Sources {
+		src, err := b.findObject(source.Name, source.Generation)
+		if err != nil {
+			return nil, err
+		}
+		if err = b.copyContents(staged, src.Name); err != nil {
+			return nil, err
+		}
+		componentCount += src.ComponentCount
+	}
+
+	// GCS doesn't like the component count to go too high.
+	if componentCount > gcs.MaxComponentCount {
+		return nil, errors.New("result would have too many components")
+	}
+
+	existing, err := b.readObjectRecord(req.DstName)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPreconditions(existing, req.DstGenerationPrecondition, req.DstMetaGenerationPrecondition); err != nil {
+		return nil, err
+	}
+	o, err := b.newObjectRecord(&gcs.CreateObjectRequest{
+		Name:               req.DstName,
+		ContentType:        req.ContentType,
+		Metadata:           req.Metadata,
+		ContentLanguage:    req.ContentLanguage,
+		ContentEncoding:    req.ContentEncoding,
+		CacheControl:       req.CacheControl,
+		ContentDisposition: req.ContentDisposition,
+		CustomTime:         req.CustomTime,
+		EventBasedHold:     req.EventBasedHold,
+		StorageClass:       req.StorageClass,
+		Acl:                req.Acl,
+	}, staged, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Composite objects have no MD5 hash in GCS.
+	o.ComponentCount = componentCount
+	o.MD5 = nil
+	if err = b.storeObject(o, staged); err != nil {
+		return nil, err
+	}
+	return copyObject(o), nil
+}
+
+func (b *bucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (*gcs.MinObject, *gcs.ExtendedObjectAttributes, error) {
+	unlock, err := b.lock(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	o, err := b.findObject(req.Name, req.Generation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var e *gcs.ExtendedObjectAttributes
+	if req.ReturnExtendedObjectAttributes {
+		e = storageutil.ConvertObjToExtendedObjectAttributes(o)
+	}
+	return storageutil.ConvertObjToMinObject(o), e, nil
+}
+
+func (b *bucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	unlock, err := b.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// A local directory has neither noncurrent nor soft-deleted generations.
+	if req.SoftDeleted {
+		return &gcs.Listing{}, nil
+	}
+	entries, err := b.listEntries(req)
+	if err != nil {
+		return nil, err
+	}
+	return listing(entries, req)
+}
+
+func (b *bucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	unlock, err := b.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	o, err := b.findObject(req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	if req.MetaGenerationPrecondition != nil && o.MetaGeneration != *req.MetaGenerationPrecondition {
+		return nil, &gcs.PreconditionError{
+			Err: fmt.Errorf("object %q has meta-generation %d", o.Name, o.MetaGeneration),
+		}
+	}
+
+	if req.ContentType != nil {
+		o.ContentType = *req.ContentType
+	}
+	if req.ContentEncoding != nil {
+		o.ContentEncoding = *req.ContentEncoding
+	}
+	if req.ContentLanguage != nil {
+		o.ContentLanguage = *req.ContentLanguage
+	}
+	if req.CacheControl != nil {
+		o.CacheControl = *req.CacheControl
+	}
+	if len(req.Metadata) > 0 {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		for k, v := range req.Metadata {
+			if v == nil {
+				delete(o.Metadata, k)
+				continue
+			}
+			o.Metadata[k] = *v
+		}
+	}
+
+	o.MetaGeneration++
+	o.Updated = b.now()
+	if err = b.writeObjectRecord(o); err != nil {
+		return nil, err
+	}
+	return copyObject(o), nil
+}
+
+func (b *bucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	unlock, err := b.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	o, err := b.readObjectRecord(req.Name)
+	if err != nil {
+		return err
+	}
+	// Non-existence of the object, or of the given generation, is not an error.
+	if o == nil || (req.Generation != 0 && o.Generation != req.Generation) {
+		return nil
+	}
+	if req.MetaGenerationPrecondition != nil && o.MetaGeneration != *req.MetaGenerationPrecondition {
+		return &gcs.PreconditionError{
+			Err: fmt.Errorf("object %q has meta-generation %d", req.Name, o.MetaGeneration),
+		}
+	}
+
+	return b.removeObject(req.Name)
+}
+
+func (b *bucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	if err := checkName(req.DstName); err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	src, err := b.findObject(req.SrcName, req.SrcGeneration)
+	if err != nil {
+		return nil, err
+	}
+	if req.SrcMetaGenerationPrecondition != nil && src.MetaGeneration != *req.SrcMetaGenerationPrecondition {
+		return nil, &gcs.PreconditionError{
+			Err: fmt.Errorf("object %q has meta-generation %d", req.SrcName, src.MetaGeneration),
+		}
+	}
+
+	dst := *src
+	dst.Name = req.DstName
+	dst.MediaLink = b.mediaLink(req.DstName)
+	if dst.Generation, err = b.mintGeneration(); err != nil {
+		return nil, err
+	}
+	if err = b.renameObject(src.Name, &dst); err != nil {
+		return nil, err
+	}
+	return copyObject(&dst), nil
+}
+
+func (b *bucket) DeleteFolder(ctx context.Context, folderName string) error {
+	unlock, err := b.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := b.readFolderRecord(folderName)
+	if err != nil || f == nil {
+		return err
+	}
+
+	// Like in GCS, only empty folders can be deleted.
+	objects, err := b.readObjectRecords(folderName)
+	if err != nil {
+		return err
+	}
+	folders, err := b.readFolderRecords(folderName)
+	if err != nil {
+		return err
+	}
+	if len(objects) > 0 || len(folders) > 1 {
+		return &gcs.PreconditionError{Err: fmt.Errorf("folder %s is not empty", folderName)}
+	}
+
+	return b.removeRecord(b.folderPath(folderName) + recordSuffix)
+}
+
+func (b *bucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	unlock, err := b.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	f, err := b.readFolderRecord(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, &gcs.NotFoundError{Err: fmt.Errorf("folder %s not found", req.Name)}
+	}
+	return f, nil
+}
+
+func (b *bucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	if err := checkName(destinationFolderId); err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	f, err := b.readFolderRecord(folderName)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, &gcs.NotFoundError{Err: fmt.Errorf("folder %s not found", folderName)}
+	}
+
+	// Move the folder along with all the folders and objects it contains,
+	// keeping their generations.
+	now := b.now()
+	folders, err := b.readFolderRecords(folderName)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range folders {
+		dst := gcs.Folder{
+			Name:       destinationFolderId + strings.TrimPrefix(src.Name, folderName),
+			UpdateTime: now,
+		}
+		if err = b.writeFolderRecord(&dst); err != nil {
+			return nil, err
+		}
+		if err = b.removeRecord(b.folderPath(src.Name) + recordSuffix); err != nil {
+			return nil, err
+		}
+	}
+
+	objects, err := b.readObjectRecords(folderName)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range objects {
+		dst := *src
+		dst.Name = destinationFolderId + strings.TrimPrefix(src.Name, folderName)
+		dst.MediaLink = b.mediaLink(dst.Name)
+		if err = b.renameObject(src.Name, &dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return &gcs.Folder{Name: destinationFolderId, UpdateTime: now}, nil
+}
+
+func (b *bucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	if err := checkName(folderName); err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lock(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	f := &gcs.Folder{Name: folderName, UpdateTime: b.now()}
+	if err = b.writeFolderRecord(f); err != nil {
+		return nil, err
+	}
+	if err = b.addParentFolders(folderName); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b *bucket) GCSName(object *gcs.MinObject) string {
+	return object.Name
+}
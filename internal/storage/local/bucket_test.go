@@ -0,0 +1,47 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"testing"
+	"time"
+
+	gcstesting "github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake/testing"
+	"github.com/jacobsa/ogletest"
+	"github.com/jacobsa/timeutil"
+	"golang.org/x/net/context"
+)
+
+func TestBucket(t *testing.T) {
+	makeDeps := func(ctx context.Context) (deps gcstesting.BucketTestDeps) {
+		// Set up a fixed, non-zero time.
+		clock := &timeutil.SimulatedClock{}
+		clock.SetTime(time.Date(2012, 8, 15, 22, 56, 0, 0, time.Local))
+		deps.Clock = clock
+
+		// Set up the bucket in a fresh directory, removed once all the tests have
+		// run.
+		var err error
+		deps.Bucket, err = NewBucket(clock, t.TempDir(), "some_bucket")
+		if err != nil {
+			panic(err)
+		}
+
+		return
+	}
+
+	gcstesting.RegisterBucketTests(makeDeps)
+	ogletest.RunTests(t)
+}
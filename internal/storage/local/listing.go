@@ -0,0 +1,157 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+)
+
+// listEntry is an object or a folder visited by a listing. Folders can only be
+// returned as prefixes.
+type listEntry struct {
+	name   string
+	object *gcs.Object // nil for folders
+}
+
+// listEntries returns the objects and folders a listing for req visits,
+// sorted by name.
+//
+// LOCKS_REQUIRED(b.lock)
+func (b *bucket) listEntries(req *gcs.ListObjectsRequest) ([]listEntry, error) {
+	objects, err := b.readObjectRecords(req.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]listEntry, 0, len(objects))
+	names := make(map[string]bool, len(objects))
+	for _, o := range objects {
+		entries = append(entries, listEntry{name: o.Name, object: o})
+		names[o.Name] = true
+	}
+
+	// Folders are returned as prefixes even if they are empty, like in GCS.
+	if b.bucketType.Hierarchical && req.IncludeFoldersAsPrefixes && req.Delimiter != "" {
+		folders, err := b.readFolderRecords(req.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range folders {
+			if f.Name != req.Prefix && !names[f.Name] {
+				entries = append(entries, listEntry{name: f.Name})
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	}
+
+	return entries, nil
+}
+
+// listing returns the page of results of req among the given entries,
+// following the algorithm of the fake bucket.
+func listing(entries []listEntry, req *gcs.ListObjectsRequest) (*gcs.Listing, error) {
+	listing := new(gcs.Listing)
+
+	// Handle defaults.
+	maxResults := req.MaxResults
+	if maxResults == 0 {
+		maxResults = 1000
+	}
+
+	// Find where in the space of names to start.
+	nameStart := req.Prefix
+	if req.ContinuationToken != "" && req.ContinuationToken > nameStart {
+		nameStart = req.ContinuationToken
+	}
+	if req.StartOffset > nameStart {
+		nameStart = req.StartOffset
+	}
+	indexStart := sort.Search(len(entries), func(i int) bool { return entries[i].name >= nameStart })
+	indexLimit := min(indexStart+maxResults, len(entries))
+
+	// Scan the entries.
+	var lastResultWasPrefix bool
+	for i := indexStart; i < indexLimit; i++ {
+		e := entries[i]
+
+		// Search for a delimiter if necessary.
+		if req.Delimiter != "" {
+			// Search only in the part after the prefix.
+			nameMinusQueryPrefix := e.name[len(req.Prefix):]
+
+			delimiterIndex := strings.Index(nameMinusQueryPrefix, req.Delimiter)
+			if delimiterIndex >= 0 {
+				// Save the result, including the delimiter, but only if it's not a
+				// duplicate.
+				resultPrefix := e.name[:len(req.Prefix)+delimiterIndex+len(req.Delimiter)]
+				if len(listing.CollapsedRuns) == 0 ||
+					listing.CollapsedRuns[len(listing.CollapsedRuns)-1] != resultPrefix {
+					listing.CollapsedRuns = append(listing.CollapsedRuns, resultPrefix)
+				}
+
+				isTrailingDelimiter := delimiterIndex == len(nameMinusQueryPrefix)-len(req.Delimiter)
+				if e.object == nil || !isTrailingDelimiter || !req.IncludeTrailingDelimiter {
+					lastResultWasPrefix = true
+					continue
+				}
+			}
+		}
+
+		lastResultWasPrefix = false
+		if e.object != nil {
+			listing.MinObjects = append(listing.MinObjects, storageutil.ConvertObjToMinObject(e.object))
+		}
+	}
+
+	// Set up a cursor for where to start the next scan if we didn't exhaust the
+	// results.
+	if indexLimit < len(entries) {
+		// If the final entry we visited was returned as an element in
+		// listing.CollapsedRuns, skip all other entries that would result in the
+		// same prefix so that it isn't returned again by the next request.
+		if lastResultWasPrefix {
+			lastResultPrefix := listing.CollapsedRuns[len(listing.CollapsedRuns)-1]
+			listing.ContinuationToken = prefixSuccessor(lastResultPrefix)
+			if listing.ContinuationToken == "" {
+				return nil, errors.New("unexpected empty string from prefixSuccessor")
+			}
+		} else {
+			listing.ContinuationToken = entries[indexLimit].name
+		}
+	}
+
+	return listing, nil
+}
+
+// prefixSuccessor returns the smallest string that is greater than prefix and
+// doesn't have it as a prefix, or the empty string if there is none.
+func prefixSuccessor(prefix string) string {
+	limit := []byte(prefix)
+	for len(limit) > 0 {
+		b := limit[len(limit)-1]
+		if b != 0xff {
+			limit[len(limit)-1]++
+			break
+		}
+
+		limit = limit[:len(limit)-1]
+	}
+
+	return string(limit)
+}
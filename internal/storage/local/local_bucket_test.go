@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBucket(t *testing.T, dir string, bucketType gcs.BucketType) gcs.Bucket {
+	t.Helper()
+	require.NoError(t, InitBucket(dir, bucketType))
+	clock := &timeutil.SimulatedClock{}
+	clock.SetTime(time.Date(2012, 8, 15, 22, 56, 0, 0, time.Local))
+	b, err := NewBucket(clock, dir, "some_bucket")
+	require.NoError(t, err)
+	return b
+}
+
+func Test_NewBucket_RestoresObjects(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	b := newTestBucket(t, dir, gcs.BucketType{})
+	created, err := storageutil.CreateObject(ctx, b, "foo/bar", []byte("taco"))
+	require.NoError(t, err)
+
+	b, err = NewBucket(timeutil.RealClock(), dir, "some_bucket")
+
+	require.NoError(t, err)
+	contents, err := storageutil.ReadObject(ctx, b, "foo/bar")
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	o, err := storageutil.CreateObject(ctx, b, "baz", []byte("burrito"))
+	require.NoError(t, err)
+	assert.Greater(t, o.Generation, created.Generation)
+}
+
+func Test_ListObjects_IncludesFoldersAsPrefixes(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucket(t, t.TempDir(), gcs.BucketType{Hierarchical: true})
+	_, err := b.CreateFolder(ctx, "dir/empty/")
+	require.NoError(t, err)
+	_, err = storageutil.CreateObject(ctx, b, "dir/sub/file", []byte("taco"))
+	require.NoError(t, err)
+
+	listing, err := b.ListObjects(ctx, &gcs.ListObjectsRequest{
+		Prefix:                   "dir/",
+		Delimiter:                "/",
+		IncludeFoldersAsPrefixes: true,
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, listing.MinObjects)
+	assert.Equal(t, []string{"dir/empty/", "dir/sub/"}, listing.CollapsedRuns)
+	// Folders containing objects are created implicitly.
+	_, err = b.GetFolder(ctx, &gcs.GetFolderRequest{Name: "dir/sub/"})
+	assert.NoError(t, err)
+}
+
+func Test_ListObjects_NoSoftDeletedObjects(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucket(t, t.TempDir(), gcs.BucketType{})
+	_, err := storageutil.CreateObject(ctx, b, "foo", []byte("taco"))
+	require.NoError(t, err)
+	require.NoError(t, b.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: "foo"}))
+
+	listing, err := b.ListObjects(ctx, &gcs.ListObjectsRequest{SoftDeleted: true})
+
+	require.NoError(t, err)
+	assert.Empty(t, listing.MinObjects)
+}
+
+func Test_RenameFolder_MovesContents(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucket(t, t.TempDir(), gcs.BucketType{Hierarchical: true})
+	created, err := storageutil.CreateObject(ctx, b, "src/sub/file", []byte("taco"))
+	require.NoError(t, err)
+
+	_, err = b.RenameFolder(ctx, "src/", "dst/")
+
+	require.NoError(t, err)
+	_, err = b.GetFolder(ctx, &gcs.GetFolderRequest{Name: "src/"})
+	assert.ErrorAs(t, err, new(*gcs.NotFoundError))
+	_, err = b.GetFolder(ctx, &gcs.GetFolderRequest{Name: "dst/sub/"})
+	assert.NoError(t, err)
+	o, _, err := b.StatObject(ctx, &gcs.StatObjectRequest{Name: "dst/sub/file"})
+	require.NoError(t, err)
+	assert.Equal(t, created.Generation, o.Generation)
+	contents, err := storageutil.ReadObject(ctx, b, "dst/sub/file")
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+}
+
+func Test_DeleteFolder_NotEmpty(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucket(t, t.TempDir(), gcs.BucketType{Hierarchical: true})
+	_, err := storageutil.CreateObject(ctx, b, "dir/file", []byte("taco"))
+	require.NoError(t, err)
+
+	err = b.DeleteFolder(ctx, "dir/")
+
+	assert.ErrorAs(t, err, new(*gcs.PreconditionError))
+	_, err = b.GetFolder(ctx, &gcs.GetFolderRequest{Name: "dir/"})
+	assert.NoError(t, err)
+}
+
+func Test_AppendableObjectWriter_FlushAndTakeOver(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBucket(t, t.TempDir(), gcs.BucketType{Zonal: true})
+	w, err := b.CreateObjectChunkWriter(ctx, &gcs.CreateObjectRequest{Name: "foo"}, 0, nil)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("taco"))
+	require.NoError(t, err)
+	o, err := b.FlushPendingWrites(ctx, w)
+	require.NoError(t, err)
+	require.True(t, o.IsUnfinalized())
+	require.EqualValues(t, 4, o.Size)
+	_, err = b.FinalizeUpload(ctx, w)
+	require.NoError(t, err)
+
+	w, err = b.CreateAppendableObjectWriter(ctx, &gcs.CreateObjectChunkWriterRequest{
+		CreateObjectRequest: gcs.CreateObjectRequest{Name: "foo", GenerationPrecondition: &o.Generation},
+		Offset:              4,
+	})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("burrito"))
+	require.NoError(t, err)
+	o, err = b.FlushPendingWrites(ctx, w)
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 11, o.Size)
+	assert.Equal(t, *storageutil.CRC32C([]byte("tacoburrito")), *o.CRC32C)
+	contents, err := storageutil.ReadObject(ctx, b, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "tacoburrito", string(contents))
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	storagev2 "cloud.google.com/go/storage"
+)
+
+// objectReader reads a range of the contents of an object. Since contents are
+// replaced by renames, it keeps reading the generation it was opened on.
+type objectReader struct {
+	io.Reader
+	file *os.File
+}
+
+func (r *objectReader) Close() error {
+	return r.file.Close()
+}
+
+func (r *objectReader) ReadHandle() storagev2.ReadHandle {
+	return nil
+}
+
+// multiRangeDownloader implements gcs.MultiRangeDownloader over the contents
+// of an object.
+type multiRangeDownloader struct {
+	file *os.File
+	name string
+	size int64
+	wg   sync.WaitGroup
+
+	mu  sync.Mutex
+	err error // GUARDED_BY(mu)
+}
+
+func (mrd *multiRangeDownloader) Add(output io.Writer, offset, length int64, callback func(int64, int64, error)) {
+	// Apply the same input checks as the fake multi-range downloader.
+	var err error
+	size := mrd.size
+	if length < 0 {
+		err = fmt.Errorf("length < 0")
+	} else if offset > size {
+		err = fmt.Errorf("out of range. offset (%v) > size of content (%v) of %s", offset, size, mrd.name)
+	} else if offset <= -size {
+		offset = 0
+		length = size
+	} else if offset < 0 {
+		offset = size + offset
+		length = min(length, size-offset)
+	} else {
+		length = min(length, size-offset)
+	}
+	if err != nil {
+		mrd.setErr(err)
+		if callback != nil {
+			callback(offset, 0, err)
+		}
+		return
+	}
+
+	mrd.wg.Add(1)
+	go func() {
+		defer mrd.wg.Done()
+
+		n, err := io.Copy(output, io.NewSectionReader(mrd.file, offset, length))
+		if err == nil && n != length {
+			err = fmt.Errorf("failed to write %v bytes to writer through multi-range-downloader, bytes written = %v", length, n)
+		}
+		if err != nil {
+			mrd.setErr(err)
+		}
+		if callback != nil {
+			callback(offset, n, err)
+		}
+	}()
+}
+
+func (mrd *multiRangeDownloader) setErr(err error) {
+	mrd.mu.Lock()
+	defer mrd.mu.Unlock()
+	if mrd.err == nil {
+		mrd.err = err
+	}
+}
+
+func (mrd *multiRangeDownloader) Close() error {
+	mrd.Wait()
+	mrd.file.Close()
+	mrd.mu.Lock()
+	defer mrd.mu.Unlock()
+	return mrd.err
+}
+
+func (mrd *multiRangeDownloader) Wait() {
+	mrd.wg.Wait()
+}
+
+func (mrd *multiRangeDownloader) Error() error {
+	return nil
+}
+
+func (mrd *multiRangeDownloader) GetHandle() []byte {
+	return nil
+}
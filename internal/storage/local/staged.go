@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// stagedContents holds the contents of an object being written in a temporary
+// file, until they are moved in place.
+type stagedContents struct {
+	file   *os.File
+	size   uint64
+	crc32c uint32
+	md5    hash.Hash
+}
+
+func (b *bucket) newStagedContents() (*stagedContents, error) {
+	f, err := os.CreateTemp(filepath.Join(b.dir, tmpDir), "")
+	if err != nil {
+		return nil, fmt.Errorf("while staging contents: %w", err)
+	}
+	return &stagedContents{file: f, md5: md5.New()}, nil
+}
+
+func (s *stagedContents) Write(p []byte) (int, error) {
+	n, err := s.file.Write(p)
+	s.size += uint64(n)
+	s.crc32c = crc32.Update(s.crc32c, crc32cTable, p[:n])
+	s.md5.Write(p[:n])
+	return n, err
+}
+
+func (s *stagedContents) md5Sum() (sum [md5.Size]byte) {
+	copy(sum[:], s.md5.Sum(nil))
+	return
+}
+
+// checkHashes returns an error if the staged contents don't match the given
+// checksums, if any.
+func (s *stagedContents) checkHashes(crc32c *uint32, md5Sum *[md5.Size]byte) error {
+	if crc32c != nil && *crc32c != s.crc32c {
+		return fmt.Errorf("CRC32C mismatch: got 0x%08x, expected 0x%08x", s.crc32c, *crc32c)
+	}
+
+	if md5Sum != nil {
+		actual := s.md5Sum()
+		if actual != *md5Sum {
+			return fmt.Errorf(
+				"MD5 mismatch: got %s, expected %s",
+				hex.EncodeToString(actual[:]),
+				hex.EncodeToString(md5Sum[:]))
+		}
+	}
+
+	return nil
+}
+
+// moveTo moves the staged contents to p, after which discard is a no-op.
+func (s *stagedContents) moveTo(p string) error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("while closing staged contents: %w", err)
+	}
+	if err := os.Rename(s.file.Name(), p); err != nil {
+		return fmt.Errorf("while moving staged contents: %w", err)
+	}
+	s.file = nil
+	return nil
+}
+
+// discard removes the staged contents, unless they were moved.
+func (s *stagedContents) discard() {
+	if s.file == nil {
+		return
+	}
+	s.file.Close()
+	os.Remove(s.file.Name())
+	s.file = nil
+}
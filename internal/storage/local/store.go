@@ -0,0 +1,466 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/sys/unix"
+)
+
+// lock acquires the lock of the bucket, shared by all the goroutines and
+// processes using its directory. The returned function releases it.
+func (b *bucket) lock(exclusive bool) (unlock func(), err error) {
+	f, err := os.OpenFile(filepath.Join(b.dir, lockFile), os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf("while opening lock file: %w", err)
+	}
+
+	how := unix.LOCK_SH
+	if exclusive {
+		how = unix.LOCK_EX
+	}
+	for {
+		err = unix.Flock(int(f.Fd()), how)
+		if err != unix.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("while locking bucket: %w", err)
+	}
+
+	// Closing the file releases the lock.
+	return func() { f.Close() }, nil
+}
+
+// now returns the current time as stored in records, so that times returned
+// before and after a round trip to disk compare equal.
+func (b *bucket) now() time.Time {
+	return b.clock.Now().Round(0)
+}
+
+// localTime undoes the loss of the location of t when stored in a record.
+func localTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(time.Local)
+}
+
+func (b *bucket) mediaLink(name string) string {
+	return fmt.Sprintf("file://%s/download/storage/v1/b/%s/o/%s?alt=media",
+		b.dir, b.name, url.PathEscape(name))
+}
+
+// mintGeneration returns a new generation number, greater than all the ones
+// minted before.
+//
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) mintGeneration() (int64, error) {
+	p := filepath.Join(b.dir, generationFile)
+	var prev int64
+	content, err := os.ReadFile(p)
+	if err == nil {
+		prev, err = strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("while parsing %s: %w", p, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	generation := prev + 1
+	if err = b.writeFileAtomically(p, []byte(strconv.FormatInt(generation, 10))); err != nil {
+		return 0, err
+	}
+	return generation, nil
+}
+
+// writeFileAtomically replaces the file at p with the given content, so that
+// readers see either the old or the new content even after a crash.
+func (b *bucket) writeFileAtomically(p string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Join(b.dir, tmpDir), "")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), p)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("while writing %s: %w", p, err)
+	}
+	return nil
+}
+
+func hashName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+// objectPath returns the path of the contents of an object. Its record is
+// stored next to it, with recordSuffix.
+func (b *bucket) objectPath(name string) string {
+	return filepath.Join(b.dir, objectsDir, hashName(name))
+}
+
+// folderPath returns the path of the record of a folder, without recordSuffix.
+func (b *bucket) folderPath(name string) string {
+	return filepath.Join(b.dir, foldersDir, hashName(name))
+}
+
+// readRecord decodes the record at p into v, returning false if it doesn't
+// exist.
+func readRecord(p string, v any) (bool, error) {
+	content, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err = json.Unmarshal(content, v); err != nil {
+		return false, fmt.Errorf("while parsing %s: %w", p, err)
+	}
+	return true, nil
+}
+
+func (b *bucket) writeRecord(p string, v any) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.writeFileAtomically(p, content)
+}
+
+func (b *bucket) removeRecord(p string) error {
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// readObjectRecord returns the record of an object, or nil if it doesn't
+// exist.
+//
+// LOCKS_REQUIRED(b.lock)
+func (b *bucket) readObjectRecord(name string) (*gcs.Object, error) {
+	o := new(gcs.Object)
+	found, err := readRecord(b.objectPath(name)+recordSuffix, o)
+	if err != nil || !found {
+		return nil, err
+	}
+	localizeObject(o)
+	b.shareChecksums(o)
+	return o, nil
+}
+
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) writeObjectRecord(o *gcs.Object) error {
+	if err := b.writeRecord(b.objectPath(o.Name)+recordSuffix, o); err != nil {
+		return err
+	}
+	b.shareChecksums(o)
+	return nil
+}
+
+// shareChecksums makes o point to the same checksums as the other records of
+// its generation.
+func (b *bucket) shareChecksums(o *gcs.Object) {
+	b.checksumsMu.Lock()
+	defer b.checksumsMu.Unlock()
+
+	c, ok := b.checksums[o.Name]
+	if !ok || c.generation != o.Generation ||
+		(c.md5 == nil) != (o.MD5 == nil) || (c.md5 != nil && *c.md5 != *o.MD5) ||
+		(c.crc32c == nil) != (o.CRC32C == nil) || (c.crc32c != nil && *c.crc32c != *o.CRC32C) {
+		b.checksums[o.Name] = objectChecksums{generation: o.Generation, md5: o.MD5, crc32c: o.CRC32C}
+		return
+	}
+	o.MD5 = c.md5
+	o.CRC32C = c.crc32c
+}
+
+// readObjectRecords returns the records of all the objects whose name starts
+// with the given prefix, sorted by name.
+//
+// LOCKS_REQUIRED(b.lock)
+func (b *bucket) readObjectRecords(prefix string) ([]*gcs.Object, error) {
+	var objects []*gcs.Object
+	err := readRecords(filepath.Join(b.dir, objectsDir), func(p string) error {
+		o := new(gcs.Object)
+		found, err := readRecord(p, o)
+		if found && strings.HasPrefix(o.Name, prefix) {
+			localizeObject(o)
+			b.shareChecksums(o)
+			objects = append(objects, o)
+		}
+		return err
+	})
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, err
+}
+
+// readFolderRecord returns the record of a folder, or nil if it doesn't exist.
+//
+// LOCKS_REQUIRED(b.lock)
+func (b *bucket) readFolderRecord(name string) (*gcs.Folder, error) {
+	f := new(gcs.Folder)
+	found, err := readRecord(b.folderPath(name)+recordSuffix, f)
+	if err != nil || !found {
+		return nil, err
+	}
+	f.UpdateTime = localTime(f.UpdateTime)
+	return f, nil
+}
+
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) writeFolderRecord(f *gcs.Folder) error {
+	return b.writeRecord(b.folderPath(f.Name)+recordSuffix, f)
+}
+
+// readFolderRecords returns the records of all the folders whose name starts
+// with the given prefix, sorted by name.
+//
+// LOCKS_REQUIRED(b.lock)
+func (b *bucket) readFolderRecords(prefix string) ([]*gcs.Folder, error) {
+	var folders []*gcs.Folder
+	err := readRecords(filepath.Join(b.dir, foldersDir), func(p string) error {
+		f := new(gcs.Folder)
+		found, err := readRecord(p, f)
+		if found && strings.HasPrefix(f.Name, prefix) {
+			f.UpdateTime = localTime(f.UpdateTime)
+			folders = append(folders, f)
+		}
+		return err
+	})
+	sort.Slice(folders, func(i, j int) bool { return folders[i].Name < folders[j].Name })
+	return folders, err
+}
+
+// readRecords calls fn with the path of each record in dir.
+func readRecords(dir string, fn func(p string) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), recordSuffix) {
+			continue
+		}
+		if err = fn(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addParentFolders creates the folders containing the given object or folder
+// in hierarchical buckets, where they exist implicitly.
+//
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) addParentFolders(name string) error {
+	if !b.bucketType.Hierarchical {
+		return nil
+	}
+
+	for dir := path.Dir(strings.TrimSuffix(name, "/")); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		folderName := dir + "/"
+		f, err := b.readFolderRecord(folderName)
+		if err != nil {
+			return err
+		}
+		if f != nil {
+			break
+		}
+		if err = b.writeFolderRecord(&gcs.Folder{Name: folderName, UpdateTime: b.now()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newObjectRecord returns the record of a new generation of the object
+// described by req, holding the staged contents.
+//
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) newObjectRecord(
+	req *gcs.CreateObjectRequest,
+	staged *stagedContents,
+	finalized bool) (*gcs.Object, error) {
+	generation, err := b.mintGeneration()
+	if err != nil {
+		return nil, err
+	}
+
+	storageClass := req.StorageClass
+	if storageClass == "" {
+		storageClass = "STANDARD"
+	}
+	md5Sum, crc32c := staged.md5Sum(), staged.crc32c
+	o := &gcs.Object{
+		Name:               req.Name,
+		ContentType:        req.ContentType,
+		ContentLanguage:    req.ContentLanguage,
+		CacheControl:       req.CacheControl,
+		Owner:              "user-local",
+		Size:               staged.size,
+		ContentEncoding:    req.ContentEncoding,
+		MD5:                &md5Sum,
+		CRC32C:             &crc32c,
+		MediaLink:          b.mediaLink(req.Name),
+		Metadata:           maps.Clone(req.Metadata),
+		Generation:         generation,
+		MetaGeneration:     1,
+		StorageClass:       storageClass,
+		Updated:            b.now(),
+		ComponentCount:     1,
+		ContentDisposition: req.ContentDisposition,
+		CustomTime:         req.CustomTime,
+		EventBasedHold:     req.EventBasedHold,
+		Acl:                req.Acl,
+	}
+
+	// Like the fake bucket, only zonal buckets report whether objects are
+	// finalized. Appendable objects have no MD5 hash.
+	if b.bucketType.Zonal {
+		if finalized {
+			o.Finalized = o.Updated
+		} else {
+			o.MD5 = nil
+		}
+	}
+	return o, nil
+}
+
+// commitObject makes the staged contents the latest generation of the object
+// described by req, if its preconditions are satisfied.
+//
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) commitObject(
+	req *gcs.CreateObjectRequest,
+	staged *stagedContents,
+	finalized bool) (*gcs.Object, error) {
+	existing, err := b.readObjectRecord(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPreconditions(existing, req.GenerationPrecondition, req.MetaGenerationPrecondition); err != nil {
+		return nil, err
+	}
+
+	o, err := b.newObjectRecord(req, staged, finalized)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.storeObject(o, staged); err != nil {
+		return nil, err
+	}
+	return copyObject(o), nil
+}
+
+// storeObject moves the staged contents in place and writes the record of the
+// object.
+//
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) storeObject(o *gcs.Object, staged *stagedContents) error {
+	if err := staged.moveTo(b.objectPath(o.Name)); err != nil {
+		return err
+	}
+	if err := b.writeObjectRecord(o); err != nil {
+		return err
+	}
+	return b.addParentFolders(o.Name)
+}
+
+// renameObject moves the contents of the object with the given name to the
+// one described by dst, whose record replaces the source one.
+//
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) renameObject(srcName string, dst *gcs.Object) error {
+	if err := os.Rename(b.objectPath(srcName), b.objectPath(dst.Name)); err != nil {
+		return fmt.Errorf("while moving contents of %s: %w", srcName, err)
+	}
+	if err := b.writeObjectRecord(dst); err != nil {
+		return err
+	}
+	if err := b.removeRecord(b.objectPath(srcName) + recordSuffix); err != nil {
+		return err
+	}
+	return b.addParentFolders(dst.Name)
+}
+
+// removeObject removes the record and then the contents of an object.
+//
+// LOCKS_REQUIRED(b.lock, exclusive)
+func (b *bucket) removeObject(name string) error {
+	if err := b.removeRecord(b.objectPath(name) + recordSuffix); err != nil {
+		return err
+	}
+	b.checksumsMu.Lock()
+	delete(b.checksums, name)
+	b.checksumsMu.Unlock()
+	return b.removeRecord(b.objectPath(name))
+}
+
+// copyContents copies the contents of the latest generation of an object to
+// w.
+//
+// LOCKS_REQUIRED(b.lock)
+func (b *bucket) copyContents(w io.Writer, name string) error {
+	f, err := os.Open(b.objectPath(name))
+	if err != nil {
+		return fmt.Errorf("while opening contents of %s: %w", name, err)
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(w, f); err != nil {
+		return fmt.Errorf("while copying contents of %s: %w", name, err)
+	}
+	return nil
+}
+
+func localizeObject(o *gcs.Object) {
+	o.Updated = localTime(o.Updated)
+	o.Deleted = localTime(o.Deleted)
+	o.Finalized = localTime(o.Finalized)
+}
+
+func copyObject(o *gcs.Object) *gcs.Object {
+	var copy gcs.Object = *o
+	copy.Metadata = maps.Clone(o.Metadata)
+	return &copy
+}
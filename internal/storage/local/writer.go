@@ -0,0 +1,235 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"cloud.google.com/go/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+)
+
+// objectWriter implements gcs.Writer. Written contents are staged until the
+// writer is closed or, for appendable objects, flushed.
+type objectWriter struct {
+	bucket   *bucket
+	req      *gcs.CreateObjectRequest
+	callBack func(bytesUploadedSoFar int64)
+
+	// appendable is true for writers of unfinalized objects in zonal buckets,
+	// which can be flushed.
+	appendable bool
+
+	// The contents written since the last flush, or nil if there are none.
+	staged *stagedContents
+
+	// The number of bytes written so far, and the last committed generation of
+	// the object, if any.
+	written int64
+	object  *gcs.Object
+	closed  bool
+}
+
+func (b *bucket) newObjectWriter(
+	req *gcs.CreateObjectRequest,
+	appendable bool,
+	callBack func(bytesUploadedSoFar int64)) *objectWriter {
+	return &objectWriter{
+		bucket:     b,
+		req:        req,
+		callBack:   callBack,
+		appendable: appendable,
+	}
+}
+
+// setObject makes the writer append to the given unfinalized object.
+func (w *objectWriter) setObject(o *gcs.Object) {
+	w.object = o
+	w.written = int64(o.Size)
+}
+
+func (w *objectWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write on closed writer")
+	}
+	if w.staged == nil {
+		staged, err := w.bucket.newStagedContents()
+		if err != nil {
+			return 0, err
+		}
+		w.staged = staged
+	}
+
+	n, err := w.staged.Write(p)
+	w.written += int64(n)
+	if w.callBack != nil {
+		w.callBack(w.written)
+	}
+	return n, err
+}
+
+func (w *objectWriter) Flush() (int64, error) {
+	if !w.appendable {
+		return 0, errors.New("Flush is only supported for appendable objects")
+	}
+	if w.closed {
+		return 0, errors.New("flush on closed writer")
+	}
+	if err := w.commit(); err != nil {
+		return 0, err
+	}
+	return w.written, nil
+}
+
+func (w *objectWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	err := w.commit()
+	w.closed = true
+	if w.staged != nil {
+		w.staged.discard()
+		w.staged = nil
+	}
+	return err
+}
+
+// commit makes the contents written so far visible in the bucket.
+func (w *objectWriter) commit() (err error) {
+	// Appendable objects are only created once.
+	if w.object != nil && w.appendable {
+		if w.staged == nil {
+			return nil
+		}
+		return w.appendStaged()
+	}
+
+	staged := w.staged
+	if staged == nil {
+		if staged, err = w.bucket.newStagedContents(); err != nil {
+			return err
+		}
+	}
+	defer staged.discard()
+	w.staged = nil
+	if err = staged.checkHashes(w.req.CRC32C, w.req.MD5); err != nil {
+		return err
+	}
+
+	unlock, err := w.bucket.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	o, err := w.bucket.commitObject(w.req, staged, !w.appendable)
+	if err != nil {
+		return err
+	}
+	w.object = o
+	return nil
+}
+
+// appendStaged appends the staged contents to the unfinalized object, which
+// must still be at the generation the writer created or took over.
+func (w *objectWriter) appendStaged() error {
+	staged := w.staged
+	defer staged.discard()
+	w.staged = nil
+
+	unlock, err := w.bucket.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	o, err := w.bucket.findObject(w.req.Name, w.object.Generation)
+	if err != nil {
+		return err
+	}
+	if !o.IsUnfinalized() {
+		return &gcs.PreconditionError{Err: fmt.Errorf("object %s is finalized", o.Name)}
+	}
+
+	f, err := os.OpenFile(w.bucket.objectPath(o.Name), os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return fmt.Errorf("while opening contents of %s: %w", o.Name, err)
+	}
+	defer f.Close()
+	if _, err = staged.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	crc32c := uint32(0)
+	if o.CRC32C != nil {
+		crc32c = *o.CRC32C
+	}
+	buf := make([]byte, 1<<20)
+	for {
+		n, readErr := staged.file.Read(buf)
+		if n > 0 {
+			if _, err = f.Write(buf[:n]); err != nil {
+				return fmt.Errorf("while appending to %s: %w", o.Name, err)
+			}
+			crc32c = crc32.Update(crc32c, crc32cTable, buf[:n])
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	o.Size += staged.size
+	o.CRC32C = &crc32c
+	o.Updated = w.bucket.now()
+	if err = w.bucket.writeObjectRecord(o); err != nil {
+		return err
+	}
+	w.object = o
+	return nil
+}
+
+func (w *objectWriter) ObjectName() string {
+	return w.req.Name
+}
+
+// Attrs returns the attributes of the object as last committed by the writer.
+func (w *objectWriter) Attrs() *storage.ObjectAttrs {
+	if w.object == nil {
+		return &storage.ObjectAttrs{Name: w.req.Name, ContentType: w.req.ContentType}
+	}
+
+	o := w.object
+	attrs := &storage.ObjectAttrs{
+		Name:            o.Name,
+		ContentType:     o.ContentType,
+		ContentEncoding: o.ContentEncoding,
+		Size:            int64(o.Size),
+		Metadata:        o.Metadata,
+		Generation:      o.Generation,
+		Metageneration:  o.MetaGeneration,
+		Updated:         o.Updated,
+		Finalized:       o.Finalized,
+	}
+	if o.CRC32C != nil {
+		attrs.CRC32C = *o.CRC32C
+	}
+	return attrs
+}
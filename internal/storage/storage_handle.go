@@ -40,6 +40,7 @@ import (
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"golang.org/x/oauth2"
 	option "google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
@@ -76,13 +77,18 @@ type storageClient struct {
 	httpClient               *storage.Client
 	grpcClient               *storage.Client
 	grpcClientWithBidiConfig *storage.Client
-	clientConfig             storageutil.StorageClientConfig
+	// rawHTTPService is created on first use, whatever the client protocol.
+	rawHTTPService *storagev1.Service
+	clientConfig   storageutil.StorageClientConfig
 	// rawStorageControlClientWithoutGaxRetries is without any retries.
 	rawStorageControlClientWithoutGaxRetries *control.StorageControlClient
 	// rawStorageControlClientWithGaxRetries is with retry for Folder APIs.
 	rawStorageControlClientWithGaxRetries *control.StorageControlClient
 	// storageControlClient is with retry for GetStorageLayout and with handling for billing project.
 	storageControlClient StorageControlClient
+	// encryptionKey is the customer-supplied encryption key sent with every
+	// object request, if non-nil.
+	encryptionKey []byte
 }
 
 // Return clientOpts for both gRPC client and control client.
@@ -266,8 +272,9 @@ func unSetDirectPathEnvVariable() {
 	}
 }
 
-func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (sc *storage.Client, err error) {
-	var clientOpts []option.ClientOption
+// Return clientOpts shared by the http storage client and the raw JSON API
+// service: authentication, http client and custom endpoint.
+func createClientOptionForHTTPClient(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (clientOpts []option.ClientOption, err error) {
 	var tokenSrc oauth2.TokenSource = nil
 
 	if clientConfig.AnonymousAccess {
@@ -291,15 +298,23 @@ func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.Stora
 
 	clientOpts = append(clientOpts, option.WithHTTPClient(httpClient))
 
-	// Create client with JSON read flow, if EnableJasonRead flag is set.
-	if clientConfig.ExperimentalEnableJsonRead {
-		clientOpts = append(clientOpts, storage.WithJSONReads())
-	}
-
 	// Add Custom endpoint option.
 	if clientConfig.CustomEndpoint != "" {
 		clientOpts = append(clientOpts, option.WithEndpoint(clientConfig.CustomEndpoint))
 	}
+	return
+}
+
+func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (sc *storage.Client, err error) {
+	clientOpts, err := createClientOptionForHTTPClient(ctx, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create client with JSON read flow, if EnableJasonRead flag is set.
+	if clientConfig.ExperimentalEnableJsonRead {
+		clientOpts = append(clientOpts, storage.WithJSONReads())
+	}
 
 	if clientConfig.ReadStallRetryConfig.Enable {
 		// Hidden way to modify the increase rate for dynamic delay algorithm in go-sdk.
@@ -333,6 +348,20 @@ func createHTTPClientHandle(ctx context.Context, clientConfig *storageutil.Stora
 	return
 }
 
+// createRawHTTPService creates a JSON API service, for the few requests that
+// the storage client can't express, like deleting a single metadata key.
+func createRawHTTPService(ctx context.Context, clientConfig *storageutil.StorageClientConfig) (*storagev1.Service, error) {
+	clientOpts, err := createClientOptionForHTTPClient(ctx, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	service, err := storagev1.NewService(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("go raw http storage service creation failed: %w", err)
+	}
+	return service, nil
+}
+
 func (sh *storageClient) lookupBucketType(bucketName string) (*gcs.BucketType, error) {
 	if sh.storageControlClient == nil {
 		return &gcs.BucketType{}, nil // Assume defaults
@@ -387,6 +416,14 @@ func NewStorageHandle(ctx context.Context, clientConfig storageutil.StorageClien
 	var rawStorageControlClientWithoutGaxRetries *control.StorageControlClient
 	var rawStorageControlClientWithGaxRetries *control.StorageControlClient
 	var clientOpts []option.ClientOption
+	var encryptionKey []byte
+
+	if clientConfig.CustomerSuppliedEncryptionKeyFile != "" {
+		encryptionKey, err = storageutil.LoadCustomerSuppliedEncryptionKey(clientConfig.CustomerSuppliedEncryptionKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load customer-supplied encryption key: %w", err)
+		}
+	}
 
 	// Control-client is needed for folder APIs and for getting storage-layout of the bucket.
 	// GetStorageLayout API is not supported for storage-testbench, which are identified by custom-endpoint containing localhost.
@@ -425,6 +462,7 @@ func NewStorageHandle(ctx context.Context, clientConfig storageutil.StorageClien
 		rawStorageControlClientWithGaxRetries:    rawStorageControlClientWithGaxRetries,
 		storageControlClient:                     controlClient,
 		clientConfig:                             clientConfig,
+		encryptionKey:                            encryptionKey,
 	}
 	return
 }
@@ -524,13 +562,22 @@ func (sh *storageClient) BucketHandle(ctx context.Context, bucketName string, bi
 	}
 	controlClient := sh.controlClientForBucketHandle(bucketType, billingProject)
 
+	if sh.rawHTTPService == nil {
+		sh.rawHTTPService, err = createRawHTTPService(ctx, &sh.clientConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	bh = &bucketHandle{
 		bucket:         storageBucketHandle,
+		rawObjects:     sh.rawHTTPService.Objects,
 		bucketName:     bucketName,
 		controlClient:  controlClient,
 		bucketType:     bucketType,
 		billingProject: billingProject,
 		writeConfig:    sh.clientConfig.WriteConfig,
+		encryptionKey:  sh.encryptionKey,
 	}
 
 	return
@@ -15,11 +15,14 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net"
 	"net/url"
 	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -1112,3 +1115,38 @@ func (testSuite *StorageHandleTest) TestControlClientForBucketHandle_NonZonalBuc
 	assert.True(testSuite.T(), controlClientWithRetry.enableRetriesOnStorageLayoutAPI, "Retries should be enabled for storage layout API on zonal buckets")
 	assert.Same(testSuite.T(), mockRawControlClientWithoutRetries, controlClientWithRetry.raw)
 }
+
+func (testSuite *StorageHandleTest) TestNewStorageHandleWithCustomerSuppliedEncryptionKeyFile() {
+	key := bytes.Repeat([]byte{0x2a}, 32)
+	keyPath := path.Join(testSuite.T().TempDir(), "csek")
+	require.NoError(testSuite.T(), os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+	sc := storageutil.GetDefaultStorageClientConfig(keyFile)
+	sc.CustomerSuppliedEncryptionKeyFile = keyPath
+
+	handleCreated, err := NewStorageHandle(testSuite.ctx, sc, "")
+
+	require.NoError(testSuite.T(), err)
+	assert.Equal(testSuite.T(), key, handleCreated.(*storageClient).encryptionKey)
+}
+
+func (testSuite *StorageHandleTest) TestNewStorageHandleWithInvalidCustomerSuppliedEncryptionKeyFile() {
+	keyPath := path.Join(testSuite.T().TempDir(), "csek")
+	require.NoError(testSuite.T(), os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString([]byte("too short"))), 0600))
+	sc := storageutil.GetDefaultStorageClientConfig(keyFile)
+	sc.CustomerSuppliedEncryptionKeyFile = keyPath
+
+	_, err := NewStorageHandle(testSuite.ctx, sc, "")
+
+	assert.ErrorContains(testSuite.T(), err, "key is 9 bytes long, want 32")
+}
+
+func (testSuite *StorageHandleTest) TestBucketHandleWithCustomerSuppliedEncryptionKey() {
+	testSuite.mockStorageLayout(gcs.BucketType{})
+	sh := testSuite.fakeStorage.CreateStorageHandle().(*storageClient)
+	sh.encryptionKey = bytes.Repeat([]byte{0x2a}, 32)
+
+	bucketHandle, err := sh.BucketHandle(testSuite.ctx, TestBucketName, "")
+
+	require.NoError(testSuite.T(), err)
+	assert.Equal(testSuite.T(), sh.encryptionKey, bucketHandle.encryptionKey)
+}
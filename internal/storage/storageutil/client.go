@@ -20,6 +20,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptrace"
+	"net/url"
 	"strings"
 	"time"
 
@@ -56,6 +57,7 @@ type StorageClientConfig struct {
 	UserAgent                               string
 	CustomEndpoint                          string
 	KeyFile                                 string
+	CustomerSuppliedEncryptionKeyFile       string
 	TokenUrl                                string
 	ReuseTokenFromUrl                       bool
 	ExperimentalNonrapidFolderApiStallRetry bool
@@ -195,3 +197,13 @@ func StripScheme(url string) string {
 	}
 	return url
 }
+
+// LocalBucketRoot returns the directory holding the directories of local
+// buckets, if the given custom endpoint is a file:// URL.
+func LocalBucketRoot(customEndpoint string) (string, bool) {
+	u, err := url.Parse(customEndpoint)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	return u.Path, true
+}
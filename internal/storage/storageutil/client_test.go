@@ -148,6 +148,34 @@ func (t *clientTest) TestStripScheme() {
 	}
 }
 
+func (t *clientTest) TestLocalBucketRoot() {
+	for _, tc := range []struct {
+		input        string
+		expectedRoot string
+		expectedOk   bool
+	}{
+		{
+			input: "",
+		},
+		{
+			input: "http://localhost:8888",
+		},
+		{
+			input: "file://",
+		},
+		{
+			input:        "file:///tmp/buckets",
+			expectedRoot: "/tmp/buckets",
+			expectedOk:   true,
+		},
+	} {
+		root, ok := LocalBucketRoot(tc.input)
+
+		assert.Equal(t.T(), tc.expectedRoot, root)
+		assert.Equal(t.T(), tc.expectedOk, ok)
+	}
+}
+
 func (t *clientTest) TestCreateHttpClientWithHttpTracing() {
 	ex := newInMemoryExporter(t.T())
 	sc := GetDefaultStorageClientConfig(keyFile)
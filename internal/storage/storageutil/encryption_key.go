@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storageutil
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// customerSuppliedEncryptionKeySize is the size of a customer-supplied
+// encryption key, which GCS requires to be an AES-256 key.
+const customerSuppliedEncryptionKeySize = 32
+
+// LoadCustomerSuppliedEncryptionKey reads the file at the given path, which
+// holds a 256-bit key encoded in standard base64, and returns the decoded key.
+// Surrounding whitespace, such as a trailing newline, is ignored.
+func LoadCustomerSuppliedEncryptionKey(path string) ([]byte, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadCustomerSuppliedEncryptionKey: %w", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, fmt.Errorf("LoadCustomerSuppliedEncryptionKey: decoding %s: %w", path, err)
+	}
+	if len(key) != customerSuppliedEncryptionKeySize {
+		return nil, fmt.Errorf("LoadCustomerSuppliedEncryptionKey: %s: key is %d bytes long, want %d", path, len(key), customerSuppliedEncryptionKeySize)
+	}
+	return key, nil
+}
+
+// SetEncryptionKeyHeaders sets the headers that send a customer-supplied
+// encryption key with a JSON API request, as the storage client does.
+func SetEncryptionKeyHeaders(headers http.Header, key []byte) {
+	sum := sha256.Sum256(key)
+	headers.Set("x-goog-encryption-algorithm", "AES256")
+	headers.Set("x-goog-encryption-key", base64.StdEncoding.EncodeToString(key))
+	headers.Set("x-goog-encryption-key-sha256", base64.StdEncoding.EncodeToString(sum[:]))
+}
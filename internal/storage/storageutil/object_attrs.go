@@ -16,6 +16,9 @@ package storageutil
 
 import (
 	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -105,6 +108,77 @@ func ObjectAttrsToBucketObject(attrs *storage.ObjectAttrs) *gcs.Object {
 	}
 }
 
+// ObjectToBucketObject converts an object returned by the JSON API, for
+// requests that bypass the storage client.
+func ObjectToBucketObject(o *storagev1.Object) (*gcs.Object, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	var md5Hash [md5.Size]byte
+	if o.Md5Hash != "" {
+		decoded, err := base64.StdEncoding.DecodeString(o.Md5Hash)
+		if err != nil {
+			return nil, fmt.Errorf("decoding MD5 hash %q: %w", o.Md5Hash, err)
+		}
+		copy(md5Hash[:], decoded)
+	}
+
+	var crc uint32
+	if o.Crc32c != "" {
+		decoded, err := base64.StdEncoding.DecodeString(o.Crc32c)
+		if err != nil {
+			return nil, fmt.Errorf("decoding CRC32C %q: %w", o.Crc32c, err)
+		}
+		if len(decoded) != 4 {
+			return nil, fmt.Errorf("decoding CRC32C %q: got %d bytes, want 4", o.Crc32c, len(decoded))
+		}
+		crc = binary.BigEndian.Uint32(decoded)
+	}
+
+	var times [4]time.Time
+	for i, t := range []string{o.Updated, o.TimeDeleted, o.TimeFinalized, o.CustomTime} {
+		if t == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return nil, fmt.Errorf("parsing time %q: %w", t, err)
+		}
+		times[i] = parsed
+	}
+
+	var owner string
+	if o.Owner != nil {
+		owner = o.Owner.Entity
+	}
+
+	return &gcs.Object{
+		Name:               o.Name,
+		ContentType:        o.ContentType,
+		ContentLanguage:    o.ContentLanguage,
+		CacheControl:       o.CacheControl,
+		Owner:              owner,
+		Size:               o.Size,
+		ContentEncoding:    o.ContentEncoding,
+		MD5:                &md5Hash,
+		CRC32C:             &crc,
+		MediaLink:          o.MediaLink,
+		Metadata:           o.Metadata,
+		Generation:         o.Generation,
+		MetaGeneration:     o.Metageneration,
+		StorageClass:       o.StorageClass,
+		Updated:            times[0],
+		Deleted:            times[1],
+		Finalized:          times[2],
+		ComponentCount:     o.ComponentCount,
+		ContentDisposition: o.ContentDisposition,
+		CustomTime:         string(times[3].Format(time.RFC3339)),
+		EventBasedHold:     o.EventBasedHold,
+		Acl:                o.Acl,
+	}, nil
+}
+
 func ObjectAttrsToMinObject(attrs *storage.ObjectAttrs) *gcs.MinObject {
 	if attrs == nil {
 		return nil
@@ -160,6 +234,28 @@ func SetAttrsInWriter(wc *storage.Writer, req *gcs.CreateObjectRequest) *storage
 	return wc
 }
 
+// SetAttrsInComposer - for setting object-attributes field in storage.Composer
+// object. These attributes will be assigned to the composed object.
+func SetAttrsInComposer(c *storage.Composer, req *gcs.ComposeObjectsRequest) *storage.Composer {
+	c.ContentType = req.ContentType
+	c.ContentLanguage = req.ContentLanguage
+	c.ContentEncoding = req.ContentEncoding
+	c.CacheControl = req.CacheControl
+	c.Metadata = req.Metadata
+	c.ContentDisposition = req.ContentDisposition
+	c.CustomTime, _ = time.Parse(time.RFC3339, req.CustomTime)
+	c.EventBasedHold = req.EventBasedHold
+	c.StorageClass = req.StorageClass
+
+	var aclRules []storage.ACLRule
+	for _, element := range req.Acl {
+		aclRules = append(aclRules, convertObjectAccessControlToACLRule(element))
+	}
+	c.ACL = aclRules
+
+	return c
+}
+
 func ConvertObjToMinObject(o *gcs.Object) *gcs.MinObject {
 	if o == nil {
 		return nil
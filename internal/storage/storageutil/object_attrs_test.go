@@ -16,6 +16,7 @@ package storageutil
 
 import (
 	"crypto/md5"
+	"encoding/base64"
 	"strings"
 	"testing"
 	"time"
@@ -143,6 +144,43 @@ func (t objectAttrsTest) TestObjectAttrsToBucketObjectMethod() {
 	ExpectEq(object.ComponentCount, attrs.ComponentCount)
 }
 
+func (t objectAttrsTest) TestObjectToBucketObjectMethod() {
+	updated := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	object := &storagev1.Object{
+		Name:           TestObjectName,
+		ContentType:    "text/plain",
+		Size:           16,
+		Md5Hash:        base64.StdEncoding.EncodeToString(md5.New().Sum(nil)),
+		Crc32c:         base64.StdEncoding.EncodeToString([]byte{0x01, 0x02, 0x03, 0x04}),
+		Metadata:       map[string]string{"test_key": "test_value"},
+		Generation:     3,
+		Metageneration: 4,
+		Updated:        updated.Format(time.RFC3339),
+		Owner:          &storagev1.ObjectOwner{Entity: "test_entity"},
+	}
+
+	o, err := ObjectToBucketObject(object)
+
+	AssertEq(nil, err)
+	ExpectEq(object.Name, o.Name)
+	ExpectEq(object.ContentType, o.ContentType)
+	ExpectEq(object.Size, o.Size)
+	ExpectEq(*(*[md5.Size]byte)(md5.New().Sum(nil)), *o.MD5)
+	ExpectEq(uint32(0x01020304), *o.CRC32C)
+	ExpectEq("test_value", o.Metadata["test_key"])
+	ExpectEq(object.Generation, o.Generation)
+	ExpectEq(object.Metageneration, o.MetaGeneration)
+	ExpectTrue(updated.Equal(o.Updated))
+	ExpectTrue(o.Deleted.IsZero())
+	ExpectEq("test_entity", o.Owner)
+}
+
+func (t objectAttrsTest) TestObjectToBucketObjectMethodWithInvalidCRC32C() {
+	_, err := ObjectToBucketObject(&storagev1.Object{Crc32c: "AAE="})
+
+	ExpectNe(nil, err)
+}
+
 func (t objectAttrsTest) TestConvertObjectAccessControlToACLRuleMethod() {
 	objectAccessControl := &storagev1.ObjectAccessControl{
 		Entity:   "test_entity",
@@ -220,6 +258,34 @@ func (t objectAttrsTest) TestSetAttrsInWriterMethod() {
 	ExpectEq(string(writer.MD5[:]), string(createObjectRequest.MD5[:]))
 }
 
+func (t objectAttrsTest) TestSetAttrsInComposerMethod() {
+	composeObjectsRequest := gcs.ComposeObjectsRequest{
+		DstName:            "test_object",
+		ContentType:        "json",
+		ContentLanguage:    "en",
+		ContentEncoding:    "universal",
+		CacheControl:       "Medium",
+		Metadata:           map[string]string{"file_name": "test.txt"},
+		ContentDisposition: "Test content disposition",
+		CustomTime:         "2006-01-02T15:04:05Z",
+		EventBasedHold:     true,
+		StorageClass:       "High Accessibility",
+	}
+	composer := &storage.Composer{}
+
+	composer = SetAttrsInComposer(composer, &composeObjectsRequest)
+
+	ExpectEq(composer.ContentType, composeObjectsRequest.ContentType)
+	ExpectEq(composer.ContentLanguage, composeObjectsRequest.ContentLanguage)
+	ExpectEq(composer.ContentEncoding, composeObjectsRequest.ContentEncoding)
+	ExpectEq(composer.CacheControl, composeObjectsRequest.CacheControl)
+	ExpectEq(composer.Metadata, composeObjectsRequest.Metadata)
+	ExpectEq(composer.ContentDisposition, composeObjectsRequest.ContentDisposition)
+	ExpectTrue(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Equal(composer.CustomTime))
+	ExpectEq(composer.EventBasedHold, composeObjectsRequest.EventBasedHold)
+	ExpectEq(composer.StorageClass, composeObjectsRequest.StorageClass)
+}
+
 func (t objectAttrsTest) Test_ConvertObjToMinObject_WithNilObject() {
 	var gcsObject *gcs.Object
 
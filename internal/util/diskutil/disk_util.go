@@ -15,6 +15,7 @@
 package diskutil
 
 import (
+	"fmt"
 	"syscall"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
@@ -66,3 +67,17 @@ func GetVolumeBlockSize(path string) uint64 {
 	}
 	return blockSize
 }
+
+// GetAvailableSpace returns the number of bytes available to unprivileged users
+// on the file system containing the given path.
+func GetAvailableSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("statfs %q: %w", path, err)
+	}
+	blockSize := uint64(stat.Bsize)
+	if stat.Frsize > 0 {
+		blockSize = uint64(stat.Frsize)
+	}
+	return stat.Bavail * blockSize, nil
+}
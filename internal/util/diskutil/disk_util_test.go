@@ -19,6 +19,7 @@ import (
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/util/diskutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetSpeculativeFileSizeOnDisk(t *testing.T) {
@@ -99,3 +100,16 @@ func TestGetVolumeBlockSize_InvalidDir(t *testing.T) {
 	// expect default value if directory doesn't exist.
 	assert.Equal(t, expectedVolumeBlockSize, blockSize)
 }
+
+func TestGetAvailableSpace(t *testing.T) {
+	available, err := diskutil.GetAvailableSpace(t.TempDir())
+
+	require.NoError(t, err)
+	assert.NotZero(t, available)
+}
+
+func TestGetAvailableSpace_NonExistentDir(t *testing.T) {
+	_, err := diskutil.GetAvailableSpace("/non/existent/dir")
+
+	assert.Error(t, err)
+}
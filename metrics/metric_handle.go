@@ -190,6 +190,9 @@ type MetricHandle interface {
 	// BufferedReadReadLatency - The cumulative distribution of latencies for ReadAt calls served by the buffered reader.
 	BufferedReadReadLatency(ctx context.Context, latency time.Duration)
 
+	// FileCacheRamTierReadCount - Specifies the number of read requests served from the in-memory tier of the file cache along with type - Sequential/Random
+	FileCacheRamTierReadCount(inc int64, readType ReadType)
+
 	// FileCacheReadBytesCount - The cumulative number of bytes read from file cache along with read type - Sequential/Random
 	FileCacheReadBytesCount(inc int64, readType ReadType)
 
@@ -26,6 +26,8 @@ func (*noopMetrics) BufferedReadFallbackTriggerCount(inc int64, reason Reason) {
 
 func (*noopMetrics) BufferedReadReadLatency(ctx context.Context, latency time.Duration) {}
 
+func (*noopMetrics) FileCacheRamTierReadCount(inc int64, readType ReadType) {}
+
 func (*noopMetrics) FileCacheReadBytesCount(inc int64, readType ReadType) {}
 
 func (*noopMetrics) FileCacheReadCount(inc int64, cacheHit bool, readType ReadType) {}
@@ -35,6 +35,10 @@ var (
 	unrecognizedAttr                                                                                       atomic.Value
 	bufferedReadFallbackTriggerCountReasonInsufficientMemoryAttrSet                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("reason", "insufficient_memory")))
 	bufferedReadFallbackTriggerCountReasonRandomReadDetectedAttrSet                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("reason", "random_read_detected")))
+	fileCacheRamTierReadCountReadTypeParallelAttrSet                                                       = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Parallel")))
+	fileCacheRamTierReadCountReadTypeRandomAttrSet                                                         = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Random")))
+	fileCacheRamTierReadCountReadTypeSequentialAttrSet                                                     = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Sequential")))
+	fileCacheRamTierReadCountReadTypeUnknownAttrSet                                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Unknown")))
 	fileCacheReadBytesCountReadTypeParallelAttrSet                                                         = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Parallel")))
 	fileCacheReadBytesCountReadTypeRandomAttrSet                                                           = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Random")))
 	fileCacheReadBytesCountReadTypeSequentialAttrSet                                                       = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Sequential")))
@@ -603,6 +607,10 @@ type otelMetrics struct {
 	wg                                                                                                    *sync.WaitGroup
 	bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic                                        *atomic.Int64
 	bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic                                        *atomic.Int64
+	fileCacheRamTierReadCountReadTypeParallelAtomic                                                       *atomic.Int64
+	fileCacheRamTierReadCountReadTypeRandomAtomic                                                         *atomic.Int64
+	fileCacheRamTierReadCountReadTypeSequentialAtomic                                                     *atomic.Int64
+	fileCacheRamTierReadCountReadTypeUnknownAtomic                                                        *atomic.Int64
 	fileCacheReadBytesCountReadTypeParallelAtomic                                                         *atomic.Int64
 	fileCacheReadBytesCountReadTypeRandomAtomic                                                           *atomic.Int64
 	fileCacheReadBytesCountReadTypeSequentialAtomic                                                       *atomic.Int64
@@ -1147,6 +1155,27 @@ func (o *otelMetrics) BufferedReadReadLatency(
 	}
 }
 
+func (o *otelMetrics) FileCacheRamTierReadCount(
+	inc int64, readType ReadType) {
+	if inc < 0 {
+		logger.Errorf("Counter metric file_cache/ram_tier_read_count received a negative increment: %d", inc)
+		return
+	}
+	switch readType {
+	case ReadTypeParallelAttr:
+		o.fileCacheRamTierReadCountReadTypeParallelAtomic.Add(inc)
+	case ReadTypeRandomAttr:
+		o.fileCacheRamTierReadCountReadTypeRandomAtomic.Add(inc)
+	case ReadTypeSequentialAttr:
+		o.fileCacheRamTierReadCountReadTypeSequentialAtomic.Add(inc)
+	case ReadTypeUnknownAttr:
+		o.fileCacheRamTierReadCountReadTypeUnknownAtomic.Add(inc)
+	default:
+		updateUnrecognizedAttribute(string(readType))
+		return
+	}
+}
+
 func (o *otelMetrics) FileCacheReadBytesCount(
 	inc int64, readType ReadType) {
 	if inc < 0 {
@@ -2674,6 +2703,11 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 	var bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic,
 		bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic atomic.Int64
 
+	var fileCacheRamTierReadCountReadTypeParallelAtomic,
+		fileCacheRamTierReadCountReadTypeRandomAtomic,
+		fileCacheRamTierReadCountReadTypeSequentialAtomic,
+		fileCacheRamTierReadCountReadTypeUnknownAtomic atomic.Int64
+
 	var fileCacheReadBytesCountReadTypeParallelAtomic,
 		fileCacheReadBytesCountReadTypeRandomAtomic,
 		fileCacheReadBytesCountReadTypeSequentialAtomic,
@@ -3212,7 +3246,18 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(50, 100, 200, 400, 800, 1500, 3000, 5000, 10000, 20000, 50000, 100000, 200000, 500000, 1000000, 2000000, 5000000, 10000000, 20000000, 50000000, 100000000, 200000000, 500000000))
 
-	_, err2 := meter.Int64ObservableCounter("file_cache/read_bytes_count",
+	_, err2 := meter.Int64ObservableCounter("file_cache/ram_tier_read_count",
+		metric.WithDescription("Specifies the number of read requests served from the in-memory tier of the file cache along with type - Sequential/Random"),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fileCacheRamTierReadCountReadTypeParallelAtomic, fileCacheRamTierReadCountReadTypeParallelAttrSet)
+			conditionallyObserve(obsrv, &fileCacheRamTierReadCountReadTypeRandomAtomic, fileCacheRamTierReadCountReadTypeRandomAttrSet)
+			conditionallyObserve(obsrv, &fileCacheRamTierReadCountReadTypeSequentialAtomic, fileCacheRamTierReadCountReadTypeSequentialAttrSet)
+			conditionallyObserve(obsrv, &fileCacheRamTierReadCountReadTypeUnknownAtomic, fileCacheRamTierReadCountReadTypeUnknownAttrSet)
+			return nil
+		}))
+
+	_, err3 := meter.Int64ObservableCounter("file_cache/read_bytes_count",
 		metric.WithDescription("The cumulative number of bytes read from file cache along with read type - Sequential/Random"),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3223,7 +3268,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err3 := meter.Int64ObservableCounter("file_cache/read_count",
+	_, err4 := meter.Int64ObservableCounter("file_cache/read_count",
 		metric.WithDescription("Specifies the number of read requests made via file cache along with type - Sequential/Random and cache hit - true/false"),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3238,12 +3283,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	fileCacheReadLatencies, err4 := meter.Int64Histogram("file_cache/read_latencies",
+	fileCacheReadLatencies, err5 := meter.Int64Histogram("file_cache/read_latencies",
 		metric.WithDescription("The cumulative distribution of the file cache read latencies along with cache hit - true/false."),
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(50, 100, 200, 400, 800, 1500, 3000, 5000, 10000, 20000, 50000, 100000, 200000, 500000, 1000000, 2000000, 5000000, 10000000, 20000000, 50000000, 100000000, 200000000, 500000000))
 
-	_, err5 := meter.Int64ObservableCounter("fs/ops_count",
+	_, err6 := meter.Int64ObservableCounter("fs/ops_count",
 		metric.WithDescription("The cumulative number of ops processed by the file system."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3275,7 +3320,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err6 := meter.Int64ObservableCounter("fs/ops_error_count",
+	_, err7 := meter.Int64ObservableCounter("fs/ops_error_count",
 		metric.WithDescription("The cumulative number of errors generated by file system operations."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3682,12 +3727,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	fsOpsLatency, err7 := meter.Int64Histogram("fs/ops_latency",
+	fsOpsLatency, err8 := meter.Int64Histogram("fs/ops_latency",
 		metric.WithDescription("The cumulative distribution of file system operation latencies"),
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(50, 100, 200, 400, 800, 1500, 3000, 5000, 10000, 20000, 50000, 100000, 200000, 500000, 1000000, 2000000, 5000000, 10000000, 20000000, 50000000, 100000000, 200000000, 500000000))
 
-	_, err8 := meter.Int64ObservableCounter("fs/streaming_write_fallback_count",
+	_, err9 := meter.Int64ObservableCounter("fs/streaming_write_fallback_count",
 		metric.WithDescription("The cumulative number of streaming write fallbacks with reason attached"),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3714,7 +3759,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err9 := meter.Int64ObservableCounter("gcs/download_bytes_count",
+	_, err10 := meter.Int64ObservableCounter("gcs/download_bytes_count",
 		metric.WithDescription("The cumulative number of bytes downloaded from GCS along with type - Sequential/Random"),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3725,7 +3770,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err10 := meter.Int64ObservableCounter("gcs/read_bytes_count",
+	_, err11 := meter.Int64ObservableCounter("gcs/read_bytes_count",
 		metric.WithDescription("The cumulative number of bytes read from GCS objects."),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3733,7 +3778,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err11 := meter.Int64ObservableCounter("gcs/read_count",
+	_, err12 := meter.Int64ObservableCounter("gcs/read_count",
 		metric.WithDescription("Specifies the number of gcs reads made along with type - Sequential/Random"),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3744,7 +3789,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err12 := meter.Int64ObservableCounter("gcs/reader_count",
+	_, err13 := meter.Int64ObservableCounter("gcs/reader_count",
 		metric.WithDescription("The cumulative number of GCS object readers opened or closed."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3753,7 +3798,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err13 := meter.Int64ObservableCounter("gcs/request_count",
+	_, err14 := meter.Int64ObservableCounter("gcs/request_count",
 		metric.WithDescription("The cumulative number of GCS requests processed along with the GCS method."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3779,12 +3824,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	gcsRequestLatencies, err14 := meter.Int64Histogram("gcs/request_latencies",
+	gcsRequestLatencies, err15 := meter.Int64Histogram("gcs/request_latencies",
 		metric.WithDescription("The cumulative distribution of the GCS request latencies."),
 		metric.WithUnit("ms"),
 		metric.WithExplicitBucketBoundaries(100, 200, 400, 800, 1500, 3000, 5000, 10000, 20000, 50000, 100000, 200000, 500000))
 
-	_, err15 := meter.Int64ObservableCounter("gcs/retry_count",
+	_, err16 := meter.Int64ObservableCounter("gcs/retry_count",
 		metric.WithDescription("The cumulative number of retry requests made to GCS."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3793,7 +3838,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err16 := meter.Int64ObservableCounter("metadata_cache/read_count",
+	_, err17 := meter.Int64ObservableCounter("metadata_cache/read_count",
 		metric.WithDescription("Total number of read requests to the metadata cache. Use attributes to analyze hit/miss ratios, entry types, and specific lookup outcomes (e.g., expiration vs. total absence)."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3818,12 +3863,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	readBlockSizes, err17 := meter.Int64Histogram("read/block_sizes",
+	readBlockSizes, err18 := meter.Int64Histogram("read/block_sizes",
 		metric.WithDescription("The cumulative distribution of read block sizes across different bucket boundaries"),
 		metric.WithUnit("By"),
 		metric.WithExplicitBucketBoundaries(0, 8192, 16384, 32768, 65536, 131072, 262144, 524288, 1048576, 2097152, 4194304, 8388608, 16777216, 33554432, 67108864, 134217728))
 
-	_, err18 := meter.Int64ObservableUpDownCounter("test/updown_counter",
+	_, err19 := meter.Int64ObservableUpDownCounter("test/updown_counter",
 		metric.WithDescription("Test metric for updown counters."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3831,7 +3876,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err19 := meter.Int64ObservableUpDownCounter("test/updown_counter_with_attrs",
+	_, err20 := meter.Int64ObservableUpDownCounter("test/updown_counter_with_attrs",
 		metric.WithDescription("Test metric for updown counters with attributes."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3840,7 +3885,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	errs := []error{err0, err1, err2, err3, err4, err5, err6, err7, err8, err9, err10, err11, err12, err13, err14, err15, err16, err17, err18, err19}
+	errs := []error{err0, err1, err2, err3, err4, err5, err6, err7, err8, err9, err10, err11, err12, err13, err14, err15, err16, err17, err18, err19, err20}
 	if err := errors.Join(errs...); err != nil {
 		return nil, err
 	}
@@ -3851,6 +3896,10 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic: &bufferedReadFallbackTriggerCountReasonInsufficientMemoryAtomic,
 		bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic: &bufferedReadFallbackTriggerCountReasonRandomReadDetectedAtomic,
 		bufferedReadReadLatency:                                                            bufferedReadReadLatency,
+		fileCacheRamTierReadCountReadTypeParallelAtomic:                                    &fileCacheRamTierReadCountReadTypeParallelAtomic,
+		fileCacheRamTierReadCountReadTypeRandomAtomic:                                      &fileCacheRamTierReadCountReadTypeRandomAtomic,
+		fileCacheRamTierReadCountReadTypeSequentialAtomic:                                  &fileCacheRamTierReadCountReadTypeSequentialAtomic,
+		fileCacheRamTierReadCountReadTypeUnknownAtomic:                                     &fileCacheRamTierReadCountReadTypeUnknownAtomic,
 		fileCacheReadBytesCountReadTypeParallelAtomic:                                      &fileCacheReadBytesCountReadTypeParallelAtomic,
 		fileCacheReadBytesCountReadTypeRandomAtomic:                                        &fileCacheReadBytesCountReadTypeRandomAtomic,
 		fileCacheReadBytesCountReadTypeSequentialAtomic:                                    &fileCacheReadBytesCountReadTypeSequentialAtomic,
@@ -222,6 +222,93 @@ func TestBufferedReadReadLatency(t *testing.T) {
 	assert.Equal(t, totalLatency.Microseconds(), dp.Sum)
 }
 
+func TestFileCacheRamTierReadCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        func(m *otelMetrics)
+		expected map[attribute.Set]int64
+	}{
+		{
+			name: "read_type_Parallel",
+			f: func(m *otelMetrics) {
+				m.FileCacheRamTierReadCount(5, "Parallel")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("read_type", "Parallel")): 5,
+			},
+		},
+		{
+			name: "read_type_Random",
+			f: func(m *otelMetrics) {
+				m.FileCacheRamTierReadCount(5, "Random")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("read_type", "Random")): 5,
+			},
+		},
+		{
+			name: "read_type_Sequential",
+			f: func(m *otelMetrics) {
+				m.FileCacheRamTierReadCount(5, "Sequential")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("read_type", "Sequential")): 5,
+			},
+		},
+		{
+			name: "read_type_Unknown",
+			f: func(m *otelMetrics) {
+				m.FileCacheRamTierReadCount(5, "Unknown")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("read_type", "Unknown")): 5,
+			},
+		}, {
+			name: "multiple_attributes_summed",
+			f: func(m *otelMetrics) {
+				m.FileCacheRamTierReadCount(5, "Parallel")
+				m.FileCacheRamTierReadCount(2, "Random")
+				m.FileCacheRamTierReadCount(3, "Parallel")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("read_type", "Parallel")): 8,
+				attribute.NewSet(attribute.String("read_type", "Random")): 2,
+			},
+		},
+		{
+			name: "negative_increment",
+			f: func(m *otelMetrics) {
+				m.FileCacheRamTierReadCount(-5, "Parallel")
+				m.FileCacheRamTierReadCount(2, "Parallel")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("read_type", "Parallel")): 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			encoder := attribute.DefaultEncoder()
+			m, rd := setupOTel(ctx, t)
+
+			tc.f(m)
+			waitForMetricsProcessing()
+
+			metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+			metric, ok := metrics["file_cache/ram_tier_read_count"]
+			if len(tc.expected) == 0 {
+				assert.False(t, ok, "file_cache/ram_tier_read_count metric should not be found")
+				return
+			}
+			require.True(t, ok, "file_cache/ram_tier_read_count metric not found")
+			expectedMap := make(map[string]int64)
+			for k, v := range tc.expected {
+				expectedMap[k.Encoded(encoder)] = v
+			}
+			assert.Equal(t, expectedMap, metric)
+		})
+	}
+}
+
 func TestFileCacheReadBytesCount(t *testing.T) {
 	tests := []struct {
 		name     string
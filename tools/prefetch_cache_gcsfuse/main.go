@@ -21,6 +21,10 @@
 // This will prefetch the cache files from the specified bucket
 // with an optional file prefix to filter the GCS objects
 // and download them into the specified cache directory
+//
+// Deprecated: the files are written in the format of the obsolete content
+// cache, which isn't read by the file cache. Use "gcsfuse warm-cache" with
+// file-cache:warm-up-manifest instead.
 package main
 
 import (
@@ -45,6 +49,7 @@ func run(args []string) (err error) {
 		prefix = args[2]
 	}
 
+	log.Printf("prefetch_cache_gcsfuse is deprecated, use \"gcsfuse warm-cache\" to populate the file cache")
 	log.Printf("Using settings:")
 	log.Printf("  cacheDir:  %s", cacheDir)
 	log.Printf("  bucketName:  %s", bucketName)